@@ -5,9 +5,19 @@ const (
 	VIDEO_ENCODING_PREFIX = "video:encoding:%s" // be-api will do encoding. both backends can check
 	// expect stream id. example : key = fmt.Sprintf(cachekeys.IS_ENDING_LIVE_PREFIX, "1"), value = boolean(true)
 	IS_ENDING_LIVE_PREFIX = "stream:ending:%d" // be-admin ends live, be-api do ending by checking in cron and ws. This key should be removed by be-api
+	// value is id of the leader be-admin instance, which runs scheduled streams and cleanup loops
+	SCHEDULER_LEADER_KEY = "scheduler:leader"
+	// value is json array of enabled comment filter rules, rewritten by be-admin on every change and read by be-api
+	COMMENT_FILTER_RULES_KEY = "comment-filter:rules"
+	// expect user id. value is the reason, the key expires with the ban. be-api refuses stream tokens and going live while it exists
+	PUBLISH_BAN_PREFIX = "user:publish-ban:%d"
 )
 
 const (
 	// be-admin publish and be-api subscirbe for ending live stream by admin
 	CHANNEL_END_LIVE = "channel:end-live-%d"
+	// be-admin publish and be-api subscribe for removing hidden and deleted comments from live chat, message is json of dto.CommentModerationEvent
+	CHANNEL_COMMENT_MODERATION = "channel:comment-moderation"
+	// be-admin publish, be-admin instances drop cached rules and be-api reloads COMMENT_FILTER_RULES_KEY, message is json of dto.CommentFilterEvent
+	CHANNEL_COMMENT_FILTER_RULES = "channel:comment-filter-rules"
 )
@@ -51,3 +51,14 @@ func (c *FCache[K, V]) Size() int {
 	defer c.mu.RUnlock()
 	return len(c.data)
 }
+
+// Keys returns the keys in no particular order.
+func (c *FCache[K, V]) Keys() []K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]K, 0, len(c.data))
+	for key := range c.data {
+		keys = append(keys, key)
+	}
+	return keys
+}
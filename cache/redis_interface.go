@@ -21,6 +21,8 @@ type RedisStore interface {
 	Publish(ctx context.Context, channel string, message any) error
 	Subscribe(ctx context.Context, handlerFunc func(channel string, message string), channels ...string) error
 	Unsubscribe(ctx context.Context, channels ...string) error
+	AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, key, owner string) error
 }
 
 type RedisClient struct {
@@ -120,3 +122,34 @@ func (c *RedisClient) Unsubscribe(ctx context.Context, channels ...string) error
 	log.Printf("Unsubscribed from channels: %s\n", strings.Join(channels, ", "))
 	return nil
 }
+
+// takes the lock or extends it when owner already holds it
+var acquireLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (c *RedisClient) AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	result, err := acquireLockScript.Run(ctx, c.Rdb, []string{key}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// ReleaseLock removes the lock only when owner still holds it
+func (c *RedisClient) ReleaseLock(ctx context.Context, key, owner string) error {
+	return releaseLockScript.Run(ctx, c.Rdb, []string{key}, owner).Err()
+}
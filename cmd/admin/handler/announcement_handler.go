@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type announcementHandler struct {
+	Handler
+	r   *echo.Group
+	srv *service.Service
+}
+
+func newAnnouncementHandler(r *echo.Group, srv *service.Service) *announcementHandler {
+	announcement := &announcementHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+	}
+
+	announcement.register()
+
+	return announcement
+}
+
+func (h *announcementHandler) register() {
+	group := h.r.Group("api/announcements")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getAnnouncements)
+	group.GET("/:id", h.getAnnouncement)
+	group.GET("/:id/stats", h.getStats)
+	group.POST("", h.createAnnouncement)
+	group.POST("/:id/cancel", h.cancelAnnouncement)
+}
+
+func (h *announcementHandler) buildAnnouncementErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrAnnouncementTargetNotFound) || errors.Is(err, service.ErrAnnouncementScheduledAt) ||
+		errors.Is(err, service.ErrAnnouncementNotCancelable) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// findAnnouncement returns the announcement of the request, or writes the error response and returns nil
+func (h *announcementHandler) findAnnouncement(c echo.Context) (*model.Announcement, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	announcement, err := h.srv.Announcement.FindByID(uint(id))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if announcement == nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return announcement, nil
+}
+
+// @Summary Get announcements
+// @Description Get announcements, latest scheduled first
+// @Tags Announcements
+// @Accept  json
+// @Produce  json
+// @Param request query dto.AnnouncementQuery true "Announcement Query"
+// @Success 200 {object} utils.PaginationModel[dto.AnnouncementDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/announcements [get]
+func (h *announcementHandler) getAnnouncements(c echo.Context) error {
+	var req dto.AnnouncementQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Announcement.GetAnnouncements(&req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get an announcement
+// @Description Get an announcement with how many users it was sent to so far
+// @Tags Announcements
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Announcement ID"
+// @Success 200 {object} dto.AnnouncementDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/announcements/{id} [get]
+func (h *announcementHandler) getAnnouncement(c echo.Context) error {
+	announcement, err := h.findAnnouncement(c)
+	if announcement == nil {
+		return err
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, h.srv.Announcement.GetAnnouncement(announcement))
+}
+
+// @Summary Get delivery stats of an announcement
+// @Description Count notifications of an announcement which were delivered, read and hidden
+// @Tags Announcements
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Announcement ID"
+// @Success 200 {object} dto.AnnouncementStatsDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/announcements/{id}/stats [get]
+func (h *announcementHandler) getStats(c echo.Context) error {
+	announcement, err := h.findAnnouncement(c)
+	if announcement == nil {
+		return err
+	}
+
+	data, err := h.srv.Announcement.GetStats(announcement)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Create an announcement
+// @Description Notify all users, users of a role, viewers of a category's streams or a saved segment. A job sends it at scheduled_at, or right away without it. Blocked users are left out.
+// @Tags Announcements
+// @Accept  json
+// @Produce  json
+// @Param request body dto.CreateAnnouncementRequest true "Create Announcement Request"
+// @Success 202 {object} dto.AnnouncementDTO
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/announcements [post]
+func (h *announcementHandler) createAnnouncement(c echo.Context) error {
+	var req dto.CreateAnnouncementRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	data, err := h.srv.Announcement.Create(&req, currentUser.ID)
+	if err != nil {
+		return h.buildAnnouncementErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.CreateAnnouncement, fmt.Sprintf("%s created announcement %d %q to %s users, scheduled at %s.", currentUser.Username, data.ID, data.Title, data.TargetType, data.ScheduledAt.Format(utils.DATETIME_LAYOUT)))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusAccepted, "Successfully", data)
+}
+
+// @Summary Cancel an announcement
+// @Description Cancel a scheduled announcement, or stop one which is being sent. Notifications sent already are kept.
+// @Tags Announcements
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Announcement ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/announcements/{id}/cancel [post]
+func (h *announcementHandler) cancelAnnouncement(c echo.Context) error {
+	announcement, err := h.findAnnouncement(c)
+	if announcement == nil {
+		return err
+	}
+
+	if err := h.srv.Announcement.Cancel(announcement); err != nil {
+		return h.buildAnnouncementErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.CancelAnnouncement, fmt.Sprintf("%s canceled announcement %d %q.", currentUser.Username, announcement.ID, announcement.Title))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type blockHandler struct {
+	Handler
+	r   *echo.Group
+	srv *service.Service
+}
+
+func newBlockHandler(r *echo.Group, srv *service.Service) *blockHandler {
+	block := &blockHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+	}
+
+	block.register()
+
+	return block
+}
+
+func (h *blockHandler) register() {
+	group := h.r.Group("api/users/:id/blocks")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getBlocks)
+	group.POST("", h.createBlock)
+	group.DELETE("/:blocked_user_id", h.deleteBlock)
+
+	ranking := h.r.Group("api/users/most-blocked")
+	ranking.Use(h.JWTMiddleware())
+	ranking.GET("", h.getMostBlocked)
+}
+
+func (h *blockHandler) buildBlockErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrUserBlockSelf) || errors.Is(err, service.ErrUserBlockExists) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if errors.Is(err, service.ErrUserBlockNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, err, nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// @Summary Get most blocked users
+// @Description Rank users by how many users blocked them in the range, a signal of abuse
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param request query dto.MostBlockedQuery true "Most Blocked Query"
+// @Success 200 {object} utils.PaginationModel[dto.MostBlockedDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/most-blocked [get]
+func (h *blockHandler) getMostBlocked(c echo.Context) error {
+	var req dto.MostBlockedQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.UserBlock.GetMostBlocked(&req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get blocks of user
+// @Description Get users blocked by a user, or users who blocked it with direction blocked_by
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param request query dto.UserBlockQuery true "User Block Query"
+// @Success 200 {object} utils.PaginationModel[dto.UserBlockDTO]
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/blocks [get]
+func (h *blockHandler) getBlocks(c echo.Context) error {
+	user, err := h.findUser(c, "id")
+	if user == nil {
+		return err
+	}
+
+	var req dto.UserBlockQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.UserBlock.GetBlocks(user.ID, &req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Block a user on behalf of user
+// @Description Add a block of blocked_user_id by a user, as if the user blocked it in be-api
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param request body dto.CreateUserBlockRequest true "Create User Block Request"
+// @Success 201 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/blocks [post]
+func (h *blockHandler) createBlock(c echo.Context) error {
+	user, err := h.findUser(c, "id")
+	if user == nil {
+		return err
+	}
+
+	var req dto.CreateUserBlockRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	blockedUser, err := h.srv.User.FindByID(req.BlockedUserID)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if blockedUser == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("blocked user not found"), nil)
+	}
+
+	if err := h.srv.UserBlock.Block(user.ID, blockedUser.ID); err != nil {
+		return h.buildBlockErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	details := fmt.Sprintf("%s blocked %s on behalf of %s.", currentUser.Username, blockedUser.Username, user.Username)
+	if req.Reason != "" {
+		details = fmt.Sprintf("%s blocked %s on behalf of %s. reason is %s", currentUser.Username, blockedUser.Username, user.Username, req.Reason)
+	}
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.AddUserBlock, details)
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+	return utils.BuildSuccessResponse(c, http.StatusCreated, "Successfully", nil)
+}
+
+// @Summary Unblock a user on behalf of user
+// @Description Remove the block of blocked_user_id by a user
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param blocked_user_id path int true "Blocked User ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/blocks/{blocked_user_id} [delete]
+func (h *blockHandler) deleteBlock(c echo.Context) error {
+	user, err := h.findUser(c, "id")
+	if user == nil {
+		return err
+	}
+	blockedUser, err := h.findUser(c, "blocked_user_id")
+	if blockedUser == nil {
+		return err
+	}
+
+	if err := h.srv.UserBlock.Unblock(user.ID, blockedUser.ID); err != nil {
+		return h.buildBlockErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.RemoveUserBlock, fmt.Sprintf("%s unblocked %s on behalf of %s.", currentUser.Username, blockedUser.Username, user.Username))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
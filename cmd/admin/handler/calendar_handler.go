@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/conf"
+	"gitlab/live/be-live-admin/dto"
+	cmiddleware "gitlab/live/be-live-admin/middleware"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_FEED_EXPIRATION = 365 * 24 * time.Hour
+
+	DEFAULT_PROBE_TIMEOUT = 10 * time.Second
+)
+
+type calendarHandler struct {
+	Handler
+	r              *echo.Group
+	srv            *service.Service
+	ApiURL         string
+	scheduleLimits service.ScheduleLimits
+	feedExpiration time.Duration
+}
+
+func newScheduleLimits(cfg *conf.ScheduleConfig) service.ScheduleLimits {
+	return service.ScheduleLimits{
+		DefaultDuration: time.Duration(cfg.DefaultDuration) * time.Second,
+		CategorySlots:   cfg.CategorySlots,
+		MaxConcurrent:   cfg.MaxConcurrent,
+	}
+}
+
+// probeDuration returns 0 when the video can't be probed
+func probeDuration(ctx context.Context, ffprobePath, videoPath string) time.Duration {
+	// the request waits for ffprobe, a stuck probe falls back to the default duration
+	timeout := time.Duration(conf.GetScheduleConfig().ProbeTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = DEFAULT_PROBE_TIMEOUT
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	duration, err := utils.ProbeVideoDuration(ctx, ffprobePath, videoPath)
+	if err != nil {
+		log.Println(err)
+		return 0
+	}
+	return duration
+}
+
+// probeVideoDuration returns the duration of an uploaded video in seconds,
+// 0 when it can't be probed so the default duration is used for conflicts
+func probeVideoDuration(ctx context.Context, ffprobePath, videoPath string) uint {
+	return uint(probeDuration(ctx, ffprobePath, videoPath).Round(time.Second) / time.Second)
+}
+
+func newCalendarHandler(r *echo.Group, srv *service.Service) *calendarHandler {
+	scheduleConfig := conf.GetScheduleConfig()
+
+	feedExpiration := time.Duration(scheduleConfig.FeedExpiration) * time.Second
+	if feedExpiration <= 0 {
+		feedExpiration = DEFAULT_FEED_EXPIRATION
+	}
+
+	calendar := &calendarHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:              r,
+		srv:            srv,
+		ApiURL:         conf.GetApiFileConfig().Url,
+		scheduleLimits: newScheduleLimits(scheduleConfig),
+		feedExpiration: feedExpiration,
+	}
+
+	calendar.register()
+
+	return calendar
+}
+
+func (h *calendarHandler) register() {
+	group := h.r.Group("api/streams/calendar")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getCalendar)
+	group.GET("/feed-url", h.getFeedURL)
+
+	// external calendars can't send tokens, feeds are opened with signed urls
+	feeds := h.r.Group("api/calendar")
+	feeds.Use(cmiddleware.SignedURLMiddleware())
+	feeds.GET("/streamers/:id/feed.ics", h.getStreamerFeed)
+	feeds.GET("/categories/:id/feed.ics", h.getCategoryFeed)
+}
+
+// @Summary Get calendar of scheduled streams
+// @Description Get scheduled streams and upcoming occurrences of recurring schedules between two days, grouped by day
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param request query dto.CalendarQuery true "Calendar Query"
+// @Success 200 {object} []dto.CalendarDayDTO
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/calendar [get]
+func (h *calendarHandler) getCalendar(c echo.Context) error {
+	var req dto.CalendarQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Calendar.GetCalendar(&req, h.scheduleLimits)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCalendarRange) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get calendar feed url
+// @Description Get a signed iCalendar (.ics) feed url of a streamer or a category for external calendars
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param request query dto.CalendarFeedQuery true "Calendar Feed Query"
+// @Success 200 {object} dto.CalendarFeedRespDTO
+// @Failure 400 "Invalid request"
+// @Security Bearer
+// @Router /api/streams/calendar/feed-url [get]
+func (h *calendarHandler) getFeedURL(c echo.Context) error {
+	var req dto.CalendarFeedQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	feedPath := fmt.Sprintf("/api/calendar/categories/%d/feed.ics", req.CategoryID)
+	if req.UserID != 0 {
+		feedPath = fmt.Sprintf("/api/calendar/streamers/%d/feed.ics", req.UserID)
+	}
+
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, dto.CalendarFeedRespDTO{
+		URL:       utils.MakeSignedURL(h.ApiURL, feedPath, h.feedExpiration),
+		ExpiresAt: time.Now().Add(h.feedExpiration),
+	})
+}
+
+func (h *calendarHandler) writeFeed(c echo.Context, userID, categoryID uint) error {
+	feed, err := h.srv.Calendar.GetFeed(userID, categoryID, h.scheduleLimits)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", []byte(feed))
+}
+
+// @Summary Get streamer calendar feed
+// @Description iCalendar feed of scheduled streams of a streamer, opened with the signed url from feed-url
+// @Tags Streams
+// @Produce  text/calendar
+// @Param id path int true "Streamer ID"
+// @Success 200 "iCalendar feed"
+// @Failure 403 "Invalid signature"
+// @Failure 404 "Not found"
+// @Router /api/calendar/streamers/{id}/feed.ics [get]
+func (h *calendarHandler) getStreamerFeed(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+	return h.writeFeed(c, uint(id), 0)
+}
+
+// @Summary Get category calendar feed
+// @Description iCalendar feed of scheduled streams of a category, opened with the signed url from feed-url
+// @Tags Streams
+// @Produce  text/calendar
+// @Param id path int true "Category ID"
+// @Success 200 "iCalendar feed"
+// @Failure 403 "Invalid signature"
+// @Failure 404 "Not found"
+// @Router /api/calendar/categories/{id}/feed.ics [get]
+func (h *calendarHandler) getCategoryFeed(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+	return h.writeFeed(c, 0, uint(id))
+}
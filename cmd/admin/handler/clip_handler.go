@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/conf"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type clipHandler struct {
+	Handler
+	r             *echo.Group
+	srv           *service.Service
+	videoFolder   string
+	clipsFolder   string
+	ApiURL        string
+	storageQuotas map[model.RoleType]int64
+	ffprobePath   string
+	timeout       time.Duration
+}
+
+func newClipHandler(r *echo.Group, srv *service.Service) *clipHandler {
+	fileStorageConfig := conf.GetFileStorageConfig()
+	clipConfig := conf.GetClipConfig()
+
+	timeout := time.Duration(clipConfig.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = service.DEFAULT_CLIP_TIMEOUT
+	}
+
+	clip := &clipHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:             r,
+		srv:           srv,
+		videoFolder:   fileStorageConfig.VideoFolder,
+		clipsFolder:   fileStorageConfig.ClipsFolder,
+		ApiURL:        conf.GetApiFileConfig().Url,
+		storageQuotas: conf.GetStorageQuotaConfig(),
+		ffprobePath:   conf.GetScheduleConfig().FFprobePath,
+		timeout:       timeout,
+	}
+
+	clip.register()
+
+	return clip
+}
+
+func (h *clipHandler) register() {
+	group := h.r.Group("api/streams/:id/clips")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getClips)
+	group.GET("/:clipId", h.getClip)
+	group.POST("", h.createClip)
+	group.DELETE("/:clipId", h.deleteClip)
+}
+
+func (h *clipHandler) buildClipErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrClipStreamNotEnded) || errors.Is(err, service.ErrInvalidClipRange) || errors.Is(err, service.ErrClipProcessing) || errors.Is(err, service.ErrStorageQuotaExceeded) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// @Summary Get clips of a stream
+// @Description Get clips cut from the recording of a stream, newest first
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Success 200 {object} []dto.ClipDTO
+// @Failure 400 "Invalid ID parameter"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/clips [get]
+func (h *clipHandler) getClips(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	data, err := h.srv.Clip.GetClips(uint(id), h.ApiURL)
+	if err != nil {
+		return h.buildClipErrorResponse(c, err)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get a clip
+// @Description Get a clip with its processing status
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Param clipId path int true "Clip ID"
+// @Success 200 {object} dto.ClipDTO
+// @Failure 400 "Invalid ID parameter"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/clips/{clipId} [get]
+func (h *clipHandler) getClip(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+	clipID, err := strconv.Atoi(c.Param("clipId"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid clipId parameter"), nil)
+	}
+
+	data, err := h.srv.Clip.GetClip(uint(id), uint(clipID), h.ApiURL)
+	if err != nil {
+		return h.buildClipErrorResponse(c, err)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Create a clip
+// @Description Cut a clip from the recording of an ended stream. The clip is cut by a background job, poll the clip or the job until the status is ready or failed.
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Param request body dto.ClipRequest true "Clip Request"
+// @Success 202 {object} dto.ClipDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/clips [post]
+func (h *clipHandler) createClip(c echo.Context) error {
+	var req dto.ClipRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	stream, err := h.srv.Stream.GetStreamByID(uint(id))
+	if err != nil {
+		return h.buildClipErrorResponse(c, err)
+	}
+	if stream.Status != model.ENDED {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, service.ErrClipStreamNotEnded, nil)
+	}
+
+	isEncoding, err := h.srv.Stream.IsEncodingVideo(c.Request().Context(), stream.StreamKey)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if isEncoding {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("recording is being encoded"), nil)
+	}
+
+	recordingPath := utils.MakeVideoPath(h.videoFolder, stream.StreamKey+".mp4")
+	recordingSize, err := utils.GetfileSize(recordingPath)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("recording not found"), nil)
+	}
+
+	// the clip size is estimated from the part of the recording
+	recordingDuration := uint(probeDuration(c.Request().Context(), h.ffprobePath, recordingPath) / time.Millisecond)
+	if recordingDuration > 0 && req.EndOffset > req.StartOffset {
+		estimatedSize := recordingSize * int64(min(req.EndOffset, recordingDuration)-min(req.StartOffset, recordingDuration)) / int64(recordingDuration)
+		if err := h.srv.Storage.CheckQuota(stream.UserID, estimatedSize, h.storageQuotas); err != nil {
+			return h.buildClipErrorResponse(c, err)
+		}
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	req.FileName = fmt.Sprintf("%d_%s.mp4", stream.UserID, utils.MakeUniqueIDWithTime())
+	req.CreatedByID = currentUser.ID
+
+	clip, err := h.srv.Clip.Create(stream.ID, &req, recordingDuration)
+	if err != nil {
+		return h.buildClipErrorResponse(c, err)
+	}
+
+	// ffmpeg is deterministic, failed clips are not retried
+	job, err := h.srv.Job.Enqueue(model.JobTypeCutClip, dto.CutClipPayload{
+		ClipID:        clip.ID,
+		OwnerID:       stream.UserID,
+		RecordingPath: recordingPath,
+		ClipPath:      utils.MakeVideoPath(h.clipsFolder, clip.FileName),
+	}, 1, currentUser.ID)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.CreateClip, fmt.Sprintf("%s created clip %d of stream %d from %dms to %dms.", currentUser.Username, clip.ID, stream.ID, clip.StartOffset, clip.EndOffset))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	data, err := h.srv.Clip.GetClip(stream.ID, clip.ID, h.ApiURL)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	data.JobID = job.ID
+	return utils.BuildSuccessResponseWithData(c, http.StatusAccepted, data)
+}
+
+// @Summary Delete a clip
+// @Description Delete a clip, its file is removed by a job, clips being processed can't be deleted
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Param clipId path int true "Clip ID"
+// @Success 200 {object} dto.JobCreatedDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/clips/{clipId} [delete]
+func (h *clipHandler) deleteClip(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+	clipID, err := strconv.Atoi(c.Param("clipId"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid clipId parameter"), nil)
+	}
+
+	stream, err := h.srv.Stream.GetStreamByID(uint(id))
+	if err != nil {
+		return h.buildClipErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	clip, job, err := h.srv.Clip.Delete(stream.ID, uint(clipID), stream.UserID, h.clipsFolder, h.timeout, currentUser.ID)
+	if err != nil {
+		return h.buildClipErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.DeleteClip, fmt.Sprintf("%s deleted clip %d of stream %d.", currentUser.Username, clip.ID, stream.ID))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", dto.JobCreatedDTO{JobID: job.ID})
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type commentFilterHandler struct {
+	Handler
+	r   *echo.Group
+	srv *service.Service
+}
+
+func newCommentFilterHandler(r *echo.Group, srv *service.Service) *commentFilterHandler {
+	commentFilter := &commentFilterHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+	}
+
+	commentFilter.register()
+
+	return commentFilter
+}
+
+func (h *commentFilterHandler) register() {
+	group := h.r.Group("api/comment-filters")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getRules)
+	group.GET("/:id", h.getRule)
+	group.POST("", h.create)
+	group.PUT("/:id", h.update)
+	group.DELETE("/:id", h.delete)
+	group.POST("/evaluate", h.evaluate)
+}
+
+func (h *commentFilterHandler) buildRuleErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrCommentFilterInvalidPattern) || errors.Is(err, service.ErrCommentFilterRuleNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("category not found"), nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// findRule returns the rule of the request, or writes the error response and returns nil
+func (h *commentFilterHandler) findRule(c echo.Context) (*model.CommentFilterRule, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	rule, err := h.srv.CommentFilter.FindByID(uint(id))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if rule == nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return rule, nil
+}
+
+// @Summary Get comment filter rules
+// @Description Get keyword and regex rules which be-api applies to new comments
+// @Tags Comment Filters
+// @Accept  json
+// @Produce  json
+// @Param request query dto.CommentFilterRuleQuery true "Comment Filter Rule Query"
+// @Success 200 {object} utils.PaginationModel[dto.CommentFilterRuleDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comment-filters [get]
+func (h *commentFilterHandler) getRules(c echo.Context) error {
+	var req dto.CommentFilterRuleQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.CommentFilter.GetRules(&req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get a comment filter rule
+// @Description Get a comment filter rule
+// @Tags Comment Filters
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} dto.CommentFilterRuleDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comment-filters/{id} [get]
+func (h *commentFilterHandler) getRule(c echo.Context) error {
+	rule, err := h.findRule(c)
+	if rule == nil {
+		return err
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, h.srv.CommentFilter.ToDto(rule))
+}
+
+// @Summary Create a comment filter rule
+// @Description Create a global rule (no category_id) or a rule for streams of a category, hit_threshold is required for block_user
+// @Tags Comment Filters
+// @Accept  json
+// @Produce  json
+// @Param request body dto.CommentFilterRuleRequest true "Comment Filter Rule Request"
+// @Success 201 {object} dto.CommentFilterRuleDTO
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comment-filters [post]
+func (h *commentFilterHandler) create(c echo.Context) error {
+	var req dto.CommentFilterRuleRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	currentUser := c.Get("user").(*utils.Claims)
+	req.CreatedByID = currentUser.ID
+
+	rule, err := h.srv.CommentFilter.Create(c.Request().Context(), &req)
+	if err != nil {
+		return h.buildRuleErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.CreateCommentFilter, fmt.Sprintf("%s created %s comment filter rule %d (%s %q, %s).", currentUser.Username, describePolicyScope(rule.CategoryID), rule.ID, rule.Type, rule.Pattern, rule.Action))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponseWithData(c, http.StatusCreated, h.srv.CommentFilter.ToDto(rule))
+}
+
+// @Summary Update a comment filter rule
+// @Description Replace a comment filter rule, be-api picks up the change immediately
+// @Tags Comment Filters
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Rule ID"
+// @Param request body dto.CommentFilterRuleRequest true "Comment Filter Rule Request"
+// @Success 200 {object} dto.CommentFilterRuleDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comment-filters/{id} [put]
+func (h *commentFilterHandler) update(c echo.Context) error {
+	var req dto.CommentFilterRuleRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	rule, err := h.findRule(c)
+	if rule == nil {
+		return err
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	req.CreatedByID = currentUser.ID
+	if err := h.srv.CommentFilter.Update(c.Request().Context(), rule, &req); err != nil {
+		return h.buildRuleErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.UpdateCommentFilter, fmt.Sprintf("%s updated %s comment filter rule %d (%s %q, %s, enabled %t).", currentUser.Username, describePolicyScope(rule.CategoryID), rule.ID, rule.Type, rule.Pattern, rule.Action, rule.Enabled))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, h.srv.CommentFilter.ToDto(rule))
+}
+
+// @Summary Delete a comment filter rule
+// @Description Delete a comment filter rule, comments it hid or flagged stay as they are
+// @Tags Comment Filters
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Rule ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comment-filters/{id} [delete]
+func (h *commentFilterHandler) delete(c echo.Context) error {
+	rule, err := h.findRule(c)
+	if rule == nil {
+		return err
+	}
+
+	if err := h.srv.CommentFilter.Delete(c.Request().Context(), rule.ID); err != nil {
+		return h.buildRuleErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.DeleteCommentFilter, fmt.Sprintf("%s deleted comment filter rule %d (%s %q, %s).", currentUser.Username, rule.ID, rule.Type, rule.Pattern, rule.Action))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Evaluate comment filter rules
+// @Description Preview what rules, enabled or not, would match in stored comments, newest first. Nothing is changed.
+// @Tags Comment Filters
+// @Accept  json
+// @Produce  json
+// @Param request body dto.CommentFilterEvaluateRequest true "Comment Filter Evaluate Request"
+// @Success 200 {object} dto.CommentFilterEvaluationDTO
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comment-filters/evaluate [post]
+func (h *commentFilterHandler) evaluate(c echo.Context) error {
+	var req dto.CommentFilterEvaluateRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if req.From > 0 && req.To > 0 && req.From > req.To {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("from must be before to"), nil)
+	}
+
+	data, err := h.srv.CommentFilter.Evaluate(c.Request().Context(), &req)
+	if err != nil {
+		return h.buildRuleErrorResponse(c, err)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
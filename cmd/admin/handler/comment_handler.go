@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type commentHandler struct {
+	Handler
+	r   *echo.Group
+	srv *service.Service
+}
+
+func newCommentHandler(r *echo.Group, srv *service.Service) *commentHandler {
+	comment := &commentHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+	}
+
+	comment.register()
+
+	return comment
+}
+
+func (h *commentHandler) register() {
+	group := h.r.Group("api/comments")
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getComments)
+	group.GET("/:id", h.getComment)
+	group.PATCH("/:id/hide", h.hideComment)
+	group.PATCH("/:id/unhide", h.unhideComment)
+	group.DELETE("/:id", h.deleteComment)
+
+	streams := h.r.Group("api/streams")
+	streams.Use(h.JWTMiddleware())
+	streams.GET("/:id/comments/timeline", h.getTimeline)
+}
+
+func (h *commentHandler) buildCommentErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrCommentHidden) || errors.Is(err, service.ErrCommentNotHidden) || errors.Is(err, service.ErrCommentTimeline) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// findComment returns the comment of the request, or writes the error response and returns nil
+func (h *commentHandler) findComment(c echo.Context) (*model.Comment, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	comment, err := h.srv.Comment.FindByID(uint(id))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if comment == nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return comment, nil
+}
+
+// @Summary Get comments
+// @Description Get comments for moderation, newest first
+// @Tags Comments
+// @Accept  json
+// @Produce  json
+// @Param request query dto.CommentQuery true "Comment Query"
+// @Success 200 {object} utils.PaginationModel[dto.CommentDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comments [get]
+func (h *commentHandler) getComments(c echo.Context) error {
+	var req dto.CommentQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Comment.GetComments(&req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get a comment
+// @Description Get a comment with its moderation state
+// @Tags Comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Comment ID"
+// @Success 200 {object} dto.CommentDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comments/{id} [get]
+func (h *commentHandler) getComment(c echo.Context) error {
+	comment, err := h.findComment(c)
+	if comment == nil {
+		return err
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, h.srv.Comment.ToDto(comment))
+}
+
+// @Summary Hide a comment
+// @Description Hide a comment, be-api removes it from live chat
+// @Tags Comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Comment ID"
+// @Param request body dto.HideCommentRequest true "Hide Comment Request"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comments/{id}/hide [patch]
+func (h *commentHandler) hideComment(c echo.Context) error {
+	var req dto.HideCommentRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	comment, err := h.findComment(c)
+	if comment == nil {
+		return err
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	if err := h.srv.Comment.Hide(c.Request().Context(), comment, currentUser.ID, req.Reason); err != nil {
+		return h.buildCommentErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.HideComment, fmt.Sprintf("%s hid comment %d of %s on stream %d.", currentUser.Username, comment.ID, comment.User.Username, comment.StreamID))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Unhide a comment
+// @Description Show a hidden comment again
+// @Tags Comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Comment ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comments/{id}/unhide [patch]
+func (h *commentHandler) unhideComment(c echo.Context) error {
+	comment, err := h.findComment(c)
+	if comment == nil {
+		return err
+	}
+
+	if err := h.srv.Comment.Unhide(c.Request().Context(), comment); err != nil {
+		return h.buildCommentErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.UnhideComment, fmt.Sprintf("%s unhid comment %d of %s on stream %d.", currentUser.Username, comment.ID, comment.User.Username, comment.StreamID))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Delete a comment
+// @Description Delete a comment, be-api removes it from live chat
+// @Tags Comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Comment ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/comments/{id} [delete]
+func (h *commentHandler) deleteComment(c echo.Context) error {
+	comment, err := h.findComment(c)
+	if comment == nil {
+		return err
+	}
+
+	if err := h.srv.Comment.Delete(c.Request().Context(), comment); err != nil {
+		return h.buildCommentErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.DeleteComment, fmt.Sprintf("%s deleted comment %d of %s on stream %d: %q.", currentUser.Username, comment.ID, comment.User.Username, comment.StreamID, comment.Comment))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Get comment timeline of a stream
+// @Description Count comments, hidden and flagged ones of a stream per interval since it started
+// @Tags Comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Param request query dto.CommentTimelineQuery true "Comment Timeline Query"
+// @Success 200 {object} dto.CommentTimelineDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/comments/timeline [get]
+func (h *commentHandler) getTimeline(c echo.Context) error {
+	var req dto.CommentTimelineQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	stream, err := h.srv.Stream.GetStreamByID(uint(id))
+	if err != nil {
+		return h.buildCommentErrorResponse(c, err)
+	}
+
+	data, err := h.srv.Comment.GetTimeline(stream, req.Interval)
+	if err != nil {
+		return h.buildCommentErrorResponse(c, err)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
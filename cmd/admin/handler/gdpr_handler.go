@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/conf"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type gdprHandler struct {
+	Handler
+	r                *echo.Group
+	srv              *service.Service
+	apiURL           string
+	exportExpiration time.Duration
+}
+
+func newGDPRHandler(r *echo.Group, srv *service.Service) *gdprHandler {
+	gdpr := &gdprHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:                r,
+		srv:              srv,
+		apiURL:           conf.GetApiFileConfig().Url,
+		exportExpiration: time.Duration(conf.GetGDPRConfig().ExportExpiration) * time.Second,
+	}
+
+	gdpr.register()
+
+	return gdpr
+}
+
+func (h *gdprHandler) register() {
+	users := h.r.Group("api/users")
+	users.Use(h.JWTMiddleware())
+	users.POST("/:id/export", h.exportUser)
+	users.POST("/:id/erasure", h.eraseUser)
+
+	group := h.r.Group("api/gdpr")
+	group.Use(h.JWTMiddleware())
+	group.GET("/policy", h.getPolicy)
+	group.GET("/requests", h.getRequests)
+	group.GET("/requests/:id", h.getRequest)
+}
+
+// findTarget returns the user of the request, or writes the error response and returns nil
+func (h *gdprHandler) findTarget(c echo.Context) (*model.User, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	user, err := h.srv.User.FindByID(uint(id))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if user == nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	if currentUser.RoleType == model.ADMINROLE && user.Role.Type == model.ADMINROLE {
+		return nil, utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request, admin can't request data of admin"), nil)
+	}
+	return user, nil
+}
+
+func (h *gdprHandler) buildRequestErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrGDPRRequestPending) || errors.Is(err, service.ErrGDPRProtectedUser) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// @Summary Export data of a user
+// @Description Queue a job which zips the profile, streams, comments, likes, views, shares, bookmarks, subscriptions, notifications and uploaded files of a user
+// @Tags GDPR
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 202 {object} dto.GDPRRequestCreatedDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/export [post]
+func (h *gdprHandler) exportUser(c echo.Context) error {
+	user, err := h.findTarget(c)
+	if user == nil {
+		return err
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	created, err := h.srv.GDPR.RequestExport(user, currentUser.ID)
+	if err != nil {
+		return h.buildRequestErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.ExportUserData, fmt.Sprintf("%s requested data export of %s in request %d.", currentUser.Username, user.Username, created.RequestID))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusAccepted, "Successfully", created)
+}
+
+// @Summary Erase data of a user
+// @Description Queue a job which deletes or anonymizes the records of a user as described by the erasure policy, it can't be undone
+// @Tags GDPR
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param request body dto.GDPRErasureRequest true "Erasure Request"
+// @Success 202 {object} dto.GDPRRequestCreatedDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/erasure [post]
+func (h *gdprHandler) eraseUser(c echo.Context) error {
+	var req dto.GDPRErasureRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	user, err := h.findTarget(c)
+	if user == nil {
+		return err
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	created, err := h.srv.GDPR.RequestErasure(user, req.Reason, currentUser.ID)
+	if err != nil {
+		return h.buildRequestErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.EraseUserData, fmt.Sprintf("%s requested erasure of %s in request %d.", currentUser.Username, user.Username, created.RequestID))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusAccepted, "Successfully", created)
+}
+
+// @Summary Get erasure policy
+// @Description Get what erasure does to each record of a user
+// @Tags GDPR
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} dto.GDPRPolicyEntry
+// @Security Bearer
+// @Router /api/gdpr/policy [get]
+func (h *gdprHandler) getPolicy(c echo.Context) error {
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, dto.GDPRErasurePolicy)
+}
+
+// @Summary Get export and erasure requests
+// @Description Get requests with their signed completion reports, newest first
+// @Tags GDPR
+// @Accept  json
+// @Produce  json
+// @Param request query dto.GDPRRequestQuery true "GDPR Request Query"
+// @Success 200 {object} utils.PaginationModel[dto.GDPRRequestDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/gdpr/requests [get]
+func (h *gdprHandler) getRequests(c echo.Context) error {
+	var req dto.GDPRRequestQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.GDPR.GetRequests(&req, h.apiURL, h.exportExpiration)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get an export or erasure request
+// @Description Get a request with its signed completion report and the download url of exports
+// @Tags GDPR
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Request ID"
+// @Success 200 {object} dto.GDPRRequestDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/gdpr/requests/{id} [get]
+func (h *gdprHandler) getRequest(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	data, err := h.srv.GDPR.GetRequest(uint(id), h.apiURL, h.exportExpiration)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if data == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
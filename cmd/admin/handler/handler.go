@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
 	"gitlab/live/be-live-admin/model"
 	"gitlab/live/be-live-admin/service"
 	"gitlab/live/be-live-admin/utils"
 	"log"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v4"
@@ -31,10 +35,38 @@ func (h *Handler) Register() {
 	newAdminHandler(h.r, h.srv)
 	newStreamHandler(h.r, h.srv)
 	newCategoryHandler(h.r, h.srv)
+	newStorageHandler(h.r, h.srv)
+	newRetentionHandler(h.r, h.srv)
+	newRecurrenceHandler(h.r, h.srv)
+	newCalendarHandler(h.r, h.srv)
+	newPlaylistHandler(h.r, h.srv)
+	newClipHandler(h.r, h.srv)
+	newJobHandler(h.r, h.srv)
+	newTrashHandler(h.r, h.srv)
+	newGDPRHandler(h.r, h.srv)
+	newInviteHandler(h.r, h.srv)
+	newCommentHandler(h.r, h.srv)
+	newCommentFilterHandler(h.r, h.srv)
+	newReportHandler(h.r, h.srv)
+	newBlockHandler(h.r, h.srv)
+	newSubscriptionHandler(h.r, h.srv)
+	newSegmentHandler(h.r, h.srv)
+	newAnnouncementHandler(h.r, h.srv)
+	newNotificationTemplateHandler(h.r, h.srv)
 
 }
 
 func (h *Handler) JWTMiddleware() echo.MiddlewareFunc {
+	return h.tokenMiddleware(model.ADMINROLE, model.SUPPERADMINROLE)
+}
+
+// UserJWTMiddleware accepts tokens of any role, be-api forwards tokens of its users
+func (h *Handler) UserJWTMiddleware() echo.MiddlewareFunc {
+	return h.tokenMiddleware()
+}
+
+// tokenMiddleware accepts tokens of roles, or of any role when roles is empty
+func (h *Handler) tokenMiddleware(roles ...model.RoleType) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			// Extract token from Authorization header
@@ -61,7 +93,7 @@ func (h *Handler) JWTMiddleware() echo.MiddlewareFunc {
 				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired token"})
 			}
 
-			if !slices.Contains([]model.RoleType{model.ADMINROLE, model.SUPPERADMINROLE}, claims.RoleType) {
+			if len(roles) > 0 && !slices.Contains(roles, claims.RoleType) {
 				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Permission denied"})
 			}
 
@@ -80,3 +112,29 @@ func (h *Handler) JWTMiddleware() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// findUser returns the user of the param, or writes the error response and returns nil
+func (h *Handler) findUser(c echo.Context, param string) (*model.User, error) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusBadRequest, fmt.Errorf("invalid %s parameter", param), nil)
+	}
+
+	user, err := h.srv.User.FindByID(uint(id))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if user == nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return user, nil
+}
+
+// removeUntrackedFiles queues removal of files which were never tracked in storage usage, e.g. copies left by a failed request.
+// The request has already failed or succeeded, so a failed enqueue is only logged.
+func (h *Handler) removeUntrackedFiles(paths []string, createdByID uint) {
+	files := utils.Map(paths, func(path string) dto.RemoveFileEntry { return dto.RemoveFileEntry{Path: path} })
+	if _, err := h.srv.Job.Enqueue(model.JobTypeRemoveFiles, dto.RemoveFilesPayload{Files: files}, 0, createdByID); err != nil {
+		log.Println(err)
+	}
+}
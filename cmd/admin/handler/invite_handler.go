@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type inviteHandler struct {
+	Handler
+	r   *echo.Group
+	srv *service.Service
+}
+
+func newInviteHandler(r *echo.Group, srv *service.Service) *inviteHandler {
+	invite := &inviteHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+	}
+
+	invite.register()
+
+	return invite
+}
+
+func (h *inviteHandler) register() {
+	group := h.r.Group("api/invites")
+
+	group.POST("/accept", h.acceptInvite)
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getInvites)
+	group.GET("/:id", h.getInvite)
+	group.POST("", h.createInvite)
+	group.POST("/:id/resend", h.resendInvite)
+	group.POST("/:id/revoke", h.revokeInvite)
+}
+
+func (h *inviteHandler) buildInviteErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrUsernameTaken) || errors.Is(err, service.ErrEmailTaken) ||
+		errors.Is(err, service.ErrInviteNotPending) || errors.Is(err, service.ErrInviteInvalid) || errors.Is(err, service.ErrInviteExpired) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if errors.Is(err, service.ErrUserConflict) {
+		return utils.BuildErrorResponse(c, http.StatusConflict, err, nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// findInvite returns the invite of the request, or writes the error response and returns nil
+func (h *inviteHandler) findInvite(c echo.Context) (*model.Invite, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	invite, err := h.srv.Invite.FindByID(uint(id))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if invite == nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return invite, nil
+}
+
+// @Summary Get invites
+// @Description Get invites, newest first
+// @Tags Invites
+// @Accept  json
+// @Produce  json
+// @Param request query dto.InviteQuery true "Invite Query"
+// @Success 200 {object} utils.PaginationModel[dto.InviteDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/invites [get]
+func (h *inviteHandler) getInvites(c echo.Context) error {
+	var req dto.InviteQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Invite.GetInvites(&req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get an invite
+// @Description Get an invite with its email delivery status
+// @Tags Invites
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Invite ID"
+// @Success 200 {object} dto.InviteDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/invites/{id} [get]
+func (h *inviteHandler) getInvite(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	data, err := h.srv.Invite.GetInvite(uint(id))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if data == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Invite a user
+// @Description Reserve a username and email and email a link where the invitee sets their password, the user is created on accept
+// @Tags Invites
+// @Accept  json
+// @Produce  json
+// @Param request body dto.CreateInviteRequest true "Create Invite Request"
+// @Success 202 {object} dto.InviteCreatedDTO
+// @Failure 400 "Invalid request"
+// @Failure 409 "Username or email was just taken"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/invites [post]
+func (h *inviteHandler) createInvite(c echo.Context) error {
+	var req dto.CreateInviteRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	invite, created, err := h.srv.Invite.Create(&req, currentUser.ID)
+	if err != nil {
+		return h.buildInviteErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.InviteUser, fmt.Sprintf("%s invited %s (%s) with role type %s in invite %d.", currentUser.Username, invite.Username, invite.Email, req.RoleType, invite.ID))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusAccepted, "Successfully", created)
+}
+
+// @Summary Resend an invite
+// @Description Email a new link of a pending invite, the previous link stops working
+// @Tags Invites
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Invite ID"
+// @Success 202 {object} dto.InviteCreatedDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/invites/{id}/resend [post]
+func (h *inviteHandler) resendInvite(c echo.Context) error {
+	invite, err := h.findInvite(c)
+	if invite == nil {
+		return err
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	created, err := h.srv.Invite.Resend(invite, currentUser.ID)
+	if err != nil {
+		return h.buildInviteErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.ResendInvite, fmt.Sprintf("%s resent invite %d to %s.", currentUser.Username, invite.ID, invite.Email))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusAccepted, "Successfully", created)
+}
+
+// @Summary Revoke an invite
+// @Description Revoke a pending invite, its link stops working and the username and email are released
+// @Tags Invites
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Invite ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/invites/{id}/revoke [post]
+func (h *inviteHandler) revokeInvite(c echo.Context) error {
+	invite, err := h.findInvite(c)
+	if invite == nil {
+		return err
+	}
+
+	if err := h.srv.Invite.Revoke(invite); err != nil {
+		return h.buildInviteErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.RevokeInvite, fmt.Sprintf("%s revoked invite %d of %s.", currentUser.Username, invite.ID, invite.Email))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary      Accept an invite
+// @Description  Create the invited user with the password chosen by the invitee, the token comes from the invite email
+// @Tags         Invites
+// @Accept       json
+// @Produce      json
+// @Param        acceptInviteDTO  body      dto.AcceptInviteDTO  true  "Accept Invite DTO"
+// @Success      201                    "Invite accepted successfully"
+// @Failure      400                    "Bad Request"
+// @Failure      409                    "Username or email was just taken"
+// @Failure      500                    "Internal Server Error"
+// @Router       /api/invites/accept [post]
+func (h *inviteHandler) acceptInvite(c echo.Context) error {
+	var req dto.AcceptInviteDTO
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	if req.NewPassword != req.ConfirmPassword {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("passwords do not match"), nil)
+	}
+
+	user, err := h.srv.Invite.Accept(req.Token, req.NewPassword)
+	if err != nil {
+		return h.buildInviteErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(user.ID, model.AcceptInvite, fmt.Sprintf("%s accepted invite.", user.Username))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusCreated, "Invite accepted successfully", nil)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type jobHandler struct {
+	Handler
+	r   *echo.Group
+	srv *service.Service
+}
+
+func newJobHandler(r *echo.Group, srv *service.Service) *jobHandler {
+	job := &jobHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+	}
+
+	job.register()
+
+	return job
+}
+
+func (h *jobHandler) register() {
+	group := h.r.Group("api/jobs")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getJobs)
+	group.GET("/:id", h.getJob)
+	group.POST("/:id/cancel", h.cancelJob)
+}
+
+// @Summary Get background jobs
+// @Description Get background jobs with their status and progress, newest first
+// @Tags Jobs
+// @Accept  json
+// @Produce  json
+// @Param request query dto.JobQuery true "Job Query"
+// @Success 200 {object} utils.PaginationModel[dto.JobDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/jobs [get]
+func (h *jobHandler) getJobs(c echo.Context) error {
+	var req dto.JobQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Job.GetJobs(&req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get a background job
+// @Description Get a background job by ID
+// @Tags Jobs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Job ID"
+// @Success 200 {object} dto.JobDTO
+// @Failure 400 "Invalid ID parameter"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/jobs/{id} [get]
+func (h *jobHandler) getJob(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	data, err := h.srv.Job.GetJob(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Cancel a background job
+// @Description Queued jobs are canceled right away, running jobs stop within the lock timeout of their worker
+// @Tags Jobs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Job ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Job already finished"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/jobs/{id}/cancel [post]
+func (h *jobHandler) cancelJob(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	if err := h.srv.Job.Cancel(uint(id)); err != nil {
+		if errors.Is(err, service.ErrJobNotCancelable) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.CancelJob, fmt.Sprintf("%s canceled job %d.", currentUser.Username, id))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
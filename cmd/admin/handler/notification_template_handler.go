@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type notificationTemplateHandler struct {
+	Handler
+	r   *echo.Group
+	srv *service.Service
+}
+
+func newNotificationTemplateHandler(r *echo.Group, srv *service.Service) *notificationTemplateHandler {
+	notificationTemplate := &notificationTemplateHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+	}
+
+	notificationTemplate.register()
+
+	return notificationTemplate
+}
+
+func (h *notificationTemplateHandler) register() {
+	group := h.r.Group("api/notification-templates")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getTemplates)
+	group.GET("/variables", h.getVariables)
+	group.POST("/preview", h.preview)
+	group.GET("/:type/:locale/versions", h.getVersions)
+	group.PUT("/:type/:locale", h.save)
+	group.POST("/:type/:locale/versions/:version/restore", h.restore)
+	group.DELETE("/:type/:locale", h.delete)
+}
+
+func (h *notificationTemplateHandler) buildTemplateErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrNotificationTemplateType) || errors.Is(err, service.ErrNotificationTemplateLocale) ||
+		errors.Is(err, service.ErrNotificationTemplateInvalid) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if errors.Is(err, service.ErrNotificationTemplateNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, err, nil)
+	}
+	if errors.Is(err, service.ErrNotificationTemplateConflict) {
+		return utils.BuildErrorResponse(c, http.StatusConflict, err, nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// @Summary Get notification templates
+// @Description Get the latest version of every notification template, newest versions are used to render notifications
+// @Tags Notification Templates
+// @Accept  json
+// @Produce  json
+// @Param request query dto.NotificationTemplateQuery true "Notification Template Query"
+// @Success 200 {object} utils.PaginationModel[dto.NotificationTemplateDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/notification-templates [get]
+func (h *notificationTemplateHandler) getTemplates(c echo.Context) error {
+	var req dto.NotificationTemplateQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.NotificationTemplate.GetTemplates(&req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get notification template variables
+// @Description Get the variables templates of each notification type can use with their sample data, and the built in templates used when no locale has one
+// @Tags Notification Templates
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} dto.NotificationTemplateVariablesDTO
+// @Security Bearer
+// @Router /api/notification-templates/variables [get]
+func (h *notificationTemplateHandler) getVariables(c echo.Context) error {
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, h.srv.NotificationTemplate.GetVariables())
+}
+
+// @Summary Preview a notification template
+// @Description Render a title and body against sample data, or the template a locale falls back to when body is empty. Nothing is saved.
+// @Tags Notification Templates
+// @Accept  json
+// @Produce  json
+// @Param request body dto.NotificationTemplatePreviewRequest true "Notification Template Preview Request"
+// @Success 200 {object} dto.RenderedNotificationDTO
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/notification-templates/preview [post]
+func (h *notificationTemplateHandler) preview(c echo.Context) error {
+	var req dto.NotificationTemplatePreviewRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.NotificationTemplate.Preview(&req)
+	if err != nil {
+		return h.buildTemplateErrorResponse(c, err)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get versions of a notification template
+// @Description Get every version of the template of a type and locale, newest first
+// @Tags Notification Templates
+// @Accept  json
+// @Produce  json
+// @Param type path string true "Notification type"
+// @Param locale path string true "Locale, like en or pt-BR"
+// @Success 200 {array} dto.NotificationTemplateDTO
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/notification-templates/{type}/{locale}/versions [get]
+func (h *notificationTemplateHandler) getVersions(c echo.Context) error {
+	notificationType, locale, err := h.srv.NotificationTemplate.ParseKey(c.Param("type"), c.Param("locale"))
+	if err != nil {
+		return h.buildTemplateErrorResponse(c, err)
+	}
+
+	data, err := h.srv.NotificationTemplate.GetVersions(notificationType, locale)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Save a notification template
+// @Description Save the template of a type and locale as a new version, it must render with the sample data of the type
+// @Tags Notification Templates
+// @Accept  json
+// @Produce  json
+// @Param type path string true "Notification type"
+// @Param locale path string true "Locale, like en or pt-BR"
+// @Param request body dto.NotificationTemplateRequest true "Notification Template Request"
+// @Success 200 {object} dto.NotificationTemplateDTO
+// @Failure 400 "Invalid request"
+// @Failure 409 "Saved by someone else at the same time"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/notification-templates/{type}/{locale} [put]
+func (h *notificationTemplateHandler) save(c echo.Context) error {
+	var req dto.NotificationTemplateRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	notificationType, locale, err := h.srv.NotificationTemplate.ParseKey(c.Param("type"), c.Param("locale"))
+	if err != nil {
+		return h.buildTemplateErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	data, err := h.srv.NotificationTemplate.Save(notificationType, locale, &req, currentUser.ID)
+	if err != nil {
+		return h.buildTemplateErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.SaveNotificationTemplate, fmt.Sprintf("%s saved %s notification template %s version %d.", currentUser.Username, notificationType, locale, data.Version))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Restore a notification template version
+// @Description Save a copy of an earlier version of the template as its newest version
+// @Tags Notification Templates
+// @Accept  json
+// @Produce  json
+// @Param type path string true "Notification type"
+// @Param locale path string true "Locale, like en or pt-BR"
+// @Param version path int true "Version"
+// @Success 200 {object} dto.NotificationTemplateDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 409 "Saved by someone else at the same time"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/notification-templates/{type}/{locale}/versions/{version}/restore [post]
+func (h *notificationTemplateHandler) restore(c echo.Context) error {
+	notificationType, locale, err := h.srv.NotificationTemplate.ParseKey(c.Param("type"), c.Param("locale"))
+	if err != nil {
+		return h.buildTemplateErrorResponse(c, err)
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid version parameter"), nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	data, err := h.srv.NotificationTemplate.Restore(notificationType, locale, uint(version), currentUser.ID)
+	if err != nil {
+		return h.buildTemplateErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.RestoreNotificationTemplate, fmt.Sprintf("%s restored %s notification template %s version %d as version %d.", currentUser.Username, notificationType, locale, version, data.Version))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Delete a notification template
+// @Description Delete every version of the template of a type and locale, the locale falls back to its language and the default one
+// @Tags Notification Templates
+// @Accept  json
+// @Produce  json
+// @Param type path string true "Notification type"
+// @Param locale path string true "Locale, like en or pt-BR"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/notification-templates/{type}/{locale} [delete]
+func (h *notificationTemplateHandler) delete(c echo.Context) error {
+	notificationType, locale, err := h.srv.NotificationTemplate.ParseKey(c.Param("type"), c.Param("locale"))
+	if err != nil {
+		return h.buildTemplateErrorResponse(c, err)
+	}
+
+	if err := h.srv.NotificationTemplate.Delete(notificationType, locale); err != nil {
+		return h.buildTemplateErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.DeleteNotificationTemplate, fmt.Sprintf("%s deleted %s notification template %s.", currentUser.Username, notificationType, locale))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
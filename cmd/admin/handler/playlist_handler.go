@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/conf"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type playlistHandler struct {
+	Handler
+	r                     *echo.Group
+	srv                   *service.Service
+	scheduledVideosFolder string
+	ApiURL                string
+	storageQuotas         map[model.RoleType]int64
+	scheduleLimits        service.ScheduleLimits
+	ffprobePath           string
+}
+
+func newPlaylistHandler(r *echo.Group, srv *service.Service) *playlistHandler {
+	scheduleConfig := conf.GetScheduleConfig()
+
+	playlist := &playlistHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:                     r,
+		srv:                   srv,
+		scheduledVideosFolder: conf.GetFileStorageConfig().ScheduledVideosFolder,
+		ApiURL:                conf.GetApiFileConfig().Url,
+		storageQuotas:         conf.GetStorageQuotaConfig(),
+		scheduleLimits:        newScheduleLimits(scheduleConfig),
+		ffprobePath:           scheduleConfig.FFprobePath,
+	}
+
+	playlist.register()
+
+	return playlist
+}
+
+func (h *playlistHandler) register() {
+	group := h.r.Group("api/streams/:id/playlist")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getPlaylist)
+	group.POST("/items", h.addItem)
+	group.PATCH("/items/:itemId", h.updateItem)
+	group.DELETE("/items/:itemId", h.deleteItem)
+}
+
+func (h *playlistHandler) buildPlaylistErrorResponse(c echo.Context, err error) error {
+	var conflictErr *service.ScheduleConflictError
+	if errors.As(err, &conflictErr) {
+		return utils.BuildErrorResponse(c, http.StatusConflict, err, conflictErr.Conflicts)
+	}
+	if errors.Is(err, service.ErrPlaylistNotEditable) || errors.Is(err, service.ErrInvalidPlaylistItem) || errors.Is(err, service.ErrStorageQuotaExceeded) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if errors.Is(err, service.ErrPlaylistItemNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, err, nil)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("scheduled stream not found"), nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// @Summary Get playlist of a scheduled stream
+// @Description Get items pushed in order for a scheduled stream, empty when only its video is pushed
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Success 200 {object} dto.PlaylistRespDTO
+// @Failure 400 "Invalid ID parameter"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/playlist [get]
+func (h *playlistHandler) getPlaylist(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	data, err := h.srv.Playlist.GetPlaylist(uint(id), h.ApiURL)
+	if err != nil {
+		return h.buildPlaylistErrorResponse(c, err)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Add a playlist item
+// @Description Upload a video or a slate image to the playlist of an upcoming scheduled stream. The video of the stream becomes the first item of a new playlist.
+// @Tags Streams
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Param type formData string true "video or slate"
+// @Param position formData int false "Position, appended when omitted"
+// @Param in_point formData int false "Start of a video in milliseconds"
+// @Param out_point formData int false "End of a video in milliseconds, 0 plays until the end"
+// @Param duration formData int false "Shown time of a slate in milliseconds"
+// @Param file formData file true "Video or image file"
+// @Success 201 {object} dto.PlaylistRespDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 409 "Schedule conflicts with other streams"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/playlist/items [post]
+func (h *playlistHandler) addItem(c echo.Context) error {
+	var req dto.PlaylistItemRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	stream, err := h.srv.Stream.GetStreamByID(uint(id))
+	if err != nil {
+		return h.buildPlaylistErrorResponse(c, err)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, fmt.Sprintf("file field is required: %s", err.Error()))
+	}
+	if req.Type == model.PlaylistItemTypeSlate {
+		isImage, err := utils.IsImage(file)
+		if err != nil {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		if !isImage {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("file is not an image"), nil)
+		}
+		if file.Size > utils.MAX_IMAGE_SIZE {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, nil, "Image size exceeds the 1MB limit")
+		}
+	} else {
+		if file.Size > utils.MAX_VIDEO_SIZE {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, nil, "Video size exceeds the 2GB limit")
+		}
+		isVideo, err := utils.IsVideoFile(file)
+		if err != nil {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		if !isVideo {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("file is not a supported video format"), nil)
+		}
+	}
+
+	if err := h.srv.Storage.CheckQuota(stream.UserID, file.Size, h.storageQuotas); err != nil {
+		return h.buildPlaylistErrorResponse(c, err)
+	}
+
+	req.FileName = fmt.Sprintf("%d_%s%s", stream.UserID, utils.MakeUniqueIDWithTime(), utils.GetFileExtension(file))
+	filePath := fmt.Sprintf("%s%s", h.scheduledVideosFolder, req.FileName)
+	if err := utils.SaveUploadedFile(file, filePath); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if req.Type == model.PlaylistItemTypeVideo {
+		req.FileDuration = uint(probeDuration(c.Request().Context(), h.ffprobePath, filePath) / time.Millisecond)
+	}
+
+	item, err := h.srv.Playlist.AddItem(uint(id), &req, h.scheduleLimits)
+	if err != nil {
+		go utils.RemoveFiles([]string{filePath})
+		return h.buildPlaylistErrorResponse(c, err)
+	}
+	h.srv.Storage.TrackFile(stream.UserID, model.StorageFileTypeScheduledVideo, filePath)
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.AddPlaylistItem, fmt.Sprintf("%s added %s item %d at position %d to playlist of stream %d.", currentUser.Username, item.Type, item.ID, item.Position, id))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	data, err := h.srv.Playlist.GetPlaylist(uint(id), h.ApiURL)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusCreated, data)
+}
+
+// @Summary Update a playlist item
+// @Description Move or trim a video, or change the shown time of a slate
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Param itemId path int true "Playlist item ID"
+// @Param request body dto.UpdatePlaylistItemRequest true "Update Playlist Item Request"
+// @Success 200 {object} dto.PlaylistRespDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 409 "Schedule conflicts with other streams"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/playlist/items/{itemId} [patch]
+func (h *playlistHandler) updateItem(c echo.Context) error {
+	var req dto.UpdatePlaylistItemRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+	itemID, err := strconv.Atoi(c.Param("itemId"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid itemId parameter"), nil)
+	}
+
+	item, err := h.srv.Playlist.UpdateItem(uint(id), uint(itemID), &req, h.scheduleLimits)
+	if err != nil {
+		return h.buildPlaylistErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.UpdatePlaylistItem, fmt.Sprintf("%s updated item %d at position %d of playlist of stream %d.", currentUser.Username, item.ID, item.Position, id))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	data, err := h.srv.Playlist.GetPlaylist(uint(id), h.ApiURL)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Delete a playlist item
+// @Description Remove an item from the playlist of an upcoming scheduled stream, the last item can't be removed
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Param itemId path int true "Playlist item ID"
+// @Success 200 {object} dto.PlaylistRespDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/playlist/items/{itemId} [delete]
+func (h *playlistHandler) deleteItem(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+	itemID, err := strconv.Atoi(c.Param("itemId"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid itemId parameter"), nil)
+	}
+
+	stream, err := h.srv.Stream.GetStreamByID(uint(id))
+	if err != nil {
+		return h.buildPlaylistErrorResponse(c, err)
+	}
+
+	item, err := h.srv.Playlist.DeleteItem(uint(id), uint(itemID))
+	if err != nil {
+		return h.buildPlaylistErrorResponse(c, err)
+	}
+
+	// the video of the stream and files of a recurring schedule are kept
+	isStreamVideo, err := h.srv.Playlist.IsStreamVideo(uint(id), item.FileName)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if !isStreamVideo && !h.srv.Recurrence.IsFileShared(item.FileName, stream.ID) {
+		filePath := fmt.Sprintf("%s%s", h.scheduledVideosFolder, item.FileName)
+		h.srv.Storage.UntrackFile(stream.UserID, model.StorageFileTypeScheduledVideo, filePath)
+		go utils.RemoveFilesWithNoErrReturn([]string{filePath})
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.DeletePlaylistItem, fmt.Sprintf("%s deleted %s item %d from playlist of stream %d.", currentUser.Username, item.Type, item.ID, id))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	data, err := h.srv.Playlist.GetPlaylist(uint(id), h.ApiURL)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
@@ -0,0 +1,359 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/conf"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type recurrenceHandler struct {
+	Handler
+	r                     *echo.Group
+	srv                   *service.Service
+	thumbnailFolder       string
+	scheduledVideosFolder string
+	ApiURL                string
+	storageQuotas         map[model.RoleType]int64
+	scheduleWindow        time.Duration
+	horizon               time.Duration
+	scheduleLimits        service.ScheduleLimits
+	ffprobePath           string
+}
+
+func newRecurrenceHandler(r *echo.Group, srv *service.Service) *recurrenceHandler {
+	fileStorageConfig := conf.GetFileStorageConfig()
+	scheduleConfig := conf.GetScheduleConfig()
+
+	// main refuses to start when the horizon doesn't cover the window
+	horizon, _ := service.RecurrenceHorizon(time.Duration(scheduleConfig.RecurrenceHorizon)*time.Second, time.Duration(scheduleConfig.Window)*time.Second)
+
+	recurrence := &recurrenceHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:                     r,
+		srv:                   srv,
+		thumbnailFolder:       fileStorageConfig.ThumbnailFolder,
+		scheduledVideosFolder: fileStorageConfig.ScheduledVideosFolder,
+		ApiURL:                conf.GetApiFileConfig().Url,
+		storageQuotas:         conf.GetStorageQuotaConfig(),
+		scheduleWindow:        time.Duration(scheduleConfig.Window) * time.Second,
+		horizon:               horizon,
+		scheduleLimits:        newScheduleLimits(scheduleConfig),
+		ffprobePath:           scheduleConfig.FFprobePath,
+	}
+
+	recurrence.register()
+
+	return recurrence
+}
+
+func (h *recurrenceHandler) register() {
+	group := h.r.Group("api/streams/recurrences")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getAll)
+	group.GET("/:id", h.getByID)
+	group.POST("", h.create)
+	group.PUT("/:id", h.update)
+	group.DELETE("/:id", h.delete)
+}
+
+func (h *recurrenceHandler) buildRecurrenceErrorResponse(c echo.Context, err error) error {
+	var conflictErr *service.ScheduleConflictError
+	if errors.As(err, &conflictErr) {
+		return utils.BuildErrorResponse(c, http.StatusConflict, err, conflictErr.Conflicts)
+	}
+	if errors.Is(err, service.ErrInvalidRecurrence) || errors.Is(err, service.ErrStorageQuotaExceeded) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("stream not found"), nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// @Summary Get recurring schedules
+// @Description Get recurring schedules of pre-recorded streams with their next occurrences
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param request query dto.StreamRecurrenceQuery true "Stream Recurrence Query"
+// @Success 200 {object} utils.PaginationModel[dto.StreamRecurrenceRespDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/recurrences [get]
+func (h *recurrenceHandler) getAll(c echo.Context) error {
+	var req dto.StreamRecurrenceQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Recurrence.GetAll(&req, h.ApiURL)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get a recurring schedule
+// @Description Get a recurring schedule by ID
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Recurrence ID"
+// @Success 200 {object} dto.StreamRecurrenceRespDTO
+// @Failure 400 "Invalid ID parameter"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/recurrences/{id} [get]
+func (h *recurrenceHandler) getByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	data, err := h.srv.Recurrence.GetDtoByID(uint(id), h.ApiURL)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if data == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Create a recurring schedule
+// @Description Schedule a pre-recorded stream repeating by RFC 5545 RRULE (FREQ DAILY, WEEKLY or MONTHLY with INTERVAL, COUNT, UNTIL, BYDAY and BYMONTHDAY). Occurrences are created as upcoming streams ahead of time.
+// @Tags Streams
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param user_id formData int true "User ID"
+// @Param title formData string true "Stream Title"
+// @Param description formData string true "Stream Description"
+// @Param start_at formData string true "First occurrence"
+// @Param rrule formData string true "RRULE, e.g. FREQ=WEEKLY;BYDAY=MO,WE"
+// @Param timezone formData string true "IANA timezone, e.g. Asia/Seoul"
+// @Param exdates formData []string false "Skipped occurrences"
+// @Param category_ids formData []int true "Category IDs"
+// @Param thumbnail formData file true "Thumbnail image file"
+// @Param video formData file true "Video file"
+// @Success 201 {object} dto.StreamRecurrenceRespDTO
+// @Failure 400 "Invalid request"
+// @Failure 409 "Schedule conflicts with other streams"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/recurrences [post]
+func (h *recurrenceHandler) create(c echo.Context) error {
+	var req dto.StreamRecurrenceRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	streamer, err := h.srv.User.CheckUserTypeByID(int(req.UserID))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if streamer == nil || streamer.Role.Type != model.STREAMER {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("user is not a streamer"), nil)
+	}
+
+	if !utils.IsValidSchedule(req.StartAt, h.scheduleWindow) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid schedule"), nil)
+	}
+
+	thumbnail, err := c.FormFile("thumbnail")
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, fmt.Sprintf("thumbnail field is required: %s", err.Error()))
+	}
+	isImage, err := utils.IsImage(thumbnail)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if !isImage {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("file is not an image"), nil)
+	}
+	if thumbnail.Size > utils.MAX_IMAGE_SIZE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, nil, "Image size exceeds the 1MB limit")
+	}
+
+	video, err := c.FormFile("video")
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, fmt.Sprintf("video field is required: %s", err.Error()))
+	}
+	if video.Size > utils.MAX_VIDEO_SIZE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, nil, "Video size exceeds the 2GB limit")
+	}
+	isVideo, err := utils.IsVideoFile(video)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if !isVideo {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("file is not a supported video format"), nil)
+	}
+
+	if err := h.srv.Storage.CheckQuota(req.UserID, thumbnail.Size+video.Size, h.storageQuotas); err != nil {
+		return h.buildRecurrenceErrorResponse(c, err)
+	}
+
+	req.ThumbnailFileName = fmt.Sprintf("%d_%s%s", req.UserID, utils.MakeUniqueIDWithTime(), utils.GetFileExtension(thumbnail))
+	thumbnailPath := fmt.Sprintf("%s%s", h.thumbnailFolder, req.ThumbnailFileName)
+	if err := utils.SaveUploadedFile(thumbnail, thumbnailPath); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	req.VideoFileName = fmt.Sprintf("%d_%s%s", req.UserID, utils.MakeUniqueIDWithTime(), utils.GetFileExtension(video))
+	videoPath := fmt.Sprintf("%s%s", h.scheduledVideosFolder, req.VideoFileName)
+	if err := utils.SaveUploadedFile(video, videoPath); err != nil {
+		go utils.RemoveFiles([]string{thumbnailPath})
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	req.VideoDuration = probeVideoDuration(c.Request().Context(), h.ffprobePath, videoPath)
+
+	currentUser := c.Get("user").(*utils.Claims)
+	req.CreatedByID = currentUser.ID
+
+	recurrence, err := h.srv.Recurrence.Create(&req, h.horizon, h.scheduleLimits)
+	if err != nil {
+		go utils.RemoveFiles([]string{thumbnailPath, videoPath})
+		return h.buildRecurrenceErrorResponse(c, err)
+	}
+	h.srv.Storage.TrackFile(req.UserID, model.StorageFileTypeThumbnail, thumbnailPath)
+	h.srv.Storage.TrackFile(req.UserID, model.StorageFileTypeScheduledVideo, videoPath)
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.CreateStreamRecurrence, fmt.Sprintf("%s scheduled recurring stream %d with rule %s.", currentUser.Username, recurrence.ID, recurrence.RRule))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	data, err := h.srv.Recurrence.GetDtoByID(recurrence.ID, h.ApiURL)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusCreated, data)
+}
+
+// @Summary Update a recurring schedule
+// @Description Edit the occurrence of from_stream_id and all following ones, or every upcoming occurrence when it's omitted. Use stream endpoints to edit a single occurrence.
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Recurrence ID"
+// @Param request body dto.UpdateStreamRecurrenceRequest true "Update Stream Recurrence Request"
+// @Success 200 {object} dto.StreamRecurrenceRespDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 409 "Schedule conflicts with other streams"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/recurrences/{id} [put]
+func (h *recurrenceHandler) update(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	var req dto.UpdateStreamRecurrenceRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if req.StartAt != "" && !utils.IsValidSchedule(req.StartAt, h.scheduleWindow) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid schedule"), nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	req.UpdatedByID = currentUser.ID
+
+	recurrence, err := h.srv.Recurrence.Update(uint(id), &req, h.horizon, h.scheduleLimits)
+	if err != nil {
+		return h.buildRecurrenceErrorResponse(c, err)
+	}
+	if recurrence == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	scope := "all upcoming occurrences"
+	if req.FromStreamID != 0 {
+		scope = fmt.Sprintf("occurrences from stream %d", req.FromStreamID)
+	}
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.UpdateStreamRecurrence, fmt.Sprintf("%s updated %s of recurring stream %d with rule %s.", currentUser.Username, scope, recurrence.ID, recurrence.RRule))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	data, err := h.srv.Recurrence.GetDtoByID(recurrence.ID, h.ApiURL)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Delete a recurring schedule
+// @Description Delete a recurring schedule with its upcoming occurrences, started and ended ones are kept
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Recurrence ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid ID parameter"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/recurrences/{id} [delete]
+func (h *recurrenceHandler) delete(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	recurrence, err := h.srv.Recurrence.GetByID(uint(id))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if recurrence == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	if err := h.srv.Recurrence.Delete(recurrence.ID); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	// past occurrences may still use the files
+	thumbnailInUse, videoInUse, err := h.srv.Recurrence.IsFileInUse(recurrence)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	var filesToRemove []string
+	if !thumbnailInUse {
+		thumbnailPath := fmt.Sprintf("%s%s", h.thumbnailFolder, recurrence.ThumbnailFileName)
+		h.srv.Storage.UntrackFile(recurrence.UserID, model.StorageFileTypeThumbnail, thumbnailPath)
+		filesToRemove = append(filesToRemove, thumbnailPath)
+	}
+	if !videoInUse {
+		videoPath := utils.MakeVideoPath(h.scheduledVideosFolder, recurrence.VideoName)
+		h.srv.Storage.UntrackFile(recurrence.UserID, model.StorageFileTypeScheduledVideo, videoPath)
+		filesToRemove = append(filesToRemove, videoPath)
+	}
+	go utils.RemoveFilesWithNoErrReturn(filesToRemove)
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.DeleteStreamRecurrence, fmt.Sprintf("%s deleted recurring stream %d.", currentUser.Username, recurrence.ID))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
@@ -0,0 +1,387 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/conf"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type reportHandler struct {
+	Handler
+	r          *echo.Group
+	srv        *service.Service
+	sla        service.ReportSLA
+	clientHost string
+	purgeDelay time.Duration
+}
+
+func newReportHandler(r *echo.Group, srv *service.Service) *reportHandler {
+	reportConfig := conf.GetReportConfig()
+
+	report := &reportHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+		sla: service.ReportSLA{
+			High:   time.Duration(reportConfig.HighSLA) * time.Second,
+			Medium: time.Duration(reportConfig.MediumSLA) * time.Second,
+			Low:    time.Duration(reportConfig.LowSLA) * time.Second,
+		},
+		clientHost: conf.GetClientConfig().Host,
+		purgeDelay: time.Duration(conf.GetTrashConfig().PurgeDelay) * time.Second,
+	}
+
+	report.register()
+
+	return report
+}
+
+func (h *reportHandler) register() {
+	group := h.r.Group("api/reports")
+
+	group.POST("/intake", h.createReport, h.UserJWTMiddleware())
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getReports)
+	group.GET("/stats", h.getStats)
+	group.GET("/:id", h.getReport)
+	group.POST("/:id/claim", h.claimReport)
+	group.POST("/:id/release", h.releaseReport)
+	group.PATCH("/:id/assign", h.assignReport)
+	group.POST("/:id/resolve", h.resolveReport)
+	group.POST("/:id/dismiss", h.dismissReport)
+}
+
+func (h *reportHandler) buildReportErrorResponse(c echo.Context, err error) error {
+	for _, target := range []error{
+		service.ErrReportTargetNotFound, service.ErrReportSelf, service.ErrReportDuplicate, service.ErrReportNotOpen,
+		service.ErrReportNotClaimed, service.ErrReportClosed, service.ErrReportClaimedByOther, service.ErrReportAssignee,
+		service.ErrReportAction, service.ErrReportBlockForbidden, service.ErrStreamNotLive, service.ErrStreamIsLive,
+		service.ErrStreamLegalHold, service.ErrStreamEncoding,
+	} {
+		if errors.Is(err, target) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+	}
+	if errors.Is(err, service.ErrReportBlocked) {
+		return utils.BuildErrorResponse(c, http.StatusForbidden, err, nil)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, service.ErrReportTargetNotFound, nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// findReport returns the report of the request, or writes the error response and returns nil
+func (h *reportHandler) findReport(c echo.Context) (*model.Report, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	report, err := h.srv.Report.FindByID(uint(id))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if report == nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return report, nil
+}
+
+func describeReportTarget(report *model.Report) string {
+	return fmt.Sprintf("%s %d", report.TargetType, report.TargetID)
+}
+
+// @Summary File a report
+// @Description Called by be-api with the token of the reporting user, the report is queued by severity of its reason
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param request body dto.CreateReportRequest true "Create Report Request"
+// @Success 201 {object} dto.ReportCreatedDTO
+// @Failure 400 "Invalid request"
+// @Failure 403 "Reporter is blocked"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/reports/intake [post]
+func (h *reportHandler) createReport(c echo.Context) error {
+	var req dto.CreateReportRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	report, err := h.srv.Report.Create(&req, currentUser.ID, h.sla)
+	if err != nil {
+		return h.buildReportErrorResponse(c, err)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusCreated, dto.ReportCreatedDTO{ReportID: report.ID, Severity: report.Severity, DueAt: report.DueAt})
+}
+
+// @Summary Get reports
+// @Description Get the moderation queue, highest priority first, then the earliest due
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param request query dto.ReportQuery true "Report Query"
+// @Success 200 {object} utils.PaginationModel[dto.ReportDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/reports [get]
+func (h *reportHandler) getReports(c echo.Context) error {
+	var req dto.ReportQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Report.GetReports(&req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get a report
+// @Description Get a report with its SLA timer
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Report ID"
+// @Success 200 {object} dto.ReportDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/reports/{id} [get]
+func (h *reportHandler) getReport(c echo.Context) error {
+	report, err := h.findReport(c)
+	if report == nil {
+		return err
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, h.srv.Report.ToDto(report))
+}
+
+// @Summary Claim a report
+// @Description Claim an open report for the current admin
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Report ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/reports/{id}/claim [post]
+func (h *reportHandler) claimReport(c echo.Context) error {
+	report, err := h.findReport(c)
+	if report == nil {
+		return err
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	if err := h.srv.Report.Claim(report, currentUser.ID); err != nil {
+		return h.buildReportErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.ClaimReport, fmt.Sprintf("%s claimed report %d of %s.", currentUser.Username, report.ID, describeReportTarget(report)))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Release a report
+// @Description Put a claimed report back in the queue
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Report ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/reports/{id}/release [post]
+func (h *reportHandler) releaseReport(c echo.Context) error {
+	report, err := h.findReport(c)
+	if report == nil {
+		return err
+	}
+
+	if err := h.srv.Report.Release(report); err != nil {
+		return h.buildReportErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.ReleaseReport, fmt.Sprintf("%s released report %d of %s.", currentUser.Username, report.ID, describeReportTarget(report)))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Assign a report
+// @Description Claim a report for an admin, a claimed report is reassigned
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Report ID"
+// @Param request body dto.AssignReportRequest true "Assign Report Request"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/reports/{id}/assign [patch]
+func (h *reportHandler) assignReport(c echo.Context) error {
+	var req dto.AssignReportRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	report, err := h.findReport(c)
+	if report == nil {
+		return err
+	}
+
+	assignee, err := h.srv.Report.Assign(report, req.AssigneeID)
+	if err != nil {
+		return h.buildReportErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.AssignReport, fmt.Sprintf("%s assigned report %d of %s to %s.", currentUser.Username, report.ID, describeReportTarget(report), assignee.Username))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Resolve a report
+// @Description Run the action on the reported target and resolve every unresolved report of it
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Report ID"
+// @Param request body dto.ResolveReportRequest true "Resolve Report Request"
+// @Success 200 {object} dto.ReportResolvedDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/reports/{id}/resolve [post]
+func (h *reportHandler) resolveReport(c echo.Context) error {
+	var req dto.ResolveReportRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	report, err := h.findReport(c)
+	if report == nil {
+		return err
+	}
+
+	currentToken, err := utils.GetTokenFromHeader(c)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	currentUser := c.Get("user").(*utils.Claims)
+	data, err := h.srv.Report.Resolve(c.Request().Context(), report, &req, currentUser.ID, currentUser.RoleType, service.ReportActionOptions{
+		ClientHost: h.clientHost,
+		Token:      currentToken,
+		PurgeDelay: h.purgeDelay,
+	})
+	if err != nil {
+		return h.buildReportErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.ResolveReport, fmt.Sprintf("%s resolved %d reports of %s with action %s: %s", currentUser.Username, data.Resolved, describeReportTarget(report), req.Action, req.Note))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", data)
+}
+
+// @Summary Dismiss a report
+// @Description Close a report without action, other reports of the target stay in the queue
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Report ID"
+// @Param request body dto.DismissReportRequest true "Dismiss Report Request"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/reports/{id}/dismiss [post]
+func (h *reportHandler) dismissReport(c echo.Context) error {
+	var req dto.DismissReportRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	report, err := h.findReport(c)
+	if report == nil {
+		return err
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	if err := h.srv.Report.Dismiss(report, currentUser.ID, req.Note); err != nil {
+		return h.buildReportErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.DismissReport, fmt.Sprintf("%s dismissed report %d of %s: %s", currentUser.Username, report.ID, describeReportTarget(report), req.Note))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Get report stats
+// @Description Count the queue now and reports closed per moderator in the range, with SLA breaches and average resolution time
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param request query dto.ReportStatsQuery true "Report Stats Query"
+// @Success 200 {object} dto.ReportStatsDTO
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/reports/stats [get]
+func (h *reportHandler) getStats(c echo.Context) error {
+	var req dto.ReportStatsQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if req.From > 0 && req.To > 0 && req.From > req.To {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("from must be before to"), nil)
+	}
+
+	data, err := h.srv.Report.GetStats(&req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/conf"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type retentionHandler struct {
+	Handler
+	r       *echo.Group
+	srv     *service.Service
+	folders service.StorageFolders
+}
+
+func newRetentionHandler(r *echo.Group, srv *service.Service) *retentionHandler {
+	fileStorageConfig := conf.GetFileStorageConfig()
+
+	retention := &retentionHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+		folders: service.StorageFolders{
+			Live:  fileStorageConfig.LiveFolder,
+			Video: fileStorageConfig.VideoFolder,
+		},
+	}
+
+	retention.register()
+
+	return retention
+}
+
+func (h *retentionHandler) register() {
+	group := h.r.Group("api/retention-policies")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getAll)
+	group.POST("", h.create)
+	group.PUT("/:id", h.update)
+	group.DELETE("/:id", h.delete)
+	group.POST("/apply", h.apply)
+}
+
+func (h *retentionHandler) buildPolicyErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrRetentionPolicyDuplicate) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("category not found"), nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+func describePolicyScope(categoryID *uint) string {
+	if categoryID == nil {
+		return "global"
+	}
+	return fmt.Sprintf("category %d", *categoryID)
+}
+
+// @Summary Get all retention policies
+// @Description Get global and per-category retention policies of ended stream recordings
+// @Tags Retention
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} dto.RetentionPolicyRespDTO
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/retention-policies [get]
+func (h *retentionHandler) getAll(c echo.Context) error {
+	data, err := h.srv.Retention.GetAll()
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Create a retention policy
+// @Description Create the global policy (no category_id) or a policy for a category
+// @Tags Retention
+// @Accept  json
+// @Produce  json
+// @Param request body dto.RetentionPolicyRequestDTO true "Retention Policy Request"
+// @Success 201 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/retention-policies [post]
+func (h *retentionHandler) create(c echo.Context) error {
+	var req dto.RetentionPolicyRequestDTO
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	currentUser := c.Get("user").(*utils.Claims)
+	req.CreatedByID = currentUser.ID
+
+	policy, err := h.srv.Retention.Create(&req)
+	if err != nil {
+		return h.buildPolicyErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.CreateRetentionPolicy, fmt.Sprintf("%s created %s retention policy %d.", currentUser.Username, describePolicyScope(policy.CategoryID), policy.ID))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponseWithData(c, http.StatusCreated, nil)
+}
+
+// @Summary Update a retention policy
+// @Description Update a retention policy by ID
+// @Tags Retention
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Retention Policy ID"
+// @Param request body dto.RetentionPolicyRequestDTO true "Retention Policy Request"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/retention-policies/{id} [put]
+func (h *retentionHandler) update(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	var req dto.RetentionPolicyRequestDTO
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	currentUser := c.Get("user").(*utils.Claims)
+	req.CreatedByID = currentUser.ID
+
+	policy, err := h.srv.Retention.Update(uint(id), &req)
+	if err != nil {
+		return h.buildPolicyErrorResponse(c, err)
+	}
+	if policy == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.UpdateRetentionPolicy, fmt.Sprintf("%s updated retention policy %d: %s, delete raw after encoding %t, delete video after %d days.", currentUser.Username, policy.ID, describePolicyScope(policy.CategoryID), policy.DeleteRawAfterEncoding, policy.DeleteVideoAfterDays))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Delete a retention policy
+// @Description Delete a retention policy by ID
+// @Tags Retention
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Retention Policy ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid ID parameter"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/retention-policies/{id} [delete]
+func (h *retentionHandler) delete(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	policy, err := h.srv.Retention.GetByID(uint(id))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if policy == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	if err := h.srv.Retention.Delete(policy.ID); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.DeleteRetentionPolicy, fmt.Sprintf("%s deleted %s retention policy %d.", currentUser.Username, describePolicyScope(policy.CategoryID), policy.ID))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Apply retention policies
+// @Description Apply retention policies now instead of waiting for the scheduled job
+// @Tags Retention
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} int "Number of deleted recordings"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/retention-policies/apply [post]
+func (h *retentionHandler) apply(c echo.Context) error {
+	deleted, err := h.srv.Retention.Apply(c.Request().Context(), h.folders)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, deleted)
+}
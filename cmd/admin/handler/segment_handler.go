@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type segmentHandler struct {
+	Handler
+	r   *echo.Group
+	srv *service.Service
+}
+
+func newSegmentHandler(r *echo.Group, srv *service.Service) *segmentHandler {
+	segment := &segmentHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+	}
+
+	segment.register()
+
+	return segment
+}
+
+func (h *segmentHandler) register() {
+	group := h.r.Group("api/segments")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getSegments)
+	group.GET("/:id", h.getSegment)
+	group.POST("", h.create)
+	group.PUT("/:id", h.update)
+	group.DELETE("/:id", h.delete)
+}
+
+func (h *segmentHandler) buildSegmentErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrUserSegmentNameTaken) || errors.Is(err, service.ErrUserSegmentInUse) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// findSegment returns the segment of the request, or writes the error response and returns nil
+func (h *segmentHandler) findSegment(c echo.Context) (*model.UserSegment, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	segment, err := h.srv.UserSegment.FindByID(uint(id))
+	if err != nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if segment == nil {
+		return nil, utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+	return segment, nil
+}
+
+// @Summary Get user segments
+// @Description Get saved user filters which announcements can target, with how many users match them now
+// @Tags Segments
+// @Accept  json
+// @Produce  json
+// @Param request query dto.UserSegmentQuery true "User Segment Query"
+// @Success 200 {object} utils.PaginationModel[dto.UserSegmentDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/segments [get]
+func (h *segmentHandler) getSegments(c echo.Context) error {
+	var req dto.UserSegmentQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.UserSegment.GetSegments(&req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get a user segment
+// @Description Get a saved user filter with how many users match it now
+// @Tags Segments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Segment ID"
+// @Success 200 {object} dto.UserSegmentDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/segments/{id} [get]
+func (h *segmentHandler) getSegment(c echo.Context) error {
+	segment, err := h.findSegment(c)
+	if segment == nil {
+		return err
+	}
+
+	data, err := h.srv.UserSegment.GetSegment(segment)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Create a user segment
+// @Description Save a user filter under a name, page, limit and sort of the filter are ignored
+// @Tags Segments
+// @Accept  json
+// @Produce  json
+// @Param request body dto.UserSegmentRequest true "User Segment Request"
+// @Success 201 {object} dto.UserSegmentDTO
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/segments [post]
+func (h *segmentHandler) create(c echo.Context) error {
+	var req dto.UserSegmentRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	data, err := h.srv.UserSegment.Create(&req, currentUser.ID)
+	if err != nil {
+		return h.buildSegmentErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.CreateUserSegment, fmt.Sprintf("%s created user segment %d %q.", currentUser.Username, data.ID, data.Name))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusCreated, "Successfully", data)
+}
+
+// @Summary Update a user segment
+// @Description Change the name or filter of a segment, announcements to it which are not sent yet use the new filter
+// @Tags Segments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Segment ID"
+// @Param request body dto.UserSegmentRequest true "User Segment Request"
+// @Success 200 {object} dto.UserSegmentDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/segments/{id} [put]
+func (h *segmentHandler) update(c echo.Context) error {
+	var req dto.UserSegmentRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	segment, err := h.findSegment(c)
+	if segment == nil {
+		return err
+	}
+
+	data, err := h.srv.UserSegment.Update(segment, &req)
+	if err != nil {
+		return h.buildSegmentErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.UpdateUserSegment, fmt.Sprintf("%s updated user segment %d %q.", currentUser.Username, data.ID, data.Name))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Delete a user segment
+// @Description Delete a segment which no announcement waiting to be sent targets
+// @Tags Segments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Segment ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/segments/{id} [delete]
+func (h *segmentHandler) delete(c echo.Context) error {
+	segment, err := h.findSegment(c)
+	if segment == nil {
+		return err
+	}
+
+	if err := h.srv.UserSegment.Delete(segment); err != nil {
+		return h.buildSegmentErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.DeleteUserSegment, fmt.Sprintf("%s deleted user segment %d %q.", currentUser.Username, segment.ID, segment.Name))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
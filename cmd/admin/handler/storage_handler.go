@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/conf"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type storageHandler struct {
+	Handler
+	r             *echo.Group
+	srv           *service.Service
+	storageQuotas map[model.RoleType]int64
+	folders       service.StorageFolders
+}
+
+func newStorageHandler(r *echo.Group, srv *service.Service) *storageHandler {
+	fileStorageConfig := conf.GetFileStorageConfig()
+
+	storage := &storageHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:             r,
+		srv:           srv,
+		storageQuotas: conf.GetStorageQuotaConfig(),
+		folders: service.StorageFolders{
+			Thumbnail:       fileStorageConfig.ThumbnailFolder,
+			Avatar:          fileStorageConfig.AvatarFolder,
+			Live:            fileStorageConfig.LiveFolder,
+			ScheduledVideos: fileStorageConfig.ScheduledVideosFolder,
+			Video:           fileStorageConfig.VideoFolder,
+			Clips:           fileStorageConfig.ClipsFolder,
+		},
+	}
+
+	storage.register()
+
+	return storage
+}
+
+func (h *storageHandler) register() {
+	group := h.r.Group("api/storage")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("/usage", h.getUsageReport)
+	group.GET("/usage/:user_id", h.getUsageByUserID)
+	group.POST("/usage/recalculate", h.recalculateUsage)
+}
+
+// @Summary Get storage usage report
+// @Description List users by storage usage with breakdown by thumbnails, scheduled videos and recordings
+// @Tags Storage
+// @Accept  json
+// @Produce  json
+// @Param request query dto.StorageUsageQuery true "Storage Usage Query"
+// @Success 200 {object} utils.PaginationModel[dto.StorageUsageRespDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/storage/usage [get]
+func (h *storageHandler) getUsageReport(c echo.Context) error {
+	var req dto.StorageUsageQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Storage.GetUsageReport(&req, h.storageQuotas)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get storage usage of a user
+// @Description Get storage usage and quota of a user
+// @Tags Storage
+// @Accept  json
+// @Produce  json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} dto.StorageUsageRespDTO
+// @Failure 400 "Invalid ID parameter"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/storage/usage/{user_id} [get]
+func (h *storageHandler) getUsageByUserID(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid user_id parameter"), nil)
+	}
+
+	data, err := h.srv.Storage.GetUsageByUserID(uint(userID), h.storageQuotas)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if data == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Recalculate storage usage
+// @Description Rebuild storage usage of every streamer from files on disk
+// @Tags Storage
+// @Accept  json
+// @Produce  json
+// @Success 200 "Successfully"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/storage/usage/recalculate [post]
+func (h *storageHandler) recalculateUsage(c echo.Context) error {
+	if err := h.srv.Storage.RecalculateUsage(h.folders); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.RecalculateStorageUsage, fmt.Sprintf("%s recalculated storage usage.", currentUser.Username))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
@@ -11,8 +11,10 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -25,16 +27,21 @@ type streamHandler struct {
 	thumbnailFolder       string
 	rtmpURL               string
 	hlsURL                string
-	liveFolder            string
 	scheduledVideosFolder string
 	videoFolder           string
 	ApiURL                string
+	storageQuotas         map[model.RoleType]int64
+	scheduleWindow        time.Duration
+	scheduleLimits        service.ScheduleLimits
+	ffprobePath           string
+	purgeDelay            time.Duration
 }
 
 func newStreamHandler(r *echo.Group, srv *service.Service) *streamHandler {
 
 	fileStorageConfig := conf.GetFileStorageConfig()
 	streamConfig := conf.GetStreamServerConfig()
+	scheduleConfig := conf.GetScheduleConfig()
 
 	stream := &streamHandler{
 		Handler: Handler{
@@ -46,10 +53,14 @@ func newStreamHandler(r *echo.Group, srv *service.Service) *streamHandler {
 		thumbnailFolder:       fileStorageConfig.ThumbnailFolder,
 		rtmpURL:               streamConfig.RTMPURL,
 		hlsURL:                streamConfig.HLSURL,
-		liveFolder:            fileStorageConfig.LiveFolder,
 		scheduledVideosFolder: fileStorageConfig.ScheduledVideosFolder,
 		videoFolder:           fileStorageConfig.VideoFolder,
 		ApiURL:                conf.GetApiFileConfig().Url,
+		storageQuotas:         conf.GetStorageQuotaConfig(),
+		scheduleWindow:        time.Duration(scheduleConfig.Window) * time.Second,
+		scheduleLimits:        newScheduleLimits(scheduleConfig),
+		ffprobePath:           scheduleConfig.FFprobePath,
+		purgeDelay:            time.Duration(conf.GetTrashConfig().PurgeDelay) * time.Second,
 	}
 
 	stream.register()
@@ -68,11 +79,15 @@ func (h *streamHandler) register() {
 	group.GET("", h.getLiveStreamWithPagination)
 	group.GET("/:id", h.getLiveStreamBroadCastByID)
 	group.POST("", h.createLiveStreamByAdmin)
+	group.POST("/bulk", h.bulkStreams)
 	group.PATCH("/:id", h.updateLiveStreamByAdmin)
 	group.PATCH("/:id/scheduled", h.updateScheduledStreamByAdmin)
 	group.PATCH("/:id/change-thumbnail", h.updateThumbnailByAdmin)
 	group.DELETE("/:id", h.deleteLiveStream)
 	group.POST("/:id/end_live", h.endLiveStream)
+	group.PATCH("/:id/legal-hold", h.updateLegalHold)
+	group.PATCH("/:id/pin", h.updatePinned)
+	group.POST("/:id/rebroadcast", h.rebroadcastStream)
 
 }
 
@@ -90,63 +105,27 @@ func (h *streamHandler) deleteLiveStream(c echo.Context) error {
 		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
 	}
 
-	if deletedStream.Stream.Status == model.STARTED {
-		return utils.BuildErrorResponse(c, http.StatusBadRequest, fmt.Errorf("you can't delete stream while live"), nil)
-	}
-
-	filesToRemove := []string{}
-
-	// remove thumbnail
-	thumbnailPath := fmt.Sprintf("%s%s", h.thumbnailFolder, deletedStream.Stream.ThumbnailFileName)
-	filesToRemove = append(filesToRemove, thumbnailPath)
-
-	if deletedStream.Stream.Status == model.ENDED {
-		isEncoding, err := h.srv.Stream.IsEncodingVideo(c.Request().Context(), deletedStream.Stream.StreamKey)
-		if err != nil {
-			return err
-		}
-
-		if isEncoding {
-			return fmt.Errorf("you can't delete a stream while video is being encoded")
-		}
-
-		videoPath := utils.MakeVideoPath(h.videoFolder, deletedStream.Stream.StreamKey+".mp4")
-		filesToRemove = append(filesToRemove, videoPath)
-
-		liveVideoPath, err := utils.MakeLiveVideoPath(h.liveFolder, deletedStream.Stream.StreamKey)
-		if err == nil {
-			filesToRemove = append(filesToRemove, liveVideoPath)
-		}
-
-		if deletedStream.ScheduleStream != nil && deletedStream.ScheduleStream.ID != 0 {
-			scheduledVideoPath := utils.MakeVideoPath(h.scheduledVideosFolder, deletedStream.ScheduleStream.VideoName)
-			filesToRemove = append(filesToRemove, scheduledVideoPath)
-		}
-
-		// utils.RemoveFilesWithNoErrReturn(filesToRemove)
-
-	}
-
-	if deletedStream.Stream.Status == model.UPCOMING {
-		if deletedStream.ScheduleStream != nil && deletedStream.ScheduleStream.ID != 0 {
-			scheduledVideoPath := utils.MakeVideoPath(h.scheduledVideosFolder, deletedStream.ScheduleStream.VideoName)
-			filesToRemove = append(filesToRemove, scheduledVideoPath)
+	if err := h.srv.Stream.CheckDeletable(c.Request().Context(), deletedStream.Stream); err != nil {
+		if errors.Is(err, service.ErrStreamIsLive) || errors.Is(err, service.ErrStreamLegalHold) || errors.Is(err, service.ErrStreamEncoding) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
 		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
 
-	if err := h.srv.Stream.DeleteLiveStream(id); err != nil {
+	// the stream goes to trash, a job purges it with its files after the purge delay
+	currentUser := c.Get("user").(*utils.Claims)
+	job, err := h.srv.Trash.DeleteStream(deletedStream.Stream.ID, currentUser.ID, h.purgeDelay)
+	if err != nil {
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
-	go utils.RemoveFilesWithNoErrReturn(filesToRemove)
 
-	currentUser := c.Get("user").(*utils.Claims)
 	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.DeleteStreamByAdmin, fmt.Sprintf("%s deleted stream id: %d, status: %s and stream_type: %s.", currentUser.Username, deletedStream.Stream.ID, deletedStream.Stream.Status, deletedStream.Stream.StreamType))
 	err = h.srv.Admin.CreateLog(adminLog)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
 	}
 
-	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", dto.JobCreatedDTO{JobID: job.ID})
 }
 
 func (h *streamHandler) getLiveStreamBroadCastByID(c echo.Context) error {
@@ -206,7 +185,7 @@ func (h *streamHandler) updateScheduledStreamByAdmin(c echo.Context) error {
 		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
 	}
 
-	if !utils.IsValidSchedule(req.ScheduledAt) {
+	if !utils.IsValidSchedule(req.ScheduledAt, h.scheduleWindow) {
 		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid schedule"), nil)
 	}
 
@@ -217,6 +196,11 @@ func (h *streamHandler) updateScheduledStreamByAdmin(c echo.Context) error {
 	}
 	oldScheduledVideoPath := fmt.Sprintf("%s%s", h.scheduledVideosFolder, scheduleStream.VideoName)
 
+	stream, err := h.srv.Stream.GetStreamByID(scheduleStream.StreamID)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
 	currentUser := c.Get("user").(*utils.Claims)
 
 	//save video
@@ -241,6 +225,11 @@ func (h *streamHandler) updateScheduledStreamByAdmin(c echo.Context) error {
 			return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("file is not a supported video format"), nil)
 		}
 
+		oldVideoSize, _ := utils.GetfileSize(oldScheduledVideoPath)
+		if err := h.srv.Storage.CheckQuota(stream.UserID, video.Size-oldVideoSize, h.storageQuotas); err != nil {
+			return h.buildQuotaErrorResponse(c, err)
+		}
+
 		fileVideoExt := utils.GetFileExtension(video)
 		req.VideoFileName = fmt.Sprintf("%d_%s%s", currentUser.ID, utils.MakeUniqueIDWithTime(), fileVideoExt)
 		videoPath := fmt.Sprintf("%s%s", h.scheduledVideosFolder, req.VideoFileName)
@@ -266,16 +255,27 @@ func (h *streamHandler) updateScheduledStreamByAdmin(c echo.Context) error {
 			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
 		}
 		//
+		req.VideoDuration = probeVideoDuration(c.Request().Context(), h.ffprobePath, videoPath)
 	}
 
 	// update stream
-	if err := h.srv.Stream.UpdateScheduledStreamByAdmin(id, &req); err != nil {
-		go utils.RemoveFiles(filesToRemove)
+	if err := h.srv.Stream.UpdateScheduledStreamByAdmin(id, &req, h.scheduleLimits); err != nil {
+		if video != nil {
+			go utils.RemoveFiles([]string{fmt.Sprintf("%s%s", h.scheduledVideosFolder, req.VideoFileName)})
+		}
+		var conflictErr *service.ScheduleConflictError
+		if errors.As(err, &conflictErr) {
+			return utils.BuildErrorResponse(c, http.StatusConflict, err, conflictErr.Conflicts)
+		}
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
 
 	if video != nil {
-		go utils.RemoveFiles([]string{oldScheduledVideoPath})
+		h.srv.Storage.TrackFile(stream.UserID, model.StorageFileTypeScheduledVideo, fmt.Sprintf("%s%s", h.scheduledVideosFolder, req.VideoFileName))
+		if !h.srv.Recurrence.IsFileShared(scheduleStream.VideoName, stream.ID) {
+			h.srv.Storage.UntrackFile(stream.UserID, model.StorageFileTypeScheduledVideo, oldScheduledVideoPath)
+			go utils.RemoveFiles([]string{oldScheduledVideoPath})
+		}
 	}
 
 	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.UpdateScheduledStreamByAdmin, fmt.Sprintf("%s updated a scheduled stream with id %d.", currentUser.Username, scheduleStream.StreamID))
@@ -320,6 +320,11 @@ func (h *streamHandler) updateThumbnailByAdmin(c echo.Context) error {
 		return utils.BuildErrorResponse(c, http.StatusBadRequest, nil, "Image size exceeds the 1MB limit")
 	}
 
+	oldThumbnailSize, _ := utils.GetfileSize(fmt.Sprintf("%s%s", h.thumbnailFolder, stream.ThumbnailFileName))
+	if err := h.srv.Storage.CheckQuota(stream.UserID, file.Size-oldThumbnailSize, h.storageQuotas); err != nil {
+		return h.buildQuotaErrorResponse(c, err)
+	}
+
 	// save thumbnail
 	fileExt := utils.GetFileExtension(file)
 	req.ThumbnailFileName = fmt.Sprintf("%d_%s%s", req.UpdatedByID, utils.MakeUniqueIDWithTime(), fileExt)
@@ -358,7 +363,11 @@ func (h *streamHandler) updateThumbnailByAdmin(c echo.Context) error {
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
 	// if update success, remove old one
-	go utils.RemoveFiles(oldThumbnailsToRemove)
+	h.srv.Storage.TrackFile(stream.UserID, model.StorageFileTypeThumbnail, thumbnailPath)
+	if !h.srv.Recurrence.IsFileShared(stream.ThumbnailFileName, stream.ID) {
+		h.srv.Storage.UntrackFile(stream.UserID, model.StorageFileTypeThumbnail, oldThumbnailPath)
+		go utils.RemoveFiles(oldThumbnailsToRemove)
+	}
 
 	adminLog := h.srv.Admin.MakeAdminLogModel(claims.ID, model.UpdateThumbnailByAdmin, fmt.Sprintf("%s updated thumbnail of a stream %d.", claims.Username, stream.ID))
 	err = h.srv.Admin.CreateLog(adminLog)
@@ -382,6 +391,7 @@ func (h *streamHandler) updateThumbnailByAdmin(c echo.Context) error {
 // @Param video formData file true "Video file"
 // @Success 201 {object} dto.CreateStreamResponseDTO
 // @Failure 400 "Invalid request"
+// @Failure 409 "Schedule conflicts with other streams"
 // @Failure 500 "Internal Server Error"
 // @Security Bearer
 // @Router /api/streams [post]
@@ -400,7 +410,7 @@ func (h *streamHandler) createLiveStreamByAdmin(c echo.Context) error {
 		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("user is not a streamer"), nil)
 	}
 
-	if !utils.IsValidSchedule(req.ScheduledAt) {
+	if !utils.IsValidSchedule(req.ScheduledAt, h.scheduleWindow) {
 		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid schedule"), nil)
 	}
 
@@ -468,6 +478,11 @@ func (h *streamHandler) createLiveStreamByAdmin(c echo.Context) error {
 		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("file is not a supported video format"), nil)
 	}
 
+	if err := h.srv.Storage.CheckQuota(req.UserID, file.Size+video.Size, h.storageQuotas); err != nil {
+		go utils.RemoveFiles(filesToRemove)
+		return h.buildQuotaErrorResponse(c, err)
+	}
+
 	fileVideoExt := utils.GetFileExtension(video)
 	req.VideoFileName = fmt.Sprintf("%d_%s%s", req.UserID, utils.MakeUniqueIDWithTime(), fileVideoExt)
 	videoPath := fmt.Sprintf("%s%s", h.scheduledVideosFolder, req.VideoFileName)
@@ -493,13 +508,20 @@ func (h *streamHandler) createLiveStreamByAdmin(c echo.Context) error {
 		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
 	}
 	//
+	req.VideoDuration = probeVideoDuration(c.Request().Context(), h.ffprobePath, videoPath)
 
-	stream, err := h.srv.Stream.CreateStreamByAdmin(&req)
+	stream, err := h.srv.Stream.CreateStreamByAdmin(&req, h.scheduleLimits)
 	if err != nil {
 		go utils.RemoveFiles(filesToRemove)
 
+		var conflictErr *service.ScheduleConflictError
+		if errors.As(err, &conflictErr) {
+			return utils.BuildErrorResponse(c, http.StatusConflict, err, conflictErr.Conflicts)
+		}
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
+	h.srv.Storage.TrackFile(stream.UserID, model.StorageFileTypeThumbnail, thumbnailPath)
+	h.srv.Storage.TrackFile(stream.UserID, model.StorageFileTypeScheduledVideo, videoPath)
 
 	adminLog := h.srv.Admin.MakeAdminLogModel(req.UserID, model.ScheduledLiveStreamByAdmin, fmt.Sprintf("%s scheduled a live stream %d", claims.Username, stream.ID))
 	err = h.srv.Admin.CreateLog(adminLog)
@@ -657,7 +679,7 @@ func (h *streamHandler) endLiveStream(c echo.Context) error {
 	}
 
 	if stream.Status != model.STARTED {
-		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("you can't end a live stream which is not started"), nil)
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, service.ErrStreamNotLive, nil)
 	}
 
 	isEndingLive, err := h.srv.Stream.IsEndingLive(c.Request().Context(), stream.ID)
@@ -681,3 +703,254 @@ func (h *streamHandler) endLiveStream(c echo.Context) error {
 
 	return utils.BuildSuccessResponse(c, http.StatusOK, "Stream is ending. Wait for a few minutes", nil)
 }
+
+// @Summary Update legal hold of a stream
+// @Description Streams under legal hold can't be deleted by admins or retention policies
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Param request body dto.LegalHoldRequest true "Legal Hold Request"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/legal-hold [patch]
+func (h *streamHandler) updateLegalHold(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	var req dto.LegalHoldRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	stream, err := h.srv.Stream.GetStreamByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id"), nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	if err := h.srv.Stream.UpdateLegalHold(stream.ID, *req.LegalHold); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.UpdateLegalHoldByAdmin, fmt.Sprintf("%s set legal hold of stream %d to %t.", currentUser.Username, stream.ID, *req.LegalHold))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Pin or unpin a stream
+// @Description Recordings of pinned streams are kept by retention policies
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Param request body dto.PinStreamRequest true "Pin Stream Request"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/pin [patch]
+func (h *streamHandler) updatePinned(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	var req dto.PinStreamRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	stream, err := h.srv.Stream.GetStreamByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id"), nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	if err := h.srv.Stream.UpdatePinned(stream.ID, *req.IsPinned); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.PinStreamByAdmin, fmt.Sprintf("%s set pinned of stream %d to %t.", currentUser.Username, stream.ID, *req.IsPinned))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Run an action on many streams
+// @Description Delete, end live or recategorize streams selected by ids or a filter, the action runs as a job with per-item results
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param request body dto.BulkStreamRequest true "Bulk Stream Request"
+// @Success 202 {object} dto.JobCreatedDTO
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/bulk [post]
+func (h *streamHandler) bulkStreams(c echo.Context) error {
+	var req dto.BulkStreamRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request"), nil)
+	}
+	// every matching stream is selected, page and limit of the filter are only required by the list endpoint
+	if req.Filter != nil {
+		req.Filter.Page, req.Filter.Limit = 1, 1
+	}
+	if err := c.Validate(&req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("validation error: "+err.Error()), nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	job, err := h.srv.Bulk.EnqueueStreams(&req, currentUser.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrBulkNoTarget) || errors.Is(err, service.ErrBulkTooManyItems) || errors.Is(err, service.ErrBulkNoMatch) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusAccepted, "Successfully", dto.JobCreatedDTO{JobID: job.ID})
+}
+
+// @Summary Rebroadcast an ended stream
+// @Description Schedule the recording of an ended stream as a new pre-recorded stream with the same title, description, categories and thumbnail
+// @Tags Streams
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Param request body dto.RebroadcastStreamRequest true "Rebroadcast Stream Request"
+// @Success 201 {object} dto.CreateStreamResponseDTO
+// @Failure 400 "Invalid request"
+// @Failure 409 "Schedule conflicts with other streams"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/streams/{id}/rebroadcast [post]
+func (h *streamHandler) rebroadcastStream(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	var req dto.RebroadcastStreamRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	if !utils.IsValidSchedule(req.ScheduledAt, h.scheduleWindow) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid schedule"), nil)
+	}
+
+	source, err := h.srv.Stream.GetStreamByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id"), nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if source.Status != model.ENDED {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, service.ErrStreamNotEnded, nil)
+	}
+
+	isEncoding, err := h.srv.Stream.IsEncodingVideo(c.Request().Context(), source.StreamKey)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if isEncoding {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("recording is being encoded"), nil)
+	}
+
+	recordingPath := utils.MakeVideoPath(h.videoFolder, source.StreamKey+".mp4")
+	recordingSize, err := utils.GetfileSize(recordingPath)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("recording not found"), nil)
+	}
+	// the rebroadcast has no thumbnail when the source lost its own
+	sourceThumbnailPath := fmt.Sprintf("%s%s", h.thumbnailFolder, source.ThumbnailFileName)
+	var thumbnailSize int64
+	hasThumbnail := false
+	if source.ThumbnailFileName != "" {
+		if size, err := utils.GetfileSize(sourceThumbnailPath); err == nil {
+			thumbnailSize, hasThumbnail = size, true
+		}
+	}
+
+	if err := h.srv.Storage.CheckQuota(source.UserID, recordingSize+thumbnailSize, h.storageQuotas); err != nil {
+		return h.buildQuotaErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+
+	// the recording can be removed by retention, the new stream keeps its own files
+	req.VideoFileName = fmt.Sprintf("%d_%s.mp4", source.UserID, utils.MakeUniqueIDWithTime())
+	videoPath := fmt.Sprintf("%s%s", h.scheduledVideosFolder, req.VideoFileName)
+	if err := utils.LinkOrCopyFile(recordingPath, videoPath); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	filesToRemove := []string{videoPath}
+
+	var thumbnailPath string
+	if hasThumbnail {
+		req.ThumbnailFileName = fmt.Sprintf("%d_%s%s", source.UserID, utils.MakeUniqueIDWithTime(), filepath.Ext(source.ThumbnailFileName))
+		thumbnailPath = fmt.Sprintf("%s%s", h.thumbnailFolder, req.ThumbnailFileName)
+		if err := utils.LinkOrCopyFile(sourceThumbnailPath, thumbnailPath); err != nil {
+			h.removeUntrackedFiles(filesToRemove, currentUser.ID)
+			return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+		}
+		filesToRemove = append(filesToRemove, thumbnailPath)
+	}
+
+	req.VideoDuration = probeVideoDuration(c.Request().Context(), h.ffprobePath, videoPath)
+
+	stream, err := h.srv.Stream.RebroadcastStream(source.ID, &req, h.scheduleLimits)
+	if err != nil {
+		h.removeUntrackedFiles(filesToRemove, currentUser.ID)
+
+		var conflictErr *service.ScheduleConflictError
+		if errors.As(err, &conflictErr) {
+			return utils.BuildErrorResponse(c, http.StatusConflict, err, conflictErr.Conflicts)
+		}
+		if errors.Is(err, service.ErrStreamNotEnded) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if hasThumbnail {
+		h.srv.Storage.TrackFile(stream.UserID, model.StorageFileTypeThumbnail, thumbnailPath)
+	}
+	h.srv.Storage.TrackFile(stream.UserID, model.StorageFileTypeScheduledVideo, videoPath)
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.RebroadcastStreamByAdmin, fmt.Sprintf("%s scheduled stream %d as a rebroadcast of stream %d.", currentUser.Username, stream.ID, source.ID))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusCreated, "Successfully", dto.CreateStreamResponseDTO{
+		ID:           stream.ID,
+		Title:        stream.Title,
+		Description:  stream.Description,
+		ThumbnailURL: utils.MakeThumbnailURL(h.ApiURL, stream.ThumbnailFileName),
+	})
+}
+
+func (h *streamHandler) buildQuotaErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrStorageQuotaExceeded) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type subscriptionHandler struct {
+	Handler
+	r   *echo.Group
+	srv *service.Service
+}
+
+func newSubscriptionHandler(r *echo.Group, srv *service.Service) *subscriptionHandler {
+	subscription := &subscriptionHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:   r,
+		srv: srv,
+	}
+
+	subscription.register()
+
+	return subscription
+}
+
+func (h *subscriptionHandler) register() {
+	subscribers := h.r.Group("api/users/:id/subscribers")
+	subscribers.Use(h.JWTMiddleware())
+	subscribers.GET("", h.getSubscribers)
+	subscribers.GET("/stats", h.getStats)
+	subscribers.GET("/growth", h.getGrowth)
+	subscribers.GET("/bursts", h.getBursts)
+	subscribers.POST("/remove", h.removeSubscribers)
+
+	subscriptions := h.r.Group("api/users/:id/subscriptions")
+	subscriptions.Use(h.JWTMiddleware())
+	subscriptions.GET("", h.getSubscriptions)
+}
+
+func (h *subscriptionHandler) buildSubscriptionErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrSubscriptionRange) || errors.Is(err, service.ErrSubscriptionGrowth) {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+func (h *subscriptionHandler) getPage(c echo.Context, direction string) error {
+	user, err := h.findUser(c, "id")
+	if user == nil {
+		return err
+	}
+
+	var req dto.SubscriptionQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Subscription.GetPage(user.ID, direction, &req)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get subscribers of streamer
+// @Description Get users subscribed to a streamer, keyword matches their username or display name
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Streamer ID"
+// @Param request query dto.SubscriptionQuery true "Subscription Query"
+// @Success 200 {object} utils.PaginationModel[dto.SubscriptionDTO]
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/subscribers [get]
+func (h *subscriptionHandler) getSubscribers(c echo.Context) error {
+	return h.getPage(c, dto.SUBSCRIPTION_DIRECTION_SUBSCRIBERS)
+}
+
+// @Summary Get subscriptions of user
+// @Description Get streamers a user is subscribed to, keyword matches their username or display name
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param request query dto.SubscriptionQuery true "Subscription Query"
+// @Success 200 {object} utils.PaginationModel[dto.SubscriptionDTO]
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/subscriptions [get]
+func (h *subscriptionHandler) getSubscriptions(c echo.Context) error {
+	return h.getPage(c, dto.SUBSCRIPTION_DIRECTION_SUBSCRIPTIONS)
+}
+
+// @Summary Get subscription stats of user
+// @Description Count subscribers and subscriptions of a user with how many are muted
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {object} dto.SubscriptionStatsDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/subscribers/stats [get]
+func (h *subscriptionHandler) getStats(c echo.Context) error {
+	user, err := h.findUser(c, "id")
+	if user == nil {
+		return err
+	}
+
+	data, err := h.srv.Subscription.GetStats(user.ID)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get follower growth of streamer
+// @Description Count current subscribers of a streamer per interval by when they subscribed, with running totals and mute ratios
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Streamer ID"
+// @Param request query dto.SubscriptionGrowthQuery true "Subscription Growth Query"
+// @Success 200 {object} dto.SubscriptionGrowthDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/subscribers/growth [get]
+func (h *subscriptionHandler) getGrowth(c echo.Context) error {
+	user, err := h.findUser(c, "id")
+	if user == nil {
+		return err
+	}
+
+	var req dto.SubscriptionGrowthQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Subscription.GetGrowth(user.ID, &req)
+	if err != nil {
+		return h.buildSubscriptionErrorResponse(c, err)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get follow spam bursts of streamer
+// @Description Find windows in which many new accounts subscribed to a streamer, subscriber ids can be removed with the remove endpoint
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Streamer ID"
+// @Param request query dto.SubscriptionBurstQuery true "Subscription Burst Query"
+// @Success 200 {object} dto.SubscriptionBurstsDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/subscribers/bursts [get]
+func (h *subscriptionHandler) getBursts(c echo.Context) error {
+	user, err := h.findUser(c, "id")
+	if user == nil {
+		return err
+	}
+
+	var req dto.SubscriptionBurstQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Subscription.GetBursts(user.ID, &req)
+	if err != nil {
+		return h.buildSubscriptionErrorResponse(c, err)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Remove subscribers of streamer
+// @Description Remove fraudulent subscriptions of the subscribers to a streamer
+// @Tags Subscriptions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Streamer ID"
+// @Param request body dto.RemoveSubscriptionsRequest true "Remove Subscriptions Request"
+// @Success 200 {object} dto.RemoveSubscriptionsDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/{id}/subscribers/remove [post]
+func (h *subscriptionHandler) removeSubscribers(c echo.Context) error {
+	user, err := h.findUser(c, "id")
+	if user == nil {
+		return err
+	}
+
+	var req dto.RemoveSubscriptionsRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	removed, err := h.srv.Subscription.Remove(user.ID, req.SubscriberIDs)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.RemoveSubscriptions, fmt.Sprintf("%s removed %d subscribers of %s. reason is %s", currentUser.Username, removed, user.Username, req.Reason))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, dto.RemoveSubscriptionsDTO{Removed: removed})
+}
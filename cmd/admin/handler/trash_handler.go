@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/conf"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type trashHandler struct {
+	Handler
+	r          *echo.Group
+	srv        *service.Service
+	purgeDelay time.Duration
+}
+
+func newTrashHandler(r *echo.Group, srv *service.Service) *trashHandler {
+	trash := &trashHandler{
+		Handler: Handler{
+			r:   r,
+			srv: srv,
+		},
+		r:          r,
+		srv:        srv,
+		purgeDelay: time.Duration(conf.GetTrashConfig().PurgeDelay) * time.Second,
+	}
+
+	trash.register()
+
+	return trash
+}
+
+func (h *trashHandler) register() {
+	group := h.r.Group("api/trash")
+
+	group.Use(h.JWTMiddleware())
+	group.GET("", h.getTrash)
+	group.POST("/users/:id/restore", h.restoreUser)
+	group.POST("/streams/:id/restore", h.restoreStream)
+}
+
+func (h *trashHandler) buildRestoreErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrNotInTrash) {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, err, nil)
+	}
+	return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+}
+
+// @Summary Get trash
+// @Description Get deleted users or streams which are not purged yet, newest first
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Param request query dto.TrashQuery true "Trash Query"
+// @Success 200 {object} utils.PaginationModel[dto.TrashItemDTO]
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/trash [get]
+func (h *trashHandler) getTrash(c echo.Context) error {
+	var req dto.TrashQuery
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	data, err := h.srv.Trash.GetTrash(&req, h.purgeDelay)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Restore a deleted user
+// @Description Restore a user from trash before it's purged
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found in trash"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/trash/users/{id}/restore [post]
+func (h *trashHandler) restoreUser(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	user, err := h.srv.Trash.GetDeletedUser(uint(id))
+	if err != nil {
+		return h.buildRestoreErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	if currentUser.RoleType == model.ADMINROLE && user.Role.Type == model.ADMINROLE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request, admin can't restore admin"), nil)
+	}
+
+	if err := h.srv.Trash.RestoreUser(user.ID); err != nil {
+		return h.buildRestoreErrorResponse(c, err)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.RestoreUserAction, fmt.Sprintf("%s restored %s.", currentUser.Username, user.Username))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
+// @Summary Restore a deleted stream
+// @Description Restore a stream from trash before it's purged
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Stream ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found in trash"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/trash/streams/{id}/restore [post]
+func (h *trashHandler) restoreStream(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	if err := h.srv.Trash.RestoreStream(uint(id)); err != nil {
+		return h.buildRestoreErrorResponse(c, err)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.RestoreStreamByAdmin, fmt.Sprintf("%s restored stream %d.", currentUser.Username, id))
+	if err := h.srv.Admin.CreateLog(adminLog); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
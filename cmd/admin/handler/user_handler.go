@@ -15,6 +15,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -26,6 +27,8 @@ type userHandler struct {
 	avatarFolder string
 	apiURL       string
 	clientHost   string
+	purgeDelay   time.Duration
+	maxStrikes   int
 }
 
 func newUserHandler(r *echo.Group, srv *service.Service) *userHandler {
@@ -42,6 +45,8 @@ func newUserHandler(r *echo.Group, srv *service.Service) *userHandler {
 		avatarFolder: fileStorageConfig.AvatarFolder,
 		apiURL:       apiURL,
 		clientHost:   clientHost,
+		purgeDelay:   time.Duration(conf.GetTrashConfig().PurgeDelay) * time.Second,
+		maxStrikes:   conf.GetSuspensionConfig().MaxStrikes,
 	}
 
 	user.register()
@@ -56,11 +61,18 @@ func (h *userHandler) register() {
 	group.GET("", h.page)
 	group.GET("/list-username", h.getUsernameList)
 	group.POST("", h.createUser)
+	group.POST("/bulk", h.bulkUsers)
+	group.POST("/import", h.importUsers)
 	group.PUT("/:id", h.updateUser)
 	group.PATCH("/:id/change-password", h.changePassword)
 	group.PATCH("/:id/change-avatar", h.changeAvatar)
 	group.PATCH("/:id/deactive", h.deactiveUser)
 	group.PATCH("/:id/reactive", h.reactiveUser)
+	group.PATCH("/:id/suspend", h.suspendUser)
+	group.GET("/:id/strikes", h.getStrikes)
+	group.PATCH("/:id/strikes/:strike_id/revoke", h.revokeStrike)
+	group.PATCH("/:id/publish-ban", h.banPublishing)
+	group.DELETE("/:id/publish-ban", h.liftPublishingBan)
 	group.GET("/:id", h.byId)
 	group.DELETE("/:id", h.deleteByID)
 	group.GET("/statistics", h.getUserStatistics)
@@ -144,22 +156,23 @@ func (h *userHandler) deleteByID(c echo.Context) error {
 		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
 	}
 
-	// remove avatar
-	if deletedUser.AvatarFileName.Valid {
-
-		avatarPath := fmt.Sprintf("%s%s", h.avatarFolder, deletedUser.AvatarFileName.String)
-		avatarsToRemove := []string{avatarPath}
-		go utils.RemoveFiles(avatarsToRemove)
+	notification, err := h.srv.NotificationTemplate.Render(model.NotificationTypeDeleted, deletedUser.Locale, map[string]string{
+		"Username":    deletedUser.Username,
+		"DisplayName": deletedUser.DisplayName,
+	})
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
 
-	if err := h.srv.User.DeleteByID(uint(id), currentUser.ID); err != nil {
+	// the user goes to trash, avatar is removed when a job purges it
+	if _, err := h.srv.Trash.DeleteUser(uint(id), currentUser.ID, h.purgeDelay); err != nil {
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
 	currentToken, err := utils.GetTokenFromHeader(c)
 	if err != nil {
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
-	_, err = utils.PostAsync[dto.CommonResponseDTO](fmt.Sprintf("%s/api/notification/blocked-deleted", h.clientHost), currentToken, map[string]interface{}{"user_id": id, "type": "account_deleted"})
+	_, err = utils.PostAsync[dto.CommonResponseDTO](fmt.Sprintf("%s/api/notification/blocked-deleted", h.clientHost), currentToken, map[string]interface{}{"user_id": id, "type": "account_deleted", "title": notification.Title, "content": notification.Content})
 	if err != nil {
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
@@ -266,6 +279,11 @@ func (h *userHandler) deactiveUser(c echo.Context) error {
 		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request, admin can't deactive admin"), nil)
 	}
 
+	notification, err := h.srv.NotificationTemplate.RenderBlocked(updatedUser, request.Reason, nil)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
 	data, err := h.srv.User.ChangeStatusUser(updatedUser, currentUser.ID, model.BLOCKED, request.Reason, h.apiURL)
 	if err != nil {
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
@@ -276,7 +294,7 @@ func (h *userHandler) deactiveUser(c echo.Context) error {
 	if err != nil {
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
-	_, err = utils.PostAsync[dto.CommonResponseDTO](fmt.Sprintf("%s/api/notification/blocked-deleted", h.clientHost), currentToken, map[string]interface{}{"user_id": id, "type": "account_blocked"})
+	_, err = utils.PostAsync[dto.CommonResponseDTO](fmt.Sprintf("%s/api/notification/blocked-deleted", h.clientHost), currentToken, map[string]interface{}{"user_id": id, "type": "account_blocked", "title": notification.Title, "content": notification.Content})
 	if err != nil {
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
@@ -290,6 +308,281 @@ func (h *userHandler) deactiveUser(c echo.Context) error {
 
 }
 
+// @Summary Suspend user by ID
+// @Description Add a strike to a user and block it for the duration, the user is blocked permanently on reaching the max strikes and reinstated automatically otherwise
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param SuspendUserRequest body dto.SuspendUserRequest true "Suspend User"
+// @Success 200 {object} dto.SuspendUserResponse
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security     Bearer
+// @Router /api/users/{id}/suspend [patch]
+func (h *userHandler) suspendUser(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	var request dto.SuspendUserRequest
+	if err := utils.BindAndValidate(c, &request); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	suspendedUser, err := h.srv.User.FindByID(uint(id))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if suspendedUser == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	if suspendedUser.Role.Type == model.SUPPERADMINROLE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request, super admin can't be suspended"), nil)
+	}
+
+	if currentUser.RoleType == model.ADMINROLE && suspendedUser.Role.Type == model.ADMINROLE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request, admin can't suspend admin"), nil)
+	}
+
+	data, err := h.srv.Suspension.Suspend(suspendedUser, &request, currentUser.ID, h.maxStrikes)
+	if err != nil {
+		if errors.Is(err, service.ErrUserBlockedPermanently) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	// the note stays internal, users see the reason of the strike
+	notification, err := h.srv.NotificationTemplate.RenderBlocked(suspendedUser, string(data.Strike.Reason), data.Strike.SuspendedUntil)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	// remove ws connection
+	currentToken, err := utils.GetTokenFromHeader(c)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	_, err = utils.PostAsync[dto.CommonResponseDTO](fmt.Sprintf("%s/api/notification/blocked-deleted", h.clientHost), currentToken, map[string]interface{}{"user_id": id, "type": "account_blocked", "title": notification.Title, "content": notification.Content})
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	details := fmt.Sprintf("%s block %s permanently, strike %d. reason is %s", currentUser.Username, suspendedUser.Username, data.Strikes, request.Reason)
+	if !data.Strike.Permanent {
+		details = fmt.Sprintf("%s suspend %s until %s, strike %d. reason is %s", currentUser.Username, suspendedUser.Username, data.Strike.SuspendedUntil.Format(time.RFC3339), data.Strikes, request.Reason)
+	}
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.SuspendUser, details)
+	err = h.srv.Admin.CreateLog(adminLog)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Get strikes of user
+// @Description Get the strike history of a user, revoked strikes included
+// @Tags Users
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {object} dto.UserStrikesDTO
+// @Failure 400 "Invalid ID parameter"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security     Bearer
+// @Router /api/users/{id}/strikes [get]
+func (h *userHandler) getStrikes(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	user, err := h.srv.User.FindByID(uint(id))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if user == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	data, err := h.srv.Suspension.GetStrikes(user, h.maxStrikes)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Revoke strike of user
+// @Description Revoke a strike so it doesn't count anymore, the user is reinstated when the strike is what blocks it
+// @Tags Users
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param strike_id path int true "Strike ID"
+// @Success 200 {object} dto.UserStrikeDTO
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security     Bearer
+// @Router /api/users/{id}/strikes/{strike_id}/revoke [patch]
+func (h *userHandler) revokeStrike(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+	strikeID, err := strconv.Atoi(c.Param("strike_id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid strike_id parameter"), nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	user, err := h.srv.User.FindByID(uint(id))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if user == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	if currentUser.RoleType == model.ADMINROLE && user.Role.Type == model.ADMINROLE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request, admin can't revoke strikes of admin"), nil)
+	}
+
+	strike, err := h.srv.Suspension.FindStrikeByID(uint(strikeID))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if strike == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	data, reinstated, err := h.srv.Suspension.RevokeStrike(user, strike, currentUser.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrStrikeRevoked) || errors.Is(err, service.ErrStrikeNotOfUser) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	details := fmt.Sprintf("%s revoke strike %d of %s.", currentUser.Username, strike.ID, user.Username)
+	if reinstated {
+		details = fmt.Sprintf("%s revoke strike %d of %s and reinstate %s.", currentUser.Username, strike.ID, user.Username, user.Username)
+	}
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.RevokeStrike, details)
+	err = h.srv.Admin.CreateLog(adminLog)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Ban user from going live
+// @Description Stop a user from going live for the duration, or until it's lifted. Live streams of the user are ended, viewing and commenting are left as they are
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param PublishBanRequest body dto.PublishBanRequest true "Publish Ban"
+// @Success 200 {object} dto.PublishBanResponse
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security     Bearer
+// @Router /api/users/{id}/publish-ban [patch]
+func (h *userHandler) banPublishing(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	var request dto.PublishBanRequest
+	if err := utils.BindAndValidate(c, &request); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	bannedUser, err := h.srv.User.FindByID(uint(id))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if bannedUser == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	if bannedUser.Role.Type == model.SUPPERADMINROLE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request, super admin can't be banned"), nil)
+	}
+
+	if currentUser.RoleType == model.ADMINROLE && bannedUser.Role.Type == model.ADMINROLE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request, admin can't ban admin"), nil)
+	}
+
+	data, err := h.srv.PublishBan.Ban(c.Request().Context(), bannedUser, &request, currentUser.ID)
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	details := fmt.Sprintf("%s ban %s from going live until lifted. reason is %s", currentUser.Username, bannedUser.Username, request.Reason)
+	if data.Ban.ExpiresAt != nil {
+		details = fmt.Sprintf("%s ban %s from going live until %s. reason is %s", currentUser.Username, bannedUser.Username, data.Ban.ExpiresAt.Format(time.RFC3339), request.Reason)
+	}
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.BanPublishing, details)
+	err = h.srv.Admin.CreateLog(adminLog)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
+}
+
+// @Summary Lift ban of user from going live
+// @Description Lift the active ban of a user from going live
+// @Tags Users
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 "Successfully"
+// @Failure 400 "Invalid request"
+// @Failure 404 "Not found"
+// @Failure 500 "Internal Server Error"
+// @Security     Bearer
+// @Router /api/users/{id}/publish-ban [delete]
+func (h *userHandler) liftPublishingBan(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid id parameter"), nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	bannedUser, err := h.srv.User.FindByID(uint(id))
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	if bannedUser == nil {
+		return utils.BuildErrorResponse(c, http.StatusNotFound, errors.New("not found"), nil)
+	}
+
+	if currentUser.RoleType == model.ADMINROLE && bannedUser.Role.Type == model.ADMINROLE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request, admin can't lift ban of admin"), nil)
+	}
+
+	if err := h.srv.PublishBan.Lift(c.Request().Context(), bannedUser.ID, currentUser.ID); err != nil {
+		if errors.Is(err, service.ErrNotPublishBanned) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	adminLog := h.srv.Admin.MakeAdminLogModel(currentUser.ID, model.LiftPublishingBan, fmt.Sprintf("%s lift ban of %s from going live.", currentUser.Username, bannedUser.Username))
+	err = h.srv.Admin.CreateLog(adminLog)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to created admin log"})
+	}
+	return utils.BuildSuccessResponse(c, http.StatusOK, "Successfully", nil)
+}
+
 // @Summary Create a new user
 // @Description Create a new user with the provided details
 // @Tags Users
@@ -359,6 +652,15 @@ func (h *userHandler) createUser(c echo.Context) error {
 	}
 
 	if err := h.srv.User.CreateUser(&req); err != nil {
+		if req.AvatarFileName != "" {
+			go utils.RemoveFiles([]string{fmt.Sprintf("%s%s", h.avatarFolder, req.AvatarFileName)})
+		}
+		if errors.Is(err, service.ErrUsernameTaken) || errors.Is(err, service.ErrEmailTaken) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		if errors.Is(err, service.ErrUserConflict) {
+			return utils.BuildErrorResponse(c, http.StatusConflict, err, nil)
+		}
 		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
 	}
 
@@ -665,3 +967,89 @@ func (h *userHandler) getUserStatistics(c echo.Context) error {
 
 	return utils.BuildSuccessResponseWithData(c, http.StatusOK, data)
 }
+
+// @Summary Run an action on many users
+// @Description Block, reactivate, delete or change role of users selected by ids or a filter, the action runs as a job with per-item results
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param BulkUserRequest body dto.BulkUserRequest true "Bulk User Request"
+// @Success 202 {object} dto.JobCreatedDTO
+// @Failure 400 "Invalid request"
+// @Failure 500 "Internal Server Error"
+// @Security     Bearer
+// @Router /api/users/bulk [post]
+func (h *userHandler) bulkUsers(c echo.Context) error {
+	var req dto.BulkUserRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	currentUser := c.Get("user").(*utils.Claims)
+	if req.Action == dto.BULK_ACTION_CHANGE_ROLE && currentUser.RoleType != model.SUPPERADMINROLE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, errors.New("invalid request, only super admin can change role"), nil)
+	}
+
+	job, err := h.srv.Bulk.EnqueueUsers(&req, currentUser.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrBulkNoTarget) || errors.Is(err, service.ErrBulkTooManyItems) || errors.Is(err, service.ErrBulkNoMatch) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusAccepted, "Successfully", dto.JobCreatedDTO{JobID: job.ID})
+}
+
+// @Summary Import users from csv
+// @Description Validate every row of a csv with columns username, email, display_name, role, avatar_url (optional) and password (unless invite) like creating a user.
+// @Description Nothing is imported when a row is invalid, otherwise a job creates the users in batches. With invite, it creates invites and users are created when they set their password.
+// @Tags Users
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "CSV file"
+// @Param dry_run formData bool false "Only validate"
+// @Param invite formData bool false "Invite users instead of setting passwords"
+// @Success 200 {object} dto.UserImportReport "Dry run, with errors of invalid rows"
+// @Success 202 {object} dto.UserImportReport "Import job queued"
+// @Failure 400 {object} dto.UserImportReport "Invalid rows"
+// @Failure 500 "Internal Server Error"
+// @Security Bearer
+// @Router /api/users/import [post]
+func (h *userHandler) importUsers(c echo.Context) error {
+	var req dto.UserImportRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, err, fmt.Sprintf("file field is required: %s", err.Error()))
+	}
+	if file.Size > dto.USER_IMPORT_MAX_SIZE {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, nil, "CSV size exceeds the 1MB limit")
+	}
+	src, err := file.Open()
+	if err != nil {
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+	defer src.Close()
+
+	currentUser := c.Get("user").(*utils.Claims)
+	report, err := h.srv.UserImport.Import(src, &req, currentUser.ID, c.Validate)
+	if err != nil {
+		if errors.Is(err, service.ErrUserImportEmpty) || errors.Is(err, service.ErrUserImportTooManyRows) || errors.Is(err, service.ErrUserImportInvalidCSV) {
+			return utils.BuildErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		return utils.BuildErrorResponse(c, http.StatusInternalServerError, err, nil)
+	}
+
+	if report.DryRun {
+		return utils.BuildSuccessResponseWithData(c, http.StatusOK, report)
+	}
+	if report.Invalid > 0 {
+		return utils.BuildErrorResponse(c, http.StatusBadRequest, fmt.Errorf("%d of %d rows are invalid", report.Invalid, report.Total), report)
+	}
+
+	return utils.BuildSuccessResponse(c, http.StatusAccepted, "Successfully", report)
+}
@@ -26,13 +26,121 @@ type Config struct {
 	StreamServer StreamServerConfig `yaml:"stream_server"`
 	ApiFile      ApiFileConfig      `yaml:"api_file"`
 	Client       ClientConfig       `yaml:"client"`
+	FileGC       FileGCConfig       `yaml:"file_gc"`
+	StorageQuota StorageQuotaConfig `yaml:"storage_quota"`
+	StorageUsage StorageUsageConfig `yaml:"storage_usage"`
+	Retention    RetentionConfig    `yaml:"retention"`
+	Scheduler    SchedulerConfig    `yaml:"scheduler"`
+	Schedule     ScheduleConfig     `yaml:"schedule"`
+	Clip         ClipConfig         `yaml:"clip"`
+	Job          JobConfig          `yaml:"job"`
+	Trash        TrashConfig        `yaml:"trash"`
+	GDPR         GDPRConfig         `yaml:"gdpr"`
+	Mail         MailConfig         `yaml:"mail"`
+	Invite       InviteConfig       `yaml:"invite"`
+	Report       ReportConfig       `yaml:"report"`
+	Suspension   SuspensionConfig   `yaml:"suspension"`
+}
+
+// bytes per role, missing or 0 is unlimited
+type StorageQuotaConfig map[model.RoleType]int64
+
+// StorageUsageConfig is how often usage is rebuilt from files on disk, recordings written by be-api are only counted then
+type StorageUsageConfig struct {
+	RecalculateInterval int `yaml:"recalculate_interval"` // in seconds, 0 disables the background job
+}
+
+type FileGCConfig struct {
+	Interval    int `yaml:"interval"`     // in seconds, 0 disables the background job
+	GracePeriod int `yaml:"grace_period"` // in seconds, newer files are never collected
+}
+
+type RetentionConfig struct {
+	Interval int `yaml:"interval"` // in seconds, 0 disables the background job
+}
+
+type SchedulerConfig struct {
+	Interval           int      `yaml:"interval"`       // in seconds, 0 disables the scheduler
+	LockTTL            int      `yaml:"lock_ttl"`       // in seconds, of the leader lock which is renewed every third of it
+	MaxRetries         uint     `yaml:"max_retries"`    // attempts after the first failed push
+	RetryDelay         int      `yaml:"retry_delay"`    // in seconds
+	MaxConcurrent      int      `yaml:"max_concurrent"` // 0 is unlimited
+	ClaimTimeout       int      `yaml:"claim_timeout"`  // in seconds, streams of an instance which died mid-push are reset after it
+	Pusher             string   `yaml:"pusher"`         // ffmpeg or fake
+	FFmpegPath         string   `yaml:"ffmpeg_path"`
+	FFmpegArgs         []string `yaml:"ffmpeg_args"`          // {input} and {output} are replaced by video path and push url
+	FFmpegPlaylistArgs []string `yaml:"ffmpeg_playlist_args"` // output of playlists, inputs and the concat filter are added before them
+	PlaylistWidth      int      `yaml:"playlist_width"`       // playlist items are scaled to it
+	PlaylistHeight     int      `yaml:"playlist_height"`
+}
+
+type ScheduleConfig struct {
+	Window             int `yaml:"window"`              // in seconds, how far ahead a stream can be scheduled
+	RecurrenceHorizon  int `yaml:"recurrence_horizon"`  // in seconds, occurrences are created this far ahead, at least window
+	RecurrenceInterval int `yaml:"recurrence_interval"` // in seconds, 0 disables the background job
+	// conflict detection
+	FFprobePath     string `yaml:"ffprobe_path"`     // probes video duration on upload
+	ProbeTimeout    int    `yaml:"probe_timeout"`    // in seconds, uploads fall back to the default duration after it
+	DefaultDuration int    `yaml:"default_duration"` // in seconds, used when video duration is unknown
+	CategorySlots   int    `yaml:"category_slots"`   // streams of a category at the same time, 0 is unlimited
+	MaxConcurrent   int    `yaml:"max_concurrent"`   // scheduled streams at the same time, 0 is unlimited
+	FeedExpiration  int    `yaml:"feed_expiration"`  // in seconds, lifetime of signed .ics feed urls
+}
+
+type ClipConfig struct {
+	FFmpegPath         string   `yaml:"ffmpeg_path"`
+	FFmpegArgs         []string `yaml:"ffmpeg_args"`          // {input}, {output}, {start} and {duration} are replaced, stream copy by default
+	FFmpegReencodeArgs []string `yaml:"ffmpeg_reencode_args"` // used when stream copy fails
+	Timeout            int      `yaml:"timeout"`              // in seconds
+}
+
+type JobConfig struct {
+	Interval    int `yaml:"interval"`     // in seconds, 0 is the default and negative disables the worker of this instance
+	Concurrency int `yaml:"concurrency"`  // jobs run at the same time by this instance
+	LockTimeout int `yaml:"lock_timeout"` // in seconds, jobs of a dead worker are queued again after it or fail when attempts ran out
+	RetryDelay  int `yaml:"retry_delay"`  // in seconds, multiplied by attempts
+}
+
+type TrashConfig struct {
+	PurgeDelay int `yaml:"purge_delay"` // in seconds, deleted users and streams can be restored until a job purges them
+}
+
+type GDPRConfig struct {
+	SigningKey       string `yaml:"signing_key"`       // signs completion reports of export and erasure requests
+	ExportExpiration int    `yaml:"export_expiration"` // in seconds, export zips are removed after it
+}
+
+type MailConfig struct {
+	Email    string `yaml:"email"`
+	Password string `yaml:"password"`
+	Host     string `yaml:"host"` // empty logs mails instead of sending them
+	Port     int    `yaml:"port"`
+}
+
+type InviteConfig struct {
+	URL        string `yaml:"url"`        // page of the client where invited users set their password
+	Expiration int    `yaml:"expiration"` // in seconds, of each emailed link
+}
+
+// in seconds, reports should be resolved within the SLA of their severity
+type ReportConfig struct {
+	HighSLA   int `yaml:"high_sla"`
+	MediumSLA int `yaml:"medium_sla"`
+	LowSLA    int `yaml:"low_sla"`
+}
+
+type SuspensionConfig struct {
+	Interval   int `yaml:"interval"`    // in seconds, how often ended suspensions are reinstated, 0 uses DEFAULT_SUSPENSION_INTERVAL
+	MaxStrikes int `yaml:"max_strikes"` // the strike that reaches it blocks permanently
 }
 
 type ClientConfig struct {
 	Host string `yaml:"host"`
 }
 type ApiFileConfig struct {
-	Url string `yaml:"url"`
+	Url           string `yaml:"url"`
+	SecretKey     string `yaml:"secret_key"`
+	URLExpiration int    `yaml:"url_expiration"` // in seconds
 }
 
 type StreamServerConfig struct {
@@ -68,6 +176,8 @@ type FileStorageConfig struct {
 	LiveFolder            string `yaml:"live_folder"`
 	ScheduledVideosFolder string `yaml:"scheduled_videos_folder"`
 	VideoFolder           string `yaml:"video_folder"`
+	ClipsFolder           string `yaml:"clips_folder"`
+	ExportsFolder         string `yaml:"exports_folder"` // served as /api/file/exports/
 }
 
 func LoadYaml(path string) (*Config, error) {
@@ -172,3 +282,59 @@ func GetStreamServerConfig() *StreamServerConfig {
 }
 
 func GetApiFileConfig() *ApiFileConfig { return &cfg.ApiFile }
+
+func GetFileGCConfig() *FileGCConfig {
+	return &cfg.FileGC
+}
+
+func GetStorageQuotaConfig() StorageQuotaConfig {
+	return cfg.StorageQuota
+}
+
+func GetStorageUsageConfig() *StorageUsageConfig {
+	return &cfg.StorageUsage
+}
+
+func GetRetentionConfig() *RetentionConfig {
+	return &cfg.Retention
+}
+
+func GetSchedulerConfig() *SchedulerConfig {
+	return &cfg.Scheduler
+}
+
+func GetScheduleConfig() *ScheduleConfig {
+	return &cfg.Schedule
+}
+
+func GetClipConfig() *ClipConfig {
+	return &cfg.Clip
+}
+
+func GetJobConfig() *JobConfig {
+	return &cfg.Job
+}
+
+func GetTrashConfig() *TrashConfig {
+	return &cfg.Trash
+}
+
+func GetGDPRConfig() *GDPRConfig {
+	return &cfg.GDPR
+}
+
+func GetMailConfig() *MailConfig {
+	return &cfg.Mail
+}
+
+func GetInviteConfig() *InviteConfig {
+	return &cfg.Invite
+}
+
+func GetReportConfig() *ReportConfig {
+	return &cfg.Report
+}
+
+func GetSuspensionConfig() *SuspensionConfig {
+	return &cfg.Suspension
+}
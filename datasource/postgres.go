@@ -17,7 +17,9 @@ func LoadDB() (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.New(postgres.Config{
 		DSN:                  dsn,
 		PreferSimpleProtocol: true,
-	}), &gorm.Config{})
+	}), &gorm.Config{
+		TranslateError: true, // unique violations are gorm.ErrDuplicatedKey
+	})
 
 	if err != nil {
 		return nil, err
@@ -36,6 +38,7 @@ func LoadDB() (*gorm.DB, error) {
 	if err := db.AutoMigrate(
 		&model.Category{},
 		&model.View{},
+		&model.StreamRecurrence{},
 		&model.ScheduleStream{},
 		&model.Bookmark{},
 	); err != nil {
@@ -56,6 +59,22 @@ func LoadDB() (*gorm.DB, error) {
 	if err := db.AutoMigrate(
 		&model.TwoFA{},
 		&model.StreamCategory{},
+		&model.StorageUsage{},
+		&model.RetentionPolicy{},
+		&model.StreamRecurrenceException{},
+		&model.ScheduleStreamItem{},
+		&model.Clip{},
+		&model.Job{},
+		&model.GDPRRequest{},
+		&model.Invite{},
+		&model.UserImportCredential{},
+		&model.CommentFilterRule{},
+		&model.Report{},
+		&model.UserStrike{},
+		&model.PublishBan{},
+		&model.UserSegment{},
+		&model.Announcement{},
+		&model.NotificationTemplate{},
 	); err != nil {
 		return nil, err
 	}
@@ -65,6 +84,19 @@ func LoadDB() (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// unique index on category_id allows many NULLs, so at most one global policy is enforced here
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_retention_policies_global ON retention_policies ((category_id IS NULL)) WHERE category_id IS NULL").Error; err != nil {
+		return nil, err
+	}
+
+	// pending invites reserve their username and email, accepted and revoked ones don't
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_invites_pending_username ON invites (username) WHERE status = 'pending'").Error; err != nil {
+		return nil, err
+	}
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_invites_pending_email ON invites (LOWER(email)) WHERE status = 'pending'").Error; err != nil {
+		return nil, err
+	}
+
 	return db, nil
 
 }
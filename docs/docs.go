@@ -222,14 +222,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/auth/forgetPassword": {
-            "post": {
+        "/api/announcements": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Generates an OTP for password reset",
+                "description": "Get announcements, latest scheduled first",
                 "consumes": [
                     "application/json"
                 ],
@@ -237,36 +237,73 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Announcements"
                 ],
-                "summary": "Forget Password",
+                "summary": "Get announcements",
                 "parameters": [
                     {
-                        "description": "Forget Password DTO",
-                        "name": "forgetPasswordDTO",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ForgetPasswordDTO"
-                        }
+                        "maxLength": 100,
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "scheduled",
+                            "sending",
+                            "sent",
+                            "canceled",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "AnnouncementStatusScheduled",
+                            "AnnouncementStatusSending",
+                            "AnnouncementStatusSent",
+                            "AnnouncementStatusCanceled",
+                            "AnnouncementStatusFailed"
+                        ],
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OTP generated successfully"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_AnnouncementDTO"
+                        }
                     },
                     "400": {
-                        "description": "Email not found"
+                        "description": "Invalid request"
                     },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            }
-        },
-        "/api/auth/login": {
+            },
             "post": {
-                "description": "Authenticates the user and returns a JWT token",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Notify all users, users of a role, viewers of a category's streams or a saved segment. A job sends it at scheduled_at, or right away without it. Blocked users are left out.",
                 "consumes": [
                     "application/json"
                 ],
@@ -274,41 +311,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Announcements"
                 ],
-                "summary": "Login a user",
+                "summary": "Create an announcement",
                 "parameters": [
                     {
-                        "description": "User Login Data",
-                        "name": "login",
+                        "description": "Create Announcement Request",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginDTO"
+                            "$ref": "#/definitions/dto.CreateAnnouncementRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Login successful",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginResponse"
+                            "$ref": "#/definitions/dto.AnnouncementDTO"
                         }
                     },
                     "400": {
                         "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/api/auth/logout": {
-            "post": {
+        "/api/announcements/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Logout the current user and invalidate the token",
+                "description": "Get an announcement with how many users it was sent to so far",
                 "consumes": [
                     "application/json"
                 ],
@@ -316,12 +356,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Announcements"
+                ],
+                "summary": "Get an announcement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Announcement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Logout user",
                 "responses": {
                     "200": {
-                        "description": "Logout successful"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AnnouncementDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -329,14 +387,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/auth/resetPassword": {
+        "/api/announcements/{id}/cancel": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Resets the user's password using OTP",
+                "description": "Cancel a scheduled announcement, or stop one which is being sent. Notifications sent already are kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -344,29 +402,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Announcements"
                 ],
-                "summary": "Reset Password",
+                "summary": "Cancel an announcement",
                 "parameters": [
                     {
-                        "description": "Reset Password DTO",
-                        "name": "resetPasswordDTO",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ResetPasswordDTO"
-                        }
+                        "type": "integer",
+                        "description": "Announcement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Password reset successfully"
+                        "description": "Successfully"
                     },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Invalid request"
                     },
                     "404": {
-                        "description": "Email not found"
+                        "description": "Not found"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -374,14 +430,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/categories": {
+        "/api/announcements/{id}/stats": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get a list of all categories",
+                "description": "Count notifications of an announcement which were delivered, read and hidden",
                 "consumes": [
                     "application/json"
                 ],
@@ -389,79 +445,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Announcements"
                 ],
-                "summary": "Get all categories",
+                "summary": "Get delivery stats of an announcement",
                 "parameters": [
                     {
-                        "maxLength": 255,
-                        "type": "string",
-                        "name": "created_by",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 99999,
-                        "minimum": 1,
-                        "type": "integer",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "maxLength": 255,
-                        "type": "string",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
                         "type": "integer",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "DESC",
-                            "ASC"
-                        ],
-                        "type": "string",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "created_at",
-                            "updated_at",
-                            "name",
-                            "created_by",
-                            "updated_by"
-                        ],
-                        "type": "string",
-                        "name": "sort_by",
-                        "in": "query"
+                        "description": "Announcement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.PaginationModel-dto_CategoryRespDto"
+                            "$ref": "#/definitions/dto.AnnouncementStatsDTO"
                         }
                     },
                     "400": {
                         "description": "Invalid request"
                     },
+                    "404": {
+                        "description": "Not found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
+            }
+        },
+        "/api/auth/forgetPassword": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new category",
+                "description": "Generates an OTP for password reset",
                 "consumes": [
                     "application/json"
                 ],
@@ -469,26 +491,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Auth"
                 ],
-                "summary": "Create a new category",
+                "summary": "Forget Password",
                 "parameters": [
                     {
-                        "description": "Category Request",
-                        "name": "request",
+                        "description": "Forget Password DTO",
+                        "name": "forgetPasswordDTO",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CategoryRequestDTO"
+                            "$ref": "#/definitions/dto.ForgetPasswordDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully"
+                    "200": {
+                        "description": "OTP generated successfully"
                     },
                     "400": {
-                        "description": "Invalid request"
+                        "description": "Email not found"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -496,14 +518,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/categories/{id}": {
-            "put": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Update a category by ID",
+        "/api/auth/login": {
+            "post": {
+                "description": "Authenticates the user and returns a JWT token",
                 "consumes": [
                     "application/json"
                 ],
@@ -511,49 +528,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Auth"
                 ],
-                "summary": "Update a category",
+                "summary": "Login a user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Category Update Request",
-                        "name": "request",
+                        "description": "User Login Data",
+                        "name": "login",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CategoryUpdateRequestDTO"
+                            "$ref": "#/definitions/dto.LoginDTO"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Login successful",
                         "schema": {
-                            "$ref": "#/definitions/dto.CategoryRespDto"
+                            "$ref": "#/definitions/dto.LoginResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID parameter"
-                    },
-                    "500": {
-                        "description": "Internal Server Error"
+                        "description": "Invalid request"
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a category by ID",
+                "description": "Logout the current user and invalidate the token",
                 "consumes": [
                     "application/json"
                 ],
@@ -561,24 +570,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
-                ],
-                "summary": "Delete a category",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Auth"
                 ],
+                "summary": "Logout user",
                 "responses": {
                     "200": {
-                        "description": "Successfully"
-                    },
-                    "400": {
-                        "description": "Invalid ID parameter"
+                        "description": "Logout successful"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -586,14 +583,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/streams": {
-            "get": {
-                "security": [
+        "/api/auth/resetPassword": {
+            "post": {
+                "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get live stream broadcast data with pagination",
+                "description": "Resets the user's password using OTP",
                 "consumes": [
                     "application/json"
                 ],
@@ -601,54 +598,143 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Streams"
+                    "Auth"
                 ],
-                "summary": "Get live streams with pagination",
+                "summary": "Reset Password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "category",
-                        "in": "query"
+                        "description": "Reset Password DTO",
+                        "name": "resetPasswordDTO",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResetPasswordDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password reset successfully"
                     },
-                    {
-                        "type": "integer",
-                        "name": "end_ended_time",
-                        "in": "query"
+                    "400": {
+                        "description": "Bad Request"
                     },
-                    {
-                        "type": "integer",
-                        "name": "end_started_time",
-                        "in": "query"
+                    "404": {
+                        "description": "Email not found"
                     },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/calendar/categories/{id}/feed.ics": {
+            "get": {
+                "description": "iCalendar feed of scheduled streams of a category, opened with the signed url from feed-url",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get category calendar feed",
+                "parameters": [
                     {
                         "type": "integer",
-                        "name": "from_ended_time",
-                        "in": "query"
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed"
+                    },
+                    "403": {
+                        "description": "Invalid signature"
                     },
+                    "404": {
+                        "description": "Not found"
+                    }
+                }
+            }
+        },
+        "/api/calendar/streamers/{id}/feed.ics": {
+            "get": {
+                "description": "iCalendar feed of scheduled streams of a streamer, opened with the signed url from feed-url",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get streamer calendar feed",
+                "parameters": [
                     {
                         "type": "integer",
-                        "name": "from_started_time",
-                        "in": "query"
+                        "description": "Streamer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed"
                     },
+                    "403": {
+                        "description": "Invalid signature"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    }
+                }
+            }
+        },
+        "/api/categories": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a list of all categories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Get all categories",
+                "parameters": [
                     {
+                        "maxLength": 255,
                         "type": "string",
-                        "name": "keyword",
+                        "name": "created_by",
                         "in": "query"
                     },
                     {
-                        "maximum": 20,
+                        "maximum": 99999,
                         "minimum": 1,
                         "type": "integer",
                         "name": "limit",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "name",
+                        "in": "query"
                     },
                     {
                         "minimum": 1,
                         "type": "integer",
                         "name": "page",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "enum": [
@@ -661,61 +747,22 @@ const docTemplate = `{
                     },
                     {
                         "enum": [
-                            "title",
-                            "started_at",
-                            "ended_at",
-                            "views",
-                            "shares",
-                            "likes",
-                            "comments",
-                            "video_size",
-                            "duration",
-                            "shares",
-                            "created_at"
+                            "created_at",
+                            "updated_at",
+                            "name",
+                            "created_by",
+                            "updated_by"
                         ],
                         "type": "string",
                         "name": "sort_by",
                         "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "enum": [
-                                "pending",
-                                "started",
-                                "ended",
-                                "upcoming"
-                            ],
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "camera",
-                            "software",
-                            "pre_record"
-                        ],
-                        "type": "string",
-                        "x-enum-comments": {
-                            "SOFTWARESTREAM": "like obs"
-                        },
-                        "x-enum-varnames": [
-                            "CAMERASTREAM",
-                            "SOFTWARESTREAM",
-                            "PRERECORDSTREAM"
-                        ],
-                        "name": "type",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.PaginationModel-dto_LiveStreamBroadCastDTO"
+                            "$ref": "#/definitions/utils.PaginationModel-dto_CategoryRespDto"
                         }
                     },
                     "400": {
@@ -732,67 +779,31 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Create a live stream by admin with thumbnail and video upload",
+                "description": "Create a new category",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Streams"
+                    "Categories"
                 ],
-                "summary": "Create a live stream by admin",
+                "summary": "Create a new category",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "userID",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Stream Title",
-                        "name": "title",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Stream Description",
-                        "name": "description",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Scheduled At",
-                        "name": "scheduledAt",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Thumbnail image file",
-                        "name": "thumbnail",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Video file",
-                        "name": "video",
-                        "in": "formData",
-                        "required": true
+                        "description": "Category Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryRequestDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateStreamResponseDTO"
-                        }
+                        "description": "Successfully"
                     },
                     "400": {
                         "description": "Invalid request"
@@ -803,14 +814,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/streams/live-statistics": {
-            "get": {
+        "/api/categories/{id}": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get live stream statistics data with pagination",
+                "description": "Update a category by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -818,63 +829,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Streams"
+                    "Categories"
                 ],
-                "summary": "Get live stream statistics with pagination",
+                "summary": "Update a category",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "keyword",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "maximum": 20,
-                        "minimum": 1,
-                        "type": "integer",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
+                        "description": "Category Update Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryUpdateRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryRespDto"
+                        }
                     },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                    "400": {
+                        "description": "Invalid ID parameter"
                     },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
                     {
-                        "enum": [
-                            "DESC",
-                            "ASC"
-                        ],
-                        "type": "string",
-                        "name": "sort",
-                        "in": "query"
-                    },
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a category by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Delete a category",
+                "parameters": [
                     {
-                        "enum": [
-                            "total_viewers",
-                            "likes",
-                            "comments",
-                            "current_viewers",
-                            "shares",
-                            "title",
-                            "description"
-                        ],
-                        "type": "string",
-                        "name": "sort_by",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/utils.PaginationModel-dto_LiveStatRespDTO"
-                        }
+                        "description": "Successfully"
                     },
                     "400": {
-                        "description": "Invalid request"
+                        "description": "Invalid ID parameter"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -882,14 +904,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/streams/statistics": {
+        "/api/comment-filters": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get statistics data for live streams",
+                "description": "Get keyword and regex rules which be-api applies to new comments",
                 "consumes": [
                     "application/json"
                 ],
@@ -897,16 +919,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Streams"
+                    "Comment Filters"
                 ],
-                "summary": "Get live stream statistics data",
+                "summary": "Get comment filter rules",
                 "parameters": [
                     {
+                        "enum": [
+                            "hide",
+                            "flag",
+                            "block_user"
+                        ],
+                        "type": "string",
+                        "x-enum-comments": {
+                            "CommentFilterActionBlockUser": "after HitThreshold matching comments of a user"
+                        },
+                        "x-enum-varnames": [
+                            "CommentFilterActionHide",
+                            "CommentFilterActionFlag",
+                            "CommentFilterActionBlockUser"
+                        ],
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
                         "type": "integer",
-                        "name": "from",
+                        "name": "categoryID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "enabled",
                         "in": "query"
                     },
                     {
+                        "maxLength": 255,
                         "type": "string",
                         "name": "keyword",
                         "in": "query"
@@ -925,44 +972,56 @@ const docTemplate = `{
                         "name": "page",
                         "in": "query",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_CommentFilterRuleDTO"
+                        }
                     },
-                    {
-                        "enum": [
-                            "DESC",
-                            "ASC"
-                        ],
-                        "type": "string",
-                        "name": "sort",
-                        "in": "query"
+                    "400": {
+                        "description": "Invalid request"
                     },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
                     {
-                        "enum": [
-                            "title",
-                            "created_at",
-                            "views",
-                            "likes",
-                            "comments",
-                            "video_size",
-                            "duration",
-                            "shares",
-                            "stream_id",
-                            "id"
-                        ],
-                        "type": "string",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a global rule (no category_id) or a rule for streams of a category, hit_threshold is required for block_user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comment Filters"
+                ],
+                "summary": "Create a comment filter rule",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "name": "to",
-                        "in": "query"
+                        "description": "Comment Filter Rule Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CommentFilterRuleRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/utils.PaginationModel-dto_LiveStreamRespDTO"
+                            "$ref": "#/definitions/dto.CommentFilterRuleDTO"
                         }
                     },
                     "400": {
@@ -974,14 +1033,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/streams/statistics/day": {
-            "get": {
+        "/api/comment-filters/evaluate": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get statistics data for live streams in a specific day",
+                "description": "Preview what rules, enabled or not, would match in stored comments, newest first. Nothing is changed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -989,29 +1048,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Streams"
+                    "Comment Filters"
                 ],
-                "summary": "Get live stream statistics data in a day",
+                "summary": "Evaluate comment filter rules",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "targeted_date",
-                        "in": "query",
-                        "required": true
+                        "description": "Comment Filter Evaluate Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CommentFilterEvaluateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.LiveStatRespInDayDTO"
-                            }
+                            "$ref": "#/definitions/dto.CommentFilterEvaluationDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid Request"
+                        "description": "Invalid request"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -1019,14 +1078,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/streams/statistics/total": {
+        "/api/comment-filters/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get total statistics data for live streams",
+                "description": "Get a comment filter rule",
                 "consumes": [
                     "application/json"
                 ],
@@ -1034,30 +1093,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Streams"
+                    "Comment Filters"
+                ],
+                "summary": "Get a comment filter rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get total live stream statistics",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.StatisticsTotalLiveStreamDTO"
+                            "$ref": "#/definitions/dto.CommentFilterRuleDTO"
                         }
                     },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            }
-        },
-        "/api/users": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get a paginated list of users based on the provided query parameters",
+                "description": "Replace a comment filter rule, be-api picks up the change immediately",
                 "consumes": [
                     "application/json"
                 ],
@@ -1065,106 +1137,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Comment Filters"
                 ],
-                "summary": "Get paginated list of users",
+                "summary": "Update a comment filter rule",
                 "parameters": [
                     {
-                        "maxLength": 255,
-                        "type": "string",
-                        "name": "created_by",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "maxLength": 255,
-                        "type": "string",
-                        "name": "keyword",
-                        "in": "query"
+                        "description": "Comment Filter Rule Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CommentFilterRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CommentFilterRuleDTO"
+                        }
                     },
-                    {
-                        "maximum": 20,
-                        "minimum": 1,
-                        "type": "integer",
-                        "name": "limit",
-                        "in": "query"
+                    "400": {
+                        "description": "Invalid request"
                     },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maxLength": 255,
-                        "minLength": 3,
-                        "type": "string",
-                        "name": "reason",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "admin",
-                            "streamer",
-                            "user"
-                        ],
-                        "type": "string",
-                        "name": "role",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "DESC",
-                            "ASC"
-                        ],
-                        "type": "string",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "created_at",
-                            "updated_at",
-                            "username",
-                            "email",
-                            "display_name"
-                        ],
-                        "type": "string",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "online",
-                            "offline",
-                            "blocked"
-                        ],
-                        "type": "string",
-                        "name": "status",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/utils.PaginationModel-dto_UserResponseDTO"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request"
+                    "404": {
+                        "description": "Not found"
                     },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new user with the provided details",
+                "description": "Delete a comment filter rule, comments it hid or flagged stay as they are",
                 "consumes": [
                     "application/json"
                 ],
@@ -1172,87 +1190,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Comment Filters"
                 ],
-                "summary": "Create a new user",
+                "summary": "Delete a comment filter rule",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "User Avatar",
-                        "name": "avatar",
-                        "in": "formData"
-                    },
-                    {
-                        "maxLength": 100,
-                        "minLength": 3,
-                        "type": "string",
-                        "name": "display_name",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "maxLength": 100,
-                        "type": "string",
-                        "name": "email",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "maxLength": 255,
-                        "minLength": 8,
-                        "type": "string",
-                        "name": "password",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "enum": [
-                            "super_admin",
-                            "admin",
-                            "streamer",
-                            "user"
-                        ],
-                        "type": "string",
-                        "x-enum-varnames": [
-                            "SUPPERADMINROLE",
-                            "ADMINROLE",
-                            "STREAMER",
-                            "USERROLE"
-                        ],
-                        "name": "role_type",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "maxLength": 50,
-                        "minLength": 3,
-                        "type": "string",
-                        "name": "username",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "Successfully"
                     },
                     "400": {
                         "description": "Invalid request"
                     },
+                    "404": {
+                        "description": "Not found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/api/users/statistics": {
+        "/api/comments": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get statistics for users based on the provided criteria",
+                "description": "Get comments for moderation, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1260,10 +1233,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Comments"
                 ],
-                "summary": "Get user statistics",
+                "summary": "Get comments",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "name": "flagged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix time",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "hidden",
+                        "in": "query"
+                    },
                     {
                         "maxLength": 255,
                         "type": "string",
@@ -1275,43 +1264,31 @@ const docTemplate = `{
                         "minimum": 1,
                         "type": "integer",
                         "name": "limit",
-                        "in": "query"
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "minimum": 1,
                         "type": "integer",
                         "name": "page",
-                        "in": "query"
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "enum": [
-                            "user",
-                            "streamer"
-                        ],
-                        "type": "string",
-                        "name": "role_type",
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "streamID",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "DESC",
-                            "ASC"
-                        ],
-                        "type": "string",
-                        "name": "sort",
+                        "type": "integer",
+                        "name": "to",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "username",
-                            "display_name",
-                            "total_streams",
-                            "total_likes",
-                            "total_comments",
-                            "total_views"
-                        ],
-                        "type": "string",
-                        "name": "sort_by",
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "userID",
                         "in": "query"
                     }
                 ],
@@ -1319,7 +1296,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.PaginationModel-dto_UserStatisticsResponse"
+                            "$ref": "#/definitions/utils.PaginationModel-dto_CommentDTO"
                         }
                     },
                     "400": {
@@ -1331,14 +1308,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/usernames": {
+        "/api/comments/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get a list of all usernames",
+                "description": "Get a comment with its moderation state",
                 "consumes": [
                     "application/json"
                 ],
@@ -1346,33 +1323,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Comments"
+                ],
+                "summary": "Get a comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get list of usernames",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/dto.CommentDTO"
                         }
                     },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            }
-        },
-        "/api/users/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get a user by their ID",
+                "description": "Delete a comment, be-api removes it from live chat",
                 "consumes": [
                     "application/json"
                 ],
@@ -1380,13 +1367,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Comments"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Delete a comment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1394,26 +1381,28 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.UserResponseDTO"
-                        }
+                        "description": "Successfully"
                     },
                     "400": {
-                        "description": "Invalid ID parameter"
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
                     },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/comments/{id}/hide": {
+            "patch": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update the details of a user by their ID",
+                "description": "Hide a comment, be-api removes it from live chat",
                 "consumes": [
                     "application/json"
                 ],
@@ -1421,33 +1410,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Comments"
                 ],
-                "summary": "Update user details",
+                "summary": "Hide a comment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update User Request",
-                        "name": "UpdateUserRequest",
+                        "description": "Hide Comment Request",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                            "$ref": "#/definitions/dto.HideCommentRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserResponse"
-                        }
+                        "description": "Successfully"
                     },
                     "400": {
                         "description": "Invalid request"
@@ -1459,14 +1445,16 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/comments/{id}/unhide": {
+            "patch": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a user by their ID",
+                "description": "Show a hidden comment again",
                 "consumes": [
                     "application/json"
                 ],
@@ -1474,13 +1462,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Comments"
                 ],
-                "summary": "Delete user by ID",
+                "summary": "Unhide a comment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1491,7 +1479,7 @@ const docTemplate = `{
                         "description": "Successfully"
                     },
                     "400": {
-                        "description": "Invalid ID parameter"
+                        "description": "Invalid request"
                     },
                     "404": {
                         "description": "Not found"
@@ -1502,14 +1490,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/{id}/change-avatar": {
-            "patch": {
+        "/api/gdpr/policy": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Change the avatar of a user by their ID",
+                "description": "Get what erasure does to each record of a user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1517,52 +1505,115 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "GDPR"
                 ],
-                "summary": "Change user avatar",
-                "parameters": [
-                    {
+                "summary": "Get erasure policy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.GDPRPolicyEntry"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/gdpr/requests": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get requests with their signed completion reports, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GDPR"
+                ],
+                "summary": "Get export and erasure requests",
+                "parameters": [
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
+                        "name": "limit",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "User Avatar",
-                        "name": "avatar",
-                        "in": "formData",
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "completed",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "GDPRRequestStatusPending",
+                            "GDPRRequestStatusCompleted",
+                            "GDPRRequestStatusFailed"
+                        ],
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "export",
+                            "erasure"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "GDPRRequestTypeExport",
+                            "GDPRRequestTypeErasure"
+                        ],
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "userID",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserResponse"
+                            "$ref": "#/definitions/utils.PaginationModel-dto_GDPRRequestDTO"
                         }
                     },
                     "400": {
                         "description": "Invalid request"
                     },
-                    "404": {
-                        "description": "Not found"
-                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/api/users/{id}/change-password": {
-            "patch": {
+        "/api/gdpr/requests/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Change the password of a user by their ID",
+                "description": "Get a request with its signed completion report and the download url of exports",
                 "consumes": [
                     "application/json"
                 ],
@@ -1570,32 +1621,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "GDPR"
                 ],
-                "summary": "Change user password",
+                "summary": "Get an export or erasure request",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Change Password Request",
-                        "name": "ChangePasswordRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ChangePasswordRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserResponse"
+                            "$ref": "#/definitions/dto.GDPRRequestDTO"
                         }
                     },
                     "400": {
@@ -1610,14 +1652,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/{id}/deactive": {
-            "patch": {
+        "/api/invites": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Deactive a user by their ID",
+                "description": "Get invites, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1625,36 +1667,185 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Invites"
                 ],
-                "summary": "Deactive user by ID",
+                "summary": "Get invites",
                 "parameters": [
                     {
+                        "maxLength": 100,
+                        "type": "string",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
+                        "name": "limit",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Deactive User",
-                        "name": "DeactiveUserRequest",
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "accepted",
+                            "revoked"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "InviteStatusPending",
+                            "InviteStatusAccepted",
+                            "InviteStatusRevoked"
+                        ],
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_InviteDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Reserve a username and email and email a link where the invitee sets their password, the user is created on accept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Invite a user",
+                "parameters": [
+                    {
+                        "description": "Create Invite Request",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.DeactiveUserRequest"
+                            "$ref": "#/definitions/dto.CreateInviteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.InviteCreatedDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "409": {
+                        "description": "Username or email was just taken"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/invites/accept": {
+            "post": {
+                "description": "Create the invited user with the password chosen by the invitee, the token comes from the invite email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Accept an invite",
+                "parameters": [
+                    {
+                        "description": "Accept Invite DTO",
+                        "name": "acceptInviteDTO",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AcceptInviteDTO"
                         }
                     }
                 ],
+                "responses": {
+                    "201": {
+                        "description": "Invite accepted successfully"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "409": {
+                        "description": "Username or email was just taken"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/invites/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get an invite with its email delivery status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Get an invite",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invite ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserResponse"
+                            "$ref": "#/definitions/dto.InviteDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID parameter"
+                        "description": "Invalid request"
                     },
                     "404": {
                         "description": "Not found"
@@ -1665,14 +1856,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/{id}/reactive": {
-            "patch": {
+        "/api/invites/{id}/resend": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Reactive a user by their ID",
+                "description": "Email a new link of a pending invite, the previous link stops working",
                 "consumes": [
                     "application/json"
                 ],
@@ -1680,27 +1871,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Invites"
                 ],
-                "summary": "Reactive user by ID",
+                "summary": "Resend an invite",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Invite ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserResponse"
+                            "$ref": "#/definitions/dto.InviteCreatedDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID parameter"
+                        "description": "Invalid request"
                     },
                     "404": {
                         "description": "Not found"
@@ -1710,668 +1901,9312 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AdminLogRespDTO": {
-            "type": "object",
-            "properties": {
-                "action": {
-                    "type": "string"
-                },
-                "details": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "performed_at": {
-                    "type": "string"
-                },
+        },
+        "/api/invites/{id}/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke a pending invite, its link stops working and the username and email are released",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Revoke an invite",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invite ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get background jobs with their status and progress, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Get background jobs",
+                "parameters": [
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "queued",
+                            "running",
+                            "succeeded",
+                            "failed",
+                            "canceled"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "JobStatusQueued",
+                            "JobStatusRunning",
+                            "JobStatusSucceeded",
+                            "JobStatusFailed",
+                            "JobStatusCanceled"
+                        ],
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "remove_files",
+                            "cut_clip",
+                            "bulk_users",
+                            "bulk_streams",
+                            "purge_user",
+                            "purge_stream",
+                            "gdpr_export",
+                            "gdpr_erasure",
+                            "import_users",
+                            "send_invite",
+                            "send_announcement"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "JobTypeRemoveFiles",
+                            "JobTypeCutClip",
+                            "JobTypeBulkUsers",
+                            "JobTypeBulkStreams",
+                            "JobTypePurgeUser",
+                            "JobTypePurgeStream",
+                            "JobTypeGDPRExport",
+                            "JobTypeGDPRErasure",
+                            "JobTypeImportUsers",
+                            "JobTypeSendInvite",
+                            "JobTypeSendAnnouncement"
+                        ],
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_JobDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a background job by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Get a background job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JobDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/jobs/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Queued jobs are canceled right away, running jobs stop within the lock timeout of their worker",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Cancel a background job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Job already finished"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/notification-templates": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the latest version of every notification template, newest versions are used to render notifications",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification Templates"
+                ],
+                "summary": "Get notification templates",
+                "parameters": [
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 20,
+                        "type": "string",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "subscribe_live",
+                            "subscribe_video",
+                            "account_blocked",
+                            "account_deleted",
+                            "announcement"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "NotificationTypeSubscribeLive",
+                            "NotificationTypeSubscribeVideo",
+                            "NotificationTypeBlocked",
+                            "NotificationTypeDeleted",
+                            "NotificationTypeAnnouncement"
+                        ],
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_NotificationTemplateDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/notification-templates/preview": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Render a title and body against sample data, or the template a locale falls back to when body is empty. Nothing is saved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification Templates"
+                ],
+                "summary": "Preview a notification template",
+                "parameters": [
+                    {
+                        "description": "Notification Template Preview Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotificationTemplatePreviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RenderedNotificationDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/notification-templates/variables": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the variables templates of each notification type can use with their sample data, and the built in templates used when no locale has one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification Templates"
+                ],
+                "summary": "Get notification template variables",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.NotificationTemplateVariablesDTO"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/notification-templates/{type}/{locale}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Save the template of a type and locale as a new version, it must render with the sample data of the type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification Templates"
+                ],
+                "summary": "Save a notification template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale, like en or pt-BR",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notification Template Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotificationTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotificationTemplateDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "409": {
+                        "description": "Saved by someone else at the same time"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete every version of the template of a type and locale, the locale falls back to its language and the default one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification Templates"
+                ],
+                "summary": "Delete a notification template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale, like en or pt-BR",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/notification-templates/{type}/{locale}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get every version of the template of a type and locale, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification Templates"
+                ],
+                "summary": "Get versions of a notification template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale, like en or pt-BR",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.NotificationTemplateDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/notification-templates/{type}/{locale}/versions/{version}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Save a copy of an earlier version of the template as its newest version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification Templates"
+                ],
+                "summary": "Restore a notification template version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale, like en or pt-BR",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotificationTemplateDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "409": {
+                        "description": "Saved by someone else at the same time"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/reports": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the moderation queue, highest priority first, then the earliest due",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get reports",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "assigneeID",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "unresolved past their SLA",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "self_harm",
+                            "violence",
+                            "hate",
+                            "harassment",
+                            "nudity",
+                            "spam",
+                            "copyright",
+                            "other"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "ReportReasonSelfHarm",
+                            "ReportReasonViolence",
+                            "ReportReasonHate",
+                            "ReportReasonHarassment",
+                            "ReportReasonNudity",
+                            "ReportReasonSpam",
+                            "ReportReasonCopyright",
+                            "ReportReasonOther"
+                        ],
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "high",
+                            "medium",
+                            "low"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "ReportSeverityHigh",
+                            "ReportSeverityMedium",
+                            "ReportSeverityLow"
+                        ],
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "claimed",
+                            "resolved",
+                            "dismissed"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "ReportStatusOpen",
+                            "ReportStatusClaimed",
+                            "ReportStatusResolved",
+                            "ReportStatusDismissed"
+                        ],
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "targetID",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "stream",
+                            "comment",
+                            "user"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "ReportTargetStream",
+                            "ReportTargetComment",
+                            "ReportTargetUser"
+                        ],
+                        "name": "targetType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "unassigned",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_ReportDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/reports/intake": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Called by be-api with the token of the reporting user, the report is queued by severity of its reason",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "File a report",
+                "parameters": [
+                    {
+                        "description": "Create Report Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReportCreatedDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "403": {
+                        "description": "Reporter is blocked"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/reports/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Count the queue now and reports closed per moderator in the range, with SLA breaches and average resolution time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get report stats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "unix time, REPORT_STATS_DEFAULT_DAYS ago when empty",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReportStatsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/reports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a report with its SLA timer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get a report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReportDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/reports/{id}/assign": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Claim a report for an admin, a claimed report is reassigned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Assign a report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Assign Report Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AssignReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/reports/{id}/claim": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Claim an open report for the current admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Claim a report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/reports/{id}/dismiss": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Close a report without action, other reports of the target stay in the queue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Dismiss a report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dismiss Report Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DismissReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/reports/{id}/release": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Put a claimed report back in the queue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Release a report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/reports/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Run the action on the reported target and resolve every unresolved report of it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Resolve a report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resolve Report Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResolveReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReportResolvedDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/retention-policies": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get global and per-category retention policies of ended stream recordings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Retention"
+                ],
+                "summary": "Get all retention policies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RetentionPolicyRespDTO"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create the global policy (no category_id) or a policy for a category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Retention"
+                ],
+                "summary": "Create a retention policy",
+                "parameters": [
+                    {
+                        "description": "Retention Policy Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RetentionPolicyRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/retention-policies/apply": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Apply retention policies now instead of waiting for the scheduled job",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Retention"
+                ],
+                "summary": "Apply retention policies",
+                "responses": {
+                    "200": {
+                        "description": "Number of deleted recordings",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/retention-policies/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update a retention policy by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Retention"
+                ],
+                "summary": "Update a retention policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Retention Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Retention Policy Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RetentionPolicyRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a retention policy by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Retention"
+                ],
+                "summary": "Delete a retention policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Retention Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/segments": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get saved user filters which announcements can target, with how many users match them now",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Segments"
+                ],
+                "summary": "Get user segments",
+                "parameters": [
+                    {
+                        "maxLength": 100,
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_UserSegmentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Save a user filter under a name, page, limit and sort of the filter are ignored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Segments"
+                ],
+                "summary": "Create a user segment",
+                "parameters": [
+                    {
+                        "description": "User Segment Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSegmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSegmentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/segments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a saved user filter with how many users match it now",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Segments"
+                ],
+                "summary": "Get a user segment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Segment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSegmentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Change the name or filter of a segment, announcements to it which are not sent yet use the new filter",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Segments"
+                ],
+                "summary": "Update a user segment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Segment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User Segment Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSegmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSegmentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a segment which no announcement waiting to be sent targets",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Segments"
+                ],
+                "summary": "Delete a user segment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Segment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/storage/usage": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List users by storage usage with breakdown by thumbnails, scheduled videos and recordings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Get storage usage report",
+                "parameters": [
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "user",
+                            "streamer"
+                        ],
+                        "type": "string",
+                        "name": "role_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "DESC",
+                            "ASC"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "total_bytes",
+                            "thumbnail_bytes",
+                            "scheduled_video_bytes",
+                            "recording_bytes",
+                            "updated_at"
+                        ],
+                        "type": "string",
+                        "name": "sort_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_StorageUsageRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/storage/usage/recalculate": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Rebuild storage usage of every streamer from files on disk",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Recalculate storage usage",
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/storage/usage/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get storage usage and quota of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Get storage usage of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StorageUsageRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get live stream broadcast data with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get live streams with pagination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "end_ended_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "end_started_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "from_ended_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "from_started_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "DESC",
+                            "ASC"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "started_at",
+                            "ended_at",
+                            "views",
+                            "shares",
+                            "likes",
+                            "comments",
+                            "video_size",
+                            "duration",
+                            "shares",
+                            "created_at"
+                        ],
+                        "type": "string",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "pending",
+                                "started",
+                                "ended",
+                                "upcoming"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "camera",
+                            "software",
+                            "pre_record"
+                        ],
+                        "type": "string",
+                        "x-enum-comments": {
+                            "SOFTWARESTREAM": "like obs"
+                        },
+                        "x-enum-varnames": [
+                            "CAMERASTREAM",
+                            "SOFTWARESTREAM",
+                            "PRERECORDSTREAM"
+                        ],
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_LiveStreamBroadCastDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a live stream by admin with thumbnail and video upload",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Create a live stream by admin",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stream Title",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stream Description",
+                        "name": "description",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheduled At",
+                        "name": "scheduledAt",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Thumbnail image file",
+                        "name": "thumbnail",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Video file",
+                        "name": "video",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateStreamResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "409": {
+                        "description": "Schedule conflicts with other streams"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete, end live or recategorize streams selected by ids or a filter, the action runs as a job with per-item results",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Run an action on many streams",
+                "parameters": [
+                    {
+                        "description": "Bulk Stream Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkStreamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JobCreatedDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/calendar": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get scheduled streams and upcoming occurrences of recurring schedules between two days, grouped by day",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get calendar of scheduled streams",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "name": "categoryID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "groups occurrences by day there, UTC by default",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "inclusive",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "userID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.CalendarDayDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/calendar/feed-url": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a signed iCalendar (.ics) feed url of a streamer or a category for external calendars",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get calendar feed url",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "name": "categoryID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "userID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CalendarFeedRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    }
+                }
+            }
+        },
+        "/api/streams/live-statistics": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get live stream statistics data with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get live stream statistics with pagination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "DESC",
+                            "ASC"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "total_viewers",
+                            "likes",
+                            "comments",
+                            "current_viewers",
+                            "shares",
+                            "title",
+                            "description"
+                        ],
+                        "type": "string",
+                        "name": "sort_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_LiveStatRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/recurrences": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get recurring schedules of pre-recorded streams with their next occurrences",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get recurring schedules",
+                "parameters": [
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "userID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_StreamRecurrenceRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Schedule a pre-recorded stream repeating by RFC 5545 RRULE (FREQ DAILY, WEEKLY or MONTHLY with INTERVAL, COUNT, UNTIL, BYDAY and BYMONTHDAY). Occurrences are created as upcoming streams ahead of time.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Create a recurring schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stream Title",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stream Description",
+                        "name": "description",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First occurrence",
+                        "name": "start_at",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RRULE, e.g. FREQ=WEEKLY;BYDAY=MO,WE",
+                        "name": "rrule",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone, e.g. Asia/Seoul",
+                        "name": "timezone",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Skipped occurrences",
+                        "name": "exdates",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Category IDs",
+                        "name": "category_ids",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Thumbnail image file",
+                        "name": "thumbnail",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Video file",
+                        "name": "video",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StreamRecurrenceRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "409": {
+                        "description": "Schedule conflicts with other streams"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/recurrences/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a recurring schedule by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get a recurring schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurrence ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StreamRecurrenceRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Edit the occurrence of from_stream_id and all following ones, or every upcoming occurrence when it's omitted. Use stream endpoints to edit a single occurrence.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Update a recurring schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurrence ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Stream Recurrence Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateStreamRecurrenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StreamRecurrenceRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "409": {
+                        "description": "Schedule conflicts with other streams"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a recurring schedule with its upcoming occurrences, started and ended ones are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Delete a recurring schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurrence ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/statistics": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get statistics data for live streams",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get live stream statistics data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "DESC",
+                            "ASC"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "created_at",
+                            "views",
+                            "likes",
+                            "comments",
+                            "video_size",
+                            "duration",
+                            "shares",
+                            "stream_id",
+                            "id"
+                        ],
+                        "type": "string",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_LiveStreamRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/statistics/day": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get statistics data for live streams in a specific day",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get live stream statistics data in a day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "targeted_date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LiveStatRespInDayDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/statistics/total": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get total statistics data for live streams",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get total live stream statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StatisticsTotalLiveStreamDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/{id}/clips": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get clips cut from the recording of a stream, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get clips of a stream",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ClipDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Cut a clip from the recording of an ended stream. The clip is cut by a background job, poll the clip or the job until the status is ready or failed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Create a clip",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Clip Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ClipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ClipDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/{id}/clips/{clipId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a clip with its processing status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get a clip",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Clip ID",
+                        "name": "clipId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ClipDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a clip, its file is removed by a job, clips being processed can't be deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Delete a clip",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Clip ID",
+                        "name": "clipId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JobCreatedDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/{id}/comments/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Count comments, hidden and flagged ones of a stream per interval since it started",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comments"
+                ],
+                "summary": "Get comment timeline of a stream",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 86400,
+                        "minimum": 10,
+                        "type": "integer",
+                        "description": "in seconds, COMMENT_TIMELINE_DEFAULT_INTERVAL when empty",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CommentTimelineDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/{id}/legal-hold": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Streams under legal hold can't be deleted by admins or retention policies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Update legal hold of a stream",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Legal Hold Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LegalHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/{id}/pin": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Recordings of pinned streams are kept by retention policies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Pin or unpin a stream",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pin Stream Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PinStreamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/{id}/playlist": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get items pushed in order for a scheduled stream, empty when only its video is pushed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Get playlist of a scheduled stream",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/{id}/playlist/items": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Upload a video or a slate image to the playlist of an upcoming scheduled stream. The video of the stream becomes the first item of a new playlist.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Add a playlist item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "video or slate",
+                        "name": "type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Position, appended when omitted",
+                        "name": "position",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start of a video in milliseconds",
+                        "name": "in_point",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End of a video in milliseconds, 0 plays until the end",
+                        "name": "out_point",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Shown time of a slate in milliseconds",
+                        "name": "duration",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Video or image file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "409": {
+                        "description": "Schedule conflicts with other streams"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/{id}/playlist/items/{itemId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove an item from the playlist of an upcoming scheduled stream, the last item can't be removed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Delete a playlist item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Playlist item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Move or trim a video, or change the shown time of a slate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Update a playlist item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Playlist item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Playlist Item Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePlaylistItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistRespDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "409": {
+                        "description": "Schedule conflicts with other streams"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/streams/{id}/rebroadcast": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Schedule the recording of an ended stream as a new pre-recorded stream with the same title, description, categories and thumbnail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streams"
+                ],
+                "summary": "Rebroadcast an ended stream",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rebroadcast Stream Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RebroadcastStreamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateStreamResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "409": {
+                        "description": "Schedule conflicts with other streams"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/trash": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get deleted users or streams which are not purged yet, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Get trash",
+                "parameters": [
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "user",
+                            "stream"
+                        ],
+                        "type": "string",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_TrashItemDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/trash/streams/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Restore a stream from trash before it's purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restore a deleted stream",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stream ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found in trash"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/trash/users/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Restore a user from trash before it's purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restore a deleted user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found in trash"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a paginated list of users based on the provided query parameters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get paginated list of users",
+                "parameters": [
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "created_by",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "min_strikes",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "minLength": 3,
+                        "type": "string",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "admin",
+                            "streamer",
+                            "user"
+                        ],
+                        "type": "string",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "DESC",
+                            "ASC"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at",
+                            "username",
+                            "email",
+                            "display_name"
+                        ],
+                        "type": "string",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "online",
+                            "offline",
+                            "blocked"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "temporary",
+                            "permanent"
+                        ],
+                        "type": "string",
+                        "description": "blocked users with or without SuspendedUntil",
+                        "name": "suspension",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_UserResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new user with the provided details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "User Avatar",
+                        "name": "avatar",
+                        "in": "formData"
+                    },
+                    {
+                        "maxLength": 100,
+                        "minLength": 3,
+                        "type": "string",
+                        "name": "display_name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 100,
+                        "type": "string",
+                        "name": "email",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 255,
+                        "minLength": 8,
+                        "type": "string",
+                        "name": "password",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "super_admin",
+                            "admin",
+                            "streamer",
+                            "user"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "SUPPERADMINROLE",
+                            "ADMINROLE",
+                            "STREAMER",
+                            "USERROLE"
+                        ],
+                        "name": "role_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 50,
+                        "minLength": 3,
+                        "type": "string",
+                        "name": "username",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Block, reactivate, delete or change role of users selected by ids or a filter, the action runs as a job with per-item results",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Run an action on many users",
+                "parameters": [
+                    {
+                        "description": "Bulk User Request",
+                        "name": "BulkUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JobCreatedDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/import": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Validate every row of a csv with columns username, email, display_name, role, avatar_url (optional) and password (unless invite) like creating a user.\nNothing is imported when a row is invalid, otherwise a job creates the users in batches. With invite, it creates invites and users are created when they set their password.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Import users from csv",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate",
+                        "name": "dry_run",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Invite users instead of setting passwords",
+                        "name": "invite",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run, with errors of invalid rows",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserImportReport"
+                        }
+                    },
+                    "202": {
+                        "description": "Import job queued",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rows",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserImportReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/most-blocked": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Rank users by how many users blocked them in the range, a signal of abuse",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get most blocked users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "unix time, MOST_BLOCKED_DEFAULT_DAYS ago when empty",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "minBlocks",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_MostBlockedDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/statistics": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get statistics for users based on the provided criteria",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user statistics",
+                "parameters": [
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "user",
+                            "streamer"
+                        ],
+                        "type": "string",
+                        "name": "role_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "DESC",
+                            "ASC"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "username",
+                            "display_name",
+                            "total_streams",
+                            "total_likes",
+                            "total_comments",
+                            "total_views"
+                        ],
+                        "type": "string",
+                        "name": "sort_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_UserStatisticsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/usernames": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a list of all usernames",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get list of usernames",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update the details of a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update User Request",
+                        "name": "UpdateUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/blocks": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get users blocked by a user, or users who blocked it with direction blocked_by",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get blocks of user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "blocking",
+                            "blocked_by"
+                        ],
+                        "type": "string",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_UserBlockDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a block of blocked_user_id by a user, as if the user blocked it in be-api",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Block a user on behalf of user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create User Block Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateUserBlockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/blocks/{blocked_user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove the block of blocked_user_id by a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Unblock a user on behalf of user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Blocked User ID",
+                        "name": "blocked_user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/change-avatar": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Change the avatar of a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Change user avatar",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "User Avatar",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/change-password": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Change the password of a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Change user password",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Change Password Request",
+                        "name": "ChangePasswordRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/deactive": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deactive a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Deactive user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Deactive User",
+                        "name": "DeactiveUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeactiveUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/erasure": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Queue a job which deletes or anonymizes the records of a user as described by the erasure policy, it can't be undone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GDPR"
+                ],
+                "summary": "Erase data of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Erasure Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GDPRErasureRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GDPRRequestCreatedDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/export": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Queue a job which zips the profile, streams, comments, likes, views, shares, bookmarks, subscriptions, notifications and uploaded files of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GDPR"
+                ],
+                "summary": "Export data of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GDPRRequestCreatedDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/publish-ban": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lift the active ban of a user from going live",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Lift ban of user from going live",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Stop a user from going live for the duration, or until it's lifted. Live streams of the user are ended, viewing and commenting are left as they are",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Ban user from going live",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Publish Ban",
+                        "name": "PublishBanRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PublishBanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PublishBanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/reactive": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Reactive a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Reactive user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/strikes": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the strike history of a user, revoked strikes included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get strikes of user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserStrikesDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID parameter"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/strikes/{strike_id}/revoke": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke a strike so it doesn't count anymore, the user is reinstated when the strike is what blocks it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Revoke strike of user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Strike ID",
+                        "name": "strike_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserStrikeDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/subscribers": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get users subscribed to a streamer, keyword matches their username or display name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Get subscribers of streamer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Streamer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "muted",
+                            "unmuted"
+                        ],
+                        "type": "string",
+                        "name": "mute",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "DESC",
+                            "ASC"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "username"
+                        ],
+                        "type": "string",
+                        "name": "sortBy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_SubscriptionDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/subscribers/bursts": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Find windows in which many new accounts subscribed to a streamer, subscriber ids can be removed with the remove endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Get follow spam bursts of streamer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Streamer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 2592000,
+                        "minimum": 3600,
+                        "type": "integer",
+                        "description": "in seconds, at subscribing",
+                        "name": "accountAge",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix time, SUBSCRIPTION_BURST_DEFAULT_DAYS ago when empty",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 10000,
+                        "minimum": 2,
+                        "type": "integer",
+                        "name": "minCount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 86400,
+                        "minimum": 60,
+                        "type": "integer",
+                        "description": "in seconds",
+                        "name": "window",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubscriptionBurstsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/subscribers/growth": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Count current subscribers of a streamer per interval by when they subscribed, with running totals and mute ratios",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Get follower growth of streamer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Streamer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix time, SUBSCRIPTION_GROWTH_DEFAULT_DAYS ago when empty",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 2592000,
+                        "minimum": 3600,
+                        "type": "integer",
+                        "description": "in seconds, SUBSCRIPTION_GROWTH_DEFAULT_INTERVAL when empty",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubscriptionGrowthDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/subscribers/remove": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove fraudulent subscriptions of the subscribers to a streamer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Remove subscribers of streamer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Streamer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Remove Subscriptions Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RemoveSubscriptionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RemoveSubscriptionsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/subscribers/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Count subscribers and subscriptions of a user with how many are muted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Get subscription stats of user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubscriptionStatsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get streamers a user is subscribed to, keyword matches their username or display name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Get subscriptions of user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "muted",
+                            "unmuted"
+                        ],
+                        "type": "string",
+                        "name": "mute",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "DESC",
+                            "ASC"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "username"
+                        ],
+                        "type": "string",
+                        "name": "sortBy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.PaginationModel-dto_SubscriptionDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/suspend": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a strike to a user and block it for the duration, the user is blocked permanently on reaching the max strikes and reinstated automatically otherwise",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Suspend user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Suspend User",
+                        "name": "SuspendUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SuspendUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SuspendUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request"
+                    },
+                    "404": {
+                        "description": "Not found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AcceptInviteDTO": {
+            "type": "object",
+            "required": [
+                "confirmPassword",
+                "password",
+                "token"
+            ],
+            "properties": {
+                "confirmPassword": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 8
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 8
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AdminLogRespDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "performed_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                }
+            }
+        },
+        "dto.AdminResp": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AnnouncementDTO": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "canceled_at": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "description": "fans out the notifications",
+                    "type": "integer"
+                },
+                "recipients": {
+                    "type": "integer"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "segment_id": {
+                    "type": "integer"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.AnnouncementStatus"
+                },
+                "stream_id": {
+                    "type": "integer"
+                },
+                "target_role": {
+                    "$ref": "#/definitions/model.RoleType"
+                },
+                "target_type": {
+                    "$ref": "#/definitions/model.AnnouncementTarget"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AnnouncementStatsDTO": {
+            "type": "object",
+            "properties": {
+                "announcement_id": {
+                    "type": "integer"
+                },
+                "delivered": {
+                    "type": "integer"
+                },
+                "first_read_at": {
+                    "type": "string"
+                },
+                "hidden": {
+                    "type": "integer"
+                },
+                "last_read_at": {
+                    "type": "string"
+                },
+                "read": {
+                    "type": "integer"
+                },
+                "read_ratio": {
+                    "type": "number"
+                },
+                "recipients": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.AnnouncementStatus"
+                }
+            }
+        },
+        "dto.AssignReportRequest": {
+            "type": "object",
+            "required": [
+                "assignee_id"
+            ],
+            "properties": {
+                "assignee_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "dto.BaseDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.BulkStreamRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "category_ids",
+                "ids"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "delete",
+                        "end_live",
+                        "recategorize"
+                    ]
+                },
+                "category_ids": {
+                    "type": "array",
+                    "maxItems": 3,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "filter": {
+                    "$ref": "#/definitions/dto.LiveStreamBroadCastQueryDTO"
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.BulkUserRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "ids"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "block",
+                        "reactivate",
+                        "delete",
+                        "change_role"
+                    ]
+                },
+                "filter": {
+                    "$ref": "#/definitions/dto.UserQuery"
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                },
+                "role_type": {
+                    "enum": [
+                        "admin",
+                        "streamer",
+                        "user"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RoleType"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.CalendarDayDTO": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "2006-01-02",
+                    "type": "string"
+                },
+                "occurrences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CalendarOccurrenceDTO"
+                    }
+                }
+            }
+        },
+        "dto.CalendarFeedRespDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CalendarOccurrenceDTO": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryDTO"
+                    }
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "recurrence_id": {
+                    "type": "integer"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.StreamStatus"
+                },
+                "stream_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "video_duration": {
+                    "description": "in seconds, 0 when it's unknown",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CategoryDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CategoryRequestDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.CategoryRespDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "integer"
+                },
+                "created_by_user": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by_id": {
+                    "type": "integer"
+                },
+                "updated_by_user": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                }
+            }
+        },
+        "dto.CategoryUpdateRequestDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "confirm_password",
+                "password"
+            ],
+            "properties": {
+                "confirm_password": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 8
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 8
+                }
+            }
+        },
+        "dto.ClipDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "duration": {
+                    "description": "in milliseconds",
+                    "type": "integer"
+                },
+                "end_offset": {
+                    "description": "in milliseconds",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "description": "job cutting the clip, returned on create",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "in bytes",
+                    "type": "integer"
+                },
+                "start_offset": {
+                    "description": "in milliseconds",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.ClipStatus"
+                },
+                "stream_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "ready clips only",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ClipRequest": {
+            "type": "object",
+            "required": [
+                "end_offset"
+            ],
+            "properties": {
+                "end_offset": {
+                    "description": "in milliseconds",
+                    "type": "integer"
+                },
+                "start_offset": {
+                    "description": "in milliseconds",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dto.CommentDTO": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "flag_reason": {
+                    "type": "string"
+                },
+                "flagged": {
+                    "type": "boolean"
+                },
+                "hidden": {
+                    "type": "boolean"
+                },
+                "hidden_at": {
+                    "type": "string"
+                },
+                "hidden_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "hidden_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "stream_id": {
+                    "type": "integer"
+                },
+                "stream_title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                }
+            }
+        },
+        "dto.CommentFilterEvaluateRequest": {
+            "type": "object",
+            "required": [
+                "rule_ids"
+            ],
+            "properties": {
+                "from": {
+                    "description": "unix time",
+                    "type": "integer"
+                },
+                "rule_ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "sample_size": {
+                    "type": "integer",
+                    "maximum": 20
+                },
+                "stream_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CommentFilterEvaluationDTO": {
+            "type": "object",
+            "properties": {
+                "matched": {
+                    "description": "comments matched by any rule",
+                    "type": "integer"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CommentFilterRuleEvaluationDTO"
+                    }
+                },
+                "scanned": {
+                    "type": "integer"
+                },
+                "truncated": {
+                    "description": "more than COMMENT_FILTER_EVALUATE_MAX_COMMENTS comments are in range, older ones were not scanned",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CommentFilterRuleDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.CommentFilterAction"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "hit_threshold": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.CommentFilterType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CommentFilterRuleEvaluationDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.CommentFilterAction"
+                },
+                "blocked_user_ids": {
+                    "description": "users with at least hit_threshold matches, for block_user rules",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "matches": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "samples": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CommentFilterSampleDTO"
+                    }
+                },
+                "users": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CommentFilterRuleRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "name",
+                "pattern",
+                "type"
+            ],
+            "properties": {
+                "action": {
+                    "enum": [
+                        "hide",
+                        "flag",
+                        "block_user"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CommentFilterAction"
+                        }
+                    ]
+                },
+                "category_id": {
+                    "description": "omitted for global rules",
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "hit_threshold": {
+                    "type": "integer",
+                    "maximum": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "pattern": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "type": {
+                    "enum": [
+                        "word",
+                        "regex"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CommentFilterType"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.CommentFilterSampleDTO": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "comment_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "stream_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CommentTimelineBucket": {
+            "type": "object",
+            "properties": {
+                "flagged": {
+                    "type": "integer"
+                },
+                "hidden": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "description": "seconds since the start of the stream",
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CommentTimelineDTO": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CommentTimelineBucket"
+                    }
+                },
+                "ended_at": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "stream_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateAnnouncementRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "target_type",
+                "title"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "scheduled_at": {
+                    "description": "expect in utc",
+                    "type": "string"
+                },
+                "segment_id": {
+                    "type": "integer"
+                },
+                "stream_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "target_role": {
+                    "enum": [
+                        "admin",
+                        "streamer",
+                        "user"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RoleType"
+                        }
+                    ]
+                },
+                "target_type": {
+                    "enum": [
+                        "all",
+                        "role",
+                        "category_viewers",
+                        "segment"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AnnouncementTarget"
+                        }
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.CreateInviteRequest": {
+            "type": "object",
+            "required": [
+                "display_name",
+                "email",
+                "role_type",
+                "username"
+            ],
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "role_type": {
+                    "enum": [
+                        "admin",
+                        "streamer",
+                        "user"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RoleType"
+                        }
+                    ]
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.CreateReportRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "target_id",
+                "target_type"
+            ],
+            "properties": {
+                "reason": {
+                    "enum": [
+                        "self_harm",
+                        "violence",
+                        "hate",
+                        "harassment",
+                        "nudity",
+                        "spam",
+                        "copyright",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ReportReason"
+                        }
+                    ]
+                },
+                "target_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "target_type": {
+                    "enum": [
+                        "stream",
+                        "comment",
+                        "user"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ReportTargetType"
+                        }
+                    ]
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "dto.CreateStreamResponseDTO": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateUserBlockRequest": {
+            "type": "object",
+            "required": [
+                "blocked_user_id"
+            ],
+            "properties": {
+                "blocked_user_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "reason": {
+                    "description": "for the admin log",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dto.DeactiveUserRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.DismissReportRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "dto.ForgetPasswordDTO": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dto.GDPRErasureRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.GDPRPolicyEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "record": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GDPRRequestCreatedDTO": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GDPRRequestDTO": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "description": "until the export expires",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "report": {
+                    "type": "object"
+                },
+                "requested_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "signature": {
+                    "type": "string"
+                },
+                "signature_valid": {
+                    "description": "whether the stored report still matches its signature",
+                    "type": "boolean"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.GDPRRequestStatus"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.GDPRRequestType"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.HideCommentRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dto.InviteCreatedDTO": {
+            "type": "object",
+            "properties": {
+                "invite_id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "description": "sends the email",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.InviteDTO": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expired": {
+                    "description": "pending invites whose last link expired, they can be resent",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invited_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "role_type": {
+                    "$ref": "#/definitions/model.RoleType"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "sent_count": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.InviteStatus"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JobCreatedDTO": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.JobDTO": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "cancel_requested": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "progress": {
+                    "description": "in percent",
+                    "type": "integer"
+                },
+                "progress_message": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "object"
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.JobStatus"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.JobType"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LegalHoldRequest": {
+            "type": "object",
+            "required": [
+                "legal_hold"
+            ],
+            "properties": {
+                "legal_hold": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.LiveStatRespDTO": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current_viewers": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "shares": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.StreamStatus"
+                },
+                "stream_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total_viewers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LiveStatRespInDayDTO": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BaseDTO"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "likes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BaseDTO"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/model.StreamStatus"
+                },
+                "stream_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "viewers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BaseDTO"
+                    }
+                }
+            }
+        },
+        "dto.LiveStreamBroadCastDTO": {
+            "type": "object",
+            "properties": {
+                "broadcast_url": {
+                    "description": "generated from web",
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryDTO"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_pinned": {
+                    "type": "boolean"
+                },
+                "legal_hold": {
+                    "type": "boolean"
+                },
+                "live_stream_analytic": {
+                    "$ref": "#/definitions/dto.LiveStreamRespDTO"
+                },
+                "push_url": {
+                    "description": "generated from streaming server",
+                    "type": "string"
+                },
+                "rebroadcasts": {
+                    "description": "streams replaying the recording",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RebroadcastDTO"
+                    }
+                },
+                "schedule_stream": {
+                    "$ref": "#/definitions/dto.ScheduleStreamDTO"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.StreamStatus"
+                },
+                "stream_type": {
+                    "$ref": "#/definitions/model.StreamType"
+                },
+                "thumbnail_file_name": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "video_url": {
+                    "description": "recording of ended stream",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LiveStreamBroadCastQueryDTO": {
+            "type": "object",
+            "required": [
+                "limit",
+                "page"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "end_ended_time": {
+                    "type": "integer"
+                },
+                "end_started_time": {
+                    "type": "integer"
+                },
+                "from_ended_time": {
+                    "type": "integer"
+                },
+                "from_started_time": {
+                    "type": "integer"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 20,
+                    "minimum": 1
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "sort": {
+                    "type": "string",
+                    "enum": [
+                        "DESC",
+                        "ASC"
+                    ]
+                },
+                "sort_by": {
+                    "type": "string",
+                    "enum": [
+                        "title",
+                        "started_at",
+                        "ended_at",
+                        "views",
+                        "shares",
+                        "likes",
+                        "comments",
+                        "video_size",
+                        "duration",
+                        "shares",
+                        "created_at"
+                    ]
+                },
+                "status": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StreamStatus"
+                    }
+                },
+                "type": {
+                    "enum": [
+                        "camera",
+                        "software",
+                        "pre_record"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StreamType"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.LiveStreamRespDTO": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "shares": {
+                    "type": "integer"
+                },
+                "stream_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "video_size": {
+                    "type": "integer"
+                },
+                "viewers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LoginDTO": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 8
+                }
+            }
+        },
+        "dto.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expired_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.RoleType"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MostBlockedDTO": {
+            "type": "object",
+            "properties": {
+                "blocks": {
+                    "description": "in the range",
+                    "type": "integer"
+                },
+                "last_blocked_at": {
+                    "type": "string"
+                },
+                "total_blocks": {
+                    "description": "of all time",
+                    "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                }
+            }
+        },
+        "dto.NotificationTemplateDTO": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.NotificationType"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.NotificationTemplatePreviewRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 4000
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "locale": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "type": {
+                    "enum": [
+                        "subscribe_live",
+                        "subscribe_video",
+                        "account_blocked",
+                        "account_deleted",
+                        "announcement"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.NotificationType"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.NotificationTemplateRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 4000
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dto.NotificationTemplateVariablesDTO": {
+            "type": "object",
+            "properties": {
+                "default_body": {
+                    "type": "string"
+                },
+                "default_title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.NotificationType"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.PinStreamRequest": {
+            "type": "object",
+            "required": [
+                "is_pinned"
+            ],
+            "properties": {
+                "is_pinned": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.PlaylistItemDTO": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "in milliseconds",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "in_point": {
+                    "type": "integer"
+                },
+                "out_point": {
+                    "type": "integer"
+                },
+                "play_duration": {
+                    "description": "in milliseconds, after trimming",
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.PlaylistItemType"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PlaylistRespDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PlaylistItemDTO"
+                    }
+                },
+                "total_duration": {
+                    "description": "in seconds",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PublishBanDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "expires_at": {
+                    "description": "nil until lifted",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PublishBanRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "duration": {
+                    "type": "integer",
+                    "maximum": 31536000,
+                    "minimum": 3600
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.PublishBanResponse": {
+            "type": "object",
+            "properties": {
+                "ban": {
+                    "$ref": "#/definitions/dto.PublishBanDTO"
+                },
+                "ended_stream_ids": {
+                    "description": "live streams of the user, ended through be-api",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.RebroadcastDTO": {
+            "type": "object",
+            "properties": {
+                "live_stream_analytic": {
+                    "$ref": "#/definitions/dto.LiveStreamRespDTO"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.StreamStatus"
+                },
+                "stream_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RebroadcastStreamRequest": {
+            "type": "object",
+            "required": [
+                "scheduled_at"
+            ],
+            "properties": {
+                "scheduled_at": {
+                    "description": "expect in utc",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RemoveSubscriptionsDTO": {
+            "type": "object",
+            "properties": {
+                "removed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.RemoveSubscriptionsRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "subscriber_ids"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                },
+                "subscriber_ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.RenderedNotificationDTO": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "of the template, the default one for built in templates",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.NotificationType"
+                },
+                "version": {
+                    "description": "0 for built in and previewed templates",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ReportCreatedDTO": {
+            "type": "object",
+            "properties": {
+                "due_at": {
+                    "type": "string"
+                },
+                "report_id": {
+                    "type": "integer"
+                },
+                "severity": {
+                    "$ref": "#/definitions/model.ReportSeverity"
+                }
+            }
+        },
+        "dto.ReportDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.ReportAction"
+                },
+                "assigned_at": {
+                    "type": "string"
+                },
+                "assignee": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "overdue": {
+                    "type": "boolean"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "$ref": "#/definitions/model.ReportReason"
+                },
+                "remaining_seconds": {
+                    "description": "seconds left of the SLA, negative when overdue, 0 once the report is closed",
+                    "type": "integer"
+                },
+                "reporter": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "severity": {
+                    "$ref": "#/definitions/model.ReportSeverity"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.ReportStatus"
+                },
+                "target_id": {
+                    "type": "integer"
+                },
+                "target_type": {
+                    "$ref": "#/definitions/model.ReportTargetType"
+                },
+                "target_user": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReportModeratorStatsDTO": {
+            "type": "object",
+            "properties": {
+                "avg_resolution_seconds": {
+                    "description": "from filing to closing",
+                    "type": "number"
+                },
+                "breached": {
+                    "description": "closed after their SLA",
+                    "type": "integer"
+                },
+                "claimed": {
+                    "description": "currently",
+                    "type": "integer"
+                },
+                "dismissed": {
+                    "type": "integer"
+                },
+                "moderator": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "resolved": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ReportResolvedDTO": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "description": "purge of a deleted stream",
+                    "type": "integer"
+                },
+                "resolved": {
+                    "description": "reports of the target",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ReportStatsDTO": {
+            "type": "object",
+            "properties": {
+                "claimed": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "moderators": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReportModeratorStatsDTO"
+                    }
+                },
+                "open": {
+                    "type": "integer"
+                },
+                "overdue": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ResetPasswordDTO": {
+            "type": "object",
+            "required": [
+                "confirmPassword",
+                "otp",
+                "password"
+            ],
+            "properties": {
+                "confirmPassword": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "otp": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "dto.ResolveReportRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "enum": [
+                        "none",
+                        "block_user",
+                        "delete_stream",
+                        "end_live",
+                        "hide_comment"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ReportAction"
+                        }
+                    ]
+                },
+                "note": {
+                    "description": "blocked reason of block_user",
+                    "type": "string",
+                    "maxLength": 1000,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.RetentionPolicyRequestDTO": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "description": "omitted for the global policy",
+                    "type": "integer"
+                },
+                "delete_raw_after_encoding": {
+                    "type": "boolean"
+                },
+                "delete_video_after_days": {
+                    "description": "0 keeps the video",
+                    "type": "integer",
+                    "maximum": 3650
+                }
+            }
+        },
+        "dto.RetentionPolicyRespDTO": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "integer"
+                },
+                "delete_raw_after_encoding": {
+                    "type": "boolean"
+                },
+                "delete_video_after_days": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.RoleDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/model.RoleType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserResponseDTO"
+                    }
+                }
+            }
+        },
+        "dto.ScheduleStreamDTO": {
+            "type": "object",
+            "properties": {
+                "last_push_error": {
+                    "type": "string"
+                },
+                "playlist": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PlaylistItemDTO"
+                    }
+                },
+                "push_attempts": {
+                    "type": "integer"
+                },
+                "rebroadcast_of": {
+                    "description": "id of the ended stream whose recording is replayed",
+                    "type": "integer"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "total_duration": {
+                    "description": "in seconds, of the video or the whole playlist, 0 when it's unknown",
+                    "type": "integer"
+                },
+                "video_duration": {
+                    "description": "deprecated, same as total_duration",
+                    "type": "integer"
+                },
+                "video_name": {
+                    "type": "string"
+                },
+                "video_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.StatisticsTotalLiveStreamDTO": {
+            "type": "object",
+            "properties": {
+                "active_live_streams": {
+                    "type": "integer"
+                },
+                "total_live_streams": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.StorageUsageRespDTO": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "quota_bytes": {
+                    "description": "0 is unlimited",
+                    "type": "integer"
+                },
+                "recording_bytes": {
+                    "type": "integer"
+                },
+                "role_type": {
+                    "$ref": "#/definitions/model.RoleType"
+                },
+                "scheduled_video_bytes": {
+                    "type": "integer"
+                },
+                "thumbnail_bytes": {
+                    "type": "integer"
+                },
+                "total_bytes": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.StreamRecurrenceRespDTO": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryDTO"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "materialized_until": {
+                    "type": "string"
+                },
+                "next_occurrences": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rrule": {
+                    "type": "string"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "video_duration": {
+                    "type": "integer"
+                },
+                "video_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SubscriptionBurstDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "subscriber_ids": {
+                    "description": "at most SUBSCRIPTION_REMOVE_MAX, for RemoveSubscriptionsRequest",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.SubscriptionBurstsDTO": {
+            "type": "object",
+            "properties": {
+                "account_age": {
+                    "type": "integer"
+                },
+                "bursts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SubscriptionBurstDTO"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "min_count": {
+                    "type": "integer"
+                },
+                "streamer_id": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "window": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.SubscriptionDTO": {
+            "type": "object",
+            "properties": {
+                "account_created_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_mute": {
+                    "type": "boolean"
+                },
+                "user": {
+                    "description": "subscriber or streamer, the other side",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.UserResponseDTO"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.SubscriptionGrowthBucket": {
+            "type": "object",
+            "properties": {
+                "mute_ratio": {
+                    "type": "number"
+                },
+                "new": {
+                    "type": "integer"
+                },
+                "new_muted": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "at the end of the bucket",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.SubscriptionGrowthDTO": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SubscriptionGrowthBucket"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "streamer_id": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SubscriptionStatsDTO": {
+            "type": "object",
+            "properties": {
+                "mute_ratio": {
+                    "description": "of subscribers, 0 when there are none",
+                    "type": "number"
+                },
+                "muted_subscribers": {
+                    "type": "integer"
+                },
+                "muted_subscriptions": {
+                    "type": "integer"
+                },
+                "subscribers": {
+                    "type": "integer"
+                },
+                "subscriptions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.SuspendUserRequest": {
+            "type": "object",
+            "required": [
+                "duration",
+                "reason"
+            ],
+            "properties": {
+                "duration": {
+                    "type": "integer",
+                    "maximum": 31536000,
+                    "minimum": 3600
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                },
+                "reason": {
+                    "enum": [
+                        "spam",
+                        "harassment",
+                        "hate",
+                        "violence",
+                        "nudity",
+                        "impersonation",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StrikeReason"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.SuspendUserResponse": {
+            "type": "object",
+            "properties": {
+                "strike": {
+                    "$ref": "#/definitions/dto.UserStrikeDTO"
+                },
+                "strikes": {
+                    "description": "unrevoked ones of the user",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.TrashItemDTO": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "username of users, title of streams",
+                    "type": "string"
+                },
+                "purge_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "owner of streams",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.UpdatePlaylistItemRequest": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "slates only",
+                    "type": "integer"
+                },
+                "in_point": {
+                    "description": "videos only",
+                    "type": "integer"
+                },
+                "out_point": {
+                    "description": "videos only",
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.UpdateStreamRecurrenceRequest": {
+            "type": "object",
+            "required": [
+                "category_ids",
+                "description",
+                "rrule",
+                "timezone",
+                "title"
+            ],
+            "properties": {
+                "category_ids": {
+                    "type": "array",
+                    "maxItems": 3,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "from_stream_id": {
+                    "type": "integer"
+                },
+                "rrule": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "start_at": {
+                    "description": "moves the series, keeps the current one when omitted",
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UpdateUserRequest": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "locale": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "role_type": {
+                    "enum": [
+                        "admin",
+                        "streamer",
+                        "user"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RoleType"
+                        }
+                    ]
+                },
+                "updated_by_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.UpdateUserResponse": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.RoleType"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.UserStatusType"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserBlockDTO": {
+            "type": "object",
+            "properties": {
+                "blocked_at": {
+                    "type": "string"
+                },
                 "user": {
+                    "description": "the other side of the block",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.UserResponseDTO"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.UserImportReport": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserImportRowError"
+                    }
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.UserImportRowError": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "row": {
+                    "description": "line of the csv, the header is line 1",
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserQuery": {
+            "type": "object",
+            "properties": {
+                "created_by": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "keyword": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 20,
+                    "minimum": 1
+                },
+                "min_strikes": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "streamer",
+                        "user"
+                    ]
+                },
+                "sort": {
+                    "type": "string",
+                    "enum": [
+                        "DESC",
+                        "ASC"
+                    ]
+                },
+                "sort_by": {
+                    "type": "string",
+                    "enum": [
+                        "created_at",
+                        "updated_at",
+                        "username",
+                        "email",
+                        "display_name"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "online",
+                        "offline",
+                        "blocked"
+                    ]
+                },
+                "suspension": {
+                    "description": "blocked users with or without SuspendedUntil",
+                    "type": "string",
+                    "enum": [
+                        "temporary",
+                        "permanent"
+                    ]
+                }
+            }
+        },
+        "dto.UserResponseDTO": {
+            "type": "object",
+            "properties": {
+                "avatar_file_name": {
+                    "type": "string"
+                },
+                "blocked_reason": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "created_by_id": {
+                    "type": "integer"
+                },
+                "deleted_by_id": {
+                    "type": "integer"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "publish_ban": {
+                    "description": "active one, of the user detail",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.PublishBanDTO"
+                        }
+                    ]
+                },
+                "role": {
+                    "$ref": "#/definitions/dto.RoleDTO"
+                },
+                "role_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.UserStatusType"
+                },
+                "strikes": {
+                    "type": "integer"
+                },
+                "suspended_until": {
+                    "description": "nil while blocked is a permanent block",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "updated_by_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserSegmentDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "filter": {
+                    "$ref": "#/definitions/dto.UserQuery"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "users": {
+                    "description": "matching the filter now",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.UserSegmentRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "filter": {
+                    "$ref": "#/definitions/dto.UserQuery"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.UserStatisticsResponse": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "role_type": {
+                    "$ref": "#/definitions/model.RoleType"
+                },
+                "total_comments": {
+                    "type": "integer"
+                },
+                "total_likes": {
+                    "type": "integer"
+                },
+                "total_streams": {
+                    "type": "integer"
+                },
+                "total_views": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserStrikeDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "issued_by": {
+                    "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "permanent": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "$ref": "#/definitions/model.StrikeReason"
+                },
+                "revoked": {
+                    "type": "boolean"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "revoked_by": {
                     "$ref": "#/definitions/dto.UserResponseDTO"
+                },
+                "suspended_until": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.UserStrikesDTO": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserStrikeDTO"
+                    }
+                },
+                "max_strikes": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.UserStatusType"
+                },
+                "strikes": {
+                    "type": "integer"
+                },
+                "suspended_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AnnouncementStatus": {
+            "type": "string",
+            "enum": [
+                "scheduled",
+                "sending",
+                "sent",
+                "canceled",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "AnnouncementStatusScheduled",
+                "AnnouncementStatusSending",
+                "AnnouncementStatusSent",
+                "AnnouncementStatusCanceled",
+                "AnnouncementStatusFailed"
+            ]
+        },
+        "model.AnnouncementTarget": {
+            "type": "string",
+            "enum": [
+                "all",
+                "role",
+                "category_viewers",
+                "segment"
+            ],
+            "x-enum-varnames": [
+                "AnnouncementTargetAll",
+                "AnnouncementTargetRole",
+                "AnnouncementTargetCategoryViewers",
+                "AnnouncementTargetSegment"
+            ]
+        },
+        "model.ClipStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "processing",
+                "ready",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ClipStatusPending",
+                "ClipStatusProcessing",
+                "ClipStatusReady",
+                "ClipStatusFailed"
+            ]
+        },
+        "model.CommentFilterAction": {
+            "type": "string",
+            "enum": [
+                "hide",
+                "flag",
+                "block_user"
+            ],
+            "x-enum-comments": {
+                "CommentFilterActionBlockUser": "after HitThreshold matching comments of a user"
+            },
+            "x-enum-varnames": [
+                "CommentFilterActionHide",
+                "CommentFilterActionFlag",
+                "CommentFilterActionBlockUser"
+            ]
+        },
+        "model.CommentFilterType": {
+            "type": "string",
+            "enum": [
+                "word",
+                "regex"
+            ],
+            "x-enum-comments": {
+                "CommentFilterTypeWord": "whole words or phrases, case insensitive"
+            },
+            "x-enum-varnames": [
+                "CommentFilterTypeWord",
+                "CommentFilterTypeRegex"
+            ]
+        },
+        "model.GDPRRequestStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "GDPRRequestStatusPending",
+                "GDPRRequestStatusCompleted",
+                "GDPRRequestStatusFailed"
+            ]
+        },
+        "model.GDPRRequestType": {
+            "type": "string",
+            "enum": [
+                "export",
+                "erasure"
+            ],
+            "x-enum-varnames": [
+                "GDPRRequestTypeExport",
+                "GDPRRequestTypeErasure"
+            ]
+        },
+        "model.InviteStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "accepted",
+                "revoked"
+            ],
+            "x-enum-varnames": [
+                "InviteStatusPending",
+                "InviteStatusAccepted",
+                "InviteStatusRevoked"
+            ]
+        },
+        "model.JobStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "succeeded",
+                "failed",
+                "canceled"
+            ],
+            "x-enum-varnames": [
+                "JobStatusQueued",
+                "JobStatusRunning",
+                "JobStatusSucceeded",
+                "JobStatusFailed",
+                "JobStatusCanceled"
+            ]
+        },
+        "model.JobType": {
+            "type": "string",
+            "enum": [
+                "remove_files",
+                "cut_clip",
+                "bulk_users",
+                "bulk_streams",
+                "purge_user",
+                "purge_stream",
+                "gdpr_export",
+                "gdpr_erasure",
+                "import_users",
+                "send_invite",
+                "send_announcement"
+            ],
+            "x-enum-varnames": [
+                "JobTypeRemoveFiles",
+                "JobTypeCutClip",
+                "JobTypeBulkUsers",
+                "JobTypeBulkStreams",
+                "JobTypePurgeUser",
+                "JobTypePurgeStream",
+                "JobTypeGDPRExport",
+                "JobTypeGDPRErasure",
+                "JobTypeImportUsers",
+                "JobTypeSendInvite",
+                "JobTypeSendAnnouncement"
+            ]
+        },
+        "model.NotificationType": {
+            "type": "string",
+            "enum": [
+                "subscribe_live",
+                "subscribe_video",
+                "account_blocked",
+                "account_deleted",
+                "announcement"
+            ],
+            "x-enum-varnames": [
+                "NotificationTypeSubscribeLive",
+                "NotificationTypeSubscribeVideo",
+                "NotificationTypeBlocked",
+                "NotificationTypeDeleted",
+                "NotificationTypeAnnouncement"
+            ]
+        },
+        "model.PlaylistItemType": {
+            "type": "string",
+            "enum": [
+                "video",
+                "slate"
+            ],
+            "x-enum-comments": {
+                "PlaylistItemTypeSlate": "still image shown between videos"
+            },
+            "x-enum-varnames": [
+                "PlaylistItemTypeVideo",
+                "PlaylistItemTypeSlate"
+            ]
+        },
+        "model.ReportAction": {
+            "type": "string",
+            "enum": [
+                "none",
+                "block_user",
+                "delete_stream",
+                "end_live",
+                "hide_comment"
+            ],
+            "x-enum-varnames": [
+                "ReportActionNone",
+                "ReportActionBlockUser",
+                "ReportActionDeleteStream",
+                "ReportActionEndLive",
+                "ReportActionHideComment"
+            ]
+        },
+        "model.ReportReason": {
+            "type": "string",
+            "enum": [
+                "self_harm",
+                "violence",
+                "hate",
+                "harassment",
+                "nudity",
+                "spam",
+                "copyright",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "ReportReasonSelfHarm",
+                "ReportReasonViolence",
+                "ReportReasonHate",
+                "ReportReasonHarassment",
+                "ReportReasonNudity",
+                "ReportReasonSpam",
+                "ReportReasonCopyright",
+                "ReportReasonOther"
+            ]
+        },
+        "model.ReportSeverity": {
+            "type": "string",
+            "enum": [
+                "high",
+                "medium",
+                "low"
+            ],
+            "x-enum-varnames": [
+                "ReportSeverityHigh",
+                "ReportSeverityMedium",
+                "ReportSeverityLow"
+            ]
+        },
+        "model.ReportStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "claimed",
+                "resolved",
+                "dismissed"
+            ],
+            "x-enum-varnames": [
+                "ReportStatusOpen",
+                "ReportStatusClaimed",
+                "ReportStatusResolved",
+                "ReportStatusDismissed"
+            ]
+        },
+        "model.ReportTargetType": {
+            "type": "string",
+            "enum": [
+                "stream",
+                "comment",
+                "user"
+            ],
+            "x-enum-varnames": [
+                "ReportTargetStream",
+                "ReportTargetComment",
+                "ReportTargetUser"
+            ]
+        },
+        "model.RoleType": {
+            "type": "string",
+            "enum": [
+                "super_admin",
+                "admin",
+                "streamer",
+                "user"
+            ],
+            "x-enum-varnames": [
+                "SUPPERADMINROLE",
+                "ADMINROLE",
+                "STREAMER",
+                "USERROLE"
+            ]
+        },
+        "model.StreamStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "started",
+                "ended",
+                "upcoming"
+            ],
+            "x-enum-varnames": [
+                "PENDING",
+                "STARTED",
+                "ENDED",
+                "UPCOMING"
+            ]
+        },
+        "model.StreamType": {
+            "type": "string",
+            "enum": [
+                "camera",
+                "software",
+                "pre_record"
+            ],
+            "x-enum-comments": {
+                "SOFTWARESTREAM": "like obs"
+            },
+            "x-enum-varnames": [
+                "CAMERASTREAM",
+                "SOFTWARESTREAM",
+                "PRERECORDSTREAM"
+            ]
+        },
+        "model.StrikeReason": {
+            "type": "string",
+            "enum": [
+                "spam",
+                "harassment",
+                "hate",
+                "violence",
+                "nudity",
+                "impersonation",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "StrikeReasonSpam",
+                "StrikeReasonHarassment",
+                "StrikeReasonHate",
+                "StrikeReasonViolence",
+                "StrikeReasonNudity",
+                "StrikeReasonImpersonation",
+                "StrikeReasonOther"
+            ]
+        },
+        "model.UserStatusType": {
+            "type": "string",
+            "enum": [
+                "online",
+                "offline",
+                "blocked"
+            ],
+            "x-enum-varnames": [
+                "ONLINE",
+                "OFFLINE",
+                "BLOCKED"
+            ]
+        },
+        "utils.PaginationModel-dto_AdminLogRespDTO": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "exec_time": {
+                    "type": "number"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "is_new_filter": {
+                    "type": "boolean"
+                },
+                "length": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "integer"
+                },
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AdminLogRespDTO"
+                    }
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "integer"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "total_items": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.AdminResp": {
+        "utils.PaginationModel-dto_AnnouncementDTO": {
             "type": "object",
             "properties": {
-                "display_name": {
-                    "type": "string"
+                "current_page": {
+                    "type": "integer"
                 },
-                "id": {
+                "exec_time": {
+                    "type": "number"
+                },
+                "index": {
                     "type": "integer"
                 },
-                "username": {
+                "is_new_filter": {
+                    "type": "boolean"
+                },
+                "length": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "integer"
+                },
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AnnouncementDTO"
+                    }
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "integer"
+                },
+                "query": {
                     "type": "string"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "total_items": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.BaseDTO": {
+        "utils.PaginationModel-dto_CategoryRespDto": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "exec_time": {
+                    "type": "number"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "is_new_filter": {
+                    "type": "boolean"
+                },
+                "length": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "integer"
+                },
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryRespDto"
+                    }
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "integer"
+                },
+                "query": {
                     "type": "string"
                 },
-                "id": {
+                "route": {
+                    "type": "string"
+                },
+                "total_items": {
                     "type": "integer"
                 }
             }
         },
-        "dto.CategoryDTO": {
+        "utils.PaginationModel-dto_CommentDTO": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "current_page": {
+                    "type": "integer"
                 },
-                "id": {
+                "exec_time": {
+                    "type": "number"
+                },
+                "index": {
                     "type": "integer"
                 },
-                "name": {
+                "is_new_filter": {
+                    "type": "boolean"
+                },
+                "length": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "integer"
+                },
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CommentDTO"
+                    }
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "integer"
+                },
+                "query": {
                     "type": "string"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "total_items": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.CategoryRequestDTO": {
-            "type": "object",
-            "required": [
-                "name"
-            ],
-            "properties": {
-                "name": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3
-                }
-            }
-        },
-        "dto.CategoryRespDto": {
+        "utils.PaginationModel-dto_CommentFilterRuleDTO": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "current_page": {
+                    "type": "integer"
                 },
-                "created_by_id": {
+                "exec_time": {
+                    "type": "number"
+                },
+                "index": {
                     "type": "integer"
                 },
-                "created_by_user": {
-                    "$ref": "#/definitions/dto.UserResponseDTO"
+                "is_new_filter": {
+                    "type": "boolean"
                 },
-                "id": {
+                "length": {
                     "type": "integer"
                 },
-                "name": {
+                "next": {
+                    "type": "integer"
+                },
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CommentFilterRuleDTO"
+                    }
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "integer"
+                },
+                "query": {
                     "type": "string"
                 },
-                "updated_at": {
+                "route": {
                     "type": "string"
                 },
-                "updated_by_id": {
+                "total_items": {
                     "type": "integer"
-                },
-                "updated_by_user": {
-                    "$ref": "#/definitions/dto.UserResponseDTO"
-                }
-            }
-        },
-        "dto.CategoryUpdateRequestDTO": {
-            "type": "object",
-            "required": [
-                "name"
-            ],
-            "properties": {
-                "name": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3
                 }
             }
         },
-        "dto.ChangePasswordRequest": {
+        "utils.PaginationModel-dto_GDPRRequestDTO": {
             "type": "object",
-            "required": [
-                "confirm_password",
-                "password"
-            ],
             "properties": {
-                "confirm_password": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 8
+                "current_page": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 8
-                }
-            }
-        },
-        "dto.CreateStreamResponseDTO": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
+                "exec_time": {
+                    "type": "number"
                 },
-                "id": {
+                "index": {
                     "type": "integer"
                 },
-                "thumbnail_url": {
+                "is_new_filter": {
+                    "type": "boolean"
+                },
+                "length": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "integer"
+                },
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GDPRRequestDTO"
+                    }
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "integer"
+                },
+                "query": {
                     "type": "string"
                 },
-                "title": {
+                "route": {
                     "type": "string"
+                },
+                "total_items": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.DeactiveUserRequest": {
-            "type": "object",
-            "required": [
-                "reason"
-            ],
-            "properties": {
-                "reason": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 3
-                }
-            }
-        },
-        "dto.ForgetPasswordDTO": {
-            "type": "object",
-            "required": [
-                "email"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "maxLength": 100
-                }
-            }
-        },
-        "dto.LiveStatRespDTO": {
+        "utils.PaginationModel-dto_InviteDTO": {
             "type": "object",
             "properties": {
-                "comments": {
+                "current_page": {
                     "type": "integer"
                 },
-                "created_at": {
-                    "type": "string"
+                "exec_time": {
+                    "type": "number"
                 },
-                "current_viewers": {
+                "index": {
                     "type": "integer"
                 },
-                "description": {
-                    "type": "string"
+                "is_new_filter": {
+                    "type": "boolean"
                 },
-                "likes": {
+                "length": {
                     "type": "integer"
                 },
-                "shares": {
+                "next": {
                     "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/model.StreamStatus"
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
                 },
-                "stream_id": {
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.InviteDTO"
+                    }
+                },
+                "page_size": {
                     "type": "integer"
                 },
-                "title": {
+                "previous": {
+                    "type": "integer"
+                },
+                "query": {
                     "type": "string"
                 },
-                "total_viewers": {
+                "route": {
+                    "type": "string"
+                },
+                "total_items": {
                     "type": "integer"
                 }
             }
         },
-        "dto.LiveStatRespInDayDTO": {
+        "utils.PaginationModel-dto_JobDTO": {
             "type": "object",
             "properties": {
-                "comments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.BaseDTO"
-                    }
+                "current_page": {
+                    "type": "integer"
+                },
+                "exec_time": {
+                    "type": "number"
+                },
+                "index": {
+                    "type": "integer"
                 },
-                "description": {
-                    "type": "string"
+                "is_new_filter": {
+                    "type": "boolean"
                 },
-                "likes": {
+                "length": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "integer"
+                },
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "page": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.BaseDTO"
+                        "$ref": "#/definitions/dto.JobDTO"
                     }
                 },
-                "status": {
-                    "$ref": "#/definitions/model.StreamStatus"
+                "page_size": {
+                    "type": "integer"
                 },
-                "stream_id": {
+                "previous": {
                     "type": "integer"
                 },
-                "title": {
+                "query": {
                     "type": "string"
                 },
-                "viewers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.BaseDTO"
-                    }
+                "route": {
+                    "type": "string"
+                },
+                "total_items": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.LiveStreamBroadCastDTO": {
+        "utils.PaginationModel-dto_LiveStatRespDTO": {
             "type": "object",
             "properties": {
-                "broadcast_url": {
-                    "description": "generated from web",
-                    "type": "string"
+                "current_page": {
+                    "type": "integer"
                 },
-                "categories": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.CategoryDTO"
-                    }
+                "exec_time": {
+                    "type": "number"
                 },
-                "description": {
-                    "type": "string"
+                "index": {
+                    "type": "integer"
                 },
-                "ended_at": {
-                    "type": "string"
+                "is_new_filter": {
+                    "type": "boolean"
                 },
-                "id": {
+                "length": {
                     "type": "integer"
                 },
-                "live_stream_analytic": {
-                    "$ref": "#/definitions/dto.LiveStreamRespDTO"
-                },
-                "push_url": {
-                    "description": "generated from streaming server",
-                    "type": "string"
+                "next": {
+                    "type": "integer"
                 },
-                "schedule_stream": {
-                    "$ref": "#/definitions/dto.ScheduleStreamDTO"
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
                 },
-                "started_at": {
-                    "type": "string"
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LiveStatRespDTO"
+                    }
                 },
-                "status": {
-                    "$ref": "#/definitions/model.StreamStatus"
+                "page_size": {
+                    "type": "integer"
                 },
-                "stream_type": {
-                    "$ref": "#/definitions/model.StreamType"
+                "previous": {
+                    "type": "integer"
                 },
-                "thumbnail_file_name": {
+                "query": {
                     "type": "string"
                 },
-                "title": {
+                "route": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/dto.UserResponseDTO"
+                "total_items": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.LiveStreamRespDTO": {
+        "utils.PaginationModel-dto_LiveStreamBroadCastDTO": {
             "type": "object",
             "properties": {
-                "comments": {
+                "current_page": {
                     "type": "integer"
                 },
-                "created_at": {
-                    "type": "string"
+                "exec_time": {
+                    "type": "number"
                 },
-                "description": {
-                    "type": "string"
+                "index": {
+                    "type": "integer"
                 },
-                "duration": {
+                "is_new_filter": {
+                    "type": "boolean"
+                },
+                "length": {
                     "type": "integer"
                 },
-                "likes": {
+                "next": {
                     "type": "integer"
                 },
-                "shares": {
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LiveStreamBroadCastDTO"
+                    }
+                },
+                "page_size": {
                     "type": "integer"
                 },
-                "stream_id": {
+                "previous": {
                     "type": "integer"
                 },
-                "title": {
+                "query": {
                     "type": "string"
                 },
-                "video_size": {
-                    "type": "integer"
+                "route": {
+                    "type": "string"
                 },
-                "viewers": {
+                "total_items": {
                     "type": "integer"
                 }
             }
         },
-        "dto.LoginDTO": {
+        "utils.PaginationModel-dto_LiveStreamRespDTO": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "maxLength": 100
+                "current_page": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 8
-                }
-            }
-        },
-        "dto.LoginResponse": {
-            "type": "object",
-            "properties": {
-                "avatar": {
-                    "type": "string"
+                "exec_time": {
+                    "type": "number"
                 },
-                "display_name": {
-                    "type": "string"
+                "index": {
+                    "type": "integer"
                 },
-                "email": {
-                    "type": "string"
+                "is_new_filter": {
+                    "type": "boolean"
                 },
-                "expired_time": {
-                    "type": "string"
+                "length": {
+                    "type": "integer"
                 },
-                "id": {
+                "next": {
                     "type": "integer"
                 },
-                "role": {
-                    "$ref": "#/definitions/model.RoleType"
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
                 },
-                "status": {
-                    "type": "string"
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LiveStreamRespDTO"
+                    }
                 },
-                "token": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer"
                 },
-                "username": {
+                "previous": {
+                    "type": "integer"
+                },
+                "query": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.ResetPasswordDTO": {
-            "type": "object",
-            "required": [
-                "confirmPassword",
-                "otp",
-                "password"
-            ],
-            "properties": {
-                "confirmPassword": {
-                    "type": "string",
-                    "minLength": 8
                 },
-                "otp": {
+                "route": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "total_items": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.RoleDTO": {
+        "utils.PaginationModel-dto_MostBlockedDTO": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "current_page": {
+                    "type": "integer"
                 },
-                "description": {
-                    "type": "string"
+                "exec_time": {
+                    "type": "number"
                 },
-                "id": {
+                "index": {
                     "type": "integer"
                 },
-                "type": {
-                    "$ref": "#/definitions/model.RoleType"
+                "is_new_filter": {
+                    "type": "boolean"
                 },
-                "updated_at": {
-                    "type": "string"
+                "length": {
+                    "type": "integer"
                 },
-                "users": {
+                "next": {
+                    "type": "integer"
+                },
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "page": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.UserResponseDTO"
+                        "$ref": "#/definitions/dto.MostBlockedDTO"
                     }
-                }
-            }
-        },
-        "dto.ScheduleStreamDTO": {
-            "type": "object",
-            "properties": {
-                "scheduled_at": {
-                    "type": "string"
                 },
-                "video_name": {
+                "page_size": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "integer"
+                },
+                "query": {
                     "type": "string"
                 },
-                "video_url": {
+                "route": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.StatisticsTotalLiveStreamDTO": {
-            "type": "object",
-            "properties": {
-                "active_live_streams": {
-                    "type": "integer"
                 },
-                "total_live_streams": {
+                "total_items": {
                     "type": "integer"
                 }
             }
         },
-        "dto.UpdateUserRequest": {
+        "utils.PaginationModel-dto_NotificationTemplateDTO": {
             "type": "object",
             "properties": {
-                "display_name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
-                },
-                "email": {
-                    "type": "string",
-                    "maxLength": 100
+                "current_page": {
+                    "type": "integer"
                 },
-                "role_type": {
-                    "enum": [
-                        "admin",
-                        "streamer",
-                        "user"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.RoleType"
-                        }
-                    ]
+                "exec_time": {
+                    "type": "number"
                 },
-                "updated_by_id": {
+                "index": {
                     "type": "integer"
                 },
-                "username": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3
-                }
-            }
-        },
-        "dto.UpdateUserResponse": {
-            "type": "object",
-            "properties": {
-                "avatar": {
-                    "type": "string"
+                "is_new_filter": {
+                    "type": "boolean"
                 },
-                "created_at": {
-                    "type": "string"
+                "length": {
+                    "type": "integer"
                 },
-                "display_name": {
-                    "type": "string"
+                "next": {
+                    "type": "integer"
                 },
-                "email": {
-                    "type": "string"
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
                 },
-                "id": {
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NotificationTemplateDTO"
+                    }
+                },
+                "page_size": {
                     "type": "integer"
                 },
-                "role": {
-                    "$ref": "#/definitions/model.RoleType"
+                "previous": {
+                    "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/model.UserStatusType"
+                "query": {
+                    "type": "string"
                 },
-                "username": {
+                "route": {
                     "type": "string"
+                },
+                "total_items": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.UserResponseDTO": {
+        "utils.PaginationModel-dto_ReportDTO": {
             "type": "object",
             "properties": {
-                "avatar_file_name": {
-                    "type": "string"
+                "current_page": {
+                    "type": "integer"
                 },
-                "blocked_reason": {
-                    "type": "string"
+                "exec_time": {
+                    "type": "number"
                 },
-                "created_at": {
-                    "type": "string"
+                "index": {
+                    "type": "integer"
                 },
-                "created_by": {
-                    "$ref": "#/definitions/dto.UserResponseDTO"
+                "is_new_filter": {
+                    "type": "boolean"
                 },
-                "created_by_id": {
+                "length": {
                     "type": "integer"
                 },
-                "deleted_by_id": {
+                "next": {
                     "type": "integer"
                 },
-                "display_name": {
-                    "type": "string"
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
                 },
-                "email": {
-                    "type": "string"
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReportDTO"
+                    }
                 },
-                "id": {
+                "page_size": {
                     "type": "integer"
                 },
-                "role": {
-                    "$ref": "#/definitions/dto.RoleDTO"
-                },
-                "role_id": {
+                "previous": {
                     "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/model.UserStatusType"
-                },
-                "updated_at": {
+                "query": {
                     "type": "string"
                 },
-                "updated_by": {
-                    "$ref": "#/definitions/dto.UserResponseDTO"
+                "route": {
+                    "type": "string"
                 },
-                "updated_by_id": {
+                "total_items": {
                     "type": "integer"
-                },
-                "username": {
-                    "type": "string"
                 }
             }
         },
-        "dto.UserStatisticsResponse": {
+        "utils.PaginationModel-dto_StorageUsageRespDTO": {
             "type": "object",
             "properties": {
-                "display_name": {
-                    "type": "string"
+                "current_page": {
+                    "type": "integer"
                 },
-                "role_type": {
-                    "$ref": "#/definitions/model.RoleType"
+                "exec_time": {
+                    "type": "number"
                 },
-                "total_comments": {
+                "index": {
                     "type": "integer"
                 },
-                "total_likes": {
+                "is_new_filter": {
+                    "type": "boolean"
+                },
+                "length": {
                     "type": "integer"
                 },
-                "total_streams": {
+                "next": {
                     "type": "integer"
                 },
-                "total_views": {
+                "obj": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "page": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.StorageUsageRespDTO"
+                    }
+                },
+                "page_size": {
                     "type": "integer"
                 },
-                "user_id": {
+                "previous": {
                     "type": "integer"
                 },
-                "username": {
+                "query": {
+                    "type": "string"
+                },
+                "route": {
                     "type": "string"
+                },
+                "total_items": {
+                    "type": "integer"
                 }
             }
         },
-        "model.RoleType": {
-            "type": "string",
-            "enum": [
-                "super_admin",
-                "admin",
-                "streamer",
-                "user"
-            ],
-            "x-enum-varnames": [
-                "SUPPERADMINROLE",
-                "ADMINROLE",
-                "STREAMER",
-                "USERROLE"
-            ]
-        },
-        "model.StreamStatus": {
-            "type": "string",
-            "enum": [
-                "pending",
-                "started",
-                "ended",
-                "upcoming"
-            ],
-            "x-enum-varnames": [
-                "PENDING",
-                "STARTED",
-                "ENDED",
-                "UPCOMING"
-            ]
-        },
-        "model.StreamType": {
-            "type": "string",
-            "enum": [
-                "camera",
-                "software",
-                "pre_record"
-            ],
-            "x-enum-comments": {
-                "SOFTWARESTREAM": "like obs"
-            },
-            "x-enum-varnames": [
-                "CAMERASTREAM",
-                "SOFTWARESTREAM",
-                "PRERECORDSTREAM"
-            ]
-        },
-        "model.UserStatusType": {
-            "type": "string",
-            "enum": [
-                "online",
-                "offline",
-                "blocked"
-            ],
-            "x-enum-varnames": [
-                "ONLINE",
-                "OFFLINE",
-                "BLOCKED"
-            ]
-        },
-        "utils.PaginationModel-dto_AdminLogRespDTO": {
+        "utils.PaginationModel-dto_StreamRecurrenceRespDTO": {
             "type": "object",
             "properties": {
                 "current_page": {
@@ -2399,7 +11234,7 @@ const docTemplate = `{
                 "page": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.AdminLogRespDTO"
+                        "$ref": "#/definitions/dto.StreamRecurrenceRespDTO"
                     }
                 },
                 "page_size": {
@@ -2419,7 +11254,7 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.PaginationModel-dto_CategoryRespDto": {
+        "utils.PaginationModel-dto_SubscriptionDTO": {
             "type": "object",
             "properties": {
                 "current_page": {
@@ -2447,7 +11282,7 @@ const docTemplate = `{
                 "page": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.CategoryRespDto"
+                        "$ref": "#/definitions/dto.SubscriptionDTO"
                     }
                 },
                 "page_size": {
@@ -2467,7 +11302,7 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.PaginationModel-dto_LiveStatRespDTO": {
+        "utils.PaginationModel-dto_TrashItemDTO": {
             "type": "object",
             "properties": {
                 "current_page": {
@@ -2495,7 +11330,7 @@ const docTemplate = `{
                 "page": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.LiveStatRespDTO"
+                        "$ref": "#/definitions/dto.TrashItemDTO"
                     }
                 },
                 "page_size": {
@@ -2515,7 +11350,7 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.PaginationModel-dto_LiveStreamBroadCastDTO": {
+        "utils.PaginationModel-dto_UserBlockDTO": {
             "type": "object",
             "properties": {
                 "current_page": {
@@ -2543,7 +11378,7 @@ const docTemplate = `{
                 "page": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.LiveStreamBroadCastDTO"
+                        "$ref": "#/definitions/dto.UserBlockDTO"
                     }
                 },
                 "page_size": {
@@ -2563,7 +11398,7 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.PaginationModel-dto_LiveStreamRespDTO": {
+        "utils.PaginationModel-dto_UserResponseDTO": {
             "type": "object",
             "properties": {
                 "current_page": {
@@ -2591,7 +11426,7 @@ const docTemplate = `{
                 "page": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.LiveStreamRespDTO"
+                        "$ref": "#/definitions/dto.UserResponseDTO"
                     }
                 },
                 "page_size": {
@@ -2611,7 +11446,7 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.PaginationModel-dto_UserResponseDTO": {
+        "utils.PaginationModel-dto_UserSegmentDTO": {
             "type": "object",
             "properties": {
                 "current_page": {
@@ -2639,7 +11474,7 @@ const docTemplate = `{
                 "page": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.UserResponseDTO"
+                        "$ref": "#/definitions/dto.UserSegmentDTO"
                     }
                 },
                 "page_size": {
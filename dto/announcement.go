@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+type AnnouncementQuery struct {
+	Status  model.AnnouncementStatus `query:"status" validate:"omitempty,oneof=scheduled sending sent canceled failed"`
+	Keyword string                   `query:"keyword" validate:"omitempty,max=100"`
+	Page    uint                     `query:"page" validate:"required,min=1"`
+	Limit   uint                     `query:"limit" validate:"required,min=1,max=20"`
+}
+
+// CreateAnnouncementRequest targets all users, a role, viewers of a category's streams or a saved segment.
+// Announcements without scheduled_at are sent right away.
+type CreateAnnouncementRequest struct {
+	Title       string                   `json:"title" validate:"required,min=3,max=100"`
+	Body        string                   `json:"body" validate:"required,max=2000"`
+	StreamID    *uint                    `json:"stream_id" validate:"omitempty,min=1"`
+	TargetType  model.AnnouncementTarget `json:"target_type" validate:"required,oneof=all role category_viewers segment"`
+	TargetRole  model.RoleType           `json:"target_role" validate:"required_if=TargetType role,omitempty,oneof=admin streamer user"`
+	CategoryID  uint                     `json:"category_id" validate:"required_if=TargetType category_viewers"`
+	SegmentID   uint                     `json:"segment_id" validate:"required_if=TargetType segment"`
+	ScheduledAt string                   `json:"scheduled_at" validate:"omitempty,datetime=2006-01-02 15:04:05.999 -0700"` //expect in utc
+}
+
+type AnnouncementDTO struct {
+	ID          uint                     `json:"id"`
+	Title       string                   `json:"title"`
+	Body        string                   `json:"body"`
+	StreamID    *uint                    `json:"stream_id,omitempty"`
+	TargetType  model.AnnouncementTarget `json:"target_type"`
+	TargetRole  model.RoleType           `json:"target_role,omitempty"`
+	CategoryID  *uint                    `json:"category_id,omitempty"`
+	SegmentID   *uint                    `json:"segment_id,omitempty"`
+	Status      model.AnnouncementStatus `json:"status"`
+	ScheduledAt time.Time                `json:"scheduled_at"`
+	JobID       *uint                    `json:"job_id,omitempty"` // fans out the notifications
+	Recipients  uint                     `json:"recipients"`
+	StartedAt   *time.Time               `json:"started_at,omitempty"`
+	SentAt      *time.Time               `json:"sent_at,omitempty"`
+	CanceledAt  *time.Time               `json:"canceled_at,omitempty"`
+	CreatedBy   *UserResponseDTO         `json:"created_by,omitempty"`
+	CreatedAt   time.Time                `json:"created_at"`
+}
+
+// AnnouncementStatsDTO counts notifications of an announcement, notifications of purged users are gone
+type AnnouncementStatsDTO struct {
+	AnnouncementID uint                     `json:"announcement_id"`
+	Status         model.AnnouncementStatus `json:"status"`
+	Recipients     uint                     `json:"recipients"`
+	Delivered      int64                    `json:"delivered"`
+	Read           int64                    `json:"read"`
+	Hidden         int64                    `json:"hidden"`
+	ReadRatio      float64                  `json:"read_ratio"`
+	FirstReadAt    *time.Time               `json:"first_read_at,omitempty"`
+	LastReadAt     *time.Time               `json:"last_read_at,omitempty"`
+}
+
+type UserSegmentQuery struct {
+	Keyword string `query:"keyword" validate:"omitempty,max=100"`
+	Page    uint   `query:"page" validate:"required,min=1"`
+	Limit   uint   `query:"limit" validate:"required,min=1,max=20"`
+}
+
+// UserSegmentRequest saves a user filter, page, limit and sort of the filter are ignored
+type UserSegmentRequest struct {
+	Name   string    `json:"name" validate:"required,min=3,max=100"`
+	Filter UserQuery `json:"filter"`
+}
+
+type UserSegmentDTO struct {
+	ID        uint             `json:"id"`
+	Name      string           `json:"name"`
+	Filter    UserQuery        `json:"filter"`
+	Users     int64            `json:"users"` // matching the filter now
+	CreatedBy *UserResponseDTO `json:"created_by,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+const (
+	USER_BLOCK_DIRECTION_BLOCKING   = "blocking"
+	USER_BLOCK_DIRECTION_BLOCKED_BY = "blocked_by"
+
+	MOST_BLOCKED_DEFAULT_DAYS = 30
+)
+
+// UserBlockQuery lists users blocked by the user, or users who blocked it with blocked_by
+type UserBlockQuery struct {
+	Direction string `query:"direction" validate:"omitempty,oneof=blocking blocked_by"`
+	Page      uint   `query:"page" validate:"required,min=1"`
+	Limit     uint   `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type UserBlockDTO struct {
+	User      *UserResponseDTO `json:"user,omitempty"` // the other side of the block
+	BlockedAt time.Time        `json:"blocked_at"`
+}
+
+// CreateUserBlockRequest blocks BlockedUserID on behalf of the user
+type CreateUserBlockRequest struct {
+	BlockedUserID uint   `json:"blocked_user_id" validate:"required,min=1"`
+	Reason        string `json:"reason" validate:"omitempty,max=255"` // for the admin log
+}
+
+// MostBlockedQuery ranks users by how many users blocked them in the range
+type MostBlockedQuery struct {
+	From      int64 `query:"from" validate:"omitempty"` // unix time, MOST_BLOCKED_DEFAULT_DAYS ago when empty
+	To        int64 `query:"to" validate:"omitempty"`
+	MinBlocks uint  `query:"min_blocks" validate:"omitempty,min=1"`
+	Page      uint  `query:"page" validate:"required,min=1"`
+	Limit     uint  `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type MostBlockedDTO struct {
+	User          *UserResponseDTO `json:"user,omitempty"`
+	Blocks        int64            `json:"blocks"`       // in the range
+	TotalBlocks   int64            `json:"total_blocks"` // of all time
+	LastBlockedAt time.Time        `json:"last_blocked_at"`
+}
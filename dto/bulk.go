@@ -0,0 +1,67 @@
+package dto
+
+import "gitlab/live/be-live-admin/model"
+
+const (
+	BULK_ACTION_BLOCK        = "block"
+	BULK_ACTION_REACTIVATE   = "reactivate"
+	BULK_ACTION_DELETE       = "delete"
+	BULK_ACTION_CHANGE_ROLE  = "change_role"
+	BULK_ACTION_END_LIVE     = "end_live"
+	BULK_ACTION_RECATEGORIZE = "recategorize"
+)
+
+const (
+	BULK_ITEM_SUCCEEDED = "succeeded"
+	BULK_ITEM_FAILED    = "failed"
+	BULK_ITEM_SKIPPED   = "skipped"
+)
+
+// BulkUserRequest selects users by ids or by a filter, page, limit and sort of the filter are ignored
+type BulkUserRequest struct {
+	IDs      []uint         `json:"ids" validate:"omitempty,max=1000,dive,required"`
+	Filter   *UserQuery     `json:"filter" validate:"omitempty"`
+	Action   string         `json:"action" validate:"required,oneof=block reactivate delete change_role"`
+	Reason   string         `json:"reason" validate:"required_if=Action block,omitempty,min=3,max=255"`
+	RoleType model.RoleType `json:"role_type" validate:"required_if=Action change_role,omitempty,oneof=admin streamer user"`
+}
+
+// BulkStreamRequest selects streams by ids or by a filter, page, limit and sort of the filter are ignored
+type BulkStreamRequest struct {
+	IDs         []uint                       `json:"ids" validate:"omitempty,max=1000,dive,required"`
+	Filter      *LiveStreamBroadCastQueryDTO `json:"filter" validate:"omitempty"`
+	Action      string                       `json:"action" validate:"required,oneof=delete end_live recategorize"`
+	CategoryIDs []uint                       `json:"category_ids" validate:"required_if=Action recategorize,omitempty,max=3,dive,required"`
+}
+
+type BulkItemResult struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkResult is saved as the result of bulk jobs
+type BulkResult struct {
+	Action    string           `json:"action"`
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Skipped   int              `json:"skipped"`
+	Items     []BulkItemResult `json:"items"`
+}
+
+func (r *BulkResult) Add(id uint, status string, err error) {
+	item := BulkItemResult{ID: id, Status: status}
+	if err != nil {
+		item.Error = err.Error()
+	}
+	switch status {
+	case BULK_ITEM_SUCCEEDED:
+		r.Succeeded++
+	case BULK_ITEM_FAILED:
+		r.Failed++
+	case BULK_ITEM_SKIPPED:
+		r.Skipped++
+	}
+	r.Items = append(r.Items, item)
+}
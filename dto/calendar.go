@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+const (
+	CONFLICT_REASON_STREAMER    = "streamer"
+	CONFLICT_REASON_CATEGORY    = "category"
+	CONFLICT_REASON_CONCURRENCY = "concurrency"
+)
+
+type CalendarQuery struct {
+	From       string `query:"from" validate:"required,datetime=2006-01-02"`
+	To         string `query:"to" validate:"required,datetime=2006-01-02"` // inclusive
+	Timezone   string `query:"timezone" validate:"omitempty,timezone"`     // groups occurrences by day there, UTC by default
+	UserID     uint   `query:"user_id"`
+	CategoryID uint   `query:"category_id"`
+}
+
+type CalendarFeedQuery struct {
+	UserID     uint `query:"user_id" validate:"required_without=CategoryID"`
+	CategoryID uint `query:"category_id" validate:"required_without=UserID"`
+}
+
+type CalendarFeedRespDTO struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CalendarOccurrenceDTO is a scheduled stream, StreamID is 0 for occurrences of a series which are not created yet
+type CalendarOccurrenceDTO struct {
+	StreamID      uint               `json:"stream_id,omitempty"`
+	RecurrenceID  *uint              `json:"recurrence_id,omitempty"`
+	Title         string             `json:"title"`
+	Status        model.StreamStatus `json:"status"`
+	User          *UserResponseDTO   `json:"user"`
+	Categories    []CategoryDTO      `json:"categories"`
+	StartAt       time.Time          `json:"start_at"`
+	EndAt         time.Time          `json:"end_at"`
+	VideoDuration uint               `json:"video_duration"` // in seconds, 0 when it's unknown
+}
+
+type CalendarDayDTO struct {
+	Date        string                  `json:"date"` // 2006-01-02
+	Occurrences []CalendarOccurrenceDTO `json:"occurrences"`
+}
+
+// ScheduleConflictDTO is an existing stream overlapping the requested slot starting at OccurrenceAt
+type ScheduleConflictDTO struct {
+	Reason       string    `json:"reason"` // streamer, category or concurrency
+	OccurrenceAt time.Time `json:"occurrence_at"`
+	StreamID     uint      `json:"stream_id"`
+	Title        string    `json:"title"`
+	UserID       uint      `json:"user_id"`
+	CategoryID   uint      `json:"category_id,omitempty"`
+	StartAt      time.Time `json:"start_at"`
+	EndAt        time.Time `json:"end_at"`
+}
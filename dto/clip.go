@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+type ClipRequest struct {
+	Title       string `json:"title" validate:"omitempty,max=100"`
+	StartOffset uint   `json:"start_offset"`                   // in milliseconds
+	EndOffset   uint   `json:"end_offset" validate:"required"` // in milliseconds
+	FileName    string `json:"-"`
+	CreatedByID uint   `json:"-"`
+}
+
+type ClipDTO struct {
+	ID          uint             `json:"id"`
+	StreamID    uint             `json:"stream_id"`
+	Title       string           `json:"title"`
+	StartOffset uint             `json:"start_offset"` // in milliseconds
+	EndOffset   uint             `json:"end_offset"`   // in milliseconds
+	Status      model.ClipStatus `json:"status"`
+	URL         string           `json:"url,omitempty"` // ready clips only
+	Size        int64            `json:"size"`          // in bytes
+	Duration    uint             `json:"duration"`      // in milliseconds
+	Error       string           `json:"error,omitempty"`
+	CreatedBy   *UserResponseDTO `json:"created_by,omitempty"`
+	JobID       uint             `json:"job_id,omitempty"` // job cutting the clip, returned on create
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
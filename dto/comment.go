@@ -0,0 +1,74 @@
+package dto
+
+import "time"
+
+const (
+	COMMENT_EVENT_HIDDEN   = "hidden"
+	COMMENT_EVENT_UNHIDDEN = "unhidden"
+	COMMENT_EVENT_DELETED  = "deleted"
+)
+
+const (
+	COMMENT_TIMELINE_DEFAULT_INTERVAL = 60
+	COMMENT_TIMELINE_MAX_BUCKETS      = 1000
+)
+
+type CommentQuery struct {
+	StreamID uint   `query:"stream_id" validate:"omitempty,min=1"`
+	UserID   uint   `query:"user_id" validate:"omitempty,min=1"`
+	From     int64  `query:"from" validate:"omitempty"` // unix time
+	To       int64  `query:"to" validate:"omitempty"`
+	Keyword  string `query:"keyword" validate:"omitempty,max=255"`
+	Flagged  *bool  `query:"flagged" validate:"omitempty"`
+	Hidden   *bool  `query:"hidden" validate:"omitempty"`
+	Page     uint   `query:"page" validate:"required,min=1"`
+	Limit    uint   `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type CommentDTO struct {
+	ID           uint             `json:"id"`
+	StreamID     uint             `json:"stream_id"`
+	StreamTitle  string           `json:"stream_title"`
+	User         *UserResponseDTO `json:"user,omitempty"`
+	Comment      string           `json:"comment"`
+	Flagged      bool             `json:"flagged"`
+	FlagReason   string           `json:"flag_reason,omitempty"`
+	Hidden       bool             `json:"hidden"`
+	HiddenAt     *time.Time       `json:"hidden_at,omitempty"`
+	HiddenReason string           `json:"hidden_reason,omitempty"`
+	HiddenBy     *UserResponseDTO `json:"hidden_by,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+type HideCommentRequest struct {
+	Reason string `json:"reason" validate:"omitempty,max=255"`
+}
+
+type CommentTimelineQuery struct {
+	Interval uint `query:"interval" validate:"omitempty,min=10,max=86400"` // in seconds, COMMENT_TIMELINE_DEFAULT_INTERVAL when empty
+}
+
+type CommentTimelineBucket struct {
+	Offset  int64     `json:"offset"` // seconds since the start of the stream
+	Start   time.Time `json:"start"`
+	Total   int64     `json:"total"`
+	Hidden  int64     `json:"hidden"`
+	Flagged int64     `json:"flagged"`
+}
+
+// CommentTimelineDTO counts comments of a stream per interval, from its start or its first comment
+type CommentTimelineDTO struct {
+	StreamID  uint                    `json:"stream_id"`
+	StartedAt *time.Time              `json:"started_at,omitempty"`
+	EndedAt   *time.Time              `json:"ended_at,omitempty"`
+	Interval  uint                    `json:"interval"`
+	Buckets   []CommentTimelineBucket `json:"buckets"`
+}
+
+// CommentModerationEvent is published on cache.CHANNEL_COMMENT_MODERATION
+type CommentModerationEvent struct {
+	Action    string `json:"action"`
+	CommentID uint   `json:"comment_id"`
+	StreamID  uint   `json:"stream_id"`
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+const (
+	COMMENT_FILTER_EVALUATE_MAX_COMMENTS = 100000
+	COMMENT_FILTER_EVALUATE_BATCH_SIZE   = 1000
+)
+
+const (
+	COMMENT_FILTER_EVENT_SAVED   = "saved"
+	COMMENT_FILTER_EVENT_DELETED = "deleted"
+)
+
+type CommentFilterRuleRequest struct {
+	Name         string                    `json:"name" validate:"required,min=3,max=100"`
+	Type         model.CommentFilterType   `json:"type" validate:"required,oneof=word regex"`
+	Pattern      string                    `json:"pattern" validate:"required,max=500"`
+	CategoryID   *uint                     `json:"category_id"` // omitted for global rules
+	Action       model.CommentFilterAction `json:"action" validate:"required,oneof=hide flag block_user"`
+	HitThreshold uint                      `json:"hit_threshold" validate:"required_if=Action block_user,max=1000"`
+	Enabled      bool                      `json:"enabled"`
+	CreatedByID  uint                      `json:"-"`
+}
+
+type CommentFilterRuleQuery struct {
+	Enabled    *bool                     `query:"enabled" validate:"omitempty"`
+	CategoryID uint                      `query:"category_id" validate:"omitempty,min=1"`
+	Action     model.CommentFilterAction `query:"action" validate:"omitempty,oneof=hide flag block_user"`
+	Keyword    string                    `query:"keyword" validate:"omitempty,max=255"`
+	Page       uint                      `query:"page" validate:"required,min=1"`
+	Limit      uint                      `query:"limit" validate:"required,min=1,max=20"`
+}
+
+// CommentFilterRuleDTO is also the format of rules in cache.COMMENT_FILTER_RULES_KEY
+type CommentFilterRuleDTO struct {
+	ID           uint                      `json:"id"`
+	Name         string                    `json:"name"`
+	Type         model.CommentFilterType   `json:"type"`
+	Pattern      string                    `json:"pattern"`
+	CategoryID   *uint                     `json:"category_id"`
+	CategoryName string                    `json:"category_name,omitempty"`
+	Action       model.CommentFilterAction `json:"action"`
+	HitThreshold uint                      `json:"hit_threshold,omitempty"`
+	Enabled      bool                      `json:"enabled"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+	CreatedByID  uint                      `json:"created_by_id"`
+	UpdatedByID  uint                      `json:"updated_by_id"`
+}
+
+// CommentFilterEvent is published on cache.CHANNEL_COMMENT_FILTER_RULES
+type CommentFilterEvent struct {
+	Action string `json:"action"`
+	RuleID uint   `json:"rule_id"`
+}
+
+// CommentFilterEvaluateRequest runs rules, enabled or not, against stored comments, newest first
+type CommentFilterEvaluateRequest struct {
+	RuleIDs    []uint `json:"rule_ids" validate:"required,min=1,max=50"`
+	StreamID   uint   `json:"stream_id" validate:"omitempty,min=1"`
+	From       int64  `json:"from" validate:"omitempty"` // unix time
+	To         int64  `json:"to" validate:"omitempty"`
+	SampleSize uint   `json:"sample_size" validate:"omitempty,max=20"`
+}
+
+type CommentFilterSampleDTO struct {
+	CommentID uint      `json:"comment_id"`
+	StreamID  uint      `json:"stream_id"`
+	UserID    uint      `json:"user_id"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CommentFilterRuleEvaluationDTO struct {
+	RuleID  uint                      `json:"rule_id"`
+	Name    string                    `json:"name"`
+	Action  model.CommentFilterAction `json:"action"`
+	Matches int64                     `json:"matches"`
+	Users   int                       `json:"users"`
+	// users with at least hit_threshold matches, for block_user rules
+	BlockedUserIDs []uint                   `json:"blocked_user_ids,omitempty"`
+	Samples        []CommentFilterSampleDTO `json:"samples"`
+}
+
+type CommentFilterEvaluationDTO struct {
+	Scanned int64 `json:"scanned"`
+	Matched int64 `json:"matched"` // comments matched by any rule
+	// more than COMMENT_FILTER_EVALUATE_MAX_COMMENTS comments are in range, older ones were not scanned
+	Truncated bool                             `json:"truncated"`
+	Rules     []CommentFilterRuleEvaluationDTO `json:"rules"`
+}
+
+// comment read by evaluation
+type CommentFilterCommentDTO struct {
+	ID        uint
+	StreamID  uint
+	UserID    uint
+	Comment   string
+	CreatedAt time.Time
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+type OrphanFileDTO struct {
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+type FileGCReport struct {
+	DryRun       bool            `json:"dry_run"`
+	ScannedFiles int             `json:"scanned_files"`
+	SkippedFiles int             `json:"skipped_files"` // in grace period or being encoded
+	Orphans      []OrphanFileDTO `json:"orphans"`
+	DeletedFiles int             `json:"deleted_files"`
+	FreedBytes   int64           `json:"freed_bytes"`
+	Errors       []string        `json:"errors,omitempty"`
+}
@@ -0,0 +1,190 @@
+package dto
+
+import (
+	"encoding/json"
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+const GDPR_POLICY_VERSION = "2026-10"
+
+const (
+	GDPR_ACTION_EXPORTED   = "exported"
+	GDPR_ACTION_DELETED    = "deleted"
+	GDPR_ACTION_ANONYMIZED = "anonymized"
+	GDPR_ACTION_KEPT       = "kept"
+)
+
+// records of a user, used as file names in export zips and in reports
+const (
+	GDPR_RECORD_PROFILE       = "profile"
+	GDPR_RECORD_STREAMS       = "streams"
+	GDPR_RECORD_COMMENTS      = "comments"
+	GDPR_RECORD_LIKES         = "likes"
+	GDPR_RECORD_VIEWS         = "views"
+	GDPR_RECORD_SHARES        = "shares"
+	GDPR_RECORD_BOOKMARKS     = "bookmarks"
+	GDPR_RECORD_SUBSCRIPTIONS = "subscriptions"
+	GDPR_RECORD_NOTIFICATIONS = "notifications"
+	GDPR_RECORD_BLOCKED_LIST  = "blocked_list"
+	GDPR_RECORD_TWO_FA        = "two_fa"
+	GDPR_RECORD_RECURRENCES   = "recurrences"
+	GDPR_RECORD_STORAGE_USAGE = "storage_usage"
+	GDPR_RECORD_FILES         = "files"
+)
+
+type GDPRPolicyEntry struct {
+	Record string `json:"record"`
+	Action string `json:"action"`
+	Detail string `json:"detail"`
+}
+
+// GDPRErasurePolicy is what erasure does to each record of a user, it's returned by the api and copied into reports
+var GDPRErasurePolicy = []GDPRPolicyEntry{
+	{GDPR_RECORD_PROFILE, GDPR_ACTION_ANONYMIZED, "username, email and display name are replaced, password, otp and blocked reason are cleared, avatar is removed and the account is blocked"},
+	{GDPR_RECORD_STREAMS, GDPR_ACTION_DELETED, "streams are deleted with thumbnails, videos, recordings and clips, streams under legal hold are kept"},
+	{GDPR_RECORD_RECURRENCES, GDPR_ACTION_DELETED, "recurring schedules are deleted, their files are removed by file gc"},
+	{GDPR_RECORD_COMMENTS, GDPR_ACTION_DELETED, "comment text is personal data"},
+	{GDPR_RECORD_LIKES, GDPR_ACTION_ANONYMIZED, "kept on the anonymized account so stream statistics stay correct"},
+	{GDPR_RECORD_VIEWS, GDPR_ACTION_ANONYMIZED, "kept on the anonymized account so stream statistics stay correct"},
+	{GDPR_RECORD_SHARES, GDPR_ACTION_ANONYMIZED, "kept on the anonymized account so stream statistics stay correct"},
+	{GDPR_RECORD_BOOKMARKS, GDPR_ACTION_DELETED, ""},
+	{GDPR_RECORD_SUBSCRIPTIONS, GDPR_ACTION_DELETED, "as subscriber and as streamer"},
+	{GDPR_RECORD_NOTIFICATIONS, GDPR_ACTION_DELETED, ""},
+	{GDPR_RECORD_BLOCKED_LIST, GDPR_ACTION_DELETED, "blocks by and of the user"},
+	{GDPR_RECORD_TWO_FA, GDPR_ACTION_DELETED, ""},
+	{GDPR_RECORD_STORAGE_USAGE, GDPR_ACTION_DELETED, ""},
+}
+
+type GDPRErasureRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=1000"`
+}
+
+type GDPRRequestQuery struct {
+	UserID uint                    `query:"user_id" validate:"omitempty,min=1"`
+	Type   model.GDPRRequestType   `query:"type" validate:"omitempty,oneof=export erasure"`
+	Status model.GDPRRequestStatus `query:"status" validate:"omitempty,oneof=pending completed failed"`
+	Page   uint                    `query:"page" validate:"required,min=1"`
+	Limit  uint                    `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type GDPRRequestCreatedDTO struct {
+	RequestID uint `json:"request_id"`
+	JobID     uint `json:"job_id"`
+}
+
+type GDPRRequestDTO struct {
+	ID          uint                    `json:"id"`
+	UserID      uint                    `json:"user_id"`
+	Username    string                  `json:"username"`
+	Type        model.GDPRRequestType   `json:"type"`
+	Status      model.GDPRRequestStatus `json:"status"`
+	Reason      string                  `json:"reason,omitempty"`
+	JobID       *uint                   `json:"job_id,omitempty"`
+	DownloadURL string                  `json:"download_url,omitempty"` // until the export expires
+	Report      json.RawMessage         `json:"report,omitempty" swaggertype:"object"`
+	Signature   string                  `json:"signature,omitempty"`
+	// whether the stored report still matches its signature
+	SignatureValid bool             `json:"signature_valid"`
+	Error          string           `json:"error,omitempty"`
+	RequestedBy    *UserResponseDTO `json:"requested_by,omitempty"`
+	CompletedAt    *time.Time       `json:"completed_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+type GDPRRecordResult struct {
+	Record string `json:"record"`
+	Action string `json:"action"`
+	Count  int64  `json:"count"`
+}
+
+// GDPRReport is stored signed when a request is completed
+type GDPRReport struct {
+	RequestID     uint                  `json:"request_id"`
+	Type          model.GDPRRequestType `json:"type"`
+	UserID        uint                  `json:"user_id"`
+	Username      string                `json:"username"`
+	RequestedByID uint                  `json:"requested_by_id"`
+	Reason        string                `json:"reason,omitempty"`
+	PolicyVersion string                `json:"policy_version"`
+	Policy        []GDPRPolicyEntry     `json:"policy,omitempty"`
+	Records       []GDPRRecordResult    `json:"records"`
+	FileName      string                `json:"file_name,omitempty"`
+	FileSize      int64                 `json:"file_size,omitempty"`
+	FileSHA256    string                `json:"file_sha256,omitempty"`
+	MissingFiles  []string              `json:"missing_files,omitempty"`
+	StartedAt     time.Time             `json:"started_at"`
+	CompletedAt   time.Time             `json:"completed_at"`
+}
+
+func (r *GDPRReport) Add(record, action string, count int64) {
+	r.Records = append(r.Records, GDPRRecordResult{Record: record, Action: action, Count: count})
+}
+
+// records of export zips
+
+type GDPRProfileDTO struct {
+	ID             uint                 `json:"id"`
+	Username       string               `json:"username"`
+	DisplayName    string               `json:"display_name"`
+	Email          string               `json:"email"`
+	Role           model.RoleType       `json:"role"`
+	Status         model.UserStatusType `json:"status"`
+	BlockedReason  string               `json:"blocked_reason,omitempty"`
+	AvatarFileName string               `json:"avatar_file_name,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+type GDPRStreamDTO struct {
+	ID                uint       `json:"id"`
+	Title             string     `json:"title"`
+	Description       string     `json:"description"`
+	Status            string     `json:"status"`
+	StreamType        string     `json:"stream_type"`
+	ThumbnailFileName string     `json:"thumbnail_file_name"`
+	StartedAt         *time.Time `json:"started_at"`
+	EndedAt           *time.Time `json:"ended_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
+}
+
+type GDPRCommentDTO struct {
+	ID        uint      `json:"id"`
+	StreamID  uint      `json:"stream_id"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GDPRLikeDTO struct {
+	StreamID  uint      `json:"stream_id"`
+	LikeEmote string    `json:"like_emote"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GDPRViewDTO struct {
+	StreamID  uint      `json:"stream_id"`
+	ViewType  string    `json:"view_type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GDPRStreamRefDTO is used for shares and bookmarks
+type GDPRStreamRefDTO struct {
+	StreamID  uint      `json:"stream_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GDPRSubscriptionDTO struct {
+	StreamerID uint      `json:"streamer_id"`
+	IsMute     bool      `json:"is_mute"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GDPRNotificationDTO struct {
+	StreamID  *uint      `json:"stream_id"`
+	Type      string     `json:"type"`
+	Content   string     `json:"content"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+// CreateInviteRequest has the fields of CreateUserRequest except the password, which the invitee sets
+type CreateInviteRequest struct {
+	UserName    string         `json:"username" validate:"required,min=3,max=50"`
+	Email       string         `json:"email" validate:"required,email,max=100"`
+	DisplayName string         `json:"display_name" validate:"required,min=3,max=100"`
+	RoleType    model.RoleType `json:"role_type" validate:"required,oneof=admin streamer user"`
+}
+
+type InviteQuery struct {
+	Status model.InviteStatus `query:"status" validate:"omitempty,oneof=pending accepted revoked"`
+	Email  string             `query:"email" validate:"omitempty,max=100"`
+	Page   uint               `query:"page" validate:"required,min=1"`
+	Limit  uint               `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type InviteDTO struct {
+	ID          uint               `json:"id"`
+	Email       string             `json:"email"`
+	Username    string             `json:"username"`
+	DisplayName string             `json:"display_name"`
+	RoleType    model.RoleType     `json:"role_type"`
+	Status      model.InviteStatus `json:"status"`
+	// pending invites whose last link expired, they can be resent
+	Expired    bool             `json:"expired"`
+	ExpiresAt  *time.Time       `json:"expires_at,omitempty"`
+	SentAt     *time.Time       `json:"sent_at,omitempty"`
+	SentCount  uint             `json:"sent_count"`
+	LastError  string           `json:"last_error,omitempty"`
+	InvitedBy  *UserResponseDTO `json:"invited_by,omitempty"`
+	UserID     *uint            `json:"user_id,omitempty"`
+	AcceptedAt *time.Time       `json:"accepted_at,omitempty"`
+	RevokedAt  *time.Time       `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+type InviteCreatedDTO struct {
+	InviteID uint `json:"invite_id"`
+	JobID    uint `json:"job_id"` // sends the email
+}
+
+type AcceptInviteDTO struct {
+	Token           string `json:"token" validate:"required"`
+	NewPassword     string `json:"password" validate:"required,min=8,max=255"`
+	ConfirmPassword string `json:"confirmPassword" validate:"required,min=8,max=255"`
+}
@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"encoding/json"
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+type JobQuery struct {
+	Type   model.JobType   `query:"type" validate:"omitempty,oneof=remove_files cut_clip bulk_users bulk_streams purge_user purge_stream gdpr_export gdpr_erasure import_users send_invite"`
+	Status model.JobStatus `query:"status" validate:"omitempty,oneof=queued running succeeded failed canceled"`
+	Page   uint            `query:"page" validate:"required,min=1"`
+	Limit  uint            `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type JobDTO struct {
+	ID              uint             `json:"id"`
+	Type            model.JobType    `json:"type"`
+	Status          model.JobStatus  `json:"status"`
+	Payload         json.RawMessage  `json:"payload" swaggertype:"object"`
+	Progress        uint             `json:"progress"` // in percent
+	ProgressMessage string           `json:"progress_message,omitempty"`
+	Attempts        uint             `json:"attempts"`
+	MaxAttempts     uint             `json:"max_attempts"`
+	LastError       string           `json:"last_error,omitempty"`
+	Result          json.RawMessage  `json:"result,omitempty" swaggertype:"object"`
+	CancelRequested bool             `json:"cancel_requested"`
+	RunAt           time.Time        `json:"run_at"`
+	StartedAt       *time.Time       `json:"started_at,omitempty"`
+	FinishedAt      *time.Time       `json:"finished_at,omitempty"`
+	CreatedBy       *UserResponseDTO `json:"created_by,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// JobCreatedDTO is returned by endpoints which leave their work to a job
+type JobCreatedDTO struct {
+	JobID uint `json:"job_id"`
+}
+
+// payloads of job types
+
+type RemoveFileEntry struct {
+	Path     string                `json:"path"`
+	UserID   uint                  `json:"user_id"`   // storage usage of the user is reduced, 0 when untracked
+	FileType model.StorageFileType `json:"file_type"` // storage file type of the user
+}
+
+type RemoveFilesPayload struct {
+	Files []RemoveFileEntry `json:"files"`
+}
+
+type CutClipPayload struct {
+	ClipID        uint   `json:"clip_id"`
+	OwnerID       uint   `json:"owner_id"`
+	RecordingPath string `json:"recording_path"`
+	ClipPath      string `json:"clip_path"`
+}
+
+// BulkUsersPayload is resolved to ids when the job is queued, so the job doesn't pick up users created later
+type BulkUsersPayload struct {
+	Action        string         `json:"action"`
+	IDs           []uint         `json:"ids"`
+	Reason        string         `json:"reason,omitempty"`
+	RoleType      model.RoleType `json:"role_type,omitempty"`
+	PerformedByID uint           `json:"performed_by_id"`
+}
+
+type BulkStreamsPayload struct {
+	Action        string `json:"action"`
+	IDs           []uint `json:"ids"`
+	CategoryIDs   []uint `json:"category_ids,omitempty"`
+	PerformedByID uint   `json:"performed_by_id"`
+}
+
+// PurgePayload purges a trashed user or stream, nothing happens when it was restored or deleted again since
+type PurgePayload struct {
+	ID        uint      `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type GDPRPayload struct {
+	RequestID uint `json:"request_id"`
+}
+
+// ImportUsersPayload carries validated rows, their password hashes are saved as model.UserImportCredential
+type ImportUsersPayload struct {
+	Rows        []UserImportRow `json:"rows"`
+	Invite      bool            `json:"invite"`
+	CreatedByID uint            `json:"created_by_id"`
+}
+
+// SendInvitePayload mails a new link of an invite, nothing is sent when it's no longer pending
+type SendInvitePayload struct {
+	InviteID      uint `json:"invite_id"`
+	RequestedByID uint `json:"requested_by_id"`
+}
+
+// SendAnnouncementPayload fans out an announcement, nothing is sent when it was canceled
+type SendAnnouncementPayload struct {
+	AnnouncementID uint `json:"announcement_id"`
+}
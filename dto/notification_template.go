@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+type NotificationTemplateQuery struct {
+	Type   model.NotificationType `query:"type" validate:"omitempty,oneof=subscribe_live subscribe_video account_blocked account_deleted announcement"`
+	Locale string                 `query:"locale" validate:"omitempty,max=20"`
+	Page   uint                   `query:"page" validate:"required,min=1"`
+	Limit  uint                   `query:"limit" validate:"required,min=1,max=20"`
+}
+
+// NotificationTemplateRequest is saved as a new version, variables are written like {{.Username}}
+type NotificationTemplateRequest struct {
+	Title string `json:"title" validate:"omitempty,max=255"`
+	Body  string `json:"body" validate:"required,max=4000"`
+}
+
+type NotificationTemplateDTO struct {
+	ID        uint                   `json:"id"`
+	Type      model.NotificationType `json:"type"`
+	Locale    string                 `json:"locale"`
+	Version   uint                   `json:"version"`
+	Title     string                 `json:"title"`
+	Body      string                 `json:"body"`
+	CreatedBy *UserResponseDTO       `json:"created_by,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// NotificationTemplateVariablesDTO lists the variables of a type with the sample data of previews
+type NotificationTemplateVariablesDTO struct {
+	Type         model.NotificationType `json:"type"`
+	Variables    map[string]string      `json:"variables"`
+	DefaultTitle string                 `json:"default_title"`
+	DefaultBody  string                 `json:"default_body"`
+}
+
+// NotificationTemplatePreviewRequest renders body and title, or the template the locale resolves to without body.
+// Data overrides the sample data of the type.
+type NotificationTemplatePreviewRequest struct {
+	Type   model.NotificationType `json:"type" validate:"required,oneof=subscribe_live subscribe_video account_blocked account_deleted announcement"`
+	Locale string                 `json:"locale" validate:"omitempty,max=20,bcp47_language_tag"`
+	Title  string                 `json:"title" validate:"omitempty,max=255"`
+	Body   string                 `json:"body" validate:"omitempty,max=4000"`
+	Data   map[string]string      `json:"data"`
+}
+
+type RenderedNotificationDTO struct {
+	Type    model.NotificationType `json:"type"`
+	Locale  string                 `json:"locale"`  // of the template, the default one for built in templates
+	Version uint                   `json:"version"` // 0 for built in and previewed templates
+	Title   string                 `json:"title"`
+	Content string                 `json:"content"`
+}
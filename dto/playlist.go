@@ -0,0 +1,37 @@
+package dto
+
+import "gitlab/live/be-live-admin/model"
+
+type PlaylistItemRequest struct {
+	Type         model.PlaylistItemType `json:"type" form:"type" validate:"required,oneof=video slate"`
+	Position     *uint                  `json:"position" form:"position"`   // appended when omitted
+	InPoint      uint                   `json:"in_point" form:"in_point"`   // in milliseconds
+	OutPoint     uint                   `json:"out_point" form:"out_point"` // in milliseconds, 0 plays until the end
+	Duration     uint                   `json:"duration" form:"duration"`   // in milliseconds, shown time of slates
+	FileName     string                 `json:"-" form:"-"`
+	FileDuration uint                   `json:"-" form:"-"` // probed, in milliseconds
+}
+
+// omitted fields are kept
+type UpdatePlaylistItemRequest struct {
+	Position *uint `json:"position"`
+	InPoint  *uint `json:"in_point"`  // videos only
+	OutPoint *uint `json:"out_point"` // videos only
+	Duration *uint `json:"duration"`  // slates only
+}
+
+type PlaylistItemDTO struct {
+	ID           uint                   `json:"id"`
+	Position     uint                   `json:"position"`
+	Type         model.PlaylistItemType `json:"type"`
+	URL          string                 `json:"url"`
+	Duration     uint                   `json:"duration"` // in milliseconds
+	InPoint      uint                   `json:"in_point"`
+	OutPoint     uint                   `json:"out_point"`
+	PlayDuration uint                   `json:"play_duration"` // in milliseconds, after trimming
+}
+
+type PlaylistRespDTO struct {
+	TotalDuration uint              `json:"total_duration"` // in seconds
+	Items         []PlaylistItemDTO `json:"items"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// PublishBanRequest stops the user from going live for Duration seconds, until it's lifted when Duration is empty
+type PublishBanRequest struct {
+	Reason   string `json:"reason" validate:"required,min=3,max=255"`
+	Duration int64  `json:"duration" validate:"omitempty,min=3600,max=31536000"`
+}
+
+type PublishBanDTO struct {
+	ID        uint             `json:"id"`
+	Reason    string           `json:"reason"`
+	ExpiresAt *time.Time       `json:"expires_at,omitempty"` // nil until lifted
+	CreatedBy *UserResponseDTO `json:"created_by,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type PublishBanResponse struct {
+	Ban            PublishBanDTO `json:"ban"`
+	EndedStreamIDs []uint        `json:"ended_stream_ids"` // live streams of the user, ended through be-api
+}
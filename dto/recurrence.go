@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+type StreamRecurrenceRequest struct {
+	Title             string   `json:"title" form:"title" validate:"required"`
+	Description       string   `json:"description" form:"description" validate:"required"`
+	UserID            uint     `json:"user_id" form:"user_id" validate:"required"`
+	VideoFileName     string   `json:"-" form:"-"`
+	VideoDuration     uint     `json:"-" form:"-"` // in seconds
+	ThumbnailFileName string   `json:"-" form:"-"`
+	StartAt           string   `json:"start_at" form:"start_at" validate:"required,datetime=2006-01-02 15:04:05.999 -0700"` // first occurrence
+	RRule             string   `json:"rrule" form:"rrule" validate:"required,max=500"`                                      // e.g. FREQ=WEEKLY;BYDAY=MO,WE
+	Timezone          string   `json:"timezone" form:"timezone" validate:"required,timezone"`
+	ExDates           []string `json:"exdates" form:"exdates" validate:"omitempty,dive,datetime=2006-01-02 15:04:05.999 -0700"` // skipped occurrences
+	CategoryIDs       []uint   `json:"category_ids" form:"category_ids" validate:"required,max=3,dive,required"`
+	CreatedByID       uint     `json:"-" form:"-"`
+}
+
+// UpdateStreamRecurrenceRequest edits the occurrence of FromStreamID and all following ones, or every upcoming one when omitted.
+// A single occurrence is edited with the stream endpoints.
+type UpdateStreamRecurrenceRequest struct {
+	Title        string   `json:"title" validate:"required"`
+	Description  string   `json:"description" validate:"required"`
+	StartAt      string   `json:"start_at" validate:"omitempty,datetime=2006-01-02 15:04:05.999 -0700"` // moves the series, keeps the current one when omitted
+	RRule        string   `json:"rrule" validate:"required,max=500"`
+	Timezone     string   `json:"timezone" validate:"required,timezone"`
+	ExDates      []string `json:"exdates" validate:"omitempty,dive,datetime=2006-01-02 15:04:05.999 -0700"`
+	CategoryIDs  []uint   `json:"category_ids" validate:"required,max=3,dive,required"`
+	FromStreamID uint     `json:"from_stream_id"`
+	UpdatedByID  uint     `json:"-"`
+}
+
+type StreamRecurrenceQuery struct {
+	UserID uint `query:"user_id"`
+	Page   uint `query:"page" validate:"required,min=1"`
+	Limit  uint `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type StreamRecurrenceRespDTO struct {
+	ID                uint            `json:"id"`
+	User              UserResponseDTO `json:"user"`
+	Title             string          `json:"title"`
+	Description       string          `json:"description"`
+	RRule             string          `json:"rrule"`
+	Timezone          string          `json:"timezone"`
+	StartAt           time.Time       `json:"start_at"`
+	ExDates           []time.Time     `json:"exdates"`
+	Categories        []CategoryDTO   `json:"categories"`
+	ThumbnailURL      string          `json:"thumbnail_url"`
+	VideoURL          string          `json:"video_url"`
+	VideoDuration     uint            `json:"video_duration"`
+	MaterializedUntil *time.Time      `json:"materialized_until,omitempty"`
+	NextOccurrences   []time.Time     `json:"next_occurrences"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+const REPORT_STATS_DEFAULT_DAYS = 7
+
+// CreateReportRequest is posted by be-api with the token of the reporter
+type CreateReportRequest struct {
+	TargetType model.ReportTargetType `json:"target_type" validate:"required,oneof=stream comment user"`
+	TargetID   uint                   `json:"target_id" validate:"required,min=1"`
+	Reason     model.ReportReason     `json:"reason" validate:"required,oneof=self_harm violence hate harassment nudity spam copyright other"`
+	Text       string                 `json:"text" validate:"required_if=Reason other,omitempty,max=1000"`
+}
+
+type ReportCreatedDTO struct {
+	ReportID uint                 `json:"report_id"`
+	Severity model.ReportSeverity `json:"severity"`
+	DueAt    time.Time            `json:"due_at"`
+}
+
+// ReportQuery lists the queue by priority, then by due time
+type ReportQuery struct {
+	Status     model.ReportStatus     `query:"status" validate:"omitempty,oneof=open claimed resolved dismissed"`
+	TargetType model.ReportTargetType `query:"target_type" validate:"omitempty,oneof=stream comment user"`
+	TargetID   uint                   `query:"target_id" validate:"omitempty,min=1"`
+	Reason     model.ReportReason     `query:"reason" validate:"omitempty,oneof=self_harm violence hate harassment nudity spam copyright other"`
+	Severity   model.ReportSeverity   `query:"severity" validate:"omitempty,oneof=high medium low"`
+	AssigneeID uint                   `query:"assignee_id" validate:"omitempty,min=1"`
+	Unassigned bool                   `query:"unassigned" validate:"omitempty"`
+	Overdue    bool                   `query:"overdue" validate:"omitempty"` // unresolved past their SLA
+	Page       uint                   `query:"page" validate:"required,min=1"`
+	Limit      uint                   `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type ReportDTO struct {
+	ID         uint                   `json:"id"`
+	Reporter   *UserResponseDTO       `json:"reporter,omitempty"`
+	TargetType model.ReportTargetType `json:"target_type"`
+	TargetID   uint                   `json:"target_id"`
+	TargetUser *UserResponseDTO       `json:"target_user,omitempty"`
+	Reason     model.ReportReason     `json:"reason"`
+	Text       string                 `json:"text,omitempty"`
+	Severity   model.ReportSeverity   `json:"severity"`
+	Priority   int                    `json:"priority"`
+	Status     model.ReportStatus     `json:"status"`
+	DueAt      time.Time              `json:"due_at"`
+	// seconds left of the SLA, negative when overdue, 0 once the report is closed
+	RemainingSeconds int64              `json:"remaining_seconds"`
+	Overdue          bool               `json:"overdue"`
+	Assignee         *UserResponseDTO   `json:"assignee,omitempty"`
+	AssignedAt       *time.Time         `json:"assigned_at,omitempty"`
+	ResolvedBy       *UserResponseDTO   `json:"resolved_by,omitempty"`
+	ResolvedAt       *time.Time         `json:"resolved_at,omitempty"`
+	Action           model.ReportAction `json:"action,omitempty"`
+	Note             string             `json:"note,omitempty"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
+
+type AssignReportRequest struct {
+	AssigneeID uint `json:"assignee_id" validate:"required,min=1"`
+}
+
+// ResolveReportRequest runs the action on the target and resolves every unresolved report of it
+type ResolveReportRequest struct {
+	Action model.ReportAction `json:"action" validate:"required,oneof=none block_user delete_stream end_live hide_comment"`
+	Note   string             `json:"note" validate:"required_if=Action block_user,omitempty,min=3,max=1000"` // blocked reason of block_user
+}
+
+type DismissReportRequest struct {
+	Note string `json:"note" validate:"omitempty,max=1000"`
+}
+
+type ReportResolvedDTO struct {
+	Resolved int64 `json:"resolved"`         // reports of the target
+	JobID    *uint `json:"job_id,omitempty"` // purge of a deleted stream
+}
+
+type ReportStatsQuery struct {
+	From int64 `query:"from" validate:"omitempty"` // unix time, REPORT_STATS_DEFAULT_DAYS ago when empty
+	To   int64 `query:"to" validate:"omitempty"`
+}
+
+// ReportModeratorStatsDTO counts reports closed by a moderator in the range
+type ReportModeratorStatsDTO struct {
+	Moderator *UserResponseDTO `json:"moderator,omitempty"`
+	Resolved  int64            `json:"resolved"`
+	Dismissed int64            `json:"dismissed"`
+	Breached  int64            `json:"breached"` // closed after their SLA
+	// from filing to closing
+	AvgResolutionSeconds float64 `json:"avg_resolution_seconds"`
+	Claimed              int64   `json:"claimed"` // currently
+}
+
+type ReportStatsDTO struct {
+	From       time.Time                 `json:"from"`
+	To         time.Time                 `json:"to"`
+	Open       int64                     `json:"open"`
+	Claimed    int64                     `json:"claimed"`
+	Overdue    int64                     `json:"overdue"`
+	Moderators []ReportModeratorStatsDTO `json:"moderators"`
+}
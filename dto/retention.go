@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type RetentionPolicyRequestDTO struct {
+	CategoryID             *uint `json:"category_id"` // omitted for the global policy
+	DeleteRawAfterEncoding bool  `json:"delete_raw_after_encoding"`
+	DeleteVideoAfterDays   uint  `json:"delete_video_after_days" validate:"max=3650"` // 0 keeps the video
+	CreatedByID            uint  `json:"-"`
+}
+
+type RetentionPolicyRespDTO struct {
+	ID                     uint      `json:"id"`
+	CategoryID             *uint     `json:"category_id"`
+	CategoryName           string    `json:"category_name,omitempty"`
+	DeleteRawAfterEncoding bool      `json:"delete_raw_after_encoding"`
+	DeleteVideoAfterDays   uint      `json:"delete_video_after_days"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+	CreatedByID            uint      `json:"created_by_id"`
+	UpdatedByID            uint      `json:"updated_by_id"`
+}
+
+type LegalHoldRequest struct {
+	LegalHold *bool `json:"legal_hold" validate:"required"`
+}
+
+type PinStreamRequest struct {
+	IsPinned *bool `json:"is_pinned" validate:"required"`
+}
+
+// ended stream checked by the retention job
+type RetentionStreamDTO struct {
+	ID        uint
+	UserID    uint
+	StreamKey string
+	IsPinned  bool
+	EndedAt   time.Time
+}
@@ -102,18 +102,36 @@ type LiveStreamBroadCastDTO struct {
 	BroadcastURL       string             `json:"broadcast_url,omitempty"` // generated from web
 	StreamType         model.StreamType   `json:"stream_type,omitempty"`
 	ThumbnailFileName  string             `json:"thumbnail_file_name,omitempty"`
+	VideoURL           string             `json:"video_url,omitempty"` // recording of ended stream
+	IsPinned           bool               `json:"is_pinned"`
+	LegalHold          bool               `json:"legal_hold"`
 	StartedAt          *time.Time         `json:"started_at,omitempty"`
 	EndedAt            *time.Time         `json:"ended_at,omitempty"`
 	User               *UserResponseDTO   `json:"user,omitempty"`
 	Categories         []CategoryDTO      `json:"categories,omitempty"`
 	LiveStreamAnalytic *LiveStreamRespDTO `json:"live_stream_analytic"`
 	ScheduleStream     *ScheduleStreamDTO `json:"schedule_stream"`
+	Rebroadcasts       []RebroadcastDTO   `json:"rebroadcasts,omitempty"` // streams replaying the recording
+}
+
+type RebroadcastDTO struct {
+	StreamID           uint               `json:"stream_id"`
+	Title              string             `json:"title"`
+	Status             model.StreamStatus `json:"status"`
+	ScheduledAt        time.Time          `json:"scheduled_at"`
+	LiveStreamAnalytic *LiveStreamRespDTO `json:"live_stream_analytic"`
 }
 
 type ScheduleStreamDTO struct {
-	ScheduledAt time.Time `json:"scheduled_at"`
-	VideoURL    string    `json:"video_url"`
-	VideoName   string    `json:"video_name"`
+	ScheduledAt   time.Time         `json:"scheduled_at"`
+	VideoURL      string            `json:"video_url"`
+	VideoName     string            `json:"video_name"`
+	VideoDuration uint              `json:"video_duration"` // deprecated, same as total_duration
+	TotalDuration uint              `json:"total_duration"` // in seconds, of the video or the whole playlist, 0 when it's unknown
+	Playlist      []PlaylistItemDTO `json:"playlist,omitempty"`
+	PushAttempts  uint              `json:"push_attempts"`
+	LastPushError string            `json:"last_push_error,omitempty"`
+	RebroadcastOf *uint             `json:"rebroadcast_of,omitempty"` // id of the ended stream whose recording is replayed
 }
 
 type CategoryDTO struct {
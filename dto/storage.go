@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+type StorageUsageQuery struct {
+	RoleType string `json:"role_type" query:"role_type" validate:"omitempty,oneof=user streamer"`
+	Keyword  string `json:"keyword" query:"keyword" validate:"omitempty,max=255"`
+	SortBy   string `json:"sort_by" query:"sort_by" validate:"omitempty,oneof=total_bytes thumbnail_bytes scheduled_video_bytes recording_bytes updated_at"`
+	Sort     string `json:"sort" query:"sort" validate:"omitempty,oneof=DESC ASC"`
+	Page     uint   `query:"page" validate:"required,min=1"`
+	Limit    uint   `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type StorageUsageRespDTO struct {
+	UserID              uint           `json:"user_id"`
+	Username            string         `json:"username"`
+	DisplayName         string         `json:"display_name"`
+	RoleType            model.RoleType `json:"role_type"`
+	ThumbnailBytes      int64          `json:"thumbnail_bytes"`
+	ScheduledVideoBytes int64          `json:"scheduled_video_bytes"`
+	RecordingBytes      int64          `json:"recording_bytes"`
+	TotalBytes          int64          `json:"total_bytes"`
+	QuotaBytes          int64          `json:"quota_bytes"` // 0 is unlimited
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+// files of a stream which are owned by the streamer
+type StreamFilesDTO struct {
+	UserID            uint
+	StreamKey         string
+	ThumbnailFileName string
+	VideoName         string
+}
+
+// files of playlist items which are owned by the streamer
+type PlaylistFilesDTO struct {
+	UserID   uint
+	FileName string
+}
+
+// clips are counted as recordings of the streamer
+type ClipFilesDTO struct {
+	UserID   uint
+	FileName string
+}
@@ -7,6 +7,7 @@ type StreamRequest struct {
 	Description       string             `json:"description" form:"description" validate:"required"`
 	UserID            uint               `json:"user_id" form:"user_id" validate:"required"`
 	VideoFileName     string             `json:"-" form:"-"`
+	VideoDuration     uint               `json:"-" form:"-"` // in seconds
 	ThumbnailFileName string             `json:"-" form:"-"`
 	Status            model.StreamStatus `json:"status" form:"status" validate:"omitempty,oneof=pending started ended upcoming"`
 	ScheduledAt       string             `json:"scheduled_at" form:"scheduled_at" validate:"required,datetime=2006-01-02 15:04:05.999 -0700"` //expect in utc
@@ -26,10 +27,18 @@ type UpdateStreamRequest struct {
 
 type UpdateScheduledStreamRequest struct {
 	VideoFileName string `json:"-" form:"-"`
+	VideoDuration uint   `json:"-" form:"-"`                                                                                  // in seconds
 	ScheduledAt   string `json:"scheduled_at" form:"scheduled_at" validate:"required,datetime=2006-01-02 15:04:05.999 -0700"` //expect in utc
 
 }
 
+type RebroadcastStreamRequest struct {
+	ScheduledAt       string `json:"scheduled_at" validate:"required,datetime=2006-01-02 15:04:05.999 -0700"` //expect in utc
+	VideoFileName     string `json:"-"`
+	VideoDuration     uint   `json:"-"` // in seconds
+	ThumbnailFileName string `json:"-"`
+}
+
 type UpdateStreamThumbnailRequest struct {
 	ThumbnailFileName string `json:"-" form:"-"`
 	UpdatedByID       uint   `json:"-" form:"-"`
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"gitlab/live/be-live-admin/model"
+	"time"
+)
+
+// SuspendUserRequest blocks the user for Duration seconds, the strike reaching the max strikes blocks permanently
+type SuspendUserRequest struct {
+	Reason   model.StrikeReason `json:"reason" validate:"required,oneof=spam harassment hate violence nudity impersonation other"`
+	Note     string             `json:"note" validate:"required_if=Reason other,omitempty,min=3,max=255"`
+	Duration int64              `json:"duration" validate:"required,min=3600,max=31536000"`
+}
+
+type UserStrikeDTO struct {
+	ID             uint               `json:"id"`
+	UserID         uint               `json:"user_id"`
+	Reason         model.StrikeReason `json:"reason"`
+	Note           string             `json:"note,omitempty"`
+	Duration       int64              `json:"duration"`
+	SuspendedUntil *time.Time         `json:"suspended_until,omitempty"`
+	Permanent      bool               `json:"permanent"`
+	IssuedBy       *UserResponseDTO   `json:"issued_by,omitempty"`
+	Revoked        bool               `json:"revoked"`
+	RevokedAt      *time.Time         `json:"revoked_at,omitempty"`
+	RevokedBy      *UserResponseDTO   `json:"revoked_by,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type SuspendUserResponse struct {
+	Strike  UserStrikeDTO `json:"strike"`
+	Strikes uint          `json:"strikes"` // unrevoked ones of the user
+}
+
+type UserStrikesDTO struct {
+	Strikes        uint                 `json:"strikes"`
+	MaxStrikes     int                  `json:"max_strikes"`
+	SuspendedUntil *time.Time           `json:"suspended_until,omitempty"`
+	Status         model.UserStatusType `json:"status"`
+	History        []UserStrikeDTO      `json:"history"`
+}
@@ -0,0 +1,105 @@
+package dto
+
+import "time"
+
+const (
+	SUBSCRIPTION_DIRECTION_SUBSCRIBERS   = "subscribers"
+	SUBSCRIPTION_DIRECTION_SUBSCRIPTIONS = "subscriptions"
+
+	SUBSCRIPTION_GROWTH_DEFAULT_DAYS     = 30
+	SUBSCRIPTION_GROWTH_DEFAULT_INTERVAL = 86400
+	SUBSCRIPTION_GROWTH_MAX_BUCKETS      = 1000
+
+	SUBSCRIPTION_BURST_DEFAULT_DAYS        = 7
+	SUBSCRIPTION_BURST_DEFAULT_WINDOW      = 600
+	SUBSCRIPTION_BURST_DEFAULT_MIN_COUNT   = 20
+	SUBSCRIPTION_BURST_DEFAULT_ACCOUNT_AGE = 3 * 86400
+	SUBSCRIPTION_BURST_MAX_BURSTS          = 50
+
+	SUBSCRIPTION_REMOVE_MAX = 1000
+)
+
+// SubscriptionQuery lists subscribers of a streamer or subscriptions of a user, Keyword matches the other side
+type SubscriptionQuery struct {
+	Keyword string `query:"keyword" validate:"omitempty,max=255"`
+	Mute    string `query:"mute" validate:"omitempty,oneof=muted unmuted"`
+	SortBy  string `query:"sort_by" validate:"omitempty,oneof=created_at username"`
+	Sort    string `query:"sort" validate:"omitempty,oneof=DESC ASC"`
+	Page    uint   `query:"page" validate:"required,min=1"`
+	Limit   uint   `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type SubscriptionDTO struct {
+	ID               uint             `json:"id"`
+	User             *UserResponseDTO `json:"user,omitempty"` // subscriber or streamer, the other side
+	AccountCreatedAt time.Time        `json:"account_created_at"`
+	IsMute           bool             `json:"is_mute"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+type SubscriptionStatsDTO struct {
+	Subscribers        int64   `json:"subscribers"`
+	MutedSubscribers   int64   `json:"muted_subscribers"`
+	MuteRatio          float64 `json:"mute_ratio"` // of subscribers, 0 when there are none
+	Subscriptions      int64   `json:"subscriptions"`
+	MutedSubscriptions int64   `json:"muted_subscriptions"`
+}
+
+type SubscriptionGrowthQuery struct {
+	From     int64 `query:"from" validate:"omitempty"` // unix time, SUBSCRIPTION_GROWTH_DEFAULT_DAYS ago when empty
+	To       int64 `query:"to" validate:"omitempty"`
+	Interval uint  `query:"interval" validate:"omitempty,min=3600,max=2592000"` // in seconds, SUBSCRIPTION_GROWTH_DEFAULT_INTERVAL when empty
+}
+
+type SubscriptionGrowthBucket struct {
+	Start     time.Time `json:"start"`
+	New       int64     `json:"new"`
+	NewMuted  int64     `json:"new_muted"`
+	Total     int64     `json:"total"` // at the end of the bucket
+	MuteRatio float64   `json:"mute_ratio"`
+}
+
+// SubscriptionGrowthDTO counts current subscribers of a streamer by when they subscribed, unsubscribes aren't kept
+type SubscriptionGrowthDTO struct {
+	StreamerID uint                       `json:"streamer_id"`
+	From       time.Time                  `json:"from"`
+	To         time.Time                  `json:"to"`
+	Interval   uint                       `json:"interval"`
+	Buckets    []SubscriptionGrowthBucket `json:"buckets"`
+}
+
+// SubscriptionBurstQuery finds spans in which at least MinCount accounts younger than AccountAge subscribed within any Window
+type SubscriptionBurstQuery struct {
+	From       int64 `query:"from" validate:"omitempty"` // unix time, SUBSCRIPTION_BURST_DEFAULT_DAYS ago when empty
+	To         int64 `query:"to" validate:"omitempty"`
+	Window     uint  `query:"window" validate:"omitempty,min=60,max=86400"` // in seconds
+	MinCount   uint  `query:"min_count" validate:"omitempty,min=2,max=10000"`
+	AccountAge uint  `query:"account_age" validate:"omitempty,min=3600,max=2592000"` // in seconds, at subscribing
+}
+
+type SubscriptionBurstDTO struct {
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	Count         int64     `json:"count"`
+	SubscriberIDs []uint    `json:"subscriber_ids"` // at most SUBSCRIPTION_REMOVE_MAX, for RemoveSubscriptionsRequest
+}
+
+type SubscriptionBurstsDTO struct {
+	StreamerID uint                   `json:"streamer_id"`
+	From       time.Time              `json:"from"`
+	To         time.Time              `json:"to"`
+	Window     uint                   `json:"window"`
+	MinCount   uint                   `json:"min_count"`
+	AccountAge uint                   `json:"account_age"`
+	Bursts     []SubscriptionBurstDTO `json:"bursts"`
+}
+
+// RemoveSubscriptionsRequest removes subscriptions of the subscribers to the streamer, e.g. ones found by bursts
+type RemoveSubscriptionsRequest struct {
+	SubscriberIDs []uint `json:"subscriber_ids" validate:"required,min=1,max=1000,dive,required"`
+	Reason        string `json:"reason" validate:"required,min=3,max=255"`
+}
+
+type RemoveSubscriptionsDTO struct {
+	Removed int64 `json:"removed"`
+}
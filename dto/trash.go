@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+const (
+	TRASH_TYPE_USER   = "user"
+	TRASH_TYPE_STREAM = "stream"
+)
+
+type TrashQuery struct {
+	Type    string `query:"type" validate:"required,oneof=user stream"`
+	Keyword string `query:"keyword" validate:"omitempty,max=255"`
+	Page    uint   `query:"page" validate:"required,min=1"`
+	Limit   uint   `query:"limit" validate:"required,min=1,max=20"`
+}
+
+type TrashItemDTO struct {
+	ID        uint             `json:"id"`
+	Type      string           `json:"type"`
+	Name      string           `json:"name"`              // username of users, title of streams
+	UserID    uint             `json:"user_id,omitempty"` // owner of streams
+	DeletedAt time.Time        `json:"deleted_at"`
+	PurgeAt   time.Time        `json:"purge_at"`
+	DeletedBy *UserResponseDTO `json:"deleted_by,omitempty"`
+}
@@ -15,6 +15,10 @@ type UserQuery struct {
 	Sort      string `json:"sort" query:"sort" validate:"omitempty,oneof=DESC ASC"`
 	Page      uint   `query:"page" validate:"omitempty,min=1"`
 	Limit     uint   `query:"limit" validate:"omitempty,min=1,max=20"`
+
+	// blocked users with or without SuspendedUntil
+	Suspension string `json:"suspension" query:"suspension" validate:"omitempty,oneof=temporary permanent"`
+	MinStrikes uint   `json:"min_strikes" query:"min_strikes" validate:"omitempty,min=1"`
 }
 
 type UserResponseDTO struct {
@@ -25,6 +29,10 @@ type UserResponseDTO struct {
 	Email          string               `json:"email,omitempty"`
 	RoleID         uint                 `json:"role_id,omitempty"`
 	BlockedReason  string               `json:"blocked_reason,omitempty"`
+	SuspendedUntil *time.Time           `json:"suspended_until,omitempty"` // nil while blocked is a permanent block
+	Strikes        uint                 `json:"strikes,omitempty"`
+	Locale         string               `json:"locale,omitempty"`
+	PublishBan     *PublishBanDTO       `json:"publish_ban,omitempty"` // active one, of the user detail
 	Role           *RoleDTO             `json:"role,omitempty"`
 	Status         model.UserStatusType `json:"status,omitempty"`
 	CreatedAt      time.Time            `json:"created_at,omitempty"`
@@ -59,6 +67,7 @@ type UpdateUserRequest struct {
 	Email       string         `json:"email" validate:"omitempty,email,max=100"`
 	DisplayName string         `json:"display_name" validate:"omitempty,min=3,max=100"`
 	RoleType    model.RoleType `json:"role_type" validate:"omitempty,oneof=admin streamer user"`
+	Locale      string         `json:"locale" validate:"omitempty,max=20,bcp47_language_tag"`
 	UpdatedByID *uint          `json:"updated_by_id"`
 }
 
@@ -0,0 +1,78 @@
+package dto
+
+import "gitlab/live/be-live-admin/model"
+
+const (
+	USER_IMPORT_MAX_ROWS   = 1000
+	USER_IMPORT_MAX_SIZE   = 1 * 1024 * 1024
+	USER_IMPORT_BATCH_SIZE = 50
+)
+
+const (
+	USER_IMPORT_ROW_CREATED = "created"
+	USER_IMPORT_ROW_INVITED = "invited"
+	USER_IMPORT_ROW_FAILED  = "failed"
+)
+
+// UserImportRequest is sent as form fields with the csv file, columns are
+// username, email, display_name, role, avatar_url (optional) and password (unless invite)
+type UserImportRequest struct {
+	DryRun bool `form:"dry_run"`
+	Invite bool `form:"invite"` // create invites, users are created when they set their password
+}
+
+type UserImportRowError struct {
+	Row      int      `json:"row"` // line of the csv, the header is line 1
+	Username string   `json:"username"`
+	Errors   []string `json:"errors"`
+}
+
+// UserImportReport is the validation result of every row, nothing is imported when Invalid > 0
+type UserImportReport struct {
+	DryRun  bool                 `json:"dry_run"`
+	Total   int                  `json:"total"`
+	Valid   int                  `json:"valid"`
+	Invalid int                  `json:"invalid"`
+	Errors  []UserImportRowError `json:"errors"`
+	JobID   uint                 `json:"job_id,omitempty"`
+}
+
+type UserImportRow struct {
+	Row         int            `json:"row"`
+	Username    string         `json:"username"`
+	Email       string         `json:"email"`
+	DisplayName string         `json:"display_name"`
+	RoleType    model.RoleType `json:"role_type"`
+	AvatarURL   string         `json:"avatar_url,omitempty"`
+}
+
+type UserImportRowResult struct {
+	Row      int    `json:"row"`
+	Username string `json:"username"`
+	Status   string `json:"status"`
+	UserID   uint   `json:"user_id,omitempty"`
+	InviteID uint   `json:"invite_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Warning  string `json:"warning,omitempty"` // avatar or queuing the invite email failed, the row is still created
+}
+
+// UserImportResult is saved as the result of import jobs
+type UserImportResult struct {
+	Total   int                   `json:"total"`
+	Created int                   `json:"created"`
+	Failed  int                   `json:"failed"`
+	Invited int                   `json:"invited"`
+	Rows    []UserImportRowResult `json:"rows"`
+}
+
+func (r *UserImportResult) Add(row UserImportRowResult) {
+	switch row.Status {
+	case USER_IMPORT_ROW_CREATED:
+		r.Created++
+	case USER_IMPORT_ROW_INVITED:
+		r.Invited++
+	case USER_IMPORT_ROW_FAILED:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
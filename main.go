@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"gitlab/live/be-live-admin/cmd/admin/handler"
 	"gitlab/live/be-live-admin/conf"
@@ -10,6 +12,7 @@ import (
 	"gitlab/live/be-live-admin/model"
 	"gitlab/live/be-live-admin/repository"
 	"gitlab/live/be-live-admin/service"
+	"gitlab/live/be-live-admin/utils"
 	"log"
 	"net/http"
 	"os"
@@ -37,6 +40,39 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 	return nil
 }
 
+func makeStreamPusher(schedulerConfig *conf.SchedulerConfig) service.StreamPusher {
+	if schedulerConfig.Pusher == "fake" {
+		return &service.FakePusher{Duration: time.Minute}
+	}
+	return service.NewFFmpegPusher(schedulerConfig.FFmpegPath, schedulerConfig.FFmpegArgs, schedulerConfig.FFmpegPlaylistArgs, schedulerConfig.PlaylistWidth, schedulerConfig.PlaylistHeight)
+}
+
+func makeMailer(mailConfig *conf.MailConfig) service.Mailer {
+	if mailConfig.Host == "" {
+		return &service.LogMailer{}
+	}
+	return service.NewSMTPMailer(mailConfig.Host, mailConfig.Port, mailConfig.Email, mailConfig.Password)
+}
+
+func runFileGCCommand(fileGC *service.FileGCService, args []string) {
+	flags := flag.NewFlagSet("gc", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report orphaned files without deleting them")
+	if err := flags.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+
+	report, err := fileGC.Run(context.Background(), *dryRun)
+	if err != nil {
+		log.Fatalf("File gc failed: %v", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(data))
+}
+
 // @title          			   Admin API Live Stream
 // @version         		   1.0
 // @description     		   Swagger API Admin Live Stream.
@@ -62,6 +98,25 @@ func main() {
 	streamServer := service.NewStreamServerService(streamServerConfig.HTTPURL, streamServerConfig.RTMPURL)
 	//roleService := service.NewRoleService(repo, ds.RClient)
 	srv := service.NewService(repo, ds.RedisStore, streamServer)
+
+	fileStorageConfig := conf.GetFileStorageConfig()
+	fileGCConfig := conf.GetFileGCConfig()
+	storageFolders := service.StorageFolders{
+		Thumbnail:       fileStorageConfig.ThumbnailFolder,
+		Avatar:          fileStorageConfig.AvatarFolder,
+		Live:            fileStorageConfig.LiveFolder,
+		ScheduledVideos: fileStorageConfig.ScheduledVideosFolder,
+		Video:           fileStorageConfig.VideoFolder,
+		Clips:           fileStorageConfig.ClipsFolder,
+	}
+	fileGC := service.NewFileGCService(repo, ds.RedisStore, storageFolders, time.Duration(fileGCConfig.GracePeriod)*time.Second)
+
+	// go run main.go gc [--dry-run]
+	if len(os.Args) > 1 && os.Args[1] == "gc" {
+		runFileGCCommand(fileGC, os.Args[2:])
+		return
+	}
+
 	conf.SeedRoles(srv.Role)
 	conf.SeedSuperAdminUser(srv.User, srv.Role)
 
@@ -102,20 +157,140 @@ func main() {
 	root := e.Group("/")
 	handler := handler.NewHandler(root, srv)
 
-	fileH := e.Group("/api/file")
-	fileH.GET("/avatar/:filename", func(c echo.Context) error {
-		avatarPath := conf.GetFileStorageConfig().AvatarFolder + c.Param("filename")
-
-		return c.File(avatarPath)
-	})
+	apiFileConfig := conf.GetApiFileConfig()
+	if err := utils.SetFileURLSigner(apiFileConfig.SecretKey, time.Duration(apiFileConfig.URLExpiration)*time.Second); err != nil {
+		log.Fatal(err)
+	}
+	gdprConfig := conf.GetGDPRConfig()
+	if err := utils.SetReportSigner(gdprConfig.SigningKey); err != nil {
+		log.Fatal(err)
+	}
+	scheduleConfig := conf.GetScheduleConfig()
+	horizon, err := service.RecurrenceHorizon(time.Duration(scheduleConfig.RecurrenceHorizon)*time.Second, time.Duration(scheduleConfig.Window)*time.Second)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	fileH.Use(handler.JWTMiddleware())
-	fileH.Static("/", conf.GetFileStorageConfig().RootFolder)
+	// files are accessed by signed urls, so <video> and <img> tags can load them without auth header
+	fileH := e.Group("/api/file")
+	fileH.Use(cmiddleware.SignedURLMiddleware())
+	fileH.GET("/*", cmiddleware.ServeFile("/api/file", conf.GetFileStorageConfig().RootFolder))
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	handler.Register()
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	schedulerConfig := conf.GetSchedulerConfig()
+	leader := service.NewLeaderElector(ds.RedisStore, time.Duration(schedulerConfig.LockTTL)*time.Second)
+	leaderDone := make(chan struct{})
+	go func() {
+		leader.Start(jobCtx)
+		close(leaderDone)
+	}()
+
+	if fileGCConfig.Interval > 0 {
+		go fileGC.Start(jobCtx, time.Duration(fileGCConfig.Interval)*time.Second, leader)
+	}
+
+	if storageUsageConfig := conf.GetStorageUsageConfig(); storageUsageConfig.RecalculateInterval > 0 {
+		go srv.Storage.Start(jobCtx, time.Duration(storageUsageConfig.RecalculateInterval)*time.Second, storageFolders, leader)
+	}
+
+	if retentionConfig := conf.GetRetentionConfig(); retentionConfig.Interval > 0 {
+		go srv.Retention.Start(jobCtx, time.Duration(retentionConfig.Interval)*time.Second, storageFolders, leader)
+	}
+
+	if scheduleConfig.RecurrenceInterval > 0 {
+		limits := service.ScheduleLimits{
+			DefaultDuration: time.Duration(scheduleConfig.DefaultDuration) * time.Second,
+			CategorySlots:   scheduleConfig.CategorySlots,
+			MaxConcurrent:   scheduleConfig.MaxConcurrent,
+		}
+		go srv.Recurrence.Start(jobCtx, time.Duration(scheduleConfig.RecurrenceInterval)*time.Second, horizon, limits, leader)
+	}
+
+	go srv.CommentFilter.Start(jobCtx)
+
+	if err := srv.PublishBan.Sync(jobCtx); err != nil {
+		log.Printf("Failed to sync publish bans: %v\n", err)
+	}
+
+	// ended suspensions must always be lifted, so the loop has no off switch
+	suspensionInterval := time.Duration(conf.GetSuspensionConfig().Interval) * time.Second
+	if suspensionInterval <= 0 {
+		suspensionInterval = service.DEFAULT_SUSPENSION_INTERVAL
+	}
+	go srv.Suspension.Start(jobCtx, suspensionInterval)
+
+	jobsDone := make(chan struct{})
+	// queued jobs only run on instances with a worker, so it runs unless it's disabled explicitly
+	if jobConfig := conf.GetJobConfig(); jobConfig.Interval >= 0 {
+		jobInterval := time.Duration(jobConfig.Interval) * time.Second
+		if jobInterval == 0 {
+			jobInterval = service.DEFAULT_JOB_INTERVAL
+		}
+		clipConfig := conf.GetClipConfig()
+		clipTimeout := time.Duration(clipConfig.Timeout) * time.Second
+		if clipTimeout <= 0 {
+			clipTimeout = service.DEFAULT_CLIP_TIMEOUT
+		}
+
+		worker := service.NewJobWorker(repo, service.JobWorkerOptions{
+			Concurrency: jobConfig.Concurrency,
+			LockTimeout: time.Duration(jobConfig.LockTimeout) * time.Second,
+			RetryDelay:  time.Duration(jobConfig.RetryDelay) * time.Second,
+		})
+		worker.Register(model.JobTypeRemoveFiles, srv.Storage.RemoveFilesJob)
+		purgeDelay := time.Duration(conf.GetTrashConfig().PurgeDelay) * time.Second
+		worker.Register(model.JobTypeBulkUsers, srv.Bulk.UsersJob(conf.GetClientConfig().Host, purgeDelay))
+		worker.Register(model.JobTypeBulkStreams, srv.Bulk.StreamsJob(purgeDelay))
+		worker.Register(model.JobTypePurgeUser, srv.Trash.PurgeUserJob(storageFolders.Avatar))
+		worker.Register(model.JobTypePurgeStream, srv.Trash.PurgeStreamJob(storageFolders))
+		gdprOptions := service.GDPROptions{
+			ExportsFolder:    fileStorageConfig.ExportsFolder,
+			Folders:          storageFolders,
+			ExportExpiration: time.Duration(gdprConfig.ExportExpiration) * time.Second,
+		}
+		worker.Register(model.JobTypeGDPRExport, srv.GDPR.ExportJob(gdprOptions))
+		worker.Register(model.JobTypeGDPRErasure, srv.GDPR.ErasureJob(gdprOptions))
+		worker.Register(model.JobTypeImportUsers, srv.UserImport.ImportJob(fileStorageConfig.AvatarFolder))
+		inviteConfig := conf.GetInviteConfig()
+		worker.Register(model.JobTypeSendInvite, srv.Invite.SendJob(service.InviteOptions{
+			Mailer:     makeMailer(conf.GetMailConfig()),
+			URL:        inviteConfig.URL,
+			Expiration: time.Duration(inviteConfig.Expiration) * time.Second,
+		}))
+		worker.Register(model.JobTypeSendAnnouncement, srv.Announcement.SendJob())
+		worker.Register(model.JobTypeCutClip, srv.Clip.CutClipJob(service.NewFFmpegClipper(clipConfig.FFmpegPath, clipConfig.FFmpegArgs, clipConfig.FFmpegReencodeArgs), clipTimeout))
+		go func() {
+			worker.Start(jobCtx, jobInterval)
+			close(jobsDone)
+		}()
+	} else {
+		close(jobsDone)
+	}
+
+	schedulerDone := make(chan struct{})
+	if schedulerConfig.Interval > 0 {
+		scheduler := service.NewStreamScheduler(repo, leader, streamServer, makeStreamPusher(schedulerConfig), service.SchedulerOptions{
+			ScheduledVideosFolder: fileStorageConfig.ScheduledVideosFolder,
+			RTMPURL:               streamServerConfig.RTMPURL,
+			MaxRetries:            schedulerConfig.MaxRetries,
+			RetryDelay:            time.Duration(schedulerConfig.RetryDelay) * time.Second,
+			MaxConcurrent:         schedulerConfig.MaxConcurrent,
+			ClaimTimeout:          time.Duration(schedulerConfig.ClaimTimeout) * time.Second,
+		})
+		go func() {
+			scheduler.Start(jobCtx, time.Duration(schedulerConfig.Interval)*time.Second)
+			close(schedulerDone)
+		}()
+	} else {
+		close(schedulerDone)
+	}
+
 	go func() {
 		if err := e.Start(fmt.Sprintf(":%d", conf.GetApplicationConfig().Port)); err != nil && err != http.ErrServerClosed {
 			e.Logger.Fatal("shutting down the server")
@@ -134,4 +309,10 @@ func main() {
 		e.Logger.Fatal(err)
 	}
 
+	// running scheduled streams and jobs save their result before exit
+	stopJobs()
+	<-schedulerDone
+	<-jobsDone
+	<-leaderDone
+
 }
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab/live/be-live-admin/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+// content types which are missing from go's builtin table
+var fileContentTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".flv":  "video/x-flv",
+	".m3u8": "application/vnd.apple.mpegurl",
+	".ts":   "video/mp2t",
+}
+
+func init() {
+	for ext, contentType := range fileContentTypes {
+		if err := mime.AddExtensionType(ext, contentType); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func SignedURLMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if err := utils.VerifySignedFilePath(req.URL.Path, req.URL.Query(), c.RealIP()); err != nil {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+			}
+			return next(c)
+		}
+	}
+}
+
+// ServeFile serves files under rootFolder, prefix is stripped from the request path.
+// http.ServeContent handles Range and conditional requests so videos can be seeked.
+func ServeFile(prefix, rootFolder string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		name := strings.TrimPrefix(c.Request().URL.Path, prefix)
+		filePath := filepath.Join(rootFolder, filepath.FromSlash(filepath.Clean("/"+name)))
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "file not found")
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil || info.IsDir() {
+			return echo.NewHTTPError(http.StatusNotFound, "file not found")
+		}
+
+		if contentType := mime.TypeByExtension(filepath.Ext(filePath)); contentType != "" {
+			c.Response().Header().Set(echo.HeaderContentType, contentType)
+		}
+		c.Response().Header().Set("Accept-Ranges", "bytes")
+
+		http.ServeContent(c.Response(), c.Request(), info.Name(), info.ModTime(), file)
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+type AnnouncementTarget string
+
+const (
+	AnnouncementTargetAll             AnnouncementTarget = "all"
+	AnnouncementTargetRole            AnnouncementTarget = "role"
+	AnnouncementTargetCategoryViewers AnnouncementTarget = "category_viewers"
+	AnnouncementTargetSegment         AnnouncementTarget = "segment"
+)
+
+type AnnouncementStatus string
+
+const (
+	AnnouncementStatusScheduled AnnouncementStatus = "scheduled"
+	AnnouncementStatusSending   AnnouncementStatus = "sending"
+	AnnouncementStatusSent      AnnouncementStatus = "sent"
+	AnnouncementStatusCanceled  AnnouncementStatus = "canceled"
+	AnnouncementStatusFailed    AnnouncementStatus = "failed"
+)
+
+// UserSegment is a saved user filter, Filter is a dto.UserQuery resolved when an announcement is sent
+type UserSegment struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"type:varchar(100);not null;unique"`
+	Filter      string `gorm:"type:jsonb;not null;default:'{}'"`
+	CreatedByID *uint
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	CreatedBy   *User     `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL"`
+}
+
+// Announcement is fanned out into notifications by a job from ScheduledAt. Recipients are walked in id order,
+// LastUserID is saved with every batch so a retried job goes on where the previous attempt stopped.
+type Announcement struct {
+	ID            uint               `gorm:"primaryKey"`
+	Title         string             `gorm:"type:varchar(100);not null"`
+	Body          string             `gorm:"type:text;not null"`
+	StreamID      *uint              // optional link
+	TargetType    AnnouncementTarget `gorm:"type:varchar(50);not null"`
+	TargetRole    RoleType           `gorm:"type:varchar(50)"`
+	CategoryID    *uint
+	SegmentID     *uint
+	Status        AnnouncementStatus `gorm:"type:varchar(20);not null;index"`
+	ScheduledAt   time.Time          `gorm:"not null"`
+	JobID         *uint
+	LastUserID    uint `gorm:"not null;default:0"`
+	Recipients    uint `gorm:"not null;default:0"` // notifications created
+	StartedAt     *time.Time
+	SentAt        *time.Time
+	CanceledAt    *time.Time
+	CreatedByID   *uint
+	CreatedAt     time.Time      `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt     time.Time      `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	Stream        *Stream        `gorm:"foreignKey:StreamID;constraint:OnDelete:SET NULL"`
+	Category      *Category      `gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL"`
+	Segment       *UserSegment   `gorm:"foreignKey:SegmentID;constraint:OnDelete:SET NULL"`
+	Job           *Job           `gorm:"foreignKey:JobID;constraint:OnDelete:SET NULL"`
+	CreatedBy     *User          `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL"`
+	Notifications []Notification `gorm:"foreignKey:AnnouncementID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type ClipStatus string
+
+const (
+	ClipStatusPending    ClipStatus = "pending"
+	ClipStatusProcessing ClipStatus = "processing"
+	ClipStatusReady      ClipStatus = "ready"
+	ClipStatusFailed     ClipStatus = "failed"
+)
+
+// Clip is a part of the recording of an ended stream, cut by ffmpeg into the clips folder
+type Clip struct {
+	ID          uint       `gorm:"primaryKey"`
+	StreamID    uint       `gorm:"not null;index"`
+	Title       string     `gorm:"type:varchar(100)"`
+	StartOffset uint       `gorm:"not null"`           // in milliseconds from the start of the recording
+	EndOffset   uint       `gorm:"not null"`           // in milliseconds from the start of the recording
+	FileName    string     `gorm:"type:text;not null"` // in clips folder
+	Status      ClipStatus `gorm:"type:varchar(20);not null"`
+	Size        int64      `gorm:"not null;default:0"` // in bytes
+	Duration    uint       `gorm:"not null;default:0"` // in milliseconds
+	Error       string     `gorm:"type:text"`
+	CreatedByID uint       `gorm:"not null"`
+	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	Stream      Stream     `gorm:"foreignKey:StreamID;constraint:OnDelete:CASCADE"`
+	CreatedBy   User       `gorm:"foreignKey:CreatedByID"`
+}
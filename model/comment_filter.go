@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type CommentFilterType string
+
+const (
+	CommentFilterTypeWord  CommentFilterType = "word" // whole words or phrases, case insensitive
+	CommentFilterTypeRegex CommentFilterType = "regex"
+)
+
+type CommentFilterAction string
+
+const (
+	CommentFilterActionHide      CommentFilterAction = "hide"
+	CommentFilterActionFlag      CommentFilterAction = "flag"
+	CommentFilterActionBlockUser CommentFilterAction = "block_user" // after HitThreshold matching comments of a user
+)
+
+// CommentFilterRule is applied to live chat by be-api, to streams of a category or to every stream when CategoryID is nil
+type CommentFilterRule struct {
+	ID           uint                `gorm:"primaryKey"`
+	Name         string              `gorm:"type:varchar(100);not null"`
+	Type         CommentFilterType   `gorm:"type:varchar(20);not null"`
+	Pattern      string              `gorm:"type:text;not null"`
+	CategoryID   *uint               `gorm:"index"`
+	Action       CommentFilterAction `gorm:"type:varchar(20);not null"`
+	HitThreshold uint                `gorm:"not null;default:0"`
+	Enabled      bool                `gorm:"not null;default:false;index"`
+	CreatedAt    time.Time           `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt    time.Time           `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	CreatedByID  uint                `gorm:"not null"`
+	UpdatedByID  uint                `gorm:"not null"`
+	Category     *Category           `gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type GDPRRequestType string
+
+const (
+	GDPRRequestTypeExport  GDPRRequestType = "export"
+	GDPRRequestTypeErasure GDPRRequestType = "erasure"
+)
+
+type GDPRRequestStatus string
+
+const (
+	GDPRRequestStatusPending   GDPRRequestStatus = "pending"
+	GDPRRequestStatusCompleted GDPRRequestStatus = "completed"
+	GDPRRequestStatusFailed    GDPRRequestStatus = "failed"
+)
+
+// GDPRRequest is a subject-access or erasure request of a user, kept for compliance after the user is erased,
+// so UserID has no foreign key
+type GDPRRequest struct {
+	ID       uint              `gorm:"primaryKey"`
+	UserID   uint              `gorm:"not null;index"`
+	Username string            `gorm:"type:varchar(50);not null"` // at the time of the request
+	Type     GDPRRequestType   `gorm:"type:varchar(20);not null"`
+	Status   GDPRRequestStatus `gorm:"type:varchar(20);not null"`
+	Reason   string            `gorm:"type:text"`
+	JobID    *uint
+	FileName string `gorm:"type:text"` // zip of exports in exports folder
+	// completion report and its HMAC-SHA256, signed with the gdpr signing key.
+	// text keeps the signed bytes, jsonb would reorder keys and whitespace
+	Report        sql.NullString `gorm:"type:text"`
+	Signature     string         `gorm:"type:varchar(64)"`
+	Error         string         `gorm:"type:text"`
+	RequestedByID *uint
+	CompletedAt   sql.NullTime `gorm:"column:completed_at"`
+	CreatedAt     time.Time    `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt     time.Time    `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	RequestedBy   *User        `gorm:"foreignKey:RequestedByID;constraint:OnDelete:SET NULL"`
+}
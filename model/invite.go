@@ -0,0 +1,40 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type InviteStatus string
+
+const (
+	InviteStatusPending  InviteStatus = "pending"
+	InviteStatusAccepted InviteStatus = "accepted"
+	InviteStatusRevoked  InviteStatus = "revoked"
+)
+
+// Invite reserves a username and email until the invitee sets a password, the user is created on accept.
+// Only the sha256 of the token is stored, a new token is mailed on every send.
+type Invite struct {
+	ID             uint           `gorm:"primaryKey"`
+	Email          string         `gorm:"type:varchar(100);not null;index"`
+	Username       string         `gorm:"type:varchar(50);not null;index"`
+	DisplayName    string         `gorm:"type:varchar(100);not null"`
+	RoleID         uint           `gorm:"not null"`
+	Role           Role           `gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE"`
+	AvatarFileName sql.NullString `gorm:"type:varchar(255)"`
+	Status         InviteStatus   `gorm:"type:varchar(20);not null;index"`
+	TokenHash      string         `gorm:"type:varchar(64);index"`
+	ExpiresAt      *time.Time
+	SentAt         *time.Time
+	SentCount      uint   `gorm:"not null;default:0"`
+	LastError      string `gorm:"type:text"` // of the last email
+	InvitedByID    *uint
+	InvitedBy      *User `gorm:"foreignKey:InvitedByID;constraint:OnDelete:SET NULL"`
+	UserID         *uint // created on accept
+	User           *User `gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
+	AcceptedAt     *time.Time
+	RevokedAt      *time.Time
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type JobType string
+
+const (
+	JobTypeRemoveFiles JobType = "remove_files"
+	JobTypeCutClip     JobType = "cut_clip"
+	JobTypeBulkUsers   JobType = "bulk_users"
+	JobTypeBulkStreams JobType = "bulk_streams"
+	JobTypePurgeUser   JobType = "purge_user"
+	JobTypePurgeStream JobType = "purge_stream"
+	JobTypeGDPRExport  JobType = "gdpr_export"
+	JobTypeGDPRErasure JobType = "gdpr_erasure"
+	JobTypeImportUsers JobType = "import_users"
+	JobTypeSendInvite  JobType = "send_invite"
+
+	JobTypeSendAnnouncement JobType = "send_announcement"
+)
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCanceled  JobStatus = "canceled"
+)
+
+// Job is a unit of background work, workers claim queued jobs with SELECT ... FOR UPDATE SKIP LOCKED
+type Job struct {
+	ID              uint      `gorm:"primaryKey"`
+	Type            JobType   `gorm:"type:varchar(50);not null;index"`
+	Status          JobStatus `gorm:"type:varchar(20);not null;index:idx_job_status_run_at"`
+	Payload         string    `gorm:"type:jsonb;not null;default:'{}'"`
+	Progress        uint      `gorm:"not null;default:0"` // in percent
+	ProgressMessage string    `gorm:"type:text"`
+	Attempts        uint      `gorm:"not null;default:0"`
+	MaxAttempts     uint      `gorm:"not null;default:1"`
+	LastError       string    `gorm:"type:text"`
+	// set by job types which report per-item results
+	Result sql.NullString `gorm:"type:jsonb"`
+	// queued jobs run from RunAt, retries are delayed
+	RunAt time.Time `gorm:"not null;index:idx_job_status_run_at"`
+	// refreshed by the worker while running, stale locks are requeued
+	LockedAt        sql.NullTime `gorm:"column:locked_at"`
+	LockedBy        string       `gorm:"type:varchar(100)"`
+	CancelRequested bool         `gorm:"not null;default:false"`
+	StartedAt       sql.NullTime `gorm:"column:started_at"`
+	FinishedAt      sql.NullTime `gorm:"column:finished_at"`
+	CreatedByID     *uint
+	CreatedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	CreatedBy       *User     `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// NotificationTemplate renders notifications of a type in a locale with text/template. Every edit is saved as a new
+// version and the latest version is used, locales without templates fall back to their language and the default one.
+type NotificationTemplate struct {
+	ID          uint             `gorm:"primaryKey"`
+	Type        NotificationType `gorm:"type:varchar(50);not null;uniqueIndex:idx_notification_template_version"`
+	Locale      string           `gorm:"type:varchar(20);not null;uniqueIndex:idx_notification_template_version"` // lower case, like pt-br
+	Version     uint             `gorm:"not null;uniqueIndex:idx_notification_template_version"`
+	Title       string           `gorm:"type:text"`
+	Body        string           `gorm:"type:text;not null"`
+	CreatedByID *uint
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	CreatedBy   *User     `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+type PlaylistItemType string
+
+const (
+	PlaylistItemTypeVideo PlaylistItemType = "video"
+	PlaylistItemTypeSlate PlaylistItemType = "slate" // still image shown between videos
+)
+
+// ScheduleStreamItem is an entry of the playlist of a scheduled stream.
+// A stream with items pushes them in Position order instead of its VideoName.
+type ScheduleStreamItem struct {
+	ID               uint             `gorm:"primaryKey"`
+	ScheduleStreamID uint             `gorm:"not null;index"`
+	Position         uint             `gorm:"not null"`
+	Type             PlaylistItemType `gorm:"type:varchar(20);not null"`
+	FileName         string           `gorm:"type:text;not null"` // in scheduled videos folder
+	Duration         uint             `gorm:"not null;default:0"` // in milliseconds, probed for videos, shown time of slates
+	InPoint          uint             `gorm:"not null;default:0"` // in milliseconds, videos only
+	OutPoint         uint             `gorm:"not null;default:0"` // in milliseconds, 0 plays until the end
+	CreatedAt        time.Time        `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt        time.Time        `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	ScheduleStream   ScheduleStream   `gorm:"foreignKey:ScheduleStreamID;constraint:OnDelete:CASCADE"`
+}
+
+// PlayDuration is the time the item is on air in milliseconds
+func (i *ScheduleStreamItem) PlayDuration() uint {
+	if i.Type == PlaylistItemTypeSlate {
+		return i.Duration
+	}
+	end := i.Duration
+	if i.OutPoint > 0 && (end == 0 || i.OutPoint < end) {
+		end = i.OutPoint
+	}
+	if end <= i.InPoint {
+		return 0
+	}
+	return end - i.InPoint
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// PublishBan stops a user from going live until ExpiresAt, or until it's lifted when ExpiresAt is nil.
+// Viewing and commenting are left as they are, be-api checks the ban through cache.PUBLISH_BAN_PREFIX.
+type PublishBan struct {
+	ID          uint       `gorm:"primaryKey"`
+	UserID      uint       `gorm:"not null;index"`
+	Reason      string     `gorm:"type:text;not null"`
+	ExpiresAt   *time.Time `gorm:"index"`
+	CreatedByID *uint      `gorm:"index"`
+	LiftedAt    *time.Time `gorm:"index"`
+	LiftedByID  *uint
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	User        User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	CreatedBy   *User     `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL"`
+	LiftedBy    *User     `gorm:"foreignKey:LiftedByID;constraint:OnDelete:SET NULL"`
+}
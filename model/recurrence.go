@@ -0,0 +1,39 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// StreamRecurrence materializes upcoming pre-recorded streams from an RFC 5545 RRULE.
+// Thumbnail and video are shared by every occurrence.
+type StreamRecurrence struct {
+	ID                uint                        `gorm:"primaryKey"`
+	UserID            uint                        `gorm:"not null"`
+	Title             string                      `gorm:"type:varchar(100);not null"`
+	Description       string                      `gorm:"type:text"`
+	ThumbnailFileName string                      `gorm:"type:text;not null"`
+	VideoName         string                      `gorm:"type:text;not null"`
+	VideoDuration     uint                        `gorm:"not null;default:0"` // in seconds, 0 when it's unknown
+	RRule             string                      `gorm:"column:rrule;type:varchar(500);not null"`
+	Timezone          string                      `gorm:"type:varchar(64);not null"` // IANA name, occurrences keep wall clock time of StartAt there
+	StartAt           time.Time                   `gorm:"not null"`                  // first occurrence
+	MaterializedUntil sql.NullTime                `gorm:"column:materialized_until"`
+	CreatedByID       uint                        `gorm:"not null"`
+	UpdatedByID       uint                        `gorm:"not null"`
+	CreatedAt         time.Time                   `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt         time.Time                   `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	User              User                        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Categories        []Category                  `gorm:"many2many:stream_recurrence_categories;constraint:OnDelete:CASCADE"`
+	Exceptions        []StreamRecurrenceException `gorm:"foreignKey:RecurrenceID"`
+}
+
+// StreamRecurrenceException is an occurrence which must not be materialized (EXDATE),
+// also added when a materialized occurrence is deleted.
+type StreamRecurrenceException struct {
+	ID           uint             `gorm:"primaryKey"`
+	RecurrenceID uint             `gorm:"not null;uniqueIndex:idx_recurrence_exception"`
+	OccurrenceAt time.Time        `gorm:"not null;uniqueIndex:idx_recurrence_exception"`
+	CreatedAt    time.Time        `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	Recurrence   StreamRecurrence `gorm:"foreignKey:RecurrenceID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+type ReportTargetType string
+
+const (
+	ReportTargetStream  ReportTargetType = "stream"
+	ReportTargetComment ReportTargetType = "comment"
+	ReportTargetUser    ReportTargetType = "user"
+)
+
+type ReportReason string
+
+const (
+	ReportReasonSelfHarm   ReportReason = "self_harm"
+	ReportReasonViolence   ReportReason = "violence"
+	ReportReasonHate       ReportReason = "hate"
+	ReportReasonHarassment ReportReason = "harassment"
+	ReportReasonNudity     ReportReason = "nudity"
+	ReportReasonSpam       ReportReason = "spam"
+	ReportReasonCopyright  ReportReason = "copyright"
+	ReportReasonOther      ReportReason = "other"
+)
+
+type ReportSeverity string
+
+const (
+	ReportSeverityHigh   ReportSeverity = "high"
+	ReportSeverityMedium ReportSeverity = "medium"
+	ReportSeverityLow    ReportSeverity = "low"
+)
+
+// ReportSeverities sets the SLA of reports, severe reasons are also queued first
+var ReportSeverities = map[ReportReason]ReportSeverity{
+	ReportReasonSelfHarm:   ReportSeverityHigh,
+	ReportReasonViolence:   ReportSeverityHigh,
+	ReportReasonHate:       ReportSeverityMedium,
+	ReportReasonHarassment: ReportSeverityMedium,
+	ReportReasonNudity:     ReportSeverityMedium,
+	ReportReasonSpam:       ReportSeverityLow,
+	ReportReasonCopyright:  ReportSeverityLow,
+	ReportReasonOther:      ReportSeverityLow,
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusClaimed   ReportStatus = "claimed"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+// ReportAction is what a moderator did when resolving a report
+type ReportAction string
+
+const (
+	ReportActionNone         ReportAction = "none"
+	ReportActionBlockUser    ReportAction = "block_user"
+	ReportActionDeleteStream ReportAction = "delete_stream"
+	ReportActionEndLive      ReportAction = "end_live"
+	ReportActionHideComment  ReportAction = "hide_comment"
+)
+
+// Report is filed by a user of be-api against a stream, a comment or an account. Targets may be deleted later,
+// so TargetID has no foreign key.
+type Report struct {
+	ID         uint             `gorm:"primaryKey"`
+	ReporterID uint             `gorm:"not null;index"`
+	TargetType ReportTargetType `gorm:"type:varchar(20);not null;index:idx_reports_target"`
+	TargetID   uint             `gorm:"not null;index:idx_reports_target"`
+	// owner of the target, the user blocked by ReportActionBlockUser
+	TargetUserID *uint          `gorm:"index"`
+	Reason       ReportReason   `gorm:"type:varchar(20);not null"`
+	Text         string         `gorm:"type:text"`
+	Severity     ReportSeverity `gorm:"type:varchar(10);not null"`
+	// severity plus other unresolved reports of the target, higher is queued first
+	Priority     int          `gorm:"not null;default:0;index"`
+	Status       ReportStatus `gorm:"type:varchar(20);not null;index"`
+	DueAt        time.Time    `gorm:"not null;index"`
+	AssigneeID   *uint        `gorm:"index"`
+	AssignedAt   *time.Time
+	ResolvedByID *uint        `gorm:"index"`
+	ResolvedAt   *time.Time   `gorm:"index"`
+	Action       ReportAction `gorm:"type:varchar(20)"`
+	Note         string       `gorm:"type:text"`
+	CreatedAt    time.Time    `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt    time.Time    `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	Reporter     User         `gorm:"foreignKey:ReporterID;constraint:OnDelete:CASCADE"`
+	TargetUser   *User        `gorm:"foreignKey:TargetUserID;constraint:OnDelete:SET NULL"`
+	Assignee     *User        `gorm:"foreignKey:AssigneeID;constraint:OnDelete:SET NULL"`
+	ResolvedBy   *User        `gorm:"foreignKey:ResolvedByID;constraint:OnDelete:SET NULL"`
+}
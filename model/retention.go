@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// RetentionPolicy applies to ended streams of a category, or to every ended stream when CategoryID is nil
+type RetentionPolicy struct {
+	ID                     uint      `gorm:"primaryKey"`
+	CategoryID             *uint     `gorm:"uniqueIndex"`
+	DeleteRawAfterEncoding bool      `gorm:"not null;default:false"` // remove .flv once .mp4 is encoded
+	DeleteVideoAfterDays   uint      `gorm:"not null;default:0"`     // 0 keeps the .mp4 forever
+	CreatedAt              time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt              time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	CreatedByID            uint      `gorm:"not null"`
+	UpdatedByID            uint      `gorm:"not null"`
+	Category               *Category `gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE"`
+}
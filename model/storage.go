@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+type StorageFileType string
+
+const (
+	StorageFileTypeThumbnail      StorageFileType = "thumbnail"
+	StorageFileTypeScheduledVideo StorageFileType = "scheduled_video"
+	StorageFileTypeRecording      StorageFileType = "recording"
+)
+
+// StorageUsage is bytes on disk owned by a user, updated on every upload and delete
+type StorageUsage struct {
+	UserID              uint      `gorm:"primaryKey"`
+	ThumbnailBytes      int64     `gorm:"not null;default:0"`
+	ScheduledVideoBytes int64     `gorm:"not null;default:0"`
+	RecordingBytes      int64     `gorm:"not null;default:0"`
+	CreatedAt           time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt           time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	User                User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (s *StorageUsage) TotalBytes() int64 {
+	return s.ThumbnailBytes + s.ScheduledVideoBytes + s.RecordingBytes
+}
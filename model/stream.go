@@ -3,6 +3,8 @@ package model
 import (
 	"database/sql"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type StreamStatus string
@@ -47,6 +49,7 @@ const (
 	NotificationTypeSubscribeVideo NotificationType = "subscribe_video"
 	NotificationTypeBlocked        NotificationType = "account_blocked"
 	NotificationTypeDeleted        NotificationType = "account_deleted"
+	NotificationTypeAnnouncement   NotificationType = "announcement"
 )
 
 type Stream struct {
@@ -62,22 +65,29 @@ type Stream struct {
 	ThumbnailFileName string         `gorm:"type:text;not null"`
 	StartedAt         sql.NullTime   `gorm:"column:started_at"`
 	EndedAt           sql.NullTime   `gorm:"column:ended_at"`
+	IsPinned          bool           `gorm:"not null;default:false"` // pinned recordings are kept by retention policies
+	LegalHold         bool           `gorm:"not null;default:false"` // blocks deletion by retention and admin
 	CreatedAt         time.Time      `gorm:"default:CURRENT_TIMESTAMP;not null"`
 	UpdatedAt         time.Time      `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
-	User              User           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	DeletedAt         gorm.DeletedAt `gorm:"index"` // trashed streams are purged by a job
+	DeletedByID       *uint
+	User              User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	DeletedBy         *User `gorm:"foreignKey:DeletedByID;constraint:OnDelete:SET NULL"`
 }
 
 type Notification struct {
-	ID        uint             `gorm:"primaryKey"`
-	UserID    uint             `gorm:"column:user_id;not null"`
-	StreamID  uint             `gorm:"column:stream_id"`
-	Type      NotificationType `gorm:"type:varchar(50);not null"`
-	Content   string           `gorm:"type:text;not null"`
-	CreatedAt time.Time        `gorm:"default:CURRENT_TIMESTAMP;not null"`
-	ReadAt    sql.NullTime     `gorm:"column:read_at"`
-	HiddenAt  sql.NullTime     `gorm:"column:hidden_at"`
-	Stream    Stream           `gorm:"foreignKey:StreamID;constraint:OnDelete:CASCADE"`
-	User      User             `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	ID             uint             `gorm:"primaryKey"`
+	UserID         uint             `gorm:"column:user_id;not null"`
+	StreamID       *uint            `gorm:"column:stream_id"` // nil for announcements without a stream
+	AnnouncementID *uint            `gorm:"index"`
+	Type           NotificationType `gorm:"type:varchar(50);not null"`
+	Title          string           `gorm:"type:varchar(100)"` // of announcements
+	Content        string           `gorm:"type:text;not null"`
+	CreatedAt      time.Time        `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	ReadAt         sql.NullTime     `gorm:"column:read_at"`
+	HiddenAt       sql.NullTime     `gorm:"column:hidden_at"`
+	Stream         *Stream          `gorm:"foreignKey:StreamID;constraint:OnDelete:CASCADE"`
+	User           User             `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 }
 
 // type Chat struct {
@@ -129,14 +139,21 @@ type Like struct {
 }
 
 type Comment struct {
-	ID        uint      `gorm:"primaryKey"`
-	UserID    uint      `gorm:"not null"`
-	StreamID  uint      `gorm:"not null"`
-	Comment   string    `gorm:"type:text;not null"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
-	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
-	Stream    Stream    `gorm:"foreignKey:StreamID;constraint:OnDelete:CASCADE"`
-	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null"`
+	StreamID   uint      `gorm:"not null"`
+	Comment    string    `gorm:"type:text;not null"`
+	Flagged    bool      `gorm:"not null;default:false;index"` // waits for review by a moderator
+	FlagReason string    `gorm:"type:text"`
+	CreatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	// hidden comments are kept for moderation but removed from chat by be-api
+	HiddenAt     sql.NullTime `gorm:"column:hidden_at;index"`
+	HiddenByID   *uint
+	HiddenReason string `gorm:"type:text"`
+	Stream       Stream `gorm:"foreignKey:StreamID;constraint:OnDelete:CASCADE"`
+	User         User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	HiddenBy     *User  `gorm:"foreignKey:HiddenByID;constraint:OnDelete:SET NULL"`
 }
 type Share struct {
 	ID        uint      `gorm:"primaryKey"`
@@ -182,9 +199,25 @@ type ScheduleStream struct {
 	ScheduledAt time.Time `gorm:"not null"`
 	StreamID    uint      `gorm:"not null"`
 	VideoName   string    `gorm:"type:text;not null"`
-	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
-	UpdatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
-	Stream      Stream    `gorm:"foreignKey:StreamID;constraint:OnDelete:CASCADE"`
+	// in seconds, probed from the video or sum of the playlist, 0 when it's unknown
+	VideoDuration uint `gorm:"not null;default:0"`
+	// filled by the scheduler which pushes the video to stream server
+	PushAttempts  uint         `gorm:"not null;default:0"`
+	LastPushError string       `gorm:"type:text"`
+	NextAttemptAt sql.NullTime `gorm:"column:next_attempt_at"`
+	// set on claim and renewed while pushing, claims of a dead instance are reset once it's stale
+	ClaimedAt sql.NullTime `gorm:"column:claimed_at"`
+	// set for occurrences of a recurring schedule
+	RecurrenceID *uint        `gorm:"uniqueIndex:idx_recurrence_occurrence"`
+	OccurrenceAt sql.NullTime `gorm:"column:occurrence_at;uniqueIndex:idx_recurrence_occurrence"`
+	IsDetached   bool         `gorm:"not null;default:false"` // edited alone, edits of the series don't change it
+	// set when the video is the recording of an ended stream
+	RebroadcastOfID *uint             `gorm:"index"`
+	CreatedAt       time.Time         `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	UpdatedAt       time.Time         `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime;not null"`
+	Stream          Stream            `gorm:"foreignKey:StreamID;constraint:OnDelete:CASCADE"`
+	Recurrence      *StreamRecurrence `gorm:"foreignKey:RecurrenceID;constraint:OnDelete:SET NULL"`
+	RebroadcastOf   *Stream           `gorm:"foreignKey:RebroadcastOfID;constraint:OnDelete:SET NULL"`
 }
 
 type Bookmark struct {
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+type StrikeReason string
+
+const (
+	StrikeReasonSpam          StrikeReason = "spam"
+	StrikeReasonHarassment    StrikeReason = "harassment"
+	StrikeReasonHate          StrikeReason = "hate"
+	StrikeReasonViolence      StrikeReason = "violence"
+	StrikeReasonNudity        StrikeReason = "nudity"
+	StrikeReasonImpersonation StrikeReason = "impersonation"
+	StrikeReasonOther         StrikeReason = "other"
+)
+
+// UserStrike suspends the user until SuspendedUntil, or permanently when it reached the max strikes.
+// Revoked strikes stay in the history but don't count.
+type UserStrike struct {
+	ID             uint         `gorm:"primaryKey"`
+	UserID         uint         `gorm:"not null;index"`
+	Reason         StrikeReason `gorm:"type:varchar(20);not null"`
+	Note           string       `gorm:"type:text"`
+	Duration       int64        `gorm:"not null;default:0"` // in seconds
+	SuspendedUntil *time.Time
+	Permanent      bool  `gorm:"not null;default:false"`
+	IssuedByID     *uint `gorm:"index"`
+	RevokedAt      *time.Time
+	RevokedByID    *uint
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP;not null"`
+	User           User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	IssuedBy       *User     `gorm:"foreignKey:IssuedByID;constraint:OnDelete:SET NULL"`
+	RevokedBy      *User     `gorm:"foreignKey:RevokedByID;constraint:OnDelete:SET NULL"`
+}
@@ -31,6 +31,63 @@ const (
 	CreateAdmin                  AdminAction = "create_admin"
 	DeleteCategory               AdminAction = "delete_category"
 	UpdateCategory               AdminAction = "update_category"
+	RecalculateStorageUsage      AdminAction = "recalculate_storage_usage"
+	CreateRetentionPolicy        AdminAction = "create_retention_policy"
+	UpdateRetentionPolicy        AdminAction = "update_retention_policy"
+	DeleteRetentionPolicy        AdminAction = "delete_retention_policy"
+	RetentionDeleteRecording     AdminAction = "retention_delete_recording"
+	UpdateLegalHoldByAdmin       AdminAction = "update_legal_hold_by_admin"
+	PinStreamByAdmin             AdminAction = "pin_stream_by_admin"
+	CreateStreamRecurrence       AdminAction = "create_stream_recurrence"
+	UpdateStreamRecurrence       AdminAction = "update_stream_recurrence"
+	DeleteStreamRecurrence       AdminAction = "delete_stream_recurrence"
+	AddPlaylistItem              AdminAction = "add_playlist_item"
+	UpdatePlaylistItem           AdminAction = "update_playlist_item"
+	DeletePlaylistItem           AdminAction = "delete_playlist_item"
+	RebroadcastStreamByAdmin     AdminAction = "rebroadcast_stream_by_admin"
+	CreateClip                   AdminAction = "create_clip"
+	DeleteClip                   AdminAction = "delete_clip"
+	CancelJob                    AdminAction = "cancel_job"
+	BulkUpdateUsers              AdminAction = "bulk_update_users"
+	BulkUpdateStreams            AdminAction = "bulk_update_streams"
+	RestoreUserAction            AdminAction = "restore_user"
+	RestoreStreamByAdmin         AdminAction = "restore_stream_by_admin"
+	ExportUserData               AdminAction = "export_user_data"
+	EraseUserData                AdminAction = "erase_user_data"
+	ImportUsers                  AdminAction = "import_users"
+	AcceptInvite                 AdminAction = "accept_invite"
+	InviteUser                   AdminAction = "invite_user"
+	SendInvite                   AdminAction = "send_invite"
+	ResendInvite                 AdminAction = "resend_invite"
+	RevokeInvite                 AdminAction = "revoke_invite"
+	HideComment                  AdminAction = "hide_comment"
+	UnhideComment                AdminAction = "unhide_comment"
+	DeleteComment                AdminAction = "delete_comment"
+	CreateCommentFilter          AdminAction = "create_comment_filter"
+	UpdateCommentFilter          AdminAction = "update_comment_filter"
+	DeleteCommentFilter          AdminAction = "delete_comment_filter"
+	ClaimReport                  AdminAction = "claim_report"
+	AssignReport                 AdminAction = "assign_report"
+	ReleaseReport                AdminAction = "release_report"
+	ResolveReport                AdminAction = "resolve_report"
+	DismissReport                AdminAction = "dismiss_report"
+	SuspendUser                  AdminAction = "suspend_user"
+	ReinstateUser                AdminAction = "reinstate_user"
+	RevokeStrike                 AdminAction = "revoke_strike"
+	BanPublishing                AdminAction = "ban_publishing"
+	LiftPublishingBan            AdminAction = "lift_publishing_ban"
+	AddUserBlock                 AdminAction = "add_user_block"
+	RemoveUserBlock              AdminAction = "remove_user_block"
+	RemoveSubscriptions          AdminAction = "remove_subscriptions"
+	CreateAnnouncement           AdminAction = "create_announcement"
+	CancelAnnouncement           AdminAction = "cancel_announcement"
+	SendAnnouncement             AdminAction = "send_announcement"
+	CreateUserSegment            AdminAction = "create_user_segment"
+	UpdateUserSegment            AdminAction = "update_user_segment"
+	DeleteUserSegment            AdminAction = "delete_user_segment"
+	SaveNotificationTemplate     AdminAction = "save_notification_template"
+	RestoreNotificationTemplate  AdminAction = "restore_notification_template"
+	DeleteNotificationTemplate   AdminAction = "delete_notification_template"
 )
 
 var Actions = map[AdminAction]string{
@@ -55,6 +112,63 @@ var Actions = map[AdminAction]string{
 	DeleteCategory:               "delete_category",
 	UpdateCategory:               "update_category",
 	LogoutAction:                 "logout",
+	RecalculateStorageUsage:      "recalculate_storage_usage",
+	CreateRetentionPolicy:        "create_retention_policy",
+	UpdateRetentionPolicy:        "update_retention_policy",
+	DeleteRetentionPolicy:        "delete_retention_policy",
+	RetentionDeleteRecording:     "retention_delete_recording",
+	UpdateLegalHoldByAdmin:       "update_legal_hold_by_admin",
+	PinStreamByAdmin:             "pin_stream_by_admin",
+	CreateStreamRecurrence:       "create_stream_recurrence",
+	UpdateStreamRecurrence:       "update_stream_recurrence",
+	DeleteStreamRecurrence:       "delete_stream_recurrence",
+	AddPlaylistItem:              "add_playlist_item",
+	UpdatePlaylistItem:           "update_playlist_item",
+	DeletePlaylistItem:           "delete_playlist_item",
+	RebroadcastStreamByAdmin:     "rebroadcast_stream_by_admin",
+	CreateClip:                   "create_clip",
+	DeleteClip:                   "delete_clip",
+	CancelJob:                    "cancel_job",
+	BulkUpdateUsers:              "bulk_update_users",
+	BulkUpdateStreams:            "bulk_update_streams",
+	RestoreUserAction:            "restore_user",
+	RestoreStreamByAdmin:         "restore_stream_by_admin",
+	ExportUserData:               "export_user_data",
+	EraseUserData:                "erase_user_data",
+	ImportUsers:                  "import_users",
+	AcceptInvite:                 "accept_invite",
+	InviteUser:                   "invite_user",
+	SendInvite:                   "send_invite",
+	ResendInvite:                 "resend_invite",
+	RevokeInvite:                 "revoke_invite",
+	HideComment:                  "hide_comment",
+	UnhideComment:                "unhide_comment",
+	DeleteComment:                "delete_comment",
+	CreateCommentFilter:          "create_comment_filter",
+	UpdateCommentFilter:          "update_comment_filter",
+	DeleteCommentFilter:          "delete_comment_filter",
+	ClaimReport:                  "claim_report",
+	AssignReport:                 "assign_report",
+	ReleaseReport:                "release_report",
+	ResolveReport:                "resolve_report",
+	DismissReport:                "dismiss_report",
+	SuspendUser:                  "suspend_user",
+	ReinstateUser:                "reinstate_user",
+	RevokeStrike:                 "revoke_strike",
+	BanPublishing:                "ban_publishing",
+	LiftPublishingBan:            "lift_publishing_ban",
+	AddUserBlock:                 "add_user_block",
+	RemoveUserBlock:              "remove_user_block",
+	RemoveSubscriptions:          "remove_subscriptions",
+	CreateAnnouncement:           "create_announcement",
+	CancelAnnouncement:           "cancel_announcement",
+	SendAnnouncement:             "send_announcement",
+	CreateUserSegment:            "create_user_segment",
+	UpdateUserSegment:            "update_user_segment",
+	DeleteUserSegment:            "delete_user_segment",
+	SaveNotificationTemplate:     "save_notification_template",
+	RestoreNotificationTemplate:  "restore_notification_template",
+	DeleteNotificationTemplate:   "delete_notification_template",
 }
 
 type RoleType string
@@ -106,9 +220,13 @@ type User struct {
 	UpdatedBy           *User          `gorm:"foreignKey:UpdatedByID" json:"updated_by,omitempty"`
 	DeletedAt           gorm.DeletedAt `json:"deleted_at,omitempty"`
 	DeletedByID         *uint          `json:"deleted_by_id,omitempty"`
+	DeletedBy           *User          `gorm:"foreignKey:DeletedByID;constraint:OnDelete:SET NULL" json:"deleted_by,omitempty"`
 	AvatarFileName      sql.NullString `gorm:"type:varchar(255)" json:"avatar_file_name,omitempty"`
 	Status              UserStatusType `gorm:"type:varchar(50);not null;default:'offline'" json:"status,omitempty"`
 	BlockedReason       string         `gorm:"type:text" json:"blocked_reason,omitempty"`
+	SuspendedUntil      *time.Time     `gorm:"index" json:"suspended_until,omitempty"`      // blocked users are reinstated at it, nil is a permanent block
+	Strikes             uint           `gorm:"not null;default:0" json:"strikes,omitempty"` // unrevoked UserStrike count
+	Locale              string         `gorm:"type:varchar(20)" json:"locale,omitempty"`    // of notifications, empty is the default language
 	NumNotification     uint           `gorm:"not null;default:0"`
 	AdminLogs           []AdminLog     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 	CreatedByCategories []Category     `gorm:"foreignKey:CreatedByID"`
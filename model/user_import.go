@@ -0,0 +1,11 @@
+package model
+
+// UserImportCredential keeps the password hash of an import row out of the job payload, which is shown by the jobs api.
+// Credentials are removed when the job finishes and with the job.
+type UserImportCredential struct {
+	ID           uint   `gorm:"primaryKey"`
+	JobID        uint   `gorm:"not null;uniqueIndex:idx_user_import_credential"`
+	Row          int    `gorm:"not null;uniqueIndex:idx_user_import_credential"`
+	PasswordHash string `gorm:"type:varchar(255);not null"`
+	Job          Job    `gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+}
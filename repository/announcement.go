@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AnnouncementRepository struct {
+	db *gorm.DB
+}
+
+func newAnnouncementRepository(db *gorm.DB) *AnnouncementRepository {
+	return &AnnouncementRepository{
+		db: db,
+	}
+}
+
+func (r *AnnouncementRepository) Create(announcement *model.Announcement) error {
+	return r.db.Omit("Stream", "Category", "Segment", "Job", "CreatedBy").Create(announcement).Error
+}
+
+func (r *AnnouncementRepository) FindByID(id uint) (*model.Announcement, error) {
+	var result model.Announcement
+	if err := r.db.Model(model.Announcement{}).Where("id = ?", id).Preload("CreatedBy").First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *AnnouncementRepository) Page(req *dto.AnnouncementQuery) (*utils.PaginationModel[model.Announcement], error) {
+	query := r.db.Model(model.Announcement{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Keyword != "" {
+		query = query.Where("title ILIKE ?", "%"+req.Keyword+"%")
+	}
+	query = query.Order("scheduled_at DESC, id DESC").Preload("CreatedBy")
+
+	pagination, err := utils.CreatePage[model.Announcement](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+func (r *AnnouncementRepository) SetJob(id, jobID uint) error {
+	return r.db.Model(&model.Announcement{}).Where("id = ?", id).Update("job_id", jobID).Error
+}
+
+// Start marks a scheduled announcement as sending, it returns false when it was canceled or finished
+func (r *AnnouncementRepository) Start(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&model.Announcement{}).
+		Where("id = ? AND status IN ?", id, []model.AnnouncementStatus{model.AnnouncementStatusScheduled, model.AnnouncementStatusSending}).
+		Updates(map[string]interface{}{
+			"status":     model.AnnouncementStatusSending,
+			"started_at": gorm.Expr("COALESCE(started_at, ?)", now),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Deliver creates notifications of a batch of users in id order, bumps their counters and moves the cursor of the
+// announcement in one transaction. It returns false when the announcement is no longer sending.
+func (r *AnnouncementRepository) Deliver(id uint, notifications []model.Notification) (bool, error) {
+	userIDs := make([]uint, len(notifications))
+	for i, notification := range notifications {
+		userIDs[i] = notification.UserID
+	}
+
+	delivered := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Announcement{}).Where("id = ? AND status = ?", id, model.AnnouncementStatusSending).Updates(map[string]interface{}{
+			"last_user_id": userIDs[len(userIDs)-1],
+			"recipients":   gorm.Expr("recipients + ?", len(userIDs)),
+		})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := tx.Omit("Stream", "User").CreateInBatches(notifications, 500).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.User{}).Where("id IN ?", userIDs).
+			UpdateColumn("num_notification", gorm.Expr("num_notification + 1")).Error; err != nil {
+			return err
+		}
+		delivered = true
+		return nil
+	})
+	return delivered, err
+}
+
+// Finish sets the final status of a sending announcement, canceled ones are left alone
+func (r *AnnouncementRepository) Finish(id uint, status model.AnnouncementStatus, now time.Time) error {
+	updates := map[string]interface{}{"status": status}
+	if status == model.AnnouncementStatusSent {
+		updates["sent_at"] = now
+	}
+	return r.db.Model(&model.Announcement{}).Where("id = ? AND status = ?", id, model.AnnouncementStatusSending).Updates(updates).Error
+}
+
+// Cancel returns false when the announcement was sent already
+func (r *AnnouncementRepository) Cancel(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&model.Announcement{}).
+		Where("id = ? AND status IN ?", id, []model.AnnouncementStatus{model.AnnouncementStatusScheduled, model.AnnouncementStatusSending}).
+		Updates(map[string]interface{}{
+			"status":      model.AnnouncementStatusCanceled,
+			"canceled_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+type AnnouncementStatsRow struct {
+	Delivered   int64
+	Read        int64
+	Hidden      int64
+	FirstReadAt *time.Time
+	LastReadAt  *time.Time
+}
+
+func (r *AnnouncementRepository) Stats(id uint) (*AnnouncementStatsRow, error) {
+	var result AnnouncementStatsRow
+	if err := r.db.Model(model.Notification{}).
+		Select("COUNT(*) AS delivered, COUNT(read_at) AS read, COUNT(hidden_at) AS hidden, MIN(read_at) AS first_read_at, MAX(read_at) AS last_read_at").
+		Where("announcement_id = ?", id).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CountPendingBySegment counts announcements to the segment which are not sent yet
+func (r *AnnouncementRepository) CountPendingBySegment(segmentID uint) (int64, error) {
+	var result int64
+	if err := r.db.Model(model.Announcement{}).
+		Where("segment_id = ? AND status IN ?", segmentID, []model.AnnouncementStatus{model.AnnouncementStatusScheduled, model.AnnouncementStatusSending}).
+		Count(&result).Error; err != nil {
+		return 0, err
+	}
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BlockRepository struct {
+	db *gorm.DB
+}
+
+func newBlockRepository(db *gorm.DB) *BlockRepository {
+	return &BlockRepository{
+		db: db,
+	}
+}
+
+// Page lists blocks of the user in the direction, blocks of trashed users are left out
+func (r *BlockRepository) Page(userID uint, req *dto.UserBlockQuery) (*utils.PaginationModel[model.BlockedList], error) {
+	query := r.db.Model(model.BlockedList{})
+	if req.Direction == dto.USER_BLOCK_DIRECTION_BLOCKED_BY {
+		query = query.Joins("JOIN users ON users.id = blocked_lists.user_id AND users.deleted_at IS NULL").
+			Where("blocked_lists.blocked_user_id = ?", userID).Preload("User")
+	} else {
+		query = query.Joins("JOIN users ON users.id = blocked_lists.blocked_user_id AND users.deleted_at IS NULL").
+			Where("blocked_lists.user_id = ?", userID).Preload("BlockedUser")
+	}
+	query = query.Order("blocked_lists.blocked_at DESC")
+
+	pagination, err := utils.CreatePage[model.BlockedList](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+type MostBlockedRow struct {
+	BlockedUserID uint
+	Blocks        int64
+	TotalBlocks   int64
+	LastBlockedAt time.Time
+}
+
+// MostBlocked ranks users by blocks in [from, to), trashed users are left out
+func (r *BlockRepository) MostBlocked(from, to time.Time, req *dto.MostBlockedQuery) (*utils.PaginationModel[MostBlockedRow], error) {
+	ranked := r.db.Model(model.BlockedList{}).
+		Select("blocked_lists.blocked_user_id, COUNT(*) FILTER (WHERE blocked_lists.blocked_at >= ? AND blocked_lists.blocked_at < ?) AS blocks, "+
+			"COUNT(*) AS total_blocks, MAX(blocked_lists.blocked_at) AS last_blocked_at", from, to).
+		Joins("JOIN users ON users.id = blocked_lists.blocked_user_id AND users.deleted_at IS NULL").
+		Group("blocked_lists.blocked_user_id").
+		Having("COUNT(*) FILTER (WHERE blocked_lists.blocked_at >= ? AND blocked_lists.blocked_at < ?) >= ?", from, to, max(req.MinBlocks, 1))
+	query := r.db.Table("(?) AS ranked", ranked).Order("blocks DESC, total_blocks DESC, blocked_user_id")
+
+	pagination, err := utils.CreatePage[MostBlockedRow](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+// Create returns false when the user blocked blockedUserID already
+func (r *BlockRepository) Create(userID, blockedUserID uint) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Omit("User", "BlockedUser").Create(&model.BlockedList{
+		UserID:        userID,
+		BlockedUserID: blockedUserID,
+		BlockedAt:     time.Now(),
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Delete returns false when the user didn't block blockedUserID
+func (r *BlockRepository) Delete(userID, blockedUserID uint) (bool, error) {
+	result := r.db.Where("user_id = ? AND blocked_user_id = ?", userID, blockedUserID).Delete(&model.BlockedList{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *BlockRepository) FindUsers(ids []uint) ([]model.User, error) {
+	var result []model.User
+	if err := r.db.Model(model.User{}).Where("id IN ?", ids).Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"gitlab/live/be-live-admin/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// statuses of scheduled streams which still take a slot
+var activeScheduleStatuses = []model.StreamStatus{model.UPCOMING, model.PENDING, model.STARTED}
+
+type CalendarRepository struct {
+	db *gorm.DB
+}
+
+func newCalendarRepository(db *gorm.DB) *CalendarRepository {
+	return &CalendarRepository{
+		db: db,
+	}
+}
+
+// streams without known duration take defaultDuration
+func (r *CalendarRepository) overlapping(query *gorm.DB, from, to time.Time, defaultDuration time.Duration) *gorm.DB {
+	return query.
+		Where("schedule_streams.scheduled_at < ?", to).
+		Where("schedule_streams.scheduled_at + (CASE WHEN schedule_streams.video_duration > 0 THEN schedule_streams.video_duration ELSE ? END) * INTERVAL '1 second' > ?", int64(defaultDuration.Seconds()), from)
+}
+
+// GetOverlappingScheduleStreams returns active scheduled streams overlapping [from, to),
+// excludeStreamID and occurrences of excludeRecurrenceID are skipped when they're not 0
+func (r *CalendarRepository) GetOverlappingScheduleStreams(from, to time.Time, defaultDuration time.Duration, excludeStreamID, excludeRecurrenceID uint) ([]model.ScheduleStream, error) {
+	query := r.db.Model(model.ScheduleStream{}).
+		Joins("INNER JOIN streams ON streams.id = schedule_streams.stream_id").
+		Where("streams.status IN ? AND streams.deleted_at IS NULL", activeScheduleStatuses).
+		Preload("Stream")
+	if excludeStreamID != 0 {
+		query = query.Where("schedule_streams.stream_id != ?", excludeStreamID)
+	}
+	if excludeRecurrenceID != 0 {
+		query = query.Where("(schedule_streams.recurrence_id IS NULL OR schedule_streams.recurrence_id != ?)", excludeRecurrenceID)
+	}
+
+	var result []model.ScheduleStream
+	if err := r.overlapping(query, from, to, defaultDuration).Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetScheduleStreams returns scheduled streams of any status overlapping [from, to)
+func (r *CalendarRepository) GetScheduleStreams(from, to time.Time, defaultDuration time.Duration, userID, categoryID uint) ([]model.ScheduleStream, error) {
+	query := r.db.Model(model.ScheduleStream{}).
+		Joins("INNER JOIN streams ON streams.id = schedule_streams.stream_id").
+		Where("streams.deleted_at IS NULL").
+		Preload("Stream.User")
+	if userID != 0 {
+		query = query.Where("streams.user_id = ?", userID)
+	}
+	if categoryID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM stream_categories WHERE stream_categories.stream_id = streams.id AND stream_categories.category_id = ?)", categoryID)
+	}
+
+	var result []model.ScheduleStream
+	if err := r.overlapping(query, from, to, defaultDuration).Order("schedule_streams.scheduled_at").Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *CalendarRepository) GetCategoriesByStreamIDs(streamIDs []uint) (map[uint][]model.Category, error) {
+	result := make(map[uint][]model.Category)
+	if len(streamIDs) == 0 {
+		return result, nil
+	}
+
+	var streamCategories []model.StreamCategory
+	if err := r.db.Model(model.StreamCategory{}).Where("stream_id IN ?", streamIDs).Preload("Category").Find(&streamCategories).Error; err != nil {
+		return nil, err
+	}
+	for _, v := range streamCategories {
+		result[v.StreamID] = append(result[v.StreamID], v.Category)
+	}
+	return result, nil
+}
+
+// GetRecurrences returns every series with its streamer, categories and exceptions
+func (r *CalendarRepository) GetRecurrences(userID, categoryID uint) ([]model.StreamRecurrence, error) {
+	query := r.db.Model(model.StreamRecurrence{}).Preload("User").Preload("Categories").Preload("Exceptions")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if categoryID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM stream_recurrence_categories WHERE stream_recurrence_categories.stream_recurrence_id = stream_recurrences.id AND stream_recurrence_categories.category_id = ?)", categoryID)
+	}
+
+	var result []model.StreamRecurrence
+	if err := query.Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
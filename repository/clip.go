@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"gitlab/live/be-live-admin/model"
+
+	"gorm.io/gorm"
+)
+
+type ClipRepository struct {
+	db *gorm.DB
+}
+
+func newClipRepository(db *gorm.DB) *ClipRepository {
+	return &ClipRepository{
+		db: db,
+	}
+}
+
+func (r *ClipRepository) Create(clip *model.Clip) error {
+	return r.db.Omit("Stream", "CreatedBy").Create(clip).Error
+}
+
+func (r *ClipRepository) FindByStreamID(streamID uint) ([]model.Clip, error) {
+	var result []model.Clip
+	if err := r.db.Model(model.Clip{}).Where("stream_id = ?", streamID).Preload("CreatedBy").Order("created_at DESC").Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *ClipRepository) FindByID(streamID, id uint) (*model.Clip, error) {
+	var result model.Clip
+	if err := r.db.Model(model.Clip{}).Where("stream_id = ? AND id = ?", streamID, id).Preload("CreatedBy").First(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *ClipRepository) Get(id uint) (*model.Clip, error) {
+	var result model.Clip
+	if err := r.db.Model(model.Clip{}).Where("id = ?", id).First(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *ClipRepository) UpdateStatus(id uint, status model.ClipStatus) error {
+	return r.db.Model(model.Clip{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// SaveResult stores the outcome of the ffmpeg job
+func (r *ClipRepository) SaveResult(clip *model.Clip) error {
+	return r.db.Model(model.Clip{}).Where("id = ?", clip.ID).Updates(map[string]interface{}{
+		"status":   clip.Status,
+		"size":     clip.Size,
+		"duration": clip.Duration,
+		"error":    clip.Error,
+	}).Error
+}
+
+func (r *ClipRepository) Delete(id uint) error {
+	return r.db.Delete(&model.Clip{}, id).Error
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func newCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{
+		db: db,
+	}
+}
+
+func (r *CommentRepository) Page(req *dto.CommentQuery) (*utils.PaginationModel[model.Comment], error) {
+	query := r.db.Model(model.Comment{})
+	if req.StreamID != 0 {
+		query = query.Where("stream_id = ?", req.StreamID)
+	}
+	if req.UserID != 0 {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+	if req.From != 0 {
+		query = query.Where("created_at >= ?", time.Unix(req.From, 0))
+	}
+	if req.To != 0 {
+		query = query.Where("created_at <= ?", time.Unix(req.To, 0))
+	}
+	if req.Keyword != "" {
+		query = query.Where("comment ILIKE ?", "%"+req.Keyword+"%")
+	}
+	if req.Flagged != nil {
+		query = query.Where("flagged = ?", *req.Flagged)
+	}
+	if req.Hidden != nil {
+		if *req.Hidden {
+			query = query.Where("hidden_at IS NOT NULL")
+		} else {
+			query = query.Where("hidden_at IS NULL")
+		}
+	}
+	query = query.Order("created_at DESC").Preload("User").Preload("HiddenBy").Preload("Stream", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Select("id, title")
+	})
+
+	pagination, err := utils.CreatePage[model.Comment](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+func (r *CommentRepository) FindByID(id uint) (*model.Comment, error) {
+	var result model.Comment
+	if err := r.db.Model(model.Comment{}).Where("id = ?", id).Preload("User").Preload("HiddenBy").Preload("Stream", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Select("id, title")
+	}).First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Hide returns false when the comment is already hidden
+func (r *CommentRepository) Hide(id, hiddenByID uint, reason string) (bool, error) {
+	result := r.db.Model(model.Comment{}).Where("id = ? AND hidden_at IS NULL", id).Updates(map[string]interface{}{
+		"hidden_at":     time.Now(),
+		"hidden_by_id":  hiddenByID,
+		"hidden_reason": reason,
+		"flagged":       false,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Unhide returns false when the comment isn't hidden
+func (r *CommentRepository) Unhide(id uint) (bool, error) {
+	result := r.db.Model(model.Comment{}).Where("id = ? AND hidden_at IS NOT NULL", id).Updates(map[string]interface{}{
+		"hidden_at":     nil,
+		"hidden_by_id":  nil,
+		"hidden_reason": "",
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *CommentRepository) Delete(id uint) error {
+	return r.db.Where("id = ?", id).Delete(&model.Comment{}).Error
+}
+
+// FirstCreatedAt returns the time of the first comment of a stream, it's not valid when there is none
+func (r *CommentRepository) FirstCreatedAt(streamID uint) (sql.NullTime, error) {
+	var result sql.NullTime
+	if err := r.db.Model(model.Comment{}).Select("MIN(created_at)").Where("stream_id = ?", streamID).Scan(&result).Error; err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+type CommentTimelineRow struct {
+	Bucket  int64
+	Total   int64
+	Hidden  int64
+	Flagged int64
+}
+
+// Timeline counts comments of a stream per interval of seconds since start, comments before start are left out
+func (r *CommentRepository) Timeline(streamID uint, start time.Time, interval uint) ([]CommentTimelineRow, error) {
+	var result []CommentTimelineRow
+	if err := r.db.Model(model.Comment{}).
+		Select("FLOOR(EXTRACT(EPOCH FROM (created_at - @start)) / @interval)::bigint AS bucket, COUNT(*) AS total, COUNT(hidden_at) AS hidden, COUNT(*) FILTER (WHERE flagged) AS flagged",
+			sql.Named("start", start), sql.Named("interval", interval)).
+		Where("stream_id = ? AND created_at >= ?", streamID, start).
+		Group("bucket").Order("bucket").
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
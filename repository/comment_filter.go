@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CommentFilterRepository struct {
+	db *gorm.DB
+}
+
+func newCommentFilterRepository(db *gorm.DB) *CommentFilterRepository {
+	return &CommentFilterRepository{
+		db: db,
+	}
+}
+
+func (r *CommentFilterRepository) Page(req *dto.CommentFilterRuleQuery) (*utils.PaginationModel[model.CommentFilterRule], error) {
+	query := r.db.Model(model.CommentFilterRule{})
+	if req.Enabled != nil {
+		query = query.Where("enabled = ?", *req.Enabled)
+	}
+	if req.CategoryID != 0 {
+		query = query.Where("category_id = ?", req.CategoryID)
+	}
+	if req.Action != "" {
+		query = query.Where("action = ?", req.Action)
+	}
+	if req.Keyword != "" {
+		query = query.Where("name ILIKE ? OR pattern ILIKE ?", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
+	query = query.Order("id DESC").Preload("Category")
+
+	pagination, err := utils.CreatePage[model.CommentFilterRule](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+func (r *CommentFilterRepository) FindByID(id uint) (*model.CommentFilterRule, error) {
+	var rule model.CommentFilterRule
+	if err := r.db.Model(model.CommentFilterRule{}).Preload("Category").Where("id = ?", id).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *CommentFilterRepository) FindByIDs(ids []uint) ([]model.CommentFilterRule, error) {
+	var result []model.CommentFilterRule
+	if err := r.db.Model(model.CommentFilterRule{}).Where("id IN ?", ids).Order("id").Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *CommentFilterRepository) FindEnabled() ([]model.CommentFilterRule, error) {
+	var result []model.CommentFilterRule
+	if err := r.db.Model(model.CommentFilterRule{}).Preload("Category").Where("enabled = ?", true).Order("id").Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *CommentFilterRepository) Create(rule *model.CommentFilterRule) error {
+	return r.db.Omit("Category").Create(rule).Error
+}
+
+func (r *CommentFilterRepository) Update(rule *model.CommentFilterRule) error {
+	return r.db.Model(rule).Select("name", "type", "pattern", "category_id", "action", "hit_threshold", "enabled", "updated_by_id").Updates(rule).Error
+}
+
+func (r *CommentFilterRepository) Delete(id uint) error {
+	return r.db.Where("id = ?", id).Delete(&model.CommentFilterRule{}).Error
+}
+
+// FindComments returns comments older than beforeID in the range of the request, newest first. beforeID 0 starts from the newest.
+func (r *CommentFilterRepository) FindComments(req *dto.CommentFilterEvaluateRequest, beforeID uint, limit int) ([]dto.CommentFilterCommentDTO, error) {
+	query := r.db.Model(model.Comment{}).Select("id, stream_id, user_id, comment, created_at")
+	if beforeID != 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	if req.StreamID != 0 {
+		query = query.Where("stream_id = ?", req.StreamID)
+	}
+	if req.From != 0 {
+		query = query.Where("created_at >= ?", time.Unix(req.From, 0))
+	}
+	if req.To != 0 {
+		query = query.Where("created_at <= ?", time.Unix(req.To, 0))
+	}
+
+	var result []dto.CommentFilterCommentDTO
+	if err := query.Order("id DESC").Limit(limit).Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *CommentFilterRepository) FindStreamCategoryIDs(streamIDs []uint) (map[uint][]uint, error) {
+	var rows []model.StreamCategory
+	if err := r.db.Model(model.StreamCategory{}).Select("stream_id, category_id").Where("stream_id IN ?", streamIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint][]uint)
+	for _, row := range rows {
+		result[row.StreamID] = append(result[row.StreamID], row.CategoryID)
+	}
+	return result, nil
+}
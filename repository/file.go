@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"gitlab/live/be-live-admin/model"
+
+	"gorm.io/gorm"
+)
+
+// FileRepository lists file names which are still referenced by database rows
+type FileRepository struct {
+	db *gorm.DB
+}
+
+func newFileRepository(db *gorm.DB) *FileRepository {
+	return &FileRepository{
+		db: db,
+	}
+}
+
+func (r *FileRepository) GetThumbnailFileNames() ([]string, error) {
+	var result, recurrenceResult []string
+	if err := r.db.Unscoped().Model(model.Stream{}).Where("thumbnail_file_name != ''").Pluck("thumbnail_file_name", &result).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Model(model.StreamRecurrence{}).Pluck("thumbnail_file_name", &recurrenceResult).Error; err != nil {
+		return nil, err
+	}
+	return append(result, recurrenceResult...), nil
+}
+
+func (r *FileRepository) GetStreamKeys() ([]string, error) {
+	var result []string
+	if err := r.db.Unscoped().Model(model.Stream{}).Pluck("stream_key", &result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *FileRepository) GetScheduledVideoNames() ([]string, error) {
+	var result, recurrenceResult, playlistResult []string
+	if err := r.db.Model(model.ScheduleStream{}).Pluck("video_name", &result).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Model(model.StreamRecurrence{}).Pluck("video_name", &recurrenceResult).Error; err != nil {
+		return nil, err
+	}
+	// videos and slates of playlists
+	if err := r.db.Model(model.ScheduleStreamItem{}).Pluck("file_name", &playlistResult).Error; err != nil {
+		return nil, err
+	}
+	return append(append(result, recurrenceResult...), playlistResult...), nil
+}
+
+func (r *FileRepository) GetClipFileNames() ([]string, error) {
+	var result []string
+	if err := r.db.Model(model.Clip{}).Pluck("file_name", &result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// soft deleted users are included, their avatar is still needed when restoring
+func (r *FileRepository) GetAvatarFileNames() ([]string, error) {
+	var result []string
+	if err := r.db.Unscoped().Model(model.User{}).Where("avatar_file_name IS NOT NULL").Pluck("avatar_file_name", &result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GDPRRepository struct {
+	db *gorm.DB
+}
+
+func newGDPRRepository(db *gorm.DB) *GDPRRepository {
+	return &GDPRRepository{
+		db: db,
+	}
+}
+
+func (r *GDPRRepository) Create(request *model.GDPRRequest) error {
+	return r.db.Omit("RequestedBy").Create(request).Error
+}
+
+func (r *GDPRRepository) FindByID(id uint) (*model.GDPRRequest, error) {
+	var result model.GDPRRequest
+	if err := r.db.Model(model.GDPRRequest{}).Where("id = ?", id).Preload("RequestedBy").First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *GDPRRepository) Page(req *dto.GDPRRequestQuery) (*utils.PaginationModel[model.GDPRRequest], error) {
+	query := r.db.Model(model.GDPRRequest{})
+	if req.UserID != 0 {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+	if req.Type != "" {
+		query = query.Where("type = ?", req.Type)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	query = query.Order("created_at DESC").Preload("RequestedBy")
+
+	pagination, err := utils.CreatePage[model.GDPRRequest](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+// HasPending ignores requests whose job was canceled before it ran
+func (r *GDPRRepository) HasPending(userID uint, requestType model.GDPRRequestType) (bool, error) {
+	var count int64
+	if err := r.db.Model(model.GDPRRequest{}).
+		Joins("INNER JOIN jobs ON jobs.id = gdpr_requests.job_id").
+		Where("gdpr_requests.user_id = ? AND gdpr_requests.type = ? AND gdpr_requests.status = ?", userID, requestType, model.GDPRRequestStatusPending).
+		Where("jobs.status IN ?", []model.JobStatus{model.JobStatusQueued, model.JobStatusRunning}).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *GDPRRepository) SetJobID(id, jobID uint) error {
+	return r.db.Model(model.GDPRRequest{}).Where("id = ?", id).Update("job_id", jobID).Error
+}
+
+func (r *GDPRRepository) Complete(id uint, fileName, report, signature string) error {
+	return r.db.Model(model.GDPRRequest{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       model.GDPRRequestStatusCompleted,
+		"file_name":    fileName,
+		"report":       report,
+		"signature":    signature,
+		"error":        "",
+		"completed_at": time.Now(),
+	}).Error
+}
+
+func (r *GDPRRepository) Fail(id uint, lastError string) error {
+	return r.db.Model(model.GDPRRequest{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status": model.GDPRRequestStatusFailed,
+		"error":  lastError,
+	}).Error
+}
+
+func findUserRecords[T any](db *gorm.DB, table any, columns, userColumn string, userID uint) ([]T, error) {
+	var result []T
+	if err := db.Model(table).Select(columns).Where(userColumn+" = ?", userID).Order("created_at").Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *GDPRRepository) FindProfile(userID uint) (*dto.GDPRProfileDTO, error) {
+	var result dto.GDPRProfileDTO
+	if err := r.db.Unscoped().Model(model.User{}).
+		Select("users.id, users.username, users.display_name, users.email, roles.type AS role, users.status, users.blocked_reason, COALESCE(users.avatar_file_name, '') AS avatar_file_name, users.created_at, users.updated_at").
+		Joins("INNER JOIN roles ON roles.id = users.role_id").
+		Where("users.id = ?", userID).
+		Take(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindStreams includes streams in trash, they aren't purged yet
+func (r *GDPRRepository) FindStreams(userID uint) ([]dto.GDPRStreamDTO, error) {
+	return findUserRecords[dto.GDPRStreamDTO](r.db.Unscoped(), model.Stream{}, "id, title, description, status, stream_type, thumbnail_file_name, started_at, ended_at, created_at, deleted_at", "user_id", userID)
+}
+
+func (r *GDPRRepository) FindComments(userID uint) ([]dto.GDPRCommentDTO, error) {
+	return findUserRecords[dto.GDPRCommentDTO](r.db, model.Comment{}, "id, stream_id, comment, created_at, updated_at", "user_id", userID)
+}
+
+func (r *GDPRRepository) FindLikes(userID uint) ([]dto.GDPRLikeDTO, error) {
+	return findUserRecords[dto.GDPRLikeDTO](r.db, model.Like{}, "stream_id, like_emote, created_at", "user_id", userID)
+}
+
+func (r *GDPRRepository) FindViews(userID uint) ([]dto.GDPRViewDTO, error) {
+	return findUserRecords[dto.GDPRViewDTO](r.db, model.View{}, "stream_id, view_type, created_at", "user_id", userID)
+}
+
+func (r *GDPRRepository) FindShares(userID uint) ([]dto.GDPRStreamRefDTO, error) {
+	return findUserRecords[dto.GDPRStreamRefDTO](r.db, model.Share{}, "stream_id, created_at", "user_id", userID)
+}
+
+func (r *GDPRRepository) FindBookmarks(userID uint) ([]dto.GDPRStreamRefDTO, error) {
+	return findUserRecords[dto.GDPRStreamRefDTO](r.db, model.Bookmark{}, "stream_id, created_at", "user_id", userID)
+}
+
+func (r *GDPRRepository) FindSubscriptions(userID uint) ([]dto.GDPRSubscriptionDTO, error) {
+	return findUserRecords[dto.GDPRSubscriptionDTO](r.db, model.Subscription{}, "streamer_id, is_mute, created_at", "subscriber_id", userID)
+}
+
+func (r *GDPRRepository) FindNotifications(userID uint) ([]dto.GDPRNotificationDTO, error) {
+	return findUserRecords[dto.GDPRNotificationDTO](r.db, model.Notification{}, "stream_id, type, content, created_at, read_at", "user_id", userID)
+}
+
+// FindUploadedFiles returns thumbnails and scheduled videos of streams of the user, trashed ones included
+func (r *GDPRRepository) FindUploadedFiles(userID uint) ([]string, []string, error) {
+	var thumbnails, videos, playlistFiles []string
+	streamIDs := r.db.Unscoped().Model(model.Stream{}).Select("id").Where("user_id = ?", userID)
+	if err := r.db.Unscoped().Model(model.Stream{}).Where("user_id = ? AND thumbnail_file_name != ''", userID).Distinct().Pluck("thumbnail_file_name", &thumbnails).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := r.db.Model(model.ScheduleStream{}).Where("stream_id IN (?) AND video_name != ''", streamIDs).Distinct().Pluck("video_name", &videos).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := r.db.Model(model.ScheduleStreamItem{}).
+		Joins("INNER JOIN schedule_streams ON schedule_streams.id = schedule_stream_items.schedule_stream_id").
+		Where("schedule_streams.stream_id IN (?)", streamIDs).
+		Distinct().Pluck("schedule_stream_items.file_name", &playlistFiles).Error; err != nil {
+		return nil, nil, err
+	}
+	return thumbnails, append(videos, playlistFiles...), nil
+}
+
+// FindAllStreams includes streams in trash
+func (r *GDPRRepository) FindAllStreams(userID uint) ([]model.Stream, error) {
+	var result []model.Stream
+	if err := r.db.Unscoped().Model(model.Stream{}).Where("user_id = ?", userID).Order("id").Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *GDPRRepository) FindRecurrenceIDs(userID uint) ([]uint, error) {
+	var result []uint
+	if err := r.db.Model(model.StreamRecurrence{}).Where("user_id = ?", userID).Order("id").Pluck("id", &result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Erase applies dto.GDPRErasurePolicy to the records of a user in one transaction, streams and recurrences are removed before by the service.
+// It returns the affected rows per record.
+func (r *GDPRRepository) Erase(userID uint, anonymized map[string]interface{}) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		deletes := []struct {
+			record string
+			table  any
+			where  string
+		}{
+			{dto.GDPR_RECORD_COMMENTS, &model.Comment{}, "user_id = @id"},
+			{dto.GDPR_RECORD_BOOKMARKS, &model.Bookmark{}, "user_id = @id"},
+			{dto.GDPR_RECORD_SUBSCRIPTIONS, &model.Subscription{}, "subscriber_id = @id OR streamer_id = @id"},
+			{dto.GDPR_RECORD_NOTIFICATIONS, &model.Notification{}, "user_id = @id"},
+			{dto.GDPR_RECORD_BLOCKED_LIST, &model.BlockedList{}, "user_id = @id OR blocked_user_id = @id"},
+			{dto.GDPR_RECORD_TWO_FA, &model.TwoFA{}, "user_id = @id"},
+			{dto.GDPR_RECORD_STORAGE_USAGE, &model.StorageUsage{}, "user_id = @id"},
+		}
+		for _, d := range deletes {
+			result := tx.Where(d.where, sql.Named("id", userID)).Delete(d.table)
+			if result.Error != nil {
+				return result.Error
+			}
+			counts[d.record] = result.RowsAffected
+		}
+
+		// kept on the anonymized account
+		for record, table := range map[string]any{dto.GDPR_RECORD_LIKES: model.Like{}, dto.GDPR_RECORD_VIEWS: model.View{}, dto.GDPR_RECORD_SHARES: model.Share{}} {
+			var count int64
+			if err := tx.Model(table).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+				return err
+			}
+			counts[record] = count
+		}
+
+		result := tx.Unscoped().Model(&model.User{}).Where("id = ?", userID).Updates(anonymized)
+		if result.Error != nil {
+			return result.Error
+		}
+		counts[dto.GDPR_RECORD_PROFILE] = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type InviteRepository struct {
+	db *gorm.DB
+}
+
+func newInviteRepository(db *gorm.DB) *InviteRepository {
+	return &InviteRepository{
+		db: db,
+	}
+}
+
+func (r *InviteRepository) Create(invite *model.Invite) error {
+	return r.db.Omit("Role", "InvitedBy", "User").Create(invite).Error
+}
+
+// CreateBatch creates invites in one transaction
+func (r *InviteRepository) CreateBatch(invites []*model.Invite) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, invite := range invites {
+			if err := tx.Omit("Role", "InvitedBy", "User").Create(invite).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *InviteRepository) FindByID(id uint) (*model.Invite, error) {
+	var result model.Invite
+	if err := r.db.Model(model.Invite{}).Where("id = ?", id).Preload("Role").Preload("InvitedBy").First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindPendingByToken returns nil when no pending invite has the token
+func (r *InviteRepository) FindPendingByToken(tokenHash string) (*model.Invite, error) {
+	var result model.Invite
+	if err := r.db.Model(model.Invite{}).Where("token_hash = ? AND status = ?", tokenHash, model.InviteStatusPending).Preload("Role").First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindPending returns pending invites which reserve one of the usernames or emails, emails are compared lower case
+func (r *InviteRepository) FindPending(usernames, emails []string) ([]model.Invite, error) {
+	var result []model.Invite
+	if err := r.db.Model(model.Invite{}).Select("id, username, email").
+		Where("status = ?", model.InviteStatusPending).
+		Where("username IN ? OR LOWER(email) IN ?", usernames, emails).
+		Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *InviteRepository) Page(req *dto.InviteQuery) (*utils.PaginationModel[model.Invite], error) {
+	query := r.db.Model(model.Invite{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Email != "" {
+		query = query.Where("email ILIKE ?", "%"+req.Email+"%")
+	}
+	query = query.Order("created_at DESC").Preload("Role").Preload("InvitedBy")
+
+	pagination, err := utils.CreatePage[model.Invite](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+// SetToken replaces the token of a pending invite before its email is sent, the previous link stops working.
+// It returns false when the invite is no longer pending.
+func (r *InviteRepository) SetToken(id uint, tokenHash string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(model.Invite{}).Where("id = ? AND status = ?", id, model.InviteStatusPending).Updates(map[string]interface{}{
+		"token_hash": tokenHash,
+		"expires_at": expiresAt,
+		"sent_at":    time.Now(),
+		"sent_count": gorm.Expr("sent_count + 1"),
+		"last_error": "",
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *InviteRepository) SetError(id uint, lastError string) error {
+	return r.db.Model(model.Invite{}).Where("id = ?", id).Update("last_error", lastError).Error
+}
+
+// Revoke returns false when the invite is no longer pending
+func (r *InviteRepository) Revoke(id uint) (bool, error) {
+	result := r.db.Model(model.Invite{}).Where("id = ? AND status = ?", id, model.InviteStatusPending).Updates(map[string]interface{}{
+		"status":     model.InviteStatusRevoked,
+		"token_hash": "",
+		"revoked_at": time.Now(),
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Accept creates the user of a pending invite, gorm.ErrRecordNotFound is returned when it was accepted or revoked meanwhile
+func (r *InviteRepository) Accept(invite *model.Invite, user *model.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Role").Create(user).Error; err != nil {
+			return err
+		}
+		result := tx.Model(model.Invite{}).Where("id = ? AND status = ?", invite.ID, model.InviteStatusPending).Updates(map[string]interface{}{
+			"status":      model.InviteStatusAccepted,
+			"token_hash":  "",
+			"user_id":     user.ID,
+			"accepted_at": time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository struct {
+	db *gorm.DB
+}
+
+func newJobRepository(db *gorm.DB) *JobRepository {
+	return &JobRepository{
+		db: db,
+	}
+}
+
+func (r *JobRepository) Create(job *model.Job) error {
+	return r.db.Omit("CreatedBy").Create(job).Error
+}
+
+func (r *JobRepository) FindByID(id uint) (*model.Job, error) {
+	var result model.Job
+	if err := r.db.Model(model.Job{}).Where("id = ?", id).Preload("CreatedBy").First(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *JobRepository) Page(req *dto.JobQuery) (*utils.PaginationModel[model.Job], error) {
+	query := r.db.Model(model.Job{})
+	if req.Type != "" {
+		query = query.Where("type = ?", req.Type)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	query = query.Order("created_at DESC").Preload("CreatedBy")
+
+	pagination, err := utils.CreatePage[model.Job](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+// Claim locks the next due job of types for workerID, nil when there is none.
+// Rows locked by other workers are skipped so workers never wait for each other.
+func (r *JobRepository) Claim(types []model.JobType, workerID string) (*model.Job, error) {
+	var job model.Job
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ? AND type IN ?", model.JobStatusQueued, time.Now(), types).
+			Order("run_at, id").
+			First(&job).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		job.Status = model.JobStatusRunning
+		job.Attempts++
+		job.LockedAt = sql.NullTime{Time: now, Valid: true}
+		job.LockedBy = workerID
+		if !job.StartedAt.Valid {
+			job.StartedAt = sql.NullTime{Time: now, Valid: true}
+		}
+		return tx.Model(model.Job{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"status":     job.Status,
+			"attempts":   job.Attempts,
+			"locked_at":  job.LockedAt,
+			"locked_by":  job.LockedBy,
+			"started_at": job.StartedAt,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// lockedBy matches a running job which workerID still holds, it was neither requeued nor claimed by another worker since
+func lockedBy(db *gorm.DB, id uint, workerID string) *gorm.DB {
+	return db.Model(model.Job{}).Where("id = ? AND status = ? AND locked_by = ?", id, model.JobStatusRunning, workerID)
+}
+
+// Heartbeat refreshes the lock workerID holds on a running job, held is false when the worker lost the job
+func (r *JobRepository) Heartbeat(id uint, workerID string) (held bool, cancelRequested bool, err error) {
+	result := lockedBy(r.db, id, workerID).Update("locked_at", time.Now())
+	if result.Error != nil {
+		return false, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, false, nil
+	}
+	if err := r.db.Model(model.Job{}).Where("id = ?", id).Pluck("cancel_requested", &cancelRequested).Error; err != nil {
+		return true, false, err
+	}
+	return true, cancelRequested, nil
+}
+
+func (r *JobRepository) UpdateProgress(id uint, workerID string, progress uint, message string) error {
+	return lockedBy(r.db, id, workerID).Updates(map[string]interface{}{
+		"progress":         min(progress, 100),
+		"progress_message": message,
+	}).Error
+}
+
+// Finish stores the final status of a job workerID holds, it returns false when the worker lost the job
+func (r *JobRepository) Finish(id uint, workerID string, status model.JobStatus, lastError string) (bool, error) {
+	updates := map[string]interface{}{
+		"status":      status,
+		"last_error":  lastError,
+		"finished_at": time.Now(),
+		"locked_at":   nil,
+		"locked_by":   "",
+	}
+	if status == model.JobStatusSucceeded {
+		updates["progress"] = 100
+	}
+	result := lockedBy(r.db, id, workerID).Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *JobRepository) SaveResult(id uint, result string) error {
+	return r.db.Model(model.Job{}).Where("id = ?", id).Update("result", result).Error
+}
+
+// Retry queues a failed job workerID holds again at runAt, it returns false when the worker lost the job
+func (r *JobRepository) Retry(id uint, workerID string, runAt time.Time, lastError string) (bool, error) {
+	result := lockedBy(r.db, id, workerID).Updates(map[string]interface{}{
+		"status":     model.JobStatusQueued,
+		"run_at":     runAt,
+		"last_error": lastError,
+		"locked_at":  nil,
+		"locked_by":  "",
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RequeueStale queues running jobs whose worker stopped refreshing the lock before lockedBefore,
+// jobs which used up their attempts fail instead
+func (r *JobRepository) RequeueStale(lockedBefore time.Time) (requeued int64, failed int64, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(model.Job{}).
+			Where("status = ? AND locked_at < ? AND attempts >= max_attempts", model.JobStatusRunning, lockedBefore).
+			Updates(map[string]interface{}{
+				"status":      model.JobStatusFailed,
+				"last_error":  "worker stopped responding",
+				"finished_at": now,
+				"locked_at":   nil,
+				"locked_by":   "",
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		failed = result.RowsAffected
+
+		result = tx.Model(model.Job{}).
+			Where("status = ? AND locked_at < ?", model.JobStatusRunning, lockedBefore).
+			Updates(map[string]interface{}{
+				"status":     model.JobStatusQueued,
+				"run_at":     now,
+				"last_error": "worker stopped responding",
+				"locked_at":  nil,
+				"locked_by":  "",
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		requeued = result.RowsAffected
+		return nil
+	})
+	return requeued, failed, err
+}
+
+// RequestCancel cancels a queued job right away, running jobs are canceled by their worker.
+// It returns false when the job already finished.
+func (r *JobRepository) RequestCancel(id uint) (bool, error) {
+	result := r.db.Model(model.Job{}).Where("id = ? AND status = ?", id, model.JobStatusQueued).Updates(map[string]interface{}{
+		"status":           model.JobStatusCanceled,
+		"cancel_requested": true,
+		"finished_at":      time.Now(),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	result = r.db.Model(model.Job{}).Where("id = ? AND status = ?", id, model.JobStatusRunning).Update("cancel_requested", true)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+
+	"gorm.io/gorm"
+)
+
+type NotificationTemplateRepository struct {
+	db *gorm.DB
+}
+
+func newNotificationTemplateRepository(db *gorm.DB) *NotificationTemplateRepository {
+	return &NotificationTemplateRepository{
+		db: db,
+	}
+}
+
+// latest selects the latest version of every type and locale
+func (r *NotificationTemplateRepository) latest(query *gorm.DB) *gorm.DB {
+	sub := query.Model(model.NotificationTemplate{}).Select("DISTINCT ON (type, locale) *").Order("type, locale, version DESC")
+	return r.db.Table("(?) AS notification_templates", sub)
+}
+
+// PageLatest lists the latest version of every template
+func (r *NotificationTemplateRepository) PageLatest(req *dto.NotificationTemplateQuery) (*utils.PaginationModel[model.NotificationTemplate], error) {
+	query := r.db
+	if req.Type != "" {
+		query = query.Where("type = ?", req.Type)
+	}
+	if req.Locale != "" {
+		query = query.Where("locale = ?", req.Locale)
+	}
+
+	pagination, err := utils.CreatePage[model.NotificationTemplate](r.latest(query).Order("type, locale").Preload("CreatedBy"), int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+// FindLatest returns the latest version of the type in each of the locales which has one
+func (r *NotificationTemplateRepository) FindLatest(notificationType model.NotificationType, locales []string) ([]model.NotificationTemplate, error) {
+	var result []model.NotificationTemplate
+	if err := r.latest(r.db.Where("type = ? AND locale IN ?", notificationType, locales)).Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindVersions returns versions of the template, newest first
+func (r *NotificationTemplateRepository) FindVersions(notificationType model.NotificationType, locale string) ([]model.NotificationTemplate, error) {
+	var result []model.NotificationTemplate
+	if err := r.db.Model(model.NotificationTemplate{}).Where("type = ? AND locale = ?", notificationType, locale).
+		Order("version DESC").Preload("CreatedBy").Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *NotificationTemplateRepository) FindVersion(notificationType model.NotificationType, locale string, version uint) (*model.NotificationTemplate, error) {
+	var result model.NotificationTemplate
+	if err := r.db.Model(model.NotificationTemplate{}).Where("type = ? AND locale = ? AND version = ?", notificationType, locale, version).
+		Preload("CreatedBy").First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreateVersion saves template as the next version of its type and locale
+func (r *NotificationTemplateRepository) CreateVersion(template *model.NotificationTemplate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var version uint
+		if err := tx.Model(model.NotificationTemplate{}).Select("COALESCE(MAX(version), 0)").
+			Where("type = ? AND locale = ?", template.Type, template.Locale).Scan(&version).Error; err != nil {
+			return err
+		}
+		template.Version = version + 1
+		return tx.Omit("CreatedBy").Create(template).Error
+	})
+}
+
+// Delete removes every version of the template, it returns false when there was none
+func (r *NotificationTemplateRepository) Delete(notificationType model.NotificationType, locale string) (bool, error) {
+	result := r.db.Where("type = ? AND locale = ?", notificationType, locale).Delete(&model.NotificationTemplate{})
+	return result.RowsAffected > 0, result.Error
+}
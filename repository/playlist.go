@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"gitlab/live/be-live-admin/model"
+
+	"gorm.io/gorm"
+)
+
+type PlaylistRepository struct {
+	db *gorm.DB
+}
+
+func newPlaylistRepository(db *gorm.DB) *PlaylistRepository {
+	return &PlaylistRepository{
+		db: db,
+	}
+}
+
+func (r *PlaylistRepository) FindItems(scheduleStreamID uint) ([]model.ScheduleStreamItem, error) {
+	var result []model.ScheduleStreamItem
+	if err := r.db.Model(model.ScheduleStreamItem{}).Where("schedule_stream_id = ?", scheduleStreamID).Order("position").Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *PlaylistRepository) FindItem(scheduleStreamID, id uint) (*model.ScheduleStreamItem, error) {
+	var result model.ScheduleStreamItem
+	if err := r.db.Model(model.ScheduleStreamItem{}).Where("schedule_stream_id = ? AND id = ?", scheduleStreamID, id).First(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *PlaylistRepository) setTotalDuration(tx *gorm.DB, scheduleStreamID, totalDuration uint) error {
+	return tx.Model(model.ScheduleStream{}).Where("id = ?", scheduleStreamID).Update("video_duration", totalDuration).Error
+}
+
+// AddItem inserts item at its position, seed is the video of the stream which becomes the first item of a new playlist
+func (r *PlaylistRepository) AddItem(seed, item *model.ScheduleStreamItem, totalDuration uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if seed != nil {
+			if err := tx.Omit("ScheduleStream").Create(seed).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(model.ScheduleStreamItem{}).
+			Where("schedule_stream_id = ? AND position >= ?", item.ScheduleStreamID, item.Position).
+			Update("position", gorm.Expr("position + 1")).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("ScheduleStream").Create(item).Error; err != nil {
+			return err
+		}
+		return r.setTotalDuration(tx, item.ScheduleStreamID, totalDuration)
+	})
+}
+
+// UpdateItem saves item and moves it from oldPosition to item.Position
+func (r *PlaylistRepository) UpdateItem(item *model.ScheduleStreamItem, oldPosition, totalDuration uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(model.ScheduleStreamItem{}).Where("schedule_stream_id = ? AND id != ?", item.ScheduleStreamID, item.ID)
+		if item.Position < oldPosition {
+			query = query.Where("position >= ? AND position < ?", item.Position, oldPosition).Update("position", gorm.Expr("position + 1"))
+		} else if item.Position > oldPosition {
+			query = query.Where("position > ? AND position <= ?", oldPosition, item.Position).Update("position", gorm.Expr("position - 1"))
+		}
+		if err := query.Error; err != nil {
+			return err
+		}
+		if err := tx.Model(item).Select("position", "in_point", "out_point", "duration").Updates(item).Error; err != nil {
+			return err
+		}
+		return r.setTotalDuration(tx, item.ScheduleStreamID, totalDuration)
+	})
+}
+
+func (r *PlaylistRepository) DeleteItem(item *model.ScheduleStreamItem, totalDuration uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.ScheduleStreamItem{}, item.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(model.ScheduleStreamItem{}).
+			Where("schedule_stream_id = ? AND position > ?", item.ScheduleStreamID, item.Position).
+			Update("position", gorm.Expr("position - 1")).Error; err != nil {
+			return err
+		}
+		return r.setTotalDuration(tx, item.ScheduleStreamID, totalDuration)
+	})
+}
+
+// ReplaceFile points items playing the replaced video of a stream to the new one
+func (r *PlaylistRepository) ReplaceFile(scheduleStreamID uint, oldFileName, newFileName string, duration uint) error {
+	return r.db.Model(model.ScheduleStreamItem{}).
+		Where("schedule_stream_id = ? AND file_name = ?", scheduleStreamID, oldFileName).
+		Updates(map[string]interface{}{"file_name": newFileName, "duration": duration, "in_point": 0, "out_point": 0}).Error
+}
+
+func (r *PlaylistRepository) SetTotalDuration(scheduleStreamID, totalDuration uint) error {
+	return r.setTotalDuration(r.db, scheduleStreamID, totalDuration)
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"errors"
+	"gitlab/live/be-live-admin/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PublishBanRepository struct {
+	db *gorm.DB
+}
+
+func newPublishBanRepository(db *gorm.DB) *PublishBanRepository {
+	return &PublishBanRepository{
+		db: db,
+	}
+}
+
+func (r *PublishBanRepository) active(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.Where("lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", now)
+}
+
+// Replace lifts the active ban of the user, if any, and creates ban
+func (r *PublishBanRepository) Replace(ban *model.PublishBan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.active(tx.Model(&model.PublishBan{}), ban.CreatedAt).Where("user_id = ?", ban.UserID).Updates(map[string]interface{}{
+			"lifted_at":    ban.CreatedAt,
+			"lifted_by_id": ban.CreatedByID,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Omit("User", "CreatedBy", "LiftedBy").Create(ban).Error
+	})
+}
+
+// FindActive returns the ban stopping the user from going live now, nil when there's none
+func (r *PublishBanRepository) FindActive(userID uint, now time.Time) (*model.PublishBan, error) {
+	var result model.PublishBan
+	if err := r.active(r.db.Model(model.PublishBan{}), now).Preload("CreatedBy").
+		Where("user_id = ?", userID).Order("created_at DESC").First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *PublishBanRepository) FindAllActive(now time.Time) ([]model.PublishBan, error) {
+	var result []model.PublishBan
+	if err := r.active(r.db.Model(model.PublishBan{}), now).Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Lift returns false when the user isn't banned
+func (r *PublishBanRepository) Lift(userID, liftedByID uint, now time.Time) (bool, error) {
+	result := r.active(r.db.Model(&model.PublishBan{}), now).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"lifted_at":    now,
+		"lifted_by_id": liftedByID,
+	})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurrenceRepository struct {
+	db *gorm.DB
+}
+
+func newRecurrenceRepository(db *gorm.DB) *RecurrenceRepository {
+	return &RecurrenceRepository{
+		db: db,
+	}
+}
+
+func (r *RecurrenceRepository) findCategories(categoryIDs []uint) ([]model.Category, error) {
+	var categories []model.Category
+	if err := r.db.Model(&model.Category{}).Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	for _, categoryID := range categoryIDs {
+		if !slices.ContainsFunc(categories, func(c model.Category) bool { return c.ID == categoryID }) {
+			return nil, fmt.Errorf("category id %d does not exist", categoryID)
+		}
+	}
+	return categories, nil
+}
+
+func (r *RecurrenceRepository) addExceptions(tx *gorm.DB, recurrenceID uint, exdates []time.Time) error {
+	if len(exdates) == 0 {
+		return nil
+	}
+	exceptions := utils.Map(exdates, func(e time.Time) model.StreamRecurrenceException {
+		return model.StreamRecurrenceException{RecurrenceID: recurrenceID, OccurrenceAt: e}
+	})
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("Recurrence").Create(&exceptions).Error
+}
+
+func (r *RecurrenceRepository) Create(recurrence *model.StreamRecurrence, categoryIDs []uint, exdates []time.Time) error {
+	categories, err := r.findCategories(categoryIDs)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		recurrence.Categories = categories
+		if err := tx.Omit("Categories.*", "Exceptions").Create(recurrence).Error; err != nil {
+			return err
+		}
+		return r.addExceptions(tx, recurrence.ID, exdates)
+	})
+}
+
+// Update saves the series and adds exdates to existing exceptions
+func (r *RecurrenceRepository) Update(recurrence *model.StreamRecurrence, categoryIDs []uint, exdates []time.Time) error {
+	categories, err := r.findCategories(categoryIDs)
+	if err != nil {
+		return err
+	}
+
+	recurrence.Categories = categories
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(recurrence).Select("title", "description", "rrule", "timezone", "start_at", "materialized_until", "updated_by_id").Updates(recurrence).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(recurrence).Omit("Categories.*").Association("Categories").Replace(categories); err != nil {
+			return err
+		}
+		return r.addExceptions(tx, recurrence.ID, exdates)
+	})
+}
+
+func (r *RecurrenceRepository) FindByID(id uint) (*model.StreamRecurrence, error) {
+	var recurrence model.StreamRecurrence
+	if err := r.db.Preload("Categories").Preload("Exceptions").Preload("User").Where("id = ?", id).First(&recurrence).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &recurrence, nil
+}
+
+func (r *RecurrenceRepository) Page(req *dto.StreamRecurrenceQuery) (*utils.PaginationModel[model.StreamRecurrence], error) {
+	query := r.db.Model(model.StreamRecurrence{})
+	if req.UserID != 0 {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+	query = query.Order("created_at DESC").Preload("Categories").Preload("Exceptions").Preload("User")
+
+	pagination, err := utils.CreatePage[model.StreamRecurrence](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+// FindDue returns series which are not materialized up to horizon
+func (r *RecurrenceRepository) FindDue(horizon time.Time) ([]model.StreamRecurrence, error) {
+	var result []model.StreamRecurrence
+	if err := r.db.Preload("Categories").Preload("Exceptions").
+		Where("materialized_until IS NULL OR materialized_until < ?", horizon).
+		Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateOccurrence creates the upcoming stream of an occurrence, false when it already exists
+func (r *RecurrenceRepository) CreateOccurrence(recurrence *model.StreamRecurrence, occurrenceAt time.Time, streamKey string) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(model.ScheduleStream{}).Where("recurrence_id = ? AND occurrence_at = ?", recurrence.ID, occurrenceAt).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		stream := &model.Stream{
+			UserID:            recurrence.UserID,
+			Title:             recurrence.Title,
+			Description:       recurrence.Description,
+			Status:            model.UPCOMING,
+			StreamKey:         streamKey,
+			StreamType:        model.PRERECORDSTREAM,
+			ThumbnailFileName: recurrence.ThumbnailFileName,
+		}
+		if err := tx.Create(stream).Error; err != nil {
+			return err
+		}
+
+		for _, category := range recurrence.Categories {
+			if err := tx.Create(&model.StreamCategory{StreamID: stream.ID, CategoryID: category.ID}).Error; err != nil {
+				return err
+			}
+		}
+
+		scheduleStream := &model.ScheduleStream{
+			StreamID:      stream.ID,
+			ScheduledAt:   occurrenceAt,
+			VideoName:     recurrence.VideoName,
+			VideoDuration: recurrence.VideoDuration,
+			RecurrenceID:  &recurrence.ID,
+			OccurrenceAt:  sql.NullTime{Time: occurrenceAt, Valid: true},
+		}
+		if err := tx.Omit("Stream", "Recurrence").Create(scheduleStream).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *RecurrenceRepository) SetMaterializedUntil(id uint, until time.Time) error {
+	return r.db.Model(model.StreamRecurrence{}).Where("id = ?", id).Update("materialized_until", until).Error
+}
+
+// GetUpcomingOccurrences returns upcoming occurrences from `from` which were not edited alone
+func (r *RecurrenceRepository) GetUpcomingOccurrences(recurrenceID uint, from time.Time) ([]model.ScheduleStream, error) {
+	var result []model.ScheduleStream
+	if err := r.db.Model(model.ScheduleStream{}).
+		Joins("INNER JOIN streams ON streams.id = schedule_streams.stream_id").
+		Where("schedule_streams.recurrence_id = ? AND schedule_streams.is_detached = ?", recurrenceID, false).
+		Where("schedule_streams.scheduled_at >= ? AND streams.status = ?", from, model.UPCOMING).
+		Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *RecurrenceRepository) DeleteOccurrences(streamIDs []uint) error {
+	if len(streamIDs) == 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, related := range []interface{}{&model.View{}, &model.Like{}, &model.Comment{}, &model.StreamCategory{}, &model.Notification{}, &model.Share{}, &model.ScheduleStream{}} {
+			if err := tx.Unscoped().Where("stream_id IN ?", streamIDs).Delete(related).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Where("id IN ?", streamIDs).Delete(&model.Stream{}).Error
+	})
+}
+
+// UpdateOccurrences copies title, description and categories of the series to its occurrences
+func (r *RecurrenceRepository) UpdateOccurrences(streamIDs []uint, recurrence *model.StreamRecurrence) error {
+	if len(streamIDs) == 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(model.Stream{}).Where("id IN ?", streamIDs).Updates(map[string]interface{}{
+			"title":       recurrence.Title,
+			"description": recurrence.Description,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("stream_id IN ?", streamIDs).Delete(&model.StreamCategory{}).Error; err != nil {
+			return err
+		}
+		for _, streamID := range streamIDs {
+			for _, category := range recurrence.Categories {
+				if err := tx.Create(&model.StreamCategory{StreamID: streamID, CategoryID: category.ID}).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// DetachOccurrence marks an occurrence as edited alone, nothing happens for streams without series
+func (r *RecurrenceRepository) DetachOccurrence(streamID uint) error {
+	return r.db.Model(model.ScheduleStream{}).Where("stream_id = ? AND recurrence_id IS NOT NULL", streamID).Update("is_detached", true).Error
+}
+
+func (r *RecurrenceRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.StreamRecurrence{ID: id}).Association("Categories").Clear(); err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.StreamRecurrence{}).Error
+	})
+}
+
+func (r *RecurrenceRepository) AddException(recurrenceID uint, occurrenceAt time.Time) error {
+	return r.addExceptions(r.db, recurrenceID, []time.Time{occurrenceAt})
+}
+
+func (r *RecurrenceRepository) RemoveException(recurrenceID uint, occurrenceAt time.Time) error {
+	return r.db.Where("recurrence_id = ? AND occurrence_at = ?", recurrenceID, occurrenceAt).Delete(&model.StreamRecurrenceException{}).Error
+}
+
+// IsFileShared reports whether a thumbnail or video is used by a series or by a stream other than streamID
+func (r *RecurrenceRepository) IsFileShared(fileName string, streamID uint) (bool, error) {
+	var recurrences, streams, scheduleStreams int64
+	if err := r.db.Model(model.StreamRecurrence{}).Where("thumbnail_file_name = ? OR video_name = ?", fileName, fileName).Count(&recurrences).Error; err != nil {
+		return false, err
+	}
+	if err := r.db.Unscoped().Model(model.Stream{}).Where("thumbnail_file_name = ? AND id != ?", fileName, streamID).Count(&streams).Error; err != nil {
+		return false, err
+	}
+	if err := r.db.Model(model.ScheduleStream{}).Where("video_name = ? AND stream_id != ?", fileName, streamID).Count(&scheduleStreams).Error; err != nil {
+		return false, err
+	}
+	return recurrences+streams+scheduleStreams > 0, nil
+}
+
+// CountStreamsUsingFiles counts streams which still reference shared thumbnail and video of a series
+func (r *RecurrenceRepository) CountStreamsUsingFiles(thumbnailFileName, videoName string) (int64, int64, error) {
+	var thumbnails, videos int64
+	if err := r.db.Unscoped().Model(model.Stream{}).Where("thumbnail_file_name = ?", thumbnailFileName).Count(&thumbnails).Error; err != nil {
+		return 0, 0, err
+	}
+	if err := r.db.Model(model.ScheduleStream{}).Where("video_name = ?", videoName).Count(&videos).Error; err != nil {
+		return 0, 0, err
+	}
+	return thumbnails, videos, nil
+}
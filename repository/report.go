@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// REPORT_PRIORITY_MAX_BUMP caps how much other reports of a target raise priority, so it stays under the next severity
+const REPORT_PRIORITY_MAX_BUMP = 9
+
+var reportSeverityPriorities = map[model.ReportSeverity]int{
+	model.ReportSeverityHigh:   30,
+	model.ReportSeverityMedium: 20,
+	model.ReportSeverityLow:    10,
+}
+
+var reportUnresolved = []model.ReportStatus{model.ReportStatusOpen, model.ReportStatusClaimed}
+
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func newReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{
+		db: db,
+	}
+}
+
+func (r *ReportRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.Preload("Reporter").Preload("TargetUser").Preload("Assignee").Preload("ResolvedBy")
+}
+
+func (r *ReportRepository) Create(report *model.Report) error {
+	return r.db.Omit("Reporter", "TargetUser", "Assignee", "ResolvedBy").Create(report).Error
+}
+
+func (r *ReportRepository) FindByID(id uint) (*model.Report, error) {
+	var result model.Report
+	if err := r.preload(r.db.Model(model.Report{})).Where("id = ?", id).First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *ReportRepository) Page(req *dto.ReportQuery) (*utils.PaginationModel[model.Report], error) {
+	query := r.db.Model(model.Report{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.TargetType != "" {
+		query = query.Where("target_type = ?", req.TargetType)
+	}
+	if req.TargetID != 0 {
+		query = query.Where("target_id = ?", req.TargetID)
+	}
+	if req.Reason != "" {
+		query = query.Where("reason = ?", req.Reason)
+	}
+	if req.Severity != "" {
+		query = query.Where("severity = ?", req.Severity)
+	}
+	if req.AssigneeID != 0 {
+		query = query.Where("assignee_id = ?", req.AssigneeID)
+	}
+	if req.Unassigned {
+		query = query.Where("assignee_id IS NULL")
+	}
+	if req.Overdue {
+		query = query.Where("status IN ? AND due_at < ?", reportUnresolved, time.Now())
+	}
+	query = r.preload(query.Order("priority DESC, due_at, id"))
+
+	pagination, err := utils.CreatePage[model.Report](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+// HasUnresolved tells whether the reporter already has an unresolved report of the target
+func (r *ReportRepository) HasUnresolved(reporterID uint, targetType model.ReportTargetType, targetID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(model.Report{}).
+		Where("reporter_id = ? AND target_type = ? AND target_id = ? AND status IN ?", reporterID, targetType, targetID, reportUnresolved).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Reprioritize sets the priority of unresolved reports of a target from their severity and how many there are
+func (r *ReportRepository) Reprioritize(targetType model.ReportTargetType, targetID uint) error {
+	query := r.db.Model(model.Report{}).Where("target_type = ? AND target_id = ? AND status IN ?", targetType, targetID, reportUnresolved)
+	var count int64
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+	bump := min(int(count)-1, REPORT_PRIORITY_MAX_BUMP)
+	return query.Update("priority", gorm.Expr("CASE severity WHEN ? THEN ? WHEN ? THEN ? ELSE ? END + ?",
+		model.ReportSeverityHigh, reportSeverityPriorities[model.ReportSeverityHigh],
+		model.ReportSeverityMedium, reportSeverityPriorities[model.ReportSeverityMedium],
+		reportSeverityPriorities[model.ReportSeverityLow], bump)).Error
+}
+
+// Claim returns false when the report isn't open
+func (r *ReportRepository) Claim(id, assigneeID uint) (bool, error) {
+	result := r.db.Model(model.Report{}).Where("id = ? AND status = ?", id, model.ReportStatusOpen).Updates(map[string]interface{}{
+		"status":      model.ReportStatusClaimed,
+		"assignee_id": assigneeID,
+		"assigned_at": time.Now(),
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Assign claims the report for the assignee, it returns false when the report is closed
+func (r *ReportRepository) Assign(id, assigneeID uint) (bool, error) {
+	result := r.db.Model(model.Report{}).Where("id = ? AND status IN ?", id, reportUnresolved).Updates(map[string]interface{}{
+		"status":      model.ReportStatusClaimed,
+		"assignee_id": assigneeID,
+		"assigned_at": time.Now(),
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Release puts a claimed report back in the queue, it returns false when the report isn't claimed
+func (r *ReportRepository) Release(id uint) (bool, error) {
+	result := r.db.Model(model.Report{}).Where("id = ? AND status = ?", id, model.ReportStatusClaimed).Updates(map[string]interface{}{
+		"status":      model.ReportStatusOpen,
+		"assignee_id": nil,
+		"assigned_at": nil,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ReportRepository) closeUpdates(status model.ReportStatus, action model.ReportAction, note string, resolvedByID uint) map[string]interface{} {
+	return map[string]interface{}{
+		"status":         status,
+		"action":         action,
+		"note":           note,
+		"resolved_by_id": resolvedByID,
+		"resolved_at":    time.Now(),
+	}
+}
+
+// ResolveTarget resolves every unresolved report of the target of report, it returns how many were resolved
+func (r *ReportRepository) ResolveTarget(report *model.Report, action model.ReportAction, note string, resolvedByID uint) (int64, error) {
+	result := r.db.Model(model.Report{}).
+		Where("target_type = ? AND target_id = ? AND status IN ?", report.TargetType, report.TargetID, reportUnresolved).
+		Updates(r.closeUpdates(model.ReportStatusResolved, action, note, resolvedByID))
+	return result.RowsAffected, result.Error
+}
+
+// Dismiss returns false when the report is closed already
+func (r *ReportRepository) Dismiss(id uint, note string, resolvedByID uint) (bool, error) {
+	result := r.db.Model(model.Report{}).Where("id = ? AND status IN ?", id, reportUnresolved).
+		Updates(r.closeUpdates(model.ReportStatusDismissed, model.ReportActionNone, note, resolvedByID))
+	return result.RowsAffected > 0, result.Error
+}
+
+type ReportQueueCounts struct {
+	Open    int64
+	Claimed int64
+	Overdue int64
+}
+
+func (r *ReportRepository) QueueCounts(now time.Time) (*ReportQueueCounts, error) {
+	var result ReportQueueCounts
+	if err := r.db.Model(model.Report{}).
+		Select("COUNT(*) FILTER (WHERE status = ?) AS open, COUNT(*) FILTER (WHERE status = ?) AS claimed, COUNT(*) FILTER (WHERE due_at < ?) AS overdue",
+			model.ReportStatusOpen, model.ReportStatusClaimed, now).
+		Where("status IN ?", reportUnresolved).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type ReportModeratorStatsRow struct {
+	ModeratorID          uint
+	Resolved             int64
+	Dismissed            int64
+	Breached             int64
+	AvgResolutionSeconds float64
+}
+
+// ModeratorStats counts reports closed in [from, to) per moderator
+func (r *ReportRepository) ModeratorStats(from, to time.Time) ([]ReportModeratorStatsRow, error) {
+	var result []ReportModeratorStatsRow
+	if err := r.db.Model(model.Report{}).
+		Select("resolved_by_id AS moderator_id, COUNT(*) FILTER (WHERE status = ?) AS resolved, COUNT(*) FILTER (WHERE status = ?) AS dismissed, "+
+			"COUNT(*) FILTER (WHERE resolved_at > due_at) AS breached, AVG(EXTRACT(EPOCH FROM (resolved_at - created_at))) AS avg_resolution_seconds",
+			model.ReportStatusResolved, model.ReportStatusDismissed).
+		Where("resolved_by_id IS NOT NULL AND resolved_at >= ? AND resolved_at < ?", from, to).
+		Group("resolved_by_id").
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ClaimedCounts returns how many reports each moderator has claimed now
+func (r *ReportRepository) ClaimedCounts() (map[uint]int64, error) {
+	var rows []struct {
+		AssigneeID uint
+		Count      int64
+	}
+	if err := r.db.Model(model.Report{}).Select("assignee_id, COUNT(*) AS count").
+		Where("status = ? AND assignee_id IS NOT NULL", model.ReportStatusClaimed).
+		Group("assignee_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		result[row.AssigneeID] = row.Count
+	}
+	return result, nil
+}
+
+// FindUsers finds moderators of stats, deleted ones included
+func (r *ReportRepository) FindUsers(ids []uint) ([]model.User, error) {
+	var result []model.User
+	if err := r.db.Unscoped().Model(model.User{}).Where("id IN ?", ids).Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
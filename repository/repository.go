@@ -2,12 +2,44 @@ package repository
 
 import "gorm.io/gorm"
 
+const (
+	// SCHEDULE_LOCK_ID is the postgres advisory lock held by schedule changes
+	SCHEDULE_LOCK_ID = 3201
+	// USER_NAMES_LOCK_ID is the postgres advisory lock held while usernames and emails of users and invites are taken
+	USER_NAMES_LOCK_ID = 3202
+)
+
 type Repository struct {
-	User     *UserRepository
-	Admin    *AdminRepository
-	Role     *RoleRepository
-	Stream   *StreamRepository
-	Category *CategoryRepository
+	db *gorm.DB
+
+	User          *UserRepository
+	Admin         *AdminRepository
+	Role          *RoleRepository
+	Stream        *StreamRepository
+	Category      *CategoryRepository
+	File          *FileRepository
+	Storage       *StorageRepository
+	Retention     *RetentionRepository
+	Recurrence    *RecurrenceRepository
+	Calendar      *CalendarRepository
+	Playlist      *PlaylistRepository
+	Clip          *ClipRepository
+	Job           *JobRepository
+	Trash         *TrashRepository
+	GDPR          *GDPRRepository
+	Invite        *InviteRepository
+	UserImport    *UserImportRepository
+	Comment       *CommentRepository
+	CommentFilter *CommentFilterRepository
+	Report        *ReportRepository
+	Strike        *StrikeRepository
+	PublishBan    *PublishBanRepository
+	Block         *BlockRepository
+	Subscription  *SubscriptionRepository
+	Segment       *SegmentRepository
+	Announcement  *AnnouncementRepository
+
+	NotificationTemplate *NotificationTemplateRepository
 }
 
 func NewRepository(db *gorm.DB) *Repository {
@@ -16,11 +48,86 @@ func NewRepository(db *gorm.DB) *Repository {
 	roleRepo := NewRoleRepository(db)
 	streamRepo := newStreamRepository(db)
 	categoryRepo := newCategoryRepository(db)
+	fileRepo := newFileRepository(db)
+	storageRepo := newStorageRepository(db)
+	retentionRepo := newRetentionRepository(db)
+	recurrenceRepo := newRecurrenceRepository(db)
+	calendarRepo := newCalendarRepository(db)
+	playlistRepo := newPlaylistRepository(db)
+	clipRepo := newClipRepository(db)
+	jobRepo := newJobRepository(db)
+	trashRepo := newTrashRepository(db)
+	gdprRepo := newGDPRRepository(db)
+	inviteRepo := newInviteRepository(db)
+	userImportRepo := newUserImportRepository(db)
+	commentRepo := newCommentRepository(db)
+	commentFilterRepo := newCommentFilterRepository(db)
+	reportRepo := newReportRepository(db)
+	strikeRepo := newStrikeRepository(db)
+	publishBanRepo := newPublishBanRepository(db)
+	blockRepo := newBlockRepository(db)
+	subscriptionRepo := newSubscriptionRepository(db)
+	segmentRepo := newSegmentRepository(db)
+	announcementRepo := newAnnouncementRepository(db)
+	notificationTemplateRepo := newNotificationTemplateRepository(db)
 	return &Repository{
-		Admin:    adminRepo,
-		User:     userRepo,
-		Role:     roleRepo,
-		Stream:   streamRepo,
-		Category: categoryRepo,
+		db: db,
+
+		Admin:         adminRepo,
+		User:          userRepo,
+		Role:          roleRepo,
+		Stream:        streamRepo,
+		Category:      categoryRepo,
+		File:          fileRepo,
+		Storage:       storageRepo,
+		Retention:     retentionRepo,
+		Recurrence:    recurrenceRepo,
+		Calendar:      calendarRepo,
+		Playlist:      playlistRepo,
+		Clip:          clipRepo,
+		Job:           jobRepo,
+		Trash:         trashRepo,
+		GDPR:          gdprRepo,
+		Invite:        inviteRepo,
+		UserImport:    userImportRepo,
+		Comment:       commentRepo,
+		CommentFilter: commentFilterRepo,
+		Report:        reportRepo,
+		Strike:        strikeRepo,
+		PublishBan:    publishBanRepo,
+		Block:         blockRepo,
+		Subscription:  subscriptionRepo,
+		Segment:       segmentRepo,
+		Announcement:  announcementRepo,
+
+		NotificationTemplate: notificationTemplateRepo,
 	}
 }
+
+// Transaction runs fn with a repository whose writes are committed together
+func (r *Repository) Transaction(fn func(repo *Repository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepository(tx))
+	})
+}
+
+// WithScheduleLock runs fn with a repository of one transaction holding the schedule lock,
+// so conflict checks and the writes they allow don't interleave with other schedule changes
+func (r *Repository) WithScheduleLock(fn func(repo *Repository) error) error {
+	return r.withLock(SCHEDULE_LOCK_ID, fn)
+}
+
+// WithUserNamesLock runs fn with a repository of one transaction holding the user names lock. Users and invites are
+// separate tables, so their unique indexes can't stop a user and an invite from taking the same username or email.
+func (r *Repository) WithUserNamesLock(fn func(repo *Repository) error) error {
+	return r.withLock(USER_NAMES_LOCK_ID, fn)
+}
+
+func (r *Repository) withLock(id int, fn func(repo *Repository) error) error {
+	return r.Transaction(func(repo *Repository) error {
+		if err := repo.db.Exec("SELECT pg_advisory_xact_lock(?)", id).Error; err != nil {
+			return err
+		}
+		return fn(repo)
+	})
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+
+	"gorm.io/gorm"
+)
+
+type RetentionRepository struct {
+	db *gorm.DB
+}
+
+func newRetentionRepository(db *gorm.DB) *RetentionRepository {
+	return &RetentionRepository{
+		db: db,
+	}
+}
+
+func (r *RetentionRepository) FindAll() ([]model.RetentionPolicy, error) {
+	var result []model.RetentionPolicy
+	if err := r.db.Model(model.RetentionPolicy{}).Preload("Category").Order("id").Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *RetentionRepository) FindByID(id uint) (*model.RetentionPolicy, error) {
+	var policy model.RetentionPolicy
+	if err := r.db.Model(model.RetentionPolicy{}).Preload("Category").Where("id = ?", id).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindByCategoryID finds the global policy when categoryID is nil
+func (r *RetentionRepository) FindByCategoryID(categoryID *uint) (*model.RetentionPolicy, error) {
+	query := r.db.Model(model.RetentionPolicy{})
+	if categoryID == nil {
+		query = query.Where("category_id IS NULL")
+	} else {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+
+	var policy model.RetentionPolicy
+	if err := query.First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *RetentionRepository) Create(policy *model.RetentionPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+func (r *RetentionRepository) Update(policy *model.RetentionPolicy) error {
+	return r.db.Model(policy).Select("category_id", "delete_raw_after_encoding", "delete_video_after_days", "updated_by_id").Updates(policy).Error
+}
+
+func (r *RetentionRepository) Delete(id uint) error {
+	return r.db.Where("id = ?", id).Delete(&model.RetentionPolicy{}).Error
+}
+
+// GetEndedStreams returns ended streams which are not under legal hold
+func (r *RetentionRepository) GetEndedStreams() ([]dto.RetentionStreamDTO, error) {
+	var result []dto.RetentionStreamDTO
+	if err := r.db.Model(model.Stream{}).
+		Select("id, user_id, stream_key, is_pinned, ended_at").
+		Where("status = ? AND legal_hold = ? AND ended_at IS NOT NULL", model.ENDED, false).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *RetentionRepository) GetStreamCategoryIDs() (map[uint][]uint, error) {
+	var rows []model.StreamCategory
+	if err := r.db.Model(model.StreamCategory{}).Select("stream_id, category_id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint][]uint)
+	for _, row := range rows {
+		result[row.StreamID] = append(result[row.StreamID], row.CategoryID)
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+
+	"gorm.io/gorm"
+)
+
+type SegmentRepository struct {
+	db *gorm.DB
+}
+
+func newSegmentRepository(db *gorm.DB) *SegmentRepository {
+	return &SegmentRepository{
+		db: db,
+	}
+}
+
+func (r *SegmentRepository) Page(req *dto.UserSegmentQuery) (*utils.PaginationModel[model.UserSegment], error) {
+	query := r.db.Model(model.UserSegment{})
+	if req.Keyword != "" {
+		query = query.Where("name ILIKE ?", "%"+req.Keyword+"%")
+	}
+	query = query.Order("name").Preload("CreatedBy")
+
+	pagination, err := utils.CreatePage[model.UserSegment](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+func (r *SegmentRepository) FindByID(id uint) (*model.UserSegment, error) {
+	var result model.UserSegment
+	if err := r.db.Model(model.UserSegment{}).Where("id = ?", id).Preload("CreatedBy").First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *SegmentRepository) FindByName(name string) (*model.UserSegment, error) {
+	var result model.UserSegment
+	if err := r.db.Model(model.UserSegment{}).Where("name = ?", name).First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *SegmentRepository) Create(segment *model.UserSegment) error {
+	return r.db.Omit("CreatedBy").Create(segment).Error
+}
+
+func (r *SegmentRepository) Update(segment *model.UserSegment) error {
+	return r.db.Model(&model.UserSegment{}).Where("id = ?", segment.ID).Updates(map[string]interface{}{
+		"name":   segment.Name,
+		"filter": segment.Filter,
+	}).Error
+}
+
+func (r *SegmentRepository) Delete(id uint) error {
+	return r.db.Delete(&model.UserSegment{}, id).Error
+}
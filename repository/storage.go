@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StorageRepository struct {
+	db *gorm.DB
+}
+
+func newStorageRepository(db *gorm.DB) *StorageRepository {
+	return &StorageRepository{
+		db: db,
+	}
+}
+
+var storageColumns = map[model.StorageFileType]string{
+	model.StorageFileTypeThumbnail:      "thumbnail_bytes",
+	model.StorageFileTypeScheduledVideo: "scheduled_video_bytes",
+	model.StorageFileTypeRecording:      "recording_bytes",
+}
+
+const storageTotalColumn = "(storage_usages.thumbnail_bytes + storage_usages.scheduled_video_bytes + storage_usages.recording_bytes)"
+
+// AddUsage adds delta bytes (negative when deleting) to the user's usage, it never goes below zero
+func (r *StorageRepository) AddUsage(userID uint, fileType model.StorageFileType, delta int64) error {
+	column, ok := storageColumns[fileType]
+	if !ok {
+		return fmt.Errorf("unknown storage file type %s", fileType)
+	}
+
+	usage := map[string]interface{}{"user_id": userID, column: max(delta, 0)}
+	return r.db.Model(model.StorageUsage{}).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			column:       gorm.Expr(fmt.Sprintf("GREATEST(storage_usages.%s + ?, 0)", column), delta),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}),
+	}).Create(usage).Error
+}
+
+func (r *StorageRepository) GetByUserID(userID uint) (*model.StorageUsage, error) {
+	var usage model.StorageUsage
+	if err := r.db.Where("user_id = ?", userID).Preload("User.Role").First(&usage).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func (r *StorageRepository) Page(req *dto.StorageUsageQuery) (*utils.PaginationModel[model.StorageUsage], error) {
+	query := r.db.Model(model.StorageUsage{}).Joins("INNER JOIN users ON users.id = storage_usages.user_id").Joins("INNER JOIN roles ON roles.id = users.role_id")
+	if req.RoleType != "" {
+		query = query.Where("roles.type = ?", req.RoleType)
+	}
+	if req.Keyword != "" {
+		query = query.Where("users.username ILIKE ? OR users.display_name ILIKE ?", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
+
+	sort := dto.SORT_DESC
+	if req.Sort != "" {
+		sort = req.Sort
+	}
+	if req.SortBy == "" || req.SortBy == "total_bytes" {
+		query = query.Order(fmt.Sprintf("%s %s", storageTotalColumn, sort))
+	} else {
+		query = query.Order(fmt.Sprintf("storage_usages.%s %s", req.SortBy, sort))
+	}
+
+	query = query.Preload("User.Role")
+	pagination, err := utils.CreatePage[model.StorageUsage](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+func (r *StorageRepository) GetStreamFiles() ([]dto.StreamFilesDTO, error) {
+	var result []dto.StreamFilesDTO
+	if err := r.db.Unscoped().Model(model.Stream{}).
+		Select("streams.user_id, streams.stream_key, streams.thumbnail_file_name, schedule_streams.video_name").
+		Joins("LEFT JOIN schedule_streams ON schedule_streams.stream_id = streams.id").
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *StorageRepository) GetPlaylistFiles() ([]dto.PlaylistFilesDTO, error) {
+	var result []dto.PlaylistFilesDTO
+	if err := r.db.Model(model.ScheduleStreamItem{}).
+		Select("streams.user_id, schedule_stream_items.file_name").
+		Joins("INNER JOIN schedule_streams ON schedule_streams.id = schedule_stream_items.schedule_stream_id").
+		Joins("INNER JOIN streams ON streams.id = schedule_streams.stream_id").
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *StorageRepository) GetClipFiles() ([]dto.ClipFilesDTO, error) {
+	var result []dto.ClipFilesDTO
+	if err := r.db.Model(model.Clip{}).
+		Select("streams.user_id, clips.file_name").
+		Joins("INNER JOIN streams ON streams.id = clips.stream_id").
+		Where("clips.status = ?", model.ClipStatusReady).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReplaceAll overwrites every usage row, used after recalculating from disk
+func (r *StorageRepository) ReplaceAll(usages []model.StorageUsage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.StorageUsage{}).Error; err != nil {
+			return err
+		}
+		if len(usages) == 0 {
+			return nil
+		}
+		return tx.Omit("User").Create(&usages).Error
+	})
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"gitlab/live/be-live-admin/dto"
 	"gitlab/live/be-live-admin/model"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StreamRepository struct {
@@ -31,6 +33,14 @@ func (s *StreamRepository) GetStartedStreams() ([]model.Stream, error) {
 	return streams, nil
 }
 
+func (s *StreamRepository) GetStartedStreamsByUserID(userID uint) ([]model.Stream, error) {
+	var streams []model.Stream
+	if err := s.db.Model(model.Stream{}).Where("user_id = ? AND status = ?", userID, model.STARTED).Find(&streams).Error; err != nil {
+		return nil, err
+	}
+	return streams, nil
+}
+
 func (r *StreamRepository) GetLikesByStreamID(id uint, startedDate, endedDate time.Time) ([]dto.BaseDTO, error) {
 	var data []dto.BaseDTO
 	if err := r.db.Model(model.Like{}).Select("likes.id, likes.created_at").Where("stream_id = ? AND created_at BETWEEN ? AND ?", id, startedDate, endedDate).Scan(&data).Error; err != nil {
@@ -81,7 +91,7 @@ func (s *StreamRepository) PaginateStreamStatisticsData(cond *dto.StatisticsQuer
 		}
 	}
 
-	query = query.Where("st.status = ?", model.ENDED)
+	query = query.Where("st.status = ? AND st.deleted_at IS NULL", model.ENDED)
 
 	pagination, err := utils.CreatePage[model.StreamAnalytics](query, int(cond.Page), int(cond.Limit))
 	if err != nil {
@@ -113,7 +123,7 @@ func (s *StreamRepository) PaginateLiveStatData(cond *dto.LiveStatQuery) (*utils
 
 		}
 	}
-	query = query.Where("st.status = ?", model.STARTED)
+	query = query.Where("st.status = ? AND st.deleted_at IS NULL", model.STARTED)
 	query = query.Preload("Stream")
 	pagination, err := utils.CreatePage[model.StreamAnalytics](query, int(cond.Page), int(cond.Limit))
 	if err != nil {
@@ -146,37 +156,57 @@ func (s *StreamRepository) GetStreamAnalyticByStream(streamId int) (*model.Strea
 	return &result, nil
 }
 
+// GetStreamAnalyticsByStreams returns analytics of streamIDs, streams without analytics are missing
+func (s *StreamRepository) GetStreamAnalyticsByStreams(streamIDs []uint) ([]model.StreamAnalytics, error) {
+	var result []model.StreamAnalytics
+	if len(streamIDs) == 0 {
+		return result, nil
+	}
+	if err := s.db.Model(model.StreamAnalytics{}).Where("stream_id IN ?", streamIDs).Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *StreamRepository) filterLiveStreamBroadCastQuery(query *gorm.DB, cond *dto.LiveStreamBroadCastQueryDTO) *gorm.DB {
+	if cond == nil {
+		return query
+	}
+
+	if cond.Category != "" {
+		query = query.Joins("LEFT JOIN stream_categories stc ON streams.id = stc.stream_id").Joins("LEFT JOIN categories c ON stc.category_id = c.id").
+			Where("c.name = ?", cond.Category)
+	}
+
+	if cond.Keyword != "" {
+		query = query.Where("streams.title ILIKE ? OR streams.description ILIKE ?", "%"+cond.Keyword+"%", "%"+cond.Keyword+"%")
+	}
+	if len(cond.Status) > 0 {
+		query = query.Where("streams.status IN ?", cond.Status)
+	}
+	if cond.Type != "" {
+		query = query.Where("streams.stream_type = ?", cond.Type)
+	}
+	if cond.FromStartedTime != 0 && cond.EndStartedTime != 0 {
+		from := time.Unix(cond.FromStartedTime, 0).Format(utils.DATETIME_LAYOUT)
+		end := time.Unix(cond.EndStartedTime, 0).Format(utils.DATETIME_LAYOUT)
+		query = query.Where("streams.started_at BETWEEN ? AND ?", from, end)
+	}
+	if cond.FromEndedTime != 0 && cond.EndEndedTime != 0 {
+		from := time.Unix(cond.FromEndedTime, 0).Format(utils.DATETIME_LAYOUT)
+		end := time.Unix(cond.EndEndedTime, 0).Format(utils.DATETIME_LAYOUT)
+		query = query.Where("streams.ended_at BETWEEN ? AND ?", from, end)
+	}
+	return query
+}
+
 func (s *StreamRepository) PaginateLiveStreamBroadCastData(page, limit uint, cond *dto.LiveStreamBroadCastQueryDTO) (*utils.PaginationModel[model.Stream], error) {
 
 	var query = s.db.Debug().Model(model.Stream{}).Preload("User")
 
 	// filter
 	if cond != nil {
-
-		if cond.Category != "" {
-			query = query.Joins("LEFT JOIN stream_categories stc ON streams.id = stc.stream_id").Joins("LEFT JOIN categories c ON stc.category_id = c.id").
-				Where("c.name = ?", cond.Category)
-		}
-
-		if cond.Keyword != "" {
-			query = query.Where("streams.title ILIKE ? OR streams.description ILIKE ?", "%"+cond.Keyword+"%", "%"+cond.Keyword+"%")
-		}
-		if len(cond.Status) > 0 {
-			query = query.Where("streams.status IN ?", cond.Status)
-		}
-		if cond.Type != "" {
-			query = query.Where("streams.stream_type = ?", cond.Type)
-		}
-		if cond.FromStartedTime != 0 && cond.EndStartedTime != 0 {
-			from := time.Unix(cond.FromStartedTime, 0).Format(utils.DATETIME_LAYOUT)
-			end := time.Unix(cond.EndStartedTime, 0).Format(utils.DATETIME_LAYOUT)
-			query = query.Where("streams.started_at BETWEEN ? AND ?", from, end)
-		}
-		if cond.FromEndedTime != 0 && cond.EndEndedTime != 0 {
-			from := time.Unix(cond.FromEndedTime, 0).Format(utils.DATETIME_LAYOUT)
-			end := time.Unix(cond.EndEndedTime, 0).Format(utils.DATETIME_LAYOUT)
-			query = query.Where("streams.ended_at BETWEEN ? AND ?", from, end)
-		}
+		query = s.filterLiveStreamBroadCastQuery(query, cond)
 
 		if cond.Sort != "" && cond.SortBy != "" && cond.SortBy != dto.SORT_BY_DURATION {
 			if slices.Contains([]string{dto.SORT_BY_VIEWERS, dto.SORT_BY_LIKES, dto.SORT_BY_COMMENTS, dto.SORT_BY_VIDEO_SIZE, dto.SORT_BY_SHARES}, cond.SortBy) {
@@ -195,6 +225,16 @@ func (s *StreamRepository) PaginateLiveStreamBroadCastData(page, limit uint, con
 	return utils.Create(pagination, int(page), int(limit))
 }
 
+// FindLiveStreamBroadCastIDs returns ids of streams matching cond, at most limit + 1 so callers can tell the limit is exceeded
+func (s *StreamRepository) FindLiveStreamBroadCastIDs(cond *dto.LiveStreamBroadCastQueryDTO, limit int) ([]uint, error) {
+	var result []uint
+	query := s.filterLiveStreamBroadCastQuery(s.db.Model(model.Stream{}), cond)
+	if err := query.Distinct("streams.id").Order("streams.id").Limit(limit+1).Pluck("streams.id", &result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (s *StreamRepository) GetByIDWithUserPreload(id int) (*model.Stream, error) {
 	var result model.Stream
 
@@ -214,6 +254,17 @@ func (s *StreamRepository) GetByID(id uint) (*model.Stream, error) {
 	return &stream, nil
 }
 
+// GetByIDForUpdate locks the stream row until the transaction ends, so its status can't change meanwhile
+func (s *StreamRepository) GetByIDForUpdate(id uint) (*model.Stream, error) {
+	var stream model.Stream
+
+	if err := s.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&stream).Error; err != nil {
+		return nil, err
+	}
+
+	return &stream, nil
+}
+
 func (s *StreamRepository) GetScheduleStreamByID(id uint) (*model.ScheduleStream, error) {
 	var stream model.ScheduleStream
 
@@ -235,6 +286,18 @@ func (s *StreamRepository) GetScheduleStreamByStreamID(id int) (*model.ScheduleS
 	return &result, nil
 }
 
+// GetRebroadcasts returns scheduled streams replaying the recordings of streamIDs
+func (s *StreamRepository) GetRebroadcasts(streamIDs []uint) ([]model.ScheduleStream, error) {
+	var result []model.ScheduleStream
+	if len(streamIDs) == 0 {
+		return result, nil
+	}
+	if err := s.db.Model(model.ScheduleStream{}).Where("rebroadcast_of_id IN ?", streamIDs).Preload("Stream").Order("scheduled_at").Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (s *StreamRepository) GetStatisticsTotalStream() (int64, int64, error) {
 	var activeStream, totalStream int64
 
@@ -251,60 +314,28 @@ func (r *StreamRepository) Create(stream *model.Stream) error {
 	return r.db.Create(stream).Error
 }
 
+// DeleteLiveStream hard deletes a stream with related rows, in the transaction of r when there's one
 func (r *StreamRepository) DeleteLiveStream(id int) error {
-	tx := r.db.Begin()
-
-	if err := tx.Unscoped().Where("stream_id = ?", id).Delete(&model.StreamAnalytics{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-	if err := tx.Unscoped().Where("stream_id = ?", id).Delete(&model.View{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-	if err := tx.Unscoped().Where("stream_id = ?", id).Delete(&model.Like{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-	if err := tx.Unscoped().Where("stream_id = ?", id).Delete(&model.Comment{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-	if err := tx.Unscoped().Where("stream_id = ?", id).Delete(&model.StreamCategory{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-	if err := tx.Unscoped().Where("stream_id = ?", id).Delete(&model.Notification{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-	if err := tx.Unscoped().Where("stream_id = ?", id).Delete(&model.Share{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	if err := tx.Unscoped().Where("stream_id = ?", id).Delete(&model.ScheduleStream{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	if err := tx.Unscoped().Where("id = ?", id).Delete(&model.Stream{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	// Defer the commit or rollback of the transaction
-	defer func() {
-		if r := recover(); r != nil {
-			// In case of panic, roll back the transaction
-			tx.Rollback()
-			log.Println("Transaction failed, rolling back")
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, related := range []interface{}{
+			&model.StreamAnalytics{}, &model.View{}, &model.Like{}, &model.Comment{},
+			&model.StreamCategory{}, &model.Notification{}, &model.Share{}, &model.Clip{},
+		} {
+			if err := tx.Unscoped().Where("stream_id = ?", id).Delete(related).Error; err != nil {
+				return err
+			}
 		}
-	}()
-
-	return tx.Commit().Error
 
+		if err := tx.Unscoped().Where("schedule_stream_id IN (?)", tx.Model(model.ScheduleStream{}).Select("id").Where("stream_id = ?", id)).Delete(&model.ScheduleStreamItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("stream_id = ?", id).Delete(&model.ScheduleStream{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ?", id).Delete(&model.Stream{}).Error
+	})
 }
+
 func (r *StreamRepository) GetCategoriesByStreamID(id uint) ([]model.Category, error) {
 	var streamCategories []model.StreamCategory
 	if err := r.db.Model(model.StreamCategory{}).Where("stream_id = ?", id).Preload("Category").Find(&streamCategories).Error; err != nil {
@@ -318,9 +349,8 @@ func (r *StreamRepository) GetCategoriesByStreamID(id uint) ([]model.Category, e
 	return result, nil
 }
 
+// CreateScheduleStream runs in the transaction of r when there's one, e.g. under the schedule lock
 func (r *StreamRepository) CreateScheduleStream(stream *model.Stream, scheduleStream *model.ScheduleStream, categoryIDs []uint) error {
-	tx := r.db.Begin()
-
 	var existingCategoryIDs []uint
 	if err := r.db.Model(&model.Category{}).Where("id IN ?", categoryIDs).Pluck("id", &existingCategoryIDs).Error; err != nil {
 		return err
@@ -334,30 +364,25 @@ func (r *StreamRepository) CreateScheduleStream(stream *model.Stream, scheduleSt
 		}
 	}
 
-	if err := tx.Create(stream).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	for _, categoryID := range categoryIDs {
-		streamCategory := &model.StreamCategory{
-			StreamID:   stream.ID,
-			CategoryID: categoryID,
-		}
-
-		if err := tx.Create(streamCategory).Error; err != nil {
-			tx.Rollback()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(stream).Error; err != nil {
 			return err
 		}
-	}
 
-	scheduleStream.StreamID = stream.ID
-	if err := tx.Create(scheduleStream).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
+		for _, categoryID := range categoryIDs {
+			streamCategory := &model.StreamCategory{
+				StreamID:   stream.ID,
+				CategoryID: categoryID,
+			}
 
-	return tx.Commit().Error
+			if err := tx.Create(streamCategory).Error; err != nil {
+				return err
+			}
+		}
+
+		scheduleStream.StreamID = stream.ID
+		return tx.Create(scheduleStream).Error
+	})
 }
 
 func (r *StreamRepository) UpdateStream(stream *model.Stream, scheduleStream *model.ScheduleStream, categoryIDs []uint) error {
@@ -419,9 +444,182 @@ func (r *StreamRepository) UpdateScheduledStream(streamID int, req *dto.UpdateSc
 	}
 	scheduleStream.ScheduledAt = parsedTime
 	scheduleStream.VideoName = req.VideoFileName
+	scheduleStream.VideoDuration = req.VideoDuration
 	return r.db.Model(model.ScheduleStream{}).Where("stream_id = ?", streamID).Updates(scheduleStream).Error
 }
 
 func (r *StreamRepository) UpdateThumbnailStream(id int, thumbnail string) error {
 	return r.db.Model(model.Stream{}).Where("id=?", id).Update("thumbnail_file_name", thumbnail).Error
 }
+
+// SoftDelete moves a stream to trash, related rows are kept until DeleteLiveStream purges it
+func (r *StreamRepository) SoftDelete(id, deletedByID uint, deletedAt time.Time) error {
+	result := r.db.Model(model.Stream{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at":    deletedAt,
+		"deleted_by_id": deletedByID,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetDeletedByID returns a trashed stream, nil when it's not in trash
+func (r *StreamRepository) GetDeletedByID(id uint) (*model.Stream, error) {
+	var stream model.Stream
+	if err := r.db.Unscoped().Model(model.Stream{}).Where("id = ? AND deleted_at IS NOT NULL", id).First(&stream).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &stream, nil
+}
+
+func (r *StreamRepository) Restore(id uint) error {
+	return r.db.Unscoped().Model(model.Stream{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at":    nil,
+		"deleted_by_id": nil,
+	}).Error
+}
+
+func (r *StreamRepository) UpdateLegalHold(id uint, legalHold bool) error {
+	return r.db.Model(model.Stream{}).Where("id = ?", id).Update("legal_hold", legalHold).Error
+}
+
+func (r *StreamRepository) UpdatePinned(id uint, isPinned bool) error {
+	return r.db.Model(model.Stream{}).Where("id = ?", id).Update("is_pinned", isPinned).Error
+}
+
+// GetDueScheduleStreams returns upcoming pre-recorded streams which should be pushed now
+func (r *StreamRepository) GetDueScheduleStreams(now time.Time, limit int) ([]model.ScheduleStream, error) {
+	var result []model.ScheduleStream
+	if err := r.db.Model(model.ScheduleStream{}).
+		Joins("INNER JOIN streams ON streams.id = schedule_streams.stream_id").
+		Where("streams.status = ? AND streams.stream_type = ? AND streams.deleted_at IS NULL", model.UPCOMING, model.PRERECORDSTREAM).
+		Where("schedule_streams.scheduled_at <= ?", now).
+		Where("schedule_streams.next_attempt_at IS NULL OR schedule_streams.next_attempt_at <= ?", now).
+		Order("schedule_streams.scheduled_at").
+		Limit(limit).
+		Preload("Stream").
+		Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ClaimScheduleStream moves an upcoming stream to pending, false when it was already claimed or changed
+func (r *StreamRepository) ClaimScheduleStream(streamID uint, now time.Time) (bool, error) {
+	claimed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(model.Stream{}).Where("id = ? AND status = ?", streamID, model.UPCOMING).Update("status", model.PENDING)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		claimed = true
+		return tx.Model(model.ScheduleStream{}).Where("stream_id = ?", streamID).Updates(map[string]interface{}{
+			"push_attempts": gorm.Expr("push_attempts + 1"),
+			"claimed_at":    now,
+		}).Error
+	})
+	return claimed, err
+}
+
+// RenewScheduleStreamClaims keeps claims of streams which are being pushed by this instance
+func (r *StreamRepository) RenewScheduleStreamClaims(streamIDs []uint, now time.Time) error {
+	return r.db.Model(model.ScheduleStream{}).Where("stream_id IN ? AND claimed_at IS NOT NULL", streamIDs).Update("claimed_at", now).Error
+}
+
+// GetStaleScheduleStreams returns pending and started pre-recorded streams whose claim wasn't renewed since staleBefore
+func (r *StreamRepository) GetStaleScheduleStreams(staleBefore time.Time) ([]model.ScheduleStream, error) {
+	var result []model.ScheduleStream
+	if err := r.db.Model(model.ScheduleStream{}).
+		Joins("INNER JOIN streams ON streams.id = schedule_streams.stream_id").
+		Where("streams.status IN ? AND streams.stream_type = ?", []model.StreamStatus{model.PENDING, model.STARTED}, model.PRERECORDSTREAM).
+		Where("schedule_streams.claimed_at < ?", staleBefore).
+		Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ResetStaleScheduleStream releases a stale claim, the stream is retried at retryAt or ended when retryAt is nil.
+// It returns false when the claim was renewed or released meanwhile.
+func (r *StreamRepository) ResetStaleScheduleStream(streamID uint, staleBefore time.Time, retryAt *time.Time, pushError string, now time.Time) (bool, error) {
+	reset := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(model.ScheduleStream{}).Where("stream_id = ? AND claimed_at < ?", streamID, staleBefore).Updates(map[string]interface{}{
+			"last_push_error": pushError,
+			"next_attempt_at": retryAt,
+			"claimed_at":      nil,
+		})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		updates := map[string]interface{}{
+			"status":   model.ENDED,
+			"ended_at": now,
+		}
+		if retryAt != nil {
+			updates = map[string]interface{}{
+				"status":     model.UPCOMING,
+				"started_at": nil,
+			}
+		}
+		result = tx.Model(model.Stream{}).Where("id = ? AND status IN ?", streamID, []model.StreamStatus{model.PENDING, model.STARTED}).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		reset = result.RowsAffected > 0
+		return nil
+	})
+	return reset, err
+}
+
+func (r *StreamRepository) MarkStreamStarted(streamID uint, streamToken string, startedAt time.Time) error {
+	return r.db.Model(model.Stream{}).Where("id = ?", streamID).Updates(map[string]interface{}{
+		"status":       model.STARTED,
+		"stream_token": streamToken,
+		"started_at":   startedAt,
+	}).Error
+}
+
+func (r *StreamRepository) MarkStreamEnded(streamID uint, endedAt time.Time, pushError string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(model.Stream{}).Where("id = ?", streamID).Updates(map[string]interface{}{
+			"status":   model.ENDED,
+			"ended_at": endedAt,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(model.ScheduleStream{}).Where("stream_id = ?", streamID).Updates(map[string]interface{}{
+			"last_push_error": pushError,
+			"next_attempt_at": nil,
+			"claimed_at":      nil,
+		}).Error
+	})
+}
+
+// RetryScheduleStream puts a failed stream back to upcoming, it is picked up again at nextAttemptAt
+func (r *StreamRepository) RetryScheduleStream(streamID uint, nextAttemptAt time.Time, pushError string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(model.Stream{}).Where("id = ?", streamID).Updates(map[string]interface{}{
+			"status":     model.UPCOMING,
+			"started_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(model.ScheduleStream{}).Where("stream_id = ?", streamID).Updates(map[string]interface{}{
+			"last_push_error": pushError,
+			"next_attempt_at": nextAttemptAt,
+			"claimed_at":      nil,
+		}).Error
+	})
+}
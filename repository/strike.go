@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"errors"
+	"gitlab/live/be-live-admin/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StrikeRepository struct {
+	db *gorm.DB
+}
+
+func newStrikeRepository(db *gorm.DB) *StrikeRepository {
+	return &StrikeRepository{
+		db: db,
+	}
+}
+
+// Create records the strike and blocks its user until strike.SuspendedUntil, or for good once the strike reaches maxStrikes.
+// The user row is locked, so concurrent strikes count each other and a running suspension is only ever extended.
+// It returns the strikes of the user including this one.
+func (r *StrikeRepository) Create(strike *model.UserStrike, maxStrikes uint, blockedReason string) (uint, error) {
+	var strikes uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "strikes", "suspended_until").
+			Where("id = ?", strike.UserID).First(&user).Error; err != nil {
+			return err
+		}
+		strikes = user.Strikes + 1
+		if strikes >= maxStrikes {
+			strike.Permanent = true
+			strike.SuspendedUntil = nil
+		} else if user.SuspendedUntil != nil && strike.SuspendedUntil != nil && user.SuspendedUntil.After(*strike.SuspendedUntil) {
+			strike.SuspendedUntil = user.SuspendedUntil
+		}
+
+		if err := tx.Omit("User", "IssuedBy", "RevokedBy").Create(strike).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.User{}).Where("id = ?", strike.UserID).Updates(map[string]interface{}{
+			"status":          model.BLOCKED,
+			"blocked_reason":  blockedReason,
+			"suspended_until": strike.SuspendedUntil,
+			"updated_by_id":   strike.IssuedByID,
+			"strikes":         strikes,
+		}).Error
+	})
+	return strikes, err
+}
+
+func (r *StrikeRepository) FindByUserID(userID uint) ([]model.UserStrike, error) {
+	var result []model.UserStrike
+	if err := r.db.Model(model.UserStrike{}).Preload("IssuedBy").Preload("RevokedBy").
+		Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *StrikeRepository) FindByID(id uint) (*model.UserStrike, error) {
+	var result model.UserStrike
+	if err := r.db.Model(model.UserStrike{}).Preload("IssuedBy").Preload("RevokedBy").Where("id = ?", id).First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Revoke stops the strike counting, and reinstates its user when reinstate is set.
+// It returns false when the strike is revoked already.
+func (r *StrikeRepository) Revoke(strike *model.UserStrike, revokedByID uint, reinstate bool) (bool, error) {
+	revoked := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.UserStrike{}).Where("id = ? AND revoked_at IS NULL", strike.ID).Updates(map[string]interface{}{
+			"revoked_at":    time.Now(),
+			"revoked_by_id": revokedByID,
+		})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		revoked = true
+
+		updates := map[string]interface{}{
+			"strikes":       gorm.Expr("GREATEST(strikes - 1, 0)"),
+			"updated_by_id": revokedByID,
+		}
+		if reinstate {
+			updates["status"] = model.OFFLINE
+			updates["blocked_reason"] = ""
+			updates["suspended_until"] = nil
+		}
+		return tx.Model(&model.User{}).Where("id = ?", strike.UserID).Updates(updates).Error
+	})
+	return revoked, err
+}
+
+// FindDueSuspensions returns blocked users whose suspension ended by now
+func (r *StrikeRepository) FindDueSuspensions(now time.Time, limit int) ([]model.User, error) {
+	var result []model.User
+	if err := r.db.Model(model.User{}).
+		Where("status = ? AND suspended_until IS NOT NULL AND suspended_until <= ?", model.BLOCKED, now).
+		Order("suspended_until").Limit(limit).Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Reinstate returns false when the user isn't suspended until now anymore, e.g. an admin blocked it permanently meanwhile
+func (r *StrikeRepository) Reinstate(userID uint, now time.Time) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND status = ? AND suspended_until IS NOT NULL AND suspended_until <= ?", userID, model.BLOCKED, now).
+		Updates(map[string]interface{}{
+			"status":          model.OFFLINE,
+			"blocked_reason":  "",
+			"suspended_until": nil,
+		})
+	return result.RowsAffected > 0, result.Error
+}
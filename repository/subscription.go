@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// subscribed by an account younger than @age seconds
+const newAccountSubscription = "users.created_at > subscriptions.created_at - @age * INTERVAL '1 second'"
+
+type SubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func newSubscriptionRepository(db *gorm.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{
+		db: db,
+	}
+}
+
+// Page lists subscribers of the user, or its subscriptions, subscriptions of trashed users are left out
+func (r *SubscriptionRepository) Page(userID uint, direction string, req *dto.SubscriptionQuery) (*utils.PaginationModel[model.Subscription], error) {
+	query := r.db.Model(model.Subscription{})
+	if direction == dto.SUBSCRIPTION_DIRECTION_SUBSCRIPTIONS {
+		query = query.Joins("JOIN users ON users.id = subscriptions.streamer_id AND users.deleted_at IS NULL").
+			Where("subscriptions.subscriber_id = ?", userID).Preload("Streamer")
+	} else {
+		query = query.Joins("JOIN users ON users.id = subscriptions.subscriber_id AND users.deleted_at IS NULL").
+			Where("subscriptions.streamer_id = ?", userID).Preload("Subscriber")
+	}
+
+	if req.Keyword != "" {
+		query = query.Where("users.username ILIKE ? OR users.display_name ILIKE ?", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
+	switch req.Mute {
+	case "muted":
+		query = query.Where("subscriptions.is_mute")
+	case "unmuted":
+		query = query.Where("NOT subscriptions.is_mute")
+	}
+
+	sort := "DESC"
+	if req.Sort != "" {
+		sort = req.Sort
+	}
+	if req.SortBy == "username" {
+		query = query.Order(fmt.Sprintf("users.username %s", sort))
+	} else {
+		query = query.Order(fmt.Sprintf("subscriptions.created_at %s", sort))
+	}
+
+	pagination, err := utils.CreatePage[model.Subscription](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+type SubscriptionCounts struct {
+	Total int64
+	Muted int64
+}
+
+// Counts counts subscriptions where column is the user, streamer_id for its subscribers
+func (r *SubscriptionRepository) Counts(column string, userID uint) (*SubscriptionCounts, error) {
+	var result SubscriptionCounts
+	if err := r.db.Model(model.Subscription{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE is_mute) AS muted").
+		Where(fmt.Sprintf("%s = ?", column), userID).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CountBefore counts subscribers of the streamer who subscribed before t
+func (r *SubscriptionRepository) CountBefore(streamerID uint, t time.Time) (*SubscriptionCounts, error) {
+	var result SubscriptionCounts
+	if err := r.db.Model(model.Subscription{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE is_mute) AS muted").
+		Where("streamer_id = ? AND created_at < ?", streamerID, t).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type SubscriptionGrowthRow struct {
+	Bucket int64
+	Total  int64
+	Muted  int64
+}
+
+// Growth counts subscribers of the streamer per interval of seconds in [from, to)
+func (r *SubscriptionRepository) Growth(streamerID uint, from, to time.Time, interval uint) ([]SubscriptionGrowthRow, error) {
+	var result []SubscriptionGrowthRow
+	if err := r.db.Model(model.Subscription{}).
+		Select("FLOOR(EXTRACT(EPOCH FROM (created_at - @start)) / @interval)::bigint AS bucket, COUNT(*) AS total, COUNT(*) FILTER (WHERE is_mute) AS muted",
+			sql.Named("start", from), sql.Named("interval", interval)).
+		Where("streamer_id = ? AND created_at >= ? AND created_at < ?", streamerID, from, to).
+		Group("bucket").Order("bucket").
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// burstsQuery slides a window of @window seconds from every new account subscription, windows holding at least
+// @min_count subscriptions are merged with the ones they overlap into a burst.
+// seq is the rank of the first subscription of a window, so a burst spans up to MAX(seq + count) - 1
+const burstsQuery = `
+WITH subscribed AS (
+	SELECT subscriptions.created_at,
+		RANK() OVER (ORDER BY subscriptions.created_at) AS seq,
+		COUNT(*) OVER (ORDER BY subscriptions.created_at
+			RANGE BETWEEN CURRENT ROW AND @window * INTERVAL '1 second' - INTERVAL '1 microsecond' FOLLOWING) AS count
+	FROM subscriptions
+	JOIN users ON users.id = subscriptions.subscriber_id
+	WHERE subscriptions.streamer_id = @streamer AND subscriptions.created_at >= @from AND subscriptions.created_at < @to
+		AND ` + newAccountSubscription + `
+), windows AS (
+	SELECT created_at, seq, count,
+		created_at < LAG(created_at) OVER (ORDER BY created_at, seq) + @window * INTERVAL '1 second' AS continues
+	FROM subscribed
+	WHERE count >= @min_count
+), numbered AS (
+	SELECT *, COUNT(*) FILTER (WHERE continues IS NOT TRUE) OVER (ORDER BY created_at, seq ROWS UNBOUNDED PRECEDING) AS burst
+	FROM windows
+)
+SELECT MIN(created_at) AS start, MAX(created_at) + @window * INTERVAL '1 second' AS "end", MAX(seq + count) - MIN(seq) AS count
+FROM numbered
+GROUP BY burst
+ORDER BY start
+LIMIT @limit`
+
+type SubscriptionBurstRow struct {
+	Start time.Time
+	End   time.Time
+	Count int64
+}
+
+// Bursts finds spans in [from, to) where accounts younger than age seconds subscribed to the streamer at least
+// minCount times within any window of seconds, overlapping windows are merged into one burst
+func (r *SubscriptionRepository) Bursts(streamerID uint, from, to time.Time, window, age, minCount uint, limit int) ([]SubscriptionBurstRow, error) {
+	var result []SubscriptionBurstRow
+	if err := r.db.Raw(burstsQuery,
+		sql.Named("streamer", streamerID), sql.Named("from", from), sql.Named("to", to), sql.Named("window", window),
+		sql.Named("age", age), sql.Named("min_count", minCount), sql.Named("limit", limit)).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindNewAccountSubscriberIDs returns subscribers of the streamer in [from, to) whose accounts were younger than age seconds
+func (r *SubscriptionRepository) FindNewAccountSubscriberIDs(streamerID uint, from, to time.Time, age uint, limit int) ([]uint, error) {
+	var result []uint
+	if err := r.db.Model(model.Subscription{}).
+		Joins("JOIN users ON users.id = subscriptions.subscriber_id").
+		Where("subscriptions.streamer_id = ? AND subscriptions.created_at >= ? AND subscriptions.created_at < ?", streamerID, from, to).
+		Where(newAccountSubscription, sql.Named("age", age)).
+		Order("subscriptions.created_at").Limit(limit).
+		Pluck("subscriptions.subscriber_id", &result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteBySubscriberIDs returns how many subscriptions to the streamer were removed
+func (r *SubscriptionRepository) DeleteBySubscriberIDs(streamerID uint, subscriberIDs []uint) (int64, error) {
+	result := r.db.Where("streamer_id = ? AND subscriber_id IN ?", streamerID, subscriberIDs).Delete(&model.Subscription{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/utils"
+
+	"gorm.io/gorm"
+)
+
+type TrashRepository struct {
+	db *gorm.DB
+}
+
+func newTrashRepository(db *gorm.DB) *TrashRepository {
+	return &TrashRepository{
+		db: db,
+	}
+}
+
+func (r *TrashRepository) PageUsers(req *dto.TrashQuery) (*utils.PaginationModel[model.User], error) {
+	query := r.db.Unscoped().Model(model.User{}).Where("users.deleted_at IS NOT NULL")
+	if req.Keyword != "" {
+		query = query.Where("users.username ILIKE ? OR users.display_name ILIKE ? OR users.email ILIKE ?", "%"+req.Keyword+"%", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
+	query = query.Preload("DeletedBy").Order("users.deleted_at DESC")
+
+	pagination, err := utils.CreatePage[model.User](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
+
+func (r *TrashRepository) PageStreams(req *dto.TrashQuery) (*utils.PaginationModel[model.Stream], error) {
+	query := r.db.Unscoped().Model(model.Stream{}).Where("streams.deleted_at IS NOT NULL")
+	if req.Keyword != "" {
+		query = query.Where("streams.title ILIKE ? OR streams.description ILIKE ?", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
+	query = query.Preload("DeletedBy").Order("streams.deleted_at DESC")
+
+	pagination, err := utils.CreatePage[model.Stream](query, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return utils.Create(pagination, int(req.Page), int(req.Limit))
+}
@@ -39,7 +39,7 @@ func (s *UserRepository) SetRoleMap() error {
 	return nil
 }
 
-func (s *UserRepository) Page(filter *dto.UserQuery, page, limit uint) (*utils.PaginationModel[model.User], error) {
+func (s *UserRepository) filterQuery(filter *dto.UserQuery) *gorm.DB {
 	var query = s.db.Model(model.User{})
 	query = query.Joins("LEFT JOIN roles ON roles.id = users.role_id")
 	query = query.Joins("LEFT JOIN users cr ON cr.id = users.created_by_id")
@@ -57,6 +57,17 @@ func (s *UserRepository) Page(filter *dto.UserQuery, page, limit uint) (*utils.P
 		query = query.Where("users.blocked_reason ILIKE ?", "%"+filter.Reason+"%")
 	}
 
+	switch filter.Suspension {
+	case "temporary":
+		query = query.Where("users.status = ? AND users.suspended_until IS NOT NULL", model.BLOCKED)
+	case "permanent":
+		query = query.Where("users.status = ? AND users.suspended_until IS NULL", model.BLOCKED)
+	}
+
+	if filter.MinStrikes > 0 {
+		query = query.Where("users.strikes >= ?", filter.MinStrikes)
+	}
+
 	if filter != nil && filter.Role != "" {
 		query = query.Where("roles.type = ?", filter.Role)
 	}
@@ -64,11 +75,14 @@ func (s *UserRepository) Page(filter *dto.UserQuery, page, limit uint) (*utils.P
 	if filter != nil && filter.Keyword != "" {
 		query = query.Where("users.username != ? AND (users.username ILIKE ? OR users.display_name ILIKE ?) OR (users.email ILIKE ? AND users.email != ?)", model.SUPER_ADMIN_USERNAME, "%"+filter.Keyword+"%", "%"+filter.Keyword+"%", "%"+filter.Keyword+"%", model.SUPER_ADMIN_EMAIL)
 	}
+	return query.Where("users.username != ? AND users.email != ?", model.SUPER_ADMIN_USERNAME, model.SUPER_ADMIN_EMAIL)
+}
 
+func (s *UserRepository) Page(filter *dto.UserQuery, page, limit uint) (*utils.PaginationModel[model.User], error) {
+	query := s.filterQuery(filter)
 	if filter != nil && filter.SortBy != "" && filter.Sort != "" {
 		query = query.Order(fmt.Sprintf("users.%s %s", filter.SortBy, filter.Sort))
 	}
-	query = query.Where("users.username != ? AND users.email != ?", model.SUPER_ADMIN_USERNAME, model.SUPER_ADMIN_EMAIL)
 	query = query.Preload("Role").Preload("CreatedBy").Preload("UpdatedBy")
 	pagination, err := utils.CreatePage[model.User](query, int(page), int(limit))
 	if err != nil {
@@ -77,6 +91,63 @@ func (s *UserRepository) Page(filter *dto.UserQuery, page, limit uint) (*utils.P
 	return utils.Create(pagination, int(page), int(limit))
 }
 
+// FindIDs returns ids of users matching filter, at most limit + 1 so callers can tell the limit is exceeded
+func (s *UserRepository) FindIDs(filter *dto.UserQuery, limit int) ([]uint, error) {
+	var result []uint
+	if err := s.filterQuery(filter).Order("users.id").Limit(limit+1).Pluck("users.id", &result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// audienceQuery selects users an announcement is sent to, blocked users are left out.
+// segment is the filter of segment targets.
+func (s *UserRepository) audienceQuery(announcement *model.Announcement, segment *dto.UserQuery) *gorm.DB {
+	if segment == nil {
+		segment = &dto.UserQuery{}
+	}
+	query := s.filterQuery(segment).Where("users.status != ?", model.BLOCKED)
+	switch announcement.TargetType {
+	case model.AnnouncementTargetRole:
+		query = query.Where("roles.type = ?", announcement.TargetRole)
+	case model.AnnouncementTargetCategoryViewers:
+		viewers := s.db.Model(model.View{}).Select("views.user_id").
+			Joins("JOIN stream_categories ON stream_categories.stream_id = views.stream_id").
+			Where("stream_categories.category_id = ?", announcement.CategoryID)
+		query = query.Where("users.id IN (?)", viewers)
+	}
+	return query
+}
+
+// CountAudience counts users of the announcement after afterID
+func (s *UserRepository) CountAudience(announcement *model.Announcement, segment *dto.UserQuery, afterID uint) (int64, error) {
+	var result int64
+	if err := s.audienceQuery(announcement, segment).Where("users.id > ?", afterID).Count(&result).Error; err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// FindAudience returns the next limit users of the announcement after afterID in id order, with the fields
+// notifications are rendered with
+func (s *UserRepository) FindAudience(announcement *model.Announcement, segment *dto.UserQuery, afterID uint, limit int) ([]model.User, error) {
+	var result []model.User
+	if err := s.audienceQuery(announcement, segment).Select("users.id, users.username, users.display_name, users.locale").
+		Where("users.id > ?", afterID).Order("users.id").Limit(limit).Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CountByFilter counts users matching filter
+func (s *UserRepository) CountByFilter(filter *dto.UserQuery) (int64, error) {
+	var result int64
+	if err := s.filterQuery(filter).Count(&result).Error; err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
 func (r *UserRepository) Update(updatedUser *model.User) error {
 	if err := r.db.Updates(updatedUser).Error; err != nil {
 		return err
@@ -84,6 +155,15 @@ func (r *UserRepository) Update(updatedUser *model.User) error {
 	return nil
 }
 
+func (r *UserRepository) ChangeStatus(id uint, status model.UserStatusType, reason string, updatedByID uint) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          status,
+		"blocked_reason":  reason,
+		"updated_by_id":   updatedByID,
+		"suspended_until": nil,
+	}).Error
+}
+
 func (r *UserRepository) GetUsernameList() ([]string, error) {
 	var result []string
 	if err := r.db.Model(model.User{}).Where("username != ?", model.SUPER_ADMIN_USERNAME).Select("username").Find(&result).Error; err != nil {
@@ -92,21 +172,65 @@ func (r *UserRepository) GetUsernameList() ([]string, error) {
 	return result, nil
 }
 
-func (r *UserRepository) Delete(id, deletedByID uint) error {
-	var userToDelete model.User
-	if err := r.db.First(&userToDelete, "id = ?", id).Error; err != nil {
-		return err
+// Delete moves a user to trash, Purge removes it for good
+func (r *UserRepository) Delete(id, deletedByID uint, deletedAt time.Time) error {
+	result := r.db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at":    deletedAt,
+		"deleted_by_id": deletedByID,
+	})
+	if result.Error != nil {
+		return result.Error
 	}
-	// userToDelete.DeletedByID = &deletedByID
-	// if err := r.db.Updates(&userToDelete).Error; err != nil {
-	// 	return err
-	// }
-	if err := r.db.Unscoped().Delete(&userToDelete).Error; err != nil {
-		return err
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
 	}
 	return nil
 }
 
+// FindDeletedByID returns a trashed user, nil when it's not in trash
+func (r *UserRepository) FindDeletedByID(id uint) (*model.User, error) {
+	var user model.User
+	if err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Preload("Role").First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) Restore(id uint) error {
+	return r.db.Unscoped().Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at":    nil,
+		"deleted_by_id": nil,
+	}).Error
+}
+
+func (r *UserRepository) Purge(id uint) error {
+	return r.db.Unscoped().Where("id = ?", id).Delete(&model.User{}).Error
+}
+
+// FindExisting returns users with any of usernames or emails, trashed ones included since they keep their unique values
+func (r *UserRepository) FindExisting(usernames, emails []string) ([]model.User, error) {
+	var result []model.User
+	if err := r.db.Unscoped().Model(model.User{}).Select("id, username, email, deleted_at").Where("username IN ? OR LOWER(email) IN ?", usernames, emails).Find(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateBatch creates users in one transaction
+func (r *UserRepository) CreateBatch(users []*model.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, user := range users {
+			if err := tx.Omit("Role").Create(user).Error; err != nil {
+				return fmt.Errorf("failed to create user %s: %w", user.Username, err)
+			}
+		}
+		return nil
+	})
+}
+
 func (r *UserRepository) Create(user *model.User) error {
 	// Perform the database insertion
 	if err := r.db.Create(user).Error; err != nil {
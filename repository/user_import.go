@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"gitlab/live/be-live-admin/model"
+
+	"gorm.io/gorm"
+)
+
+type UserImportRepository struct {
+	db *gorm.DB
+}
+
+func newUserImportRepository(db *gorm.DB) *UserImportRepository {
+	return &UserImportRepository{
+		db: db,
+	}
+}
+
+func (r *UserImportRepository) CreateCredentials(credentials []model.UserImportCredential) error {
+	if len(credentials) == 0 {
+		return nil
+	}
+	return r.db.Omit("Job").CreateInBatches(credentials, 500).Error
+}
+
+// FindCredentials returns password hashes of the rows of jobID by row
+func (r *UserImportRepository) FindCredentials(jobID uint) (map[int]string, error) {
+	var credentials []model.UserImportCredential
+	if err := r.db.Where("job_id = ?", jobID).Find(&credentials).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int]string, len(credentials))
+	for _, credential := range credentials {
+		result[credential.Row] = credential.PasswordHash
+	}
+	return result, nil
+}
+
+func (r *UserImportRepository) DeleteCredentials(jobID uint) error {
+	return r.db.Where("job_id = ?", jobID).Delete(&model.UserImportCredential{}).Error
+}
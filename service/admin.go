@@ -5,6 +5,7 @@ import (
 	"gitlab/live/be-live-admin/model"
 	"gitlab/live/be-live-admin/repository"
 	"gitlab/live/be-live-admin/utils"
+	"time"
 )
 
 type AdminService struct {
@@ -75,7 +76,20 @@ func (s *AdminService) ById(id uint, apiURL string) (*dto.UserResponseDTO, error
 		return nil, nil
 	}
 	result := s.toAdminResponseDTO(user, apiURL)
-	return &result, err
+	result.Status = user.Status
+	result.BlockedReason = user.BlockedReason
+	result.SuspendedUntil = user.SuspendedUntil
+	result.Strikes = user.Strikes
+	result.Locale = user.Locale
+
+	ban, err := s.repo.PublishBan.FindActive(user.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if ban != nil {
+		result.PublishBan = toPublishBanDto(ban)
+	}
+	return &result, nil
 }
 
 func (s *AdminService) MakeAdminLogModel(userID uint, action model.AdminAction, details string) *model.AdminLog {
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const ANNOUNCEMENT_BATCH_SIZE = 1000
+
+var (
+	ErrAnnouncementTargetNotFound = errors.New("stream, category or segment of the announcement not found")
+	ErrAnnouncementScheduledAt    = errors.New("scheduled_at must be in the future")
+	ErrAnnouncementNotCancelable  = errors.New("announcement was sent already")
+)
+
+// AnnouncementService sends notifications composed by admins, they are fanned out by a model.JobTypeSendAnnouncement job.
+// Contents are rendered with the announcement template of each user's locale.
+type AnnouncementService struct {
+	repo     *repository.Repository
+	job      *JobService
+	template *NotificationTemplateService
+}
+
+func newAnnouncementService(repo *repository.Repository, job *JobService, template *NotificationTemplateService) *AnnouncementService {
+	return &AnnouncementService{
+		repo:     repo,
+		job:      job,
+		template: template,
+	}
+}
+
+func toAnnouncementDto(announcement *model.Announcement) *dto.AnnouncementDTO {
+	return &dto.AnnouncementDTO{
+		ID:          announcement.ID,
+		Title:       announcement.Title,
+		Body:        announcement.Body,
+		StreamID:    announcement.StreamID,
+		TargetType:  announcement.TargetType,
+		TargetRole:  announcement.TargetRole,
+		CategoryID:  announcement.CategoryID,
+		SegmentID:   announcement.SegmentID,
+		Status:      announcement.Status,
+		ScheduledAt: announcement.ScheduledAt,
+		JobID:       announcement.JobID,
+		Recipients:  announcement.Recipients,
+		StartedAt:   announcement.StartedAt,
+		SentAt:      announcement.SentAt,
+		CanceledAt:  announcement.CanceledAt,
+		CreatedBy:   toUserSummaryDto(announcement.CreatedBy),
+		CreatedAt:   announcement.CreatedAt,
+	}
+}
+
+func (s *AnnouncementService) GetAnnouncements(req *dto.AnnouncementQuery) (*utils.PaginationModel[dto.AnnouncementDTO], error) {
+	pagination, err := s.repo.Announcement.Page(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.AnnouncementDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.Announcement) dto.AnnouncementDTO {
+		return *toAnnouncementDto(&e)
+	})
+	return result, nil
+}
+
+func (s *AnnouncementService) FindByID(id uint) (*model.Announcement, error) {
+	return s.repo.Announcement.FindByID(id)
+}
+
+func (s *AnnouncementService) GetAnnouncement(announcement *model.Announcement) *dto.AnnouncementDTO {
+	return toAnnouncementDto(announcement)
+}
+
+// checkTarget makes sure the stream and the target of req exist
+func (s *AnnouncementService) checkTarget(req *dto.CreateAnnouncementRequest) error {
+	if req.StreamID != nil {
+		if _, err := s.repo.Stream.GetByID(*req.StreamID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAnnouncementTargetNotFound
+			}
+			return err
+		}
+	}
+	switch req.TargetType {
+	case model.AnnouncementTargetCategoryViewers:
+		if _, err := s.repo.Category.FindByID(req.CategoryID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAnnouncementTargetNotFound
+			}
+			return err
+		}
+	case model.AnnouncementTargetSegment:
+		segment, err := s.repo.Segment.FindByID(req.SegmentID)
+		if err != nil {
+			return err
+		}
+		if segment == nil {
+			return ErrAnnouncementTargetNotFound
+		}
+	}
+	return nil
+}
+
+// Create saves the announcement and queues its job at the scheduled time
+func (s *AnnouncementService) Create(req *dto.CreateAnnouncementRequest, createdByID uint) (*dto.AnnouncementDTO, error) {
+	now := time.Now()
+	scheduledAt := now
+	if req.ScheduledAt != "" {
+		var err error
+		if scheduledAt, err = time.Parse(utils.DATETIME_LAYOUT, req.ScheduledAt); err != nil {
+			return nil, err
+		}
+		if scheduledAt.Before(now) {
+			return nil, ErrAnnouncementScheduledAt
+		}
+	}
+	if err := s.checkTarget(req); err != nil {
+		return nil, err
+	}
+
+	announcement := &model.Announcement{
+		Title:       req.Title,
+		Body:        req.Body,
+		StreamID:    req.StreamID,
+		TargetType:  req.TargetType,
+		Status:      model.AnnouncementStatusScheduled,
+		ScheduledAt: scheduledAt,
+		CreatedByID: &createdByID,
+	}
+	switch req.TargetType {
+	case model.AnnouncementTargetRole:
+		announcement.TargetRole = req.TargetRole
+	case model.AnnouncementTargetCategoryViewers:
+		announcement.CategoryID = &req.CategoryID
+	case model.AnnouncementTargetSegment:
+		announcement.SegmentID = &req.SegmentID
+	}
+	if err := s.repo.Announcement.Create(announcement); err != nil {
+		return nil, err
+	}
+
+	job, err := s.job.EnqueueAt(model.JobTypeSendAnnouncement, dto.SendAnnouncementPayload{AnnouncementID: announcement.ID}, 0, createdByID, scheduledAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Announcement.SetJob(announcement.ID, job.ID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Announcement.FindByID(announcement.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toAnnouncementDto(created), nil
+}
+
+// Cancel stops a scheduled or sending announcement, notifications sent already are kept
+func (s *AnnouncementService) Cancel(announcement *model.Announcement) error {
+	canceled, err := s.repo.Announcement.Cancel(announcement.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !canceled {
+		return ErrAnnouncementNotCancelable
+	}
+	if announcement.JobID != nil {
+		if err := s.job.Cancel(*announcement.JobID); err != nil && !errors.Is(err, ErrJobNotCancelable) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *AnnouncementService) GetStats(announcement *model.Announcement) (*dto.AnnouncementStatsDTO, error) {
+	stats, err := s.repo.Announcement.Stats(announcement.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.AnnouncementStatsDTO{
+		AnnouncementID: announcement.ID,
+		Status:         announcement.Status,
+		Recipients:     announcement.Recipients,
+		Delivered:      stats.Delivered,
+		Read:           stats.Read,
+		Hidden:         stats.Hidden,
+		FirstReadAt:    stats.FirstReadAt,
+		LastReadAt:     stats.LastReadAt,
+	}
+	if stats.Delivered > 0 {
+		result.ReadRatio = float64(stats.Read) / float64(stats.Delivered)
+	}
+	return result, nil
+}
+
+// SendJob runs a model.JobTypeSendAnnouncement job. Users are notified in batches, a retried job goes on after the
+// last batch and a canceled announcement stops at the next one.
+func (s *AnnouncementService) SendJob() JobHandler {
+	return func(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+		payload, err := decodeJobPayload[dto.SendAnnouncementPayload](job)
+		if err != nil {
+			return err
+		}
+		announcement, err := s.repo.Announcement.FindByID(payload.AnnouncementID)
+		if err != nil {
+			return err
+		}
+		if announcement == nil {
+			return nil
+		}
+		started, err := s.repo.Announcement.Start(announcement.ID, time.Now())
+		if err != nil || !started {
+			return err
+		}
+
+		sent, err := s.send(ctx, announcement, report)
+		if err != nil {
+			if job.Attempts >= job.MaxAttempts && ctx.Err() == nil {
+				if err := s.repo.Announcement.Finish(announcement.ID, model.AnnouncementStatusFailed, time.Now()); err != nil {
+					log.Printf("Job %d failed to save announcement %d: %v\n", job.ID, announcement.ID, err)
+				}
+			}
+			return err
+		}
+
+		if !sent || job.CreatedByID == nil {
+			return nil
+		}
+		adminLog := &model.AdminLog{
+			UserID:  *job.CreatedByID,
+			Action:  string(model.SendAnnouncement),
+			Details: fmt.Sprintf("Sent announcement %d to %d users in job %d.", announcement.ID, announcement.Recipients, job.ID),
+		}
+		if err := s.repo.Admin.Create(adminLog); err != nil {
+			log.Printf("Job %d failed to create admin log: %v\n", job.ID, err)
+		}
+		return nil
+	}
+}
+
+// send delivers the batches of a started announcement, it returns false when the announcement was canceled.
+// Recipients of announcement is kept up to date.
+func (s *AnnouncementService) send(ctx context.Context, announcement *model.Announcement, report JobProgressFunc) (bool, error) {
+	var segment *dto.UserQuery
+	if announcement.TargetType == model.AnnouncementTargetSegment {
+		if announcement.SegmentID == nil {
+			return false, ErrAnnouncementTargetNotFound
+		}
+		found, err := s.repo.Segment.FindByID(*announcement.SegmentID)
+		if err != nil {
+			return false, err
+		}
+		if found == nil {
+			return false, ErrAnnouncementTargetNotFound
+		}
+		if segment, err = decodeSegmentFilter(found); err != nil {
+			return false, err
+		}
+	}
+
+	remaining, err := s.repo.User.CountAudience(announcement, segment, announcement.LastUserID)
+	if err != nil {
+		return false, err
+	}
+	total := uint(remaining) + announcement.Recipients
+	renderer := s.template.Renderer(model.NotificationTypeAnnouncement)
+	for {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		users, err := s.repo.User.FindAudience(announcement, segment, announcement.LastUserID, ANNOUNCEMENT_BATCH_SIZE)
+		if err != nil {
+			return false, err
+		}
+		if len(users) == 0 {
+			break
+		}
+		notifications, err := s.makeNotifications(renderer, announcement, users)
+		if err != nil {
+			return false, err
+		}
+		delivered, err := s.repo.Announcement.Deliver(announcement.ID, notifications)
+		if err != nil {
+			return false, err
+		}
+		if !delivered {
+			return false, nil
+		}
+		announcement.LastUserID = users[len(users)-1].ID
+		announcement.Recipients += uint(len(users))
+		if total > 0 {
+			report(min(announcement.Recipients*100/total, 100), fmt.Sprintf("%d of %d users", announcement.Recipients, total))
+		}
+	}
+	return true, s.repo.Announcement.Finish(announcement.ID, model.AnnouncementStatusSent, time.Now())
+}
+
+// makeNotifications renders the announcement for each user
+func (s *AnnouncementService) makeNotifications(renderer *NotificationRenderer, announcement *model.Announcement, users []model.User) ([]model.Notification, error) {
+	now := time.Now()
+	result := make([]model.Notification, len(users))
+	for i, user := range users {
+		rendered, err := renderer.Render(user.Locale, map[string]string{
+			"Username":    user.Username,
+			"DisplayName": user.DisplayName,
+			"Title":       announcement.Title,
+			"Body":        announcement.Body,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result[i] = model.Notification{
+			UserID:         user.ID,
+			StreamID:       announcement.StreamID,
+			AnnouncementID: &announcement.ID,
+			Type:           model.NotificationTypeAnnouncement,
+			Title:          rendered.Title,
+			Content:        rendered.Content,
+			CreatedAt:      now,
+		}
+	}
+	return result, nil
+}
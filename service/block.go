@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+)
+
+var (
+	ErrUserBlockSelf     = errors.New("users can't block themselves")
+	ErrUserBlockExists   = errors.New("user is already blocked by the user")
+	ErrUserBlockNotFound = errors.New("user is not blocked by the user")
+)
+
+// UserBlockService manages blocks between users of be-api, which hide users from each other there
+type UserBlockService struct {
+	repo *repository.Repository
+}
+
+func newUserBlockService(repo *repository.Repository) *UserBlockService {
+	return &UserBlockService{
+		repo: repo,
+	}
+}
+
+func (s *UserBlockService) GetBlocks(userID uint, req *dto.UserBlockQuery) (*utils.PaginationModel[dto.UserBlockDTO], error) {
+	pagination, err := s.repo.Block.Page(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.UserBlockDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.BlockedList) dto.UserBlockDTO {
+		other := &e.BlockedUser
+		if req.Direction == dto.USER_BLOCK_DIRECTION_BLOCKED_BY {
+			other = &e.User
+		}
+		return dto.UserBlockDTO{User: toUserSummaryDto(other), BlockedAt: e.BlockedAt}
+	})
+	return result, nil
+}
+
+// GetMostBlocked ranks users by how many users blocked them, a signal of abuse
+func (s *UserBlockService) GetMostBlocked(req *dto.MostBlockedQuery) (*utils.PaginationModel[dto.MostBlockedDTO], error) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -dto.MOST_BLOCKED_DEFAULT_DAYS)
+	if req.From != 0 {
+		from = time.Unix(req.From, 0)
+	}
+	if req.To != 0 {
+		to = time.Unix(req.To, 0)
+	}
+
+	pagination, err := s.repo.Block.MostBlocked(from, to, req)
+	if err != nil {
+		return nil, err
+	}
+
+	users := map[uint]*model.User{}
+	if len(pagination.Page) > 0 {
+		found, err := s.repo.Block.FindUsers(utils.Map(pagination.Page, func(e repository.MostBlockedRow) uint {
+			return e.BlockedUserID
+		}))
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			users[found[i].ID] = &found[i]
+		}
+	}
+
+	result := new(utils.PaginationModel[dto.MostBlockedDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e repository.MostBlockedRow) dto.MostBlockedDTO {
+		item := dto.MostBlockedDTO{Blocks: e.Blocks, TotalBlocks: e.TotalBlocks, LastBlockedAt: e.LastBlockedAt}
+		if user := users[e.BlockedUserID]; user != nil {
+			item.User = toUserSummaryDto(user)
+			item.User.Status = user.Status
+			item.User.Strikes = user.Strikes
+		}
+		return item
+	})
+	return result, nil
+}
+
+// Block blocks blockedUserID on behalf of the user
+func (s *UserBlockService) Block(userID, blockedUserID uint) error {
+	if userID == blockedUserID {
+		return ErrUserBlockSelf
+	}
+	created, err := s.repo.Block.Create(userID, blockedUserID)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return ErrUserBlockExists
+	}
+	return nil
+}
+
+// Unblock removes the block of blockedUserID by the user
+func (s *UserBlockService) Unblock(userID, blockedUserID uint) error {
+	deleted, err := s.repo.Block.Delete(userID, blockedUserID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrUserBlockNotFound
+	}
+	return nil
+}
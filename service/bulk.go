@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"slices"
+	"time"
+)
+
+const BULK_MAX_ITEMS = 1000
+
+var (
+	ErrBulkNoTarget     = errors.New("ids or filter is required")
+	ErrBulkTooManyItems = fmt.Errorf("bulk operations are limited to %d items", BULK_MAX_ITEMS)
+	ErrBulkNoMatch      = errors.New("no items matched")
+)
+
+// BulkService runs admin actions on many users or streams in a job, every item gets its own result
+type BulkService struct {
+	repo     *repository.Repository
+	stream   *StreamService
+	job      *JobService
+	trash    *TrashService
+	template *NotificationTemplateService
+}
+
+func newBulkService(repo *repository.Repository, stream *StreamService, job *JobService, trash *TrashService, template *NotificationTemplateService) *BulkService {
+	return &BulkService{
+		repo:     repo,
+		stream:   stream,
+		job:      job,
+		trash:    trash,
+		template: template,
+	}
+}
+
+func resolveBulkIDs(ids []uint, hasFilter bool, find func(limit int) ([]uint, error)) ([]uint, error) {
+	if len(ids) == 0 && !hasFilter {
+		return nil, ErrBulkNoTarget
+	}
+	if len(ids) == 0 {
+		var err error
+		if ids, err = find(BULK_MAX_ITEMS); err != nil {
+			return nil, err
+		}
+	}
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if len(ids) > BULK_MAX_ITEMS {
+		return nil, ErrBulkTooManyItems
+	}
+	if len(ids) == 0 {
+		return nil, ErrBulkNoMatch
+	}
+	return ids, nil
+}
+
+// EnqueueUsers resolves the filter of req to ids and queues a model.JobTypeBulkUsers job
+func (s *BulkService) EnqueueUsers(req *dto.BulkUserRequest, performedByID uint) (*model.Job, error) {
+	ids, err := resolveBulkIDs(req.IDs, req.Filter != nil, func(limit int) ([]uint, error) {
+		return s.repo.User.FindIDs(req.Filter, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.job.Enqueue(model.JobTypeBulkUsers, dto.BulkUsersPayload{
+		Action:        req.Action,
+		IDs:           ids,
+		Reason:        req.Reason,
+		RoleType:      req.RoleType,
+		PerformedByID: performedByID,
+	}, 1, performedByID)
+}
+
+// EnqueueStreams resolves the filter of req to ids and queues a model.JobTypeBulkStreams job
+func (s *BulkService) EnqueueStreams(req *dto.BulkStreamRequest, performedByID uint) (*model.Job, error) {
+	ids, err := resolveBulkIDs(req.IDs, req.Filter != nil, func(limit int) ([]uint, error) {
+		return s.repo.Stream.FindLiveStreamBroadCastIDs(req.Filter, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.job.Enqueue(model.JobTypeBulkStreams, dto.BulkStreamsPayload{
+		Action:        req.Action,
+		IDs:           ids,
+		CategoryIDs:   req.CategoryIDs,
+		PerformedByID: performedByID,
+	}, 1, performedByID)
+}
+
+// finish saves the per-item results and the grouped audit entry, partial results of canceled jobs are saved too
+func (s *BulkService) finish(job *model.Job, result *dto.BulkResult, action model.AdminAction, details string) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("Job %d failed to encode result: %v\n", job.ID, err)
+	} else if err := s.repo.Job.SaveResult(job.ID, string(data)); err != nil {
+		log.Printf("Job %d failed to save result: %v\n", job.ID, err)
+	}
+
+	if job.CreatedByID == nil {
+		return
+	}
+	adminLog := &model.AdminLog{
+		UserID:  *job.CreatedByID,
+		Action:  string(action),
+		Details: fmt.Sprintf("%s in job %d: %d of %d succeeded, %d failed, %d skipped.", details, job.ID, result.Succeeded, result.Total, result.Failed, result.Skipped),
+	}
+	if err := s.repo.Admin.Create(adminLog); err != nil {
+		log.Printf("Job %d failed to create admin log: %v\n", job.ID, err)
+	}
+}
+
+// UsersJob runs a model.JobTypeBulkUsers job, users are notified through the client like the single user endpoints
+func (s *BulkService) UsersJob(clientHost string, purgeDelay time.Duration) JobHandler {
+	return func(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+		payload, err := decodeJobPayload[dto.BulkUsersPayload](job)
+		if err != nil {
+			return err
+		}
+		performer, err := s.repo.User.FindByID(int(payload.PerformedByID))
+		if err != nil {
+			return err
+		}
+		if performer == nil {
+			return errors.New("admin of the job not found")
+		}
+		token, _, err := utils.GenerateAccessToken(performer.ID, performer.Username, performer.Email, performer.Role.Type)
+		if err != nil {
+			return err
+		}
+
+		var role *model.Role
+		if payload.Action == dto.BULK_ACTION_CHANGE_ROLE {
+			if performer.Role.Type != model.SUPPERADMINROLE {
+				return errors.New("only super admin can change role")
+			}
+			if role, err = s.repo.Role.FindByType(payload.RoleType); err != nil {
+				return err
+			}
+		}
+
+		result := &dto.BulkResult{Action: payload.Action, Total: len(payload.IDs)}
+		defer s.finish(job, result, model.BulkUpdateUsers, fmt.Sprintf("%s bulk %s %d users", performer.Username, payload.Action, len(payload.IDs)))
+
+		for i, id := range payload.IDs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			status, err := s.applyUserAction(payload, performer, role, id, clientHost, token, purgeDelay)
+			result.Add(id, status, err)
+			report(uint((i+1)*100/len(payload.IDs)), fmt.Sprintf("%d of %d users", i+1, len(payload.IDs)))
+		}
+		return nil
+	}
+}
+
+func (s *BulkService) applyUserAction(payload *dto.BulkUsersPayload, performer *model.User, role *model.Role, id uint, clientHost, token string, purgeDelay time.Duration) (string, error) {
+	user, err := s.repo.User.FindByID(int(id))
+	if err != nil {
+		return dto.BULK_ITEM_FAILED, err
+	}
+	if user == nil {
+		return dto.BULK_ITEM_FAILED, errors.New("not found")
+	}
+	if user.ID == performer.ID {
+		return dto.BULK_ITEM_SKIPPED, errors.New("admin can't change itself")
+	}
+	if user.Role.Type == model.SUPPERADMINROLE {
+		return dto.BULK_ITEM_FAILED, errors.New("super admin can't be changed")
+	}
+	if performer.Role.Type == model.ADMINROLE && user.Role.Type == model.ADMINROLE {
+		return dto.BULK_ITEM_FAILED, errors.New("admin can't change admin")
+	}
+
+	notify := func(notification *dto.RenderedNotificationDTO) {
+		_, err := utils.PostAsync[dto.CommonResponseDTO](fmt.Sprintf("%s/api/notification/blocked-deleted", clientHost), token, map[string]interface{}{"user_id": user.ID, "type": notification.Type, "title": notification.Title, "content": notification.Content})
+		if err != nil {
+			log.Printf("Failed to notify user %d: %v\n", user.ID, err)
+		}
+	}
+
+	switch payload.Action {
+	case dto.BULK_ACTION_BLOCK:
+		if user.Status == model.BLOCKED && user.SuspendedUntil == nil {
+			return dto.BULK_ITEM_SKIPPED, nil
+		}
+		notification, err := s.template.RenderBlocked(user, payload.Reason, nil)
+		if err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		// suspended users are blocked permanently
+		if err := s.repo.User.ChangeStatus(user.ID, model.BLOCKED, payload.Reason, performer.ID); err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		notify(notification)
+	case dto.BULK_ACTION_REACTIVATE:
+		if user.Status != model.BLOCKED {
+			return dto.BULK_ITEM_SKIPPED, nil
+		}
+		// Updates skips zero values, so the reason is cleared explicitly
+		if err := s.repo.User.ChangeStatus(user.ID, model.OFFLINE, "", performer.ID); err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+	case dto.BULK_ACTION_DELETE:
+		notification, err := s.template.Render(model.NotificationTypeDeleted, user.Locale, map[string]string{
+			"Username":    user.Username,
+			"DisplayName": user.DisplayName,
+		})
+		if err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		if _, err := s.trash.DeleteUser(user.ID, performer.ID, purgeDelay); err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		notify(notification)
+	case dto.BULK_ACTION_CHANGE_ROLE:
+		if user.RoleID == role.ID {
+			return dto.BULK_ITEM_SKIPPED, nil
+		}
+		user.RoleID = role.ID
+		user.Role = *role
+		user.UpdatedByID = &performer.ID
+		if err := s.repo.User.Update(user); err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+	default:
+		return dto.BULK_ITEM_FAILED, fmt.Errorf("unknown action %s", payload.Action)
+	}
+	return dto.BULK_ITEM_SUCCEEDED, nil
+}
+
+// StreamsJob runs a model.JobTypeBulkStreams job, deleted streams go to trash
+func (s *BulkService) StreamsJob(purgeDelay time.Duration) JobHandler {
+	return func(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+		payload, err := decodeJobPayload[dto.BulkStreamsPayload](job)
+		if err != nil {
+			return err
+		}
+		performer, err := s.repo.User.FindByID(int(payload.PerformedByID))
+		if err != nil {
+			return err
+		}
+		if performer == nil {
+			return errors.New("admin of the job not found")
+		}
+
+		result := &dto.BulkResult{Action: payload.Action, Total: len(payload.IDs)}
+		defer s.finish(job, result, model.BulkUpdateStreams, fmt.Sprintf("%s bulk %s %d streams", performer.Username, payload.Action, len(payload.IDs)))
+
+		for i, id := range payload.IDs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			status, err := s.applyStreamAction(ctx, payload, id, purgeDelay)
+			result.Add(id, status, err)
+			report(uint((i+1)*100/len(payload.IDs)), fmt.Sprintf("%d of %d streams", i+1, len(payload.IDs)))
+		}
+		return nil
+	}
+}
+
+func (s *BulkService) applyStreamAction(ctx context.Context, payload *dto.BulkStreamsPayload, id uint, purgeDelay time.Duration) (string, error) {
+	switch payload.Action {
+	case dto.BULK_ACTION_DELETE:
+		stream, err := s.stream.GetStreamByID(id)
+		if err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		if err := s.stream.CheckDeletable(ctx, stream); err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		if _, err := s.trash.DeleteStream(id, payload.PerformedByID, purgeDelay); err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		return dto.BULK_ITEM_SUCCEEDED, nil
+	case dto.BULK_ACTION_END_LIVE:
+		stream, err := s.stream.GetStreamByID(id)
+		if err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		if stream.Status != model.STARTED {
+			return dto.BULK_ITEM_SKIPPED, ErrStreamNotLive
+		}
+		isEndingLive, err := s.stream.IsEndingLive(ctx, id)
+		if err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		if isEndingLive {
+			return dto.BULK_ITEM_SKIPPED, nil
+		}
+		if err := s.stream.EndLivByRedis(ctx, id); err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		return dto.BULK_ITEM_SUCCEEDED, nil
+	case dto.BULK_ACTION_RECATEGORIZE:
+		if err := s.stream.UpdateCategories(id, payload.CategoryIDs); err != nil {
+			return dto.BULK_ITEM_FAILED, err
+		}
+		return dto.BULK_ITEM_SUCCEEDED, nil
+	}
+	return dto.BULK_ITEM_FAILED, fmt.Errorf("unknown action %s", payload.Action)
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_SCHEDULE_DURATION = time.Hour
+
+	maxCalendarDays = 92
+	// feeds show a bit of history for external calendars
+	feedPastDays   = 30
+	feedFutureDays = 90
+)
+
+var (
+	ErrScheduleConflict     = errors.New("schedule conflicts with other streams")
+	ErrInvalidCalendarRange = fmt.Errorf("calendar range must be at most %d days", maxCalendarDays)
+)
+
+// ScheduleConflictError lists streams overlapping the requested schedule
+type ScheduleConflictError struct {
+	Conflicts []dto.ScheduleConflictDTO
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return fmt.Sprintf("%s: %d conflicts", ErrScheduleConflict, len(e.Conflicts))
+}
+
+func (e *ScheduleConflictError) Unwrap() error {
+	return ErrScheduleConflict
+}
+
+// ScheduleLimits are read from schedule config
+type ScheduleLimits struct {
+	DefaultDuration time.Duration // used when video duration is unknown
+	CategorySlots   int           // streams of a category at the same time, 0 is unlimited
+	MaxConcurrent   int           // scheduled streams at the same time, 0 is unlimited
+}
+
+func (l ScheduleLimits) duration(videoDuration uint) time.Duration {
+	if videoDuration > 0 {
+		return time.Duration(videoDuration) * time.Second
+	}
+	if l.DefaultDuration > 0 {
+		return l.DefaultDuration
+	}
+	return DEFAULT_SCHEDULE_DURATION
+}
+
+// scheduleSlot is a stream about to be scheduled, the stream itself or its series are excluded on update
+type scheduleSlot struct {
+	UserID              uint
+	CategoryIDs         []uint
+	StartAt             time.Time
+	Duration            time.Duration
+	ExcludeStreamID     uint
+	ExcludeRecurrenceID uint
+}
+
+// checkScheduleConflicts compares slots with created streams only,
+// occurrences of series are created recurrence_horizon ahead which covers the schedule window, see RecurrenceHorizon.
+func checkScheduleConflicts(repo *repository.Repository, slots []scheduleSlot, limits ScheduleLimits) error {
+	var conflicts []dto.ScheduleConflictDTO
+	for _, slot := range slots {
+		overlapping, err := repo.Calendar.GetOverlappingScheduleStreams(slot.StartAt, slot.StartAt.Add(slot.Duration), limits.duration(0), slot.ExcludeStreamID, slot.ExcludeRecurrenceID)
+		if err != nil {
+			return err
+		}
+		if len(overlapping) == 0 {
+			continue
+		}
+
+		categories, err := repo.Calendar.GetCategoriesByStreamIDs(utils.Map(overlapping, func(e model.ScheduleStream) uint {
+			return e.StreamID
+		}))
+		if err != nil {
+			return err
+		}
+
+		toConflict := func(reason string, v model.ScheduleStream, categoryID uint) dto.ScheduleConflictDTO {
+			return dto.ScheduleConflictDTO{
+				Reason:       reason,
+				OccurrenceAt: slot.StartAt,
+				StreamID:     v.StreamID,
+				Title:        v.Stream.Title,
+				UserID:       v.Stream.UserID,
+				CategoryID:   categoryID,
+				StartAt:      v.ScheduledAt,
+				EndAt:        v.ScheduledAt.Add(limits.duration(v.VideoDuration)),
+			}
+		}
+
+		for _, v := range overlapping {
+			if v.Stream.UserID == slot.UserID {
+				conflicts = append(conflicts, toConflict(dto.CONFLICT_REASON_STREAMER, v, 0))
+			}
+		}
+
+		if limits.CategorySlots > 0 {
+			for _, categoryID := range slot.CategoryIDs {
+				var inCategory []model.ScheduleStream
+				for _, v := range overlapping {
+					if slices.ContainsFunc(categories[v.StreamID], func(c model.Category) bool { return c.ID == categoryID }) {
+						inCategory = append(inCategory, v)
+					}
+				}
+				if len(inCategory) < limits.CategorySlots {
+					continue
+				}
+				for _, v := range inCategory {
+					conflicts = append(conflicts, toConflict(dto.CONFLICT_REASON_CATEGORY, v, categoryID))
+				}
+			}
+		}
+
+		if limits.MaxConcurrent > 0 && len(overlapping) >= limits.MaxConcurrent {
+			for _, v := range overlapping {
+				conflicts = append(conflicts, toConflict(dto.CONFLICT_REASON_CONCURRENCY, v, 0))
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return &ScheduleConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+type CalendarService struct {
+	repo *repository.Repository
+}
+
+func newCalendarService(repo *repository.Repository) *CalendarService {
+	return &CalendarService{
+		repo: repo,
+	}
+}
+
+// occurrences returns scheduled streams and occurrences of series which are not created yet in [from, to), ordered
+func (s *CalendarService) occurrences(from, to time.Time, userID, categoryID uint, limits ScheduleLimits) ([]dto.CalendarOccurrenceDTO, error) {
+	scheduleStreams, err := s.repo.Calendar.GetScheduleStreams(from, to, limits.duration(0), userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.repo.Calendar.GetCategoriesByStreamIDs(utils.Map(scheduleStreams, func(e model.ScheduleStream) uint {
+		return e.StreamID
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	toCategoryDtos := func(categories []model.Category) []dto.CategoryDTO {
+		return utils.Map(categories, func(e model.Category) dto.CategoryDTO {
+			return dto.CategoryDTO{ID: e.ID, Name: e.Name, CreatedAt: e.CreatedAt}
+		})
+	}
+	result := utils.Map(scheduleStreams, func(e model.ScheduleStream) dto.CalendarOccurrenceDTO {
+		return dto.CalendarOccurrenceDTO{
+			StreamID:      e.StreamID,
+			RecurrenceID:  e.RecurrenceID,
+			Title:         e.Stream.Title,
+			Status:        e.Stream.Status,
+			User:          toUserSummaryDto(&e.Stream.User),
+			Categories:    toCategoryDtos(categories[e.StreamID]),
+			StartAt:       e.ScheduledAt,
+			EndAt:         e.ScheduledAt.Add(limits.duration(e.VideoDuration)),
+			VideoDuration: e.VideoDuration,
+		}
+	})
+
+	recurrences, err := s.repo.Calendar.GetRecurrences(userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range recurrences {
+		recurrence := &recurrences[i]
+		rule, err := parseRecurrenceRule(recurrence.RRule, recurrence.Timezone)
+		if err != nil {
+			log.Printf("Recurrence %d has invalid rule: %v\n", recurrence.ID, err)
+			continue
+		}
+
+		// created occurrences are already in scheduleStreams
+		start := from
+		if start.Before(now) {
+			start = now
+		}
+		if recurrence.MaterializedUntil.Valid && recurrence.MaterializedUntil.Time.After(start) {
+			start = recurrence.MaterializedUntil.Time
+		}
+		if !start.Before(to) {
+			continue
+		}
+
+		for _, occurrence := range rule.between(recurrence, start, to) {
+			result = append(result, dto.CalendarOccurrenceDTO{
+				RecurrenceID:  &recurrence.ID,
+				Title:         recurrence.Title,
+				Status:        model.UPCOMING,
+				User:          toUserSummaryDto(&recurrence.User),
+				Categories:    toCategoryDtos(recurrence.Categories),
+				StartAt:       occurrence,
+				EndAt:         occurrence.Add(limits.duration(recurrence.VideoDuration)),
+				VideoDuration: recurrence.VideoDuration,
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartAt.Before(result[j].StartAt)
+	})
+	return result, nil
+}
+
+// GetCalendar returns occurrences from req.From to req.To days grouped by start day in req.Timezone
+func (s *CalendarService) GetCalendar(req *dto.CalendarQuery, limits ScheduleLimits) ([]dto.CalendarDayDTO, error) {
+	location := time.UTC
+	if req.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, err
+		}
+	}
+
+	from, err := time.ParseInLocation(time.DateOnly, req.From, location)
+	if err != nil {
+		return nil, err
+	}
+	to, err := time.ParseInLocation(time.DateOnly, req.To, location)
+	if err != nil {
+		return nil, err
+	}
+	// to is inclusive
+	to = to.AddDate(0, 0, 1)
+	if !from.Before(to) || to.Sub(from) > maxCalendarDays*24*time.Hour {
+		return nil, ErrInvalidCalendarRange
+	}
+
+	occurrences, err := s.occurrences(from, to, req.UserID, req.CategoryID, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []dto.CalendarDayDTO{}
+	for _, occurrence := range occurrences {
+		date := occurrence.StartAt.In(location).Format(time.DateOnly)
+		if len(result) == 0 || result[len(result)-1].Date != date {
+			result = append(result, dto.CalendarDayDTO{Date: date})
+		}
+		result[len(result)-1].Occurrences = append(result[len(result)-1].Occurrences, occurrence)
+	}
+	return result, nil
+}
+
+// GetFeed builds an iCalendar feed of a streamer or a category
+func (s *CalendarService) GetFeed(userID, categoryID uint, limits ScheduleLimits) (string, error) {
+	var name string
+	if userID != 0 {
+		user, err := s.repo.User.FindByID(int(userID))
+		if err != nil {
+			return "", err
+		}
+		if user == nil {
+			return "", gorm.ErrRecordNotFound
+		}
+		name = fmt.Sprintf("%s streams", user.DisplayName)
+	} else {
+		category, err := s.repo.Category.FindByID(categoryID)
+		if err != nil {
+			return "", err
+		}
+		name = fmt.Sprintf("%s streams", category.Name)
+	}
+
+	now := time.Now()
+	occurrences, err := s.occurrences(now.AddDate(0, 0, -feedPastDays), now.AddDate(0, 0, feedFutureDays), userID, categoryID, limits)
+	if err != nil {
+		return "", err
+	}
+
+	events := utils.Map(occurrences, func(e dto.CalendarOccurrenceDTO) utils.ICalEvent {
+		event := utils.ICalEvent{
+			UID:        fmt.Sprintf("stream-%d@be-live-admin", e.StreamID),
+			Summary:    e.Title,
+			Categories: utils.Map(e.Categories, func(c dto.CategoryDTO) string { return c.Name }),
+			Start:      e.StartAt,
+			End:        e.EndAt,
+			Status:     "CONFIRMED",
+		}
+		if e.User.DisplayName != "" {
+			event.Description = fmt.Sprintf("Streamed by %s", e.User.DisplayName)
+		}
+		// the stream of this occurrence is created later
+		if e.StreamID == 0 && e.RecurrenceID != nil {
+			event.UID = fmt.Sprintf("recurrence-%d-%d@be-live-admin", *e.RecurrenceID, e.StartAt.Unix())
+			event.Status = "TENTATIVE"
+		}
+		return event
+	})
+	return utils.BuildICalendar(name, events), nil
+}
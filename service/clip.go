@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"os"
+	"time"
+)
+
+const DEFAULT_CLIP_TIMEOUT = time.Hour
+
+var (
+	ErrClipStreamNotEnded = errors.New("clips can only be cut from ended streams")
+	ErrInvalidClipRange   = errors.New("end_offset must be after start_offset and within the recording")
+	ErrClipProcessing     = errors.New("clip is being processed")
+)
+
+type ClipService struct {
+	repo *repository.Repository
+	job  *JobService
+}
+
+func newClipService(repo *repository.Repository, job *JobService) *ClipService {
+	return &ClipService{
+		repo: repo,
+		job:  job,
+	}
+}
+
+func toClipDto(clip *model.Clip, apiURL string) dto.ClipDTO {
+	result := dto.ClipDTO{
+		ID:          clip.ID,
+		StreamID:    clip.StreamID,
+		Title:       clip.Title,
+		StartOffset: clip.StartOffset,
+		EndOffset:   clip.EndOffset,
+		Status:      clip.Status,
+		Size:        clip.Size,
+		Duration:    clip.Duration,
+		Error:       clip.Error,
+		CreatedAt:   clip.CreatedAt,
+		UpdatedAt:   clip.UpdatedAt,
+	}
+	if clip.Status == model.ClipStatusReady {
+		result.URL = utils.MakeClipURL(apiURL, clip.FileName)
+	}
+	if clip.CreatedBy.ID != 0 {
+		result.CreatedBy = &dto.UserResponseDTO{ID: clip.CreatedBy.ID, Username: clip.CreatedBy.Username, DisplayName: clip.CreatedBy.DisplayName}
+	}
+	return result
+}
+
+func (s *ClipService) GetClips(streamID uint, apiURL string) ([]dto.ClipDTO, error) {
+	clips, err := s.repo.Clip.FindByStreamID(streamID)
+	if err != nil {
+		return nil, err
+	}
+	return utils.Map(clips, func(e model.Clip) dto.ClipDTO {
+		return toClipDto(&e, apiURL)
+	}), nil
+}
+
+func (s *ClipService) GetClip(streamID, id uint, apiURL string) (*dto.ClipDTO, error) {
+	clip, err := s.repo.Clip.FindByID(streamID, id)
+	if err != nil {
+		return nil, err
+	}
+	result := toClipDto(clip, apiURL)
+	return &result, nil
+}
+
+// Create saves a pending clip, recordingDuration is in milliseconds and 0 when it's unknown
+func (s *ClipService) Create(streamID uint, req *dto.ClipRequest, recordingDuration uint) (*model.Clip, error) {
+	stream, err := s.repo.Stream.GetByID(streamID)
+	if err != nil {
+		return nil, err
+	}
+	if stream.Status != model.ENDED {
+		return nil, ErrClipStreamNotEnded
+	}
+	if req.EndOffset <= req.StartOffset || (recordingDuration > 0 && req.EndOffset > recordingDuration) {
+		return nil, ErrInvalidClipRange
+	}
+
+	clip := &model.Clip{
+		StreamID:    stream.ID,
+		Title:       req.Title,
+		StartOffset: req.StartOffset,
+		EndOffset:   req.EndOffset,
+		FileName:    req.FileName,
+		Status:      model.ClipStatusPending,
+		CreatedByID: req.CreatedByID,
+	}
+	if err := s.repo.Clip.Create(clip); err != nil {
+		return nil, err
+	}
+	return clip, nil
+}
+
+// Process cuts the clip from the recording and saves the result, the size is added to storage usage of ownerID
+func (s *ClipService) Process(ctx context.Context, clip *model.Clip, clipper Clipper, recordingPath, clipPath string, ownerID uint) {
+	if err := s.repo.Clip.UpdateStatus(clip.ID, model.ClipStatusProcessing); err != nil {
+		log.Printf("Clip %d: %v\n", clip.ID, err)
+	}
+
+	start := time.Duration(clip.StartOffset) * time.Millisecond
+	duration := time.Duration(clip.EndOffset-clip.StartOffset) * time.Millisecond
+	if err := clipper.Cut(ctx, recordingPath, clipPath, start, duration); err != nil {
+		log.Printf("Clip %d failed: %v\n", clip.ID, err)
+		if err := os.Remove(clipPath); err != nil && !os.IsNotExist(err) {
+			log.Println(err)
+		}
+		clip.Status = model.ClipStatusFailed
+		clip.Error = err.Error()
+	} else {
+		clip.Status = model.ClipStatusReady
+		clip.Duration = clip.EndOffset - clip.StartOffset
+		clip.Size, _ = utils.GetfileSize(clipPath)
+		if err := s.repo.Storage.AddUsage(ownerID, model.StorageFileTypeRecording, clip.Size); err != nil {
+			log.Println(err)
+		}
+	}
+
+	if err := s.repo.Clip.SaveResult(clip); err != nil {
+		log.Printf("Clip %d: %v\n", clip.ID, err)
+	}
+}
+
+// CutClipJob runs model.JobTypeCutClip jobs with clipper, each cut is limited to timeout
+func (s *ClipService) CutClipJob(clipper Clipper, timeout time.Duration) JobHandler {
+	return func(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+		payload, err := decodeJobPayload[dto.CutClipPayload](job)
+		if err != nil {
+			return err
+		}
+		clip, err := s.repo.Clip.Get(payload.ClipID)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		report(0, "cutting")
+		s.Process(ctx, clip, clipper, payload.RecordingPath, payload.ClipPath, payload.OwnerID)
+		if clip.Status == model.ClipStatusFailed {
+			return errors.New(clip.Error)
+		}
+		return nil
+	}
+}
+
+// Delete removes the row and queues removal of the clip file in clipsFolder, the size of ready clips is untracked from ownerID.
+// Clips processed longer than timeout were interrupted and can be deleted.
+func (s *ClipService) Delete(streamID, id, ownerID uint, clipsFolder string, timeout time.Duration, deletedByID uint) (*model.Clip, *model.Job, error) {
+	clip, err := s.repo.Clip.FindByID(streamID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if clip.Status == model.ClipStatusProcessing && time.Since(clip.UpdatedAt) < timeout {
+		return nil, nil, ErrClipProcessing
+	}
+
+	file := dto.RemoveFileEntry{Path: utils.MakeVideoPath(clipsFolder, clip.FileName)}
+	if clip.Status == model.ClipStatusReady {
+		file.UserID, file.FileType = ownerID, model.StorageFileTypeRecording
+	}
+	var job *model.Job
+	// the file is only removed once the delete commits
+	if err := s.repo.Transaction(func(repo *repository.Repository) error {
+		if err := repo.Clip.Delete(clip.ID); err != nil {
+			return err
+		}
+		job, err = s.job.EnqueueWith(repo, model.JobTypeRemoveFiles, dto.RemoveFilesPayload{Files: []dto.RemoveFileEntry{file}}, 0, deletedByID)
+		return err
+	}); err != nil {
+		return nil, nil, err
+	}
+	return clip, job, nil
+}
+
+// GetClipsOfStream is used to remove clip files with the stream
+func (s *ClipService) GetClipsOfStream(streamID uint) ([]model.Clip, error) {
+	return s.repo.Clip.FindByStreamID(streamID)
+}
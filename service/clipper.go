@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Clipper cuts [start, start+duration) of a recording into output
+type Clipper interface {
+	Cut(ctx context.Context, input, output string, start, duration time.Duration) error
+}
+
+const (
+	CLIPPER_START_PLACEHOLDER    = "{start}"
+	CLIPPER_DURATION_PLACEHOLDER = "{duration}"
+)
+
+// stream copy starts at the keyframe before {start}, it's fast and keeps the quality
+var DefaultFFmpegClipArgs = []string{
+	"-ss", CLIPPER_START_PLACEHOLDER, "-i", PUSHER_INPUT_PLACEHOLDER, "-t", CLIPPER_DURATION_PLACEHOLDER,
+	"-c", "copy", "-avoid_negative_ts", "make_zero", "-movflags", "+faststart", "-y", PUSHER_OUTPUT_PLACEHOLDER,
+}
+
+var DefaultFFmpegClipReencodeArgs = []string{
+	"-ss", CLIPPER_START_PLACEHOLDER, "-i", PUSHER_INPUT_PLACEHOLDER, "-t", CLIPPER_DURATION_PLACEHOLDER,
+	"-c:v", "libx264", "-preset", "veryfast", "-c:a", "aac", "-movflags", "+faststart", "-y", PUSHER_OUTPUT_PLACEHOLDER,
+}
+
+type FFmpegClipper struct {
+	binary       string
+	args         []string
+	reencodeArgs []string
+}
+
+// NewFFmpegClipper runs binary with args and falls back to reencodeArgs when stream copy fails
+func NewFFmpegClipper(binary string, args, reencodeArgs []string) *FFmpegClipper {
+	if binary == "" {
+		binary = "ffmpeg"
+	}
+	if len(args) == 0 {
+		args = DefaultFFmpegClipArgs
+	}
+	if len(reencodeArgs) == 0 {
+		reencodeArgs = DefaultFFmpegClipReencodeArgs
+	}
+	return &FFmpegClipper{
+		binary:       binary,
+		args:         args,
+		reencodeArgs: reencodeArgs,
+	}
+}
+
+func (c *FFmpegClipper) Cut(ctx context.Context, input, output string, start, duration time.Duration) error {
+	replacer := strings.NewReplacer(
+		PUSHER_INPUT_PLACEHOLDER, input,
+		PUSHER_OUTPUT_PLACEHOLDER, output,
+		CLIPPER_START_PLACEHOLDER, fmt.Sprintf("%.3f", start.Seconds()),
+		CLIPPER_DURATION_PLACEHOLDER, fmt.Sprintf("%.3f", duration.Seconds()),
+	)
+
+	err := runFFmpeg(ctx, c.binary, c.args, replacer)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	log.Printf("Stream copy of %s failed, re-encoding: %v\n", input, err)
+	if err := os.Remove(output); err != nil && !os.IsNotExist(err) {
+		log.Println(err)
+	}
+	return runFFmpeg(ctx, c.binary, c.reencodeArgs, replacer)
+}
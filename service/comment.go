@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gitlab/live/be-live-admin/cache"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"time"
+)
+
+var (
+	ErrCommentHidden    = errors.New("comment is already hidden")
+	ErrCommentNotHidden = errors.New("comment is not hidden")
+	ErrCommentTimeline  = errors.New("timeline is too long for the interval, use a larger one")
+)
+
+// CommentService lets moderators review comments, be-api is told to update live chat through redis
+type CommentService struct {
+	repo       *repository.Repository
+	redisStore cache.RedisStore
+}
+
+func newCommentService(repo *repository.Repository, redisStore cache.RedisStore) *CommentService {
+	return &CommentService{
+		repo:       repo,
+		redisStore: redisStore,
+	}
+}
+
+func toCommentDto(comment *model.Comment) dto.CommentDTO {
+	result := dto.CommentDTO{
+		ID:           comment.ID,
+		StreamID:     comment.StreamID,
+		StreamTitle:  comment.Stream.Title,
+		User:         toUserSummaryDto(&comment.User),
+		Comment:      comment.Comment,
+		Flagged:      comment.Flagged,
+		FlagReason:   comment.FlagReason,
+		Hidden:       comment.HiddenAt.Valid,
+		HiddenReason: comment.HiddenReason,
+		HiddenBy:     toUserSummaryDto(comment.HiddenBy),
+		CreatedAt:    comment.CreatedAt,
+		UpdatedAt:    comment.UpdatedAt,
+	}
+	if comment.HiddenAt.Valid {
+		result.HiddenAt = &comment.HiddenAt.Time
+	}
+	return result
+}
+
+func (s *CommentService) GetComments(req *dto.CommentQuery) (*utils.PaginationModel[dto.CommentDTO], error) {
+	pagination, err := s.repo.Comment.Page(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.CommentDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.Comment) dto.CommentDTO {
+		return toCommentDto(&e)
+	})
+	return result, nil
+}
+
+// FindByID returns nil when the comment doesn't exist
+func (s *CommentService) FindByID(id uint) (*model.Comment, error) {
+	return s.repo.Comment.FindByID(id)
+}
+
+func (s *CommentService) ToDto(comment *model.Comment) dto.CommentDTO {
+	return toCommentDto(comment)
+}
+
+// publish tells be-api to update live chat, the change is saved already so failures are only logged
+func (s *CommentService) publish(ctx context.Context, action string, comment *model.Comment) {
+	data, err := json.Marshal(dto.CommentModerationEvent{Action: action, CommentID: comment.ID, StreamID: comment.StreamID})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if err := s.redisStore.Publish(ctx, cache.CHANNEL_COMMENT_MODERATION, string(data)); err != nil {
+		log.Printf("Failed to publish %s event of comment %d: %v\n", action, comment.ID, err)
+	}
+}
+
+func (s *CommentService) Hide(ctx context.Context, comment *model.Comment, hiddenByID uint, reason string) error {
+	hidden, err := s.repo.Comment.Hide(comment.ID, hiddenByID, reason)
+	if err != nil {
+		return err
+	}
+	if !hidden {
+		return ErrCommentHidden
+	}
+	s.publish(ctx, dto.COMMENT_EVENT_HIDDEN, comment)
+	return nil
+}
+
+func (s *CommentService) Unhide(ctx context.Context, comment *model.Comment) error {
+	unhidden, err := s.repo.Comment.Unhide(comment.ID)
+	if err != nil {
+		return err
+	}
+	if !unhidden {
+		return ErrCommentNotHidden
+	}
+	s.publish(ctx, dto.COMMENT_EVENT_UNHIDDEN, comment)
+	return nil
+}
+
+func (s *CommentService) Delete(ctx context.Context, comment *model.Comment) error {
+	if err := s.repo.Comment.Delete(comment.ID); err != nil {
+		return err
+	}
+	s.publish(ctx, dto.COMMENT_EVENT_DELETED, comment)
+	return nil
+}
+
+// GetTimeline counts comments of a stream per interval, empty intervals included
+func (s *CommentService) GetTimeline(stream *model.Stream, interval uint) (*dto.CommentTimelineDTO, error) {
+	if interval == 0 {
+		interval = dto.COMMENT_TIMELINE_DEFAULT_INTERVAL
+	}
+	result := &dto.CommentTimelineDTO{StreamID: stream.ID, Interval: interval, Buckets: []dto.CommentTimelineBucket{}}
+	if stream.StartedAt.Valid {
+		result.StartedAt = &stream.StartedAt.Time
+	}
+	if stream.EndedAt.Valid {
+		result.EndedAt = &stream.EndedAt.Time
+	}
+
+	// streams which never started have comments on their recording only
+	start := stream.StartedAt
+	if !start.Valid {
+		first, err := s.repo.Comment.FirstCreatedAt(stream.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !first.Valid {
+			return result, nil
+		}
+		start = first
+	}
+
+	rows, err := s.repo.Comment.Timeline(stream.ID, start.Time, interval)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return result, nil
+	}
+	last := rows[len(rows)-1].Bucket
+	if last >= dto.COMMENT_TIMELINE_MAX_BUCKETS {
+		return nil, ErrCommentTimeline
+	}
+
+	step := time.Duration(interval) * time.Second
+	result.Buckets = make([]dto.CommentTimelineBucket, last+1)
+	for i := range result.Buckets {
+		result.Buckets[i] = dto.CommentTimelineBucket{Offset: int64(i) * int64(interval), Start: start.Time.Add(time.Duration(i) * step)}
+	}
+	for _, row := range rows {
+		result.Buckets[row.Bucket].Total = row.Total
+		result.Buckets[row.Bucket].Hidden = row.Hidden
+		result.Buckets[row.Bucket].Flagged = row.Flagged
+	}
+	return result, nil
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/cache"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	COMMENT_FILTER_DEFAULT_SAMPLE_SIZE = 5
+	COMMENT_FILTER_RESUBSCRIBE_DELAY   = 5 * time.Second
+)
+
+var (
+	ErrCommentFilterInvalidPattern = errors.New("invalid pattern")
+	ErrCommentFilterRuleNotFound   = errors.New("some rules don't exist")
+)
+
+// commentFilterMatcher is a compiled rule
+type commentFilterMatcher struct {
+	rule  model.CommentFilterRule
+	match func(comment string) bool
+}
+
+func (m *commentFilterMatcher) appliesTo(categoryIDs []uint) bool {
+	return m.rule.CategoryID == nil || slices.Contains(categoryIDs, *m.rule.CategoryID)
+}
+
+// commentWords splits text into lower case words, punctuation and spaces separate words
+func commentWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func containsPhrase(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileCommentFilter(rule *model.CommentFilterRule) (*commentFilterMatcher, error) {
+	switch rule.Type {
+	case model.CommentFilterTypeWord:
+		phrase := commentWords(rule.Pattern)
+		if len(phrase) == 0 {
+			return nil, fmt.Errorf("%w: no words in pattern", ErrCommentFilterInvalidPattern)
+		}
+		return &commentFilterMatcher{rule: *rule, match: func(comment string) bool {
+			return containsPhrase(commentWords(comment), phrase)
+		}}, nil
+	case model.CommentFilterTypeRegex:
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCommentFilterInvalidPattern, err)
+		}
+		return &commentFilterMatcher{rule: *rule, match: re.MatchString}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown type %s", ErrCommentFilterInvalidPattern, rule.Type)
+}
+
+// CommentFilterService manages rules which be-api applies to live chat. Compiled rules are cached per instance,
+// other instances drop theirs through cache.CHANNEL_COMMENT_FILTER_RULES.
+type CommentFilterService struct {
+	repo       *repository.Repository
+	redisStore cache.RedisStore
+	matchers   *cache.FCache[uint, *commentFilterMatcher]
+}
+
+func newCommentFilterService(repo *repository.Repository, redis cache.RedisStore) *CommentFilterService {
+	return &CommentFilterService{
+		repo:       repo,
+		redisStore: redis,
+		matchers:   cache.NewFCache[uint, *commentFilterMatcher](),
+	}
+}
+
+func toCommentFilterRuleDto(rule *model.CommentFilterRule) dto.CommentFilterRuleDTO {
+	result := dto.CommentFilterRuleDTO{
+		ID:           rule.ID,
+		Name:         rule.Name,
+		Type:         rule.Type,
+		Pattern:      rule.Pattern,
+		CategoryID:   rule.CategoryID,
+		Action:       rule.Action,
+		HitThreshold: rule.HitThreshold,
+		Enabled:      rule.Enabled,
+		CreatedAt:    rule.CreatedAt,
+		UpdatedAt:    rule.UpdatedAt,
+		CreatedByID:  rule.CreatedByID,
+		UpdatedByID:  rule.UpdatedByID,
+	}
+	if rule.Category != nil {
+		result.CategoryName = rule.Category.Name
+	}
+	return result
+}
+
+func (s *CommentFilterService) GetRules(req *dto.CommentFilterRuleQuery) (*utils.PaginationModel[dto.CommentFilterRuleDTO], error) {
+	pagination, err := s.repo.CommentFilter.Page(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.CommentFilterRuleDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.CommentFilterRule) dto.CommentFilterRuleDTO {
+		return toCommentFilterRuleDto(&e)
+	})
+	return result, nil
+}
+
+// FindByID returns nil when the rule doesn't exist
+func (s *CommentFilterService) FindByID(id uint) (*model.CommentFilterRule, error) {
+	return s.repo.CommentFilter.FindByID(id)
+}
+
+func (s *CommentFilterService) ToDto(rule *model.CommentFilterRule) dto.CommentFilterRuleDTO {
+	return toCommentFilterRuleDto(rule)
+}
+
+// matcher returns the compiled rule from cache, it's compiled again when the loaded rule changed since,
+// e.g. by another instance whose invalidation hasn't arrived yet
+func (s *CommentFilterService) matcher(rule *model.CommentFilterRule) (*commentFilterMatcher, error) {
+	if m, ok := s.matchers.Get(rule.ID); ok && m.rule.UpdatedAt.Equal(rule.UpdatedAt) && m.rule.Type == rule.Type && m.rule.Pattern == rule.Pattern {
+		return m, nil
+	}
+	m, err := compileCommentFilter(rule)
+	if err != nil {
+		return nil, err
+	}
+	s.matchers.Set(rule.ID, m)
+	return m, nil
+}
+
+// apply copies the request to the rule, checking its category and pattern
+func (s *CommentFilterService) apply(rule *model.CommentFilterRule, req *dto.CommentFilterRuleRequest) error {
+	if req.CategoryID != nil {
+		if _, err := s.repo.Category.FindByID(*req.CategoryID); err != nil {
+			return err
+		}
+	}
+
+	rule.Name = req.Name
+	rule.Type = req.Type
+	rule.Pattern = req.Pattern
+	rule.CategoryID = req.CategoryID
+	rule.Category = nil
+	rule.Action = req.Action
+	rule.HitThreshold = 0
+	if req.Action == model.CommentFilterActionBlockUser {
+		rule.HitThreshold = req.HitThreshold
+	}
+	rule.Enabled = req.Enabled
+	rule.UpdatedByID = req.CreatedByID
+	_, err := compileCommentFilter(rule)
+	return err
+}
+
+func (s *CommentFilterService) Create(ctx context.Context, req *dto.CommentFilterRuleRequest) (*model.CommentFilterRule, error) {
+	rule := &model.CommentFilterRule{CreatedByID: req.CreatedByID}
+	if err := s.apply(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CommentFilter.Create(rule); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, dto.COMMENT_FILTER_EVENT_SAVED, rule.ID)
+	return rule, nil
+}
+
+func (s *CommentFilterService) Update(ctx context.Context, rule *model.CommentFilterRule, req *dto.CommentFilterRuleRequest) error {
+	if err := s.apply(rule, req); err != nil {
+		return err
+	}
+	if err := s.repo.CommentFilter.Update(rule); err != nil {
+		return err
+	}
+	s.publish(ctx, dto.COMMENT_FILTER_EVENT_SAVED, rule.ID)
+	return nil
+}
+
+func (s *CommentFilterService) Delete(ctx context.Context, id uint) error {
+	if err := s.repo.CommentFilter.Delete(id); err != nil {
+		return err
+	}
+	s.publish(ctx, dto.COMMENT_FILTER_EVENT_DELETED, id)
+	return nil
+}
+
+// writeRules replaces the enabled rules read by be-api
+func (s *CommentFilterService) writeRules(ctx context.Context) error {
+	rules, err := s.repo.CommentFilter.FindEnabled()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(utils.Map(rules, func(e model.CommentFilterRule) dto.CommentFilterRuleDTO {
+		return toCommentFilterRuleDto(&e)
+	}))
+	if err != nil {
+		return err
+	}
+	return s.redisStore.Set(ctx, cache.COMMENT_FILTER_RULES_KEY, data, 0)
+}
+
+// publish syncs be-api and other instances, the rule is saved already so failures are only logged
+func (s *CommentFilterService) publish(ctx context.Context, action string, ruleID uint) {
+	s.matchers.Delete(ruleID)
+	if err := s.writeRules(ctx); err != nil {
+		log.Printf("Failed to write comment filter rules: %v\n", err)
+	}
+
+	data, err := json.Marshal(dto.CommentFilterEvent{Action: action, RuleID: ruleID})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if err := s.redisStore.Publish(ctx, cache.CHANNEL_COMMENT_FILTER_RULES, string(data)); err != nil {
+		log.Printf("Failed to publish %s event of comment filter rule %d: %v\n", action, ruleID, err)
+	}
+}
+
+func (s *CommentFilterService) onEvent(channel, message string) {
+	var event dto.CommentFilterEvent
+	if err := json.Unmarshal([]byte(message), &event); err != nil {
+		log.Printf("Invalid message on %s: %v\n", channel, err)
+		return
+	}
+	s.matchers.Delete(event.RuleID)
+}
+
+// Start writes the rules for be-api, then drops cached rules changed by other instances until ctx is done
+func (s *CommentFilterService) Start(ctx context.Context) {
+	if err := s.writeRules(ctx); err != nil {
+		log.Printf("Failed to write comment filter rules: %v\n", err)
+	}
+	for {
+		err := s.redisStore.Subscribe(ctx, s.onEvent, cache.CHANNEL_COMMENT_FILTER_RULES)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Comment filter subscription stopped: %v\n", err)
+		// events may be missed until it subscribes again
+		s.matchers.Clear()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(COMMENT_FILTER_RESUBSCRIBE_DELAY):
+		}
+	}
+}
+
+// Evaluate runs rules against stored comments to preview what they would hide, flag or block, nothing is changed
+func (s *CommentFilterService) Evaluate(ctx context.Context, req *dto.CommentFilterEvaluateRequest) (*dto.CommentFilterEvaluationDTO, error) {
+	ruleIDs := slices.Clone(req.RuleIDs)
+	slices.Sort(ruleIDs)
+	ruleIDs = slices.Compact(ruleIDs)
+	rules, err := s.repo.CommentFilter.FindByIDs(ruleIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) != len(ruleIDs) {
+		return nil, ErrCommentFilterRuleNotFound
+	}
+	sampleSize := int(req.SampleSize)
+	if sampleSize == 0 {
+		sampleSize = COMMENT_FILTER_DEFAULT_SAMPLE_SIZE
+	}
+
+	matchers := make([]*commentFilterMatcher, len(rules))
+	byCategory := false
+	for i := range rules {
+		if matchers[i], err = s.matcher(&rules[i]); err != nil {
+			return nil, err
+		}
+		byCategory = byCategory || rules[i].CategoryID != nil
+	}
+
+	result := &dto.CommentFilterEvaluationDTO{Rules: make([]dto.CommentFilterRuleEvaluationDTO, len(rules))}
+	hits := make([]map[uint]uint, len(rules))
+	for i, rule := range rules {
+		result.Rules[i] = dto.CommentFilterRuleEvaluationDTO{RuleID: rule.ID, Name: rule.Name, Action: rule.Action, Samples: []dto.CommentFilterSampleDTO{}}
+		hits[i] = make(map[uint]uint)
+	}
+
+	var beforeID uint
+	for result.Scanned < dto.COMMENT_FILTER_EVALUATE_MAX_COMMENTS {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		comments, err := s.repo.CommentFilter.FindComments(req, beforeID, dto.COMMENT_FILTER_EVALUATE_BATCH_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		if len(comments) == 0 {
+			break
+		}
+
+		var categories map[uint][]uint
+		if byCategory {
+			streamIDs := utils.Map(comments, func(e dto.CommentFilterCommentDTO) uint { return e.StreamID })
+			slices.Sort(streamIDs)
+			if categories, err = s.repo.CommentFilter.FindStreamCategoryIDs(slices.Compact(streamIDs)); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, comment := range comments {
+			matched := false
+			for i, m := range matchers {
+				if !m.appliesTo(categories[comment.StreamID]) || !m.match(comment.Comment) {
+					continue
+				}
+				matched = true
+				result.Rules[i].Matches++
+				hits[i][comment.UserID]++
+				if len(result.Rules[i].Samples) < sampleSize {
+					result.Rules[i].Samples = append(result.Rules[i].Samples, dto.CommentFilterSampleDTO{
+						CommentID: comment.ID, StreamID: comment.StreamID, UserID: comment.UserID, Comment: comment.Comment, CreatedAt: comment.CreatedAt,
+					})
+				}
+			}
+			if matched {
+				result.Matched++
+			}
+		}
+		result.Scanned += int64(len(comments))
+		beforeID = comments[len(comments)-1].ID
+		if len(comments) < dto.COMMENT_FILTER_EVALUATE_BATCH_SIZE {
+			break
+		}
+		result.Truncated = result.Scanned >= dto.COMMENT_FILTER_EVALUATE_MAX_COMMENTS
+	}
+
+	for i, rule := range rules {
+		result.Rules[i].Users = len(hits[i])
+		if rule.Action != model.CommentFilterActionBlockUser {
+			continue
+		}
+		for userID, count := range hits[i] {
+			if count >= rule.HitThreshold {
+				result.Rules[i].BlockedUserIDs = append(result.Rules[i].BlockedUserIDs, userID)
+			}
+		}
+		slices.Sort(result.Rules[i].BlockedUserIDs)
+	}
+	return result, nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"gitlab/live/be-live-admin/cache"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/repository"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type StorageFolders struct {
+	Thumbnail       string
+	Avatar          string
+	Live            string
+	ScheduledVideos string
+	Video           string
+	Clips           string
+}
+
+// FileGCService removes files in the storage tree which are not referenced by any row anymore.
+// Handlers remove files in background goroutines and ignore failures, this cleans up what they leak.
+type FileGCService struct {
+	repo        *repository.Repository
+	redisStore  cache.RedisStore
+	folders     StorageFolders
+	gracePeriod time.Duration
+}
+
+func NewFileGCService(repo *repository.Repository, redis cache.RedisStore, folders StorageFolders, gracePeriod time.Duration) *FileGCService {
+	return &FileGCService{
+		repo:        repo,
+		redisStore:  redis,
+		folders:     folders,
+		gracePeriod: gracePeriod,
+	}
+}
+
+type fileGCTarget struct {
+	folder string
+	// returns whether the file is referenced and the video name used for encoding lock ("" if none)
+	check func(fileName string) (bool, string)
+}
+
+func toSet(values []string) map[string]struct{} {
+	result := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		result[v] = struct{}{}
+	}
+	return result
+}
+
+func (s *FileGCService) makeTargets() ([]fileGCTarget, error) {
+	thumbnails, err := s.repo.File.GetThumbnailFileNames()
+	if err != nil {
+		return nil, err
+	}
+	avatars, err := s.repo.File.GetAvatarFileNames()
+	if err != nil {
+		return nil, err
+	}
+	scheduledVideos, err := s.repo.File.GetScheduledVideoNames()
+	if err != nil {
+		return nil, err
+	}
+	streamKeys, err := s.repo.File.GetStreamKeys()
+	if err != nil {
+		return nil, err
+	}
+	clips, err := s.repo.File.GetClipFileNames()
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnailSet, avatarSet, scheduledSet, streamKeySet, clipSet := toSet(thumbnails), toSet(avatars), toSet(scheduledVideos), toSet(streamKeys), toSet(clips)
+
+	return []fileGCTarget{
+		{folder: s.folders.Thumbnail, check: func(fileName string) (bool, string) {
+			_, ok := thumbnailSet[fileName]
+			return ok, ""
+		}},
+		{folder: s.folders.Avatar, check: func(fileName string) (bool, string) {
+			_, ok := avatarSet[fileName]
+			return ok, ""
+		}},
+		{folder: s.folders.ScheduledVideos, check: func(fileName string) (bool, string) {
+			_, ok := scheduledSet[fileName]
+			return ok, ""
+		}},
+		{folder: s.folders.Clips, check: func(fileName string) (bool, string) {
+			_, ok := clipSet[fileName]
+			return ok, ""
+		}},
+		// ended videos are named {stream_key}.mp4
+		{folder: s.folders.Video, check: func(fileName string) (bool, string) {
+			_, ok := streamKeySet[strings.TrimSuffix(fileName, filepath.Ext(fileName))]
+			return ok, fileName
+		}},
+		// live recordings are named {stream_key}_{timestamp}.flv and encoded to {stream_key}.mp4
+		{folder: s.folders.Live, check: func(fileName string) (bool, string) {
+			streamKey, _, _ := strings.Cut(fileName, "_")
+			_, ok := streamKeySet[streamKey]
+			return ok, streamKey + ".mp4"
+		}},
+	}, nil
+}
+
+// Run scans every storage folder once. With dryRun orphans are only reported.
+func (s *FileGCService) Run(ctx context.Context, dryRun bool) (*dto.FileGCReport, error) {
+	targets, err := s.makeTargets()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.FileGCReport{DryRun: dryRun, Orphans: []dto.OrphanFileDTO{}}
+	threshold := time.Now().Add(-s.gracePeriod)
+
+	for _, target := range targets {
+		if target.folder == "" {
+			continue
+		}
+		entries, err := os.ReadDir(target.folder)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				report.Errors = append(report.Errors, err.Error())
+				continue
+			}
+			report.ScannedFiles++
+
+			referenced, videoName := target.check(entry.Name())
+			if referenced {
+				continue
+			}
+
+			// uploads are written before the row is created
+			if info.ModTime().After(threshold) {
+				report.SkippedFiles++
+				continue
+			}
+
+			if videoName != "" {
+				isEncoding, err := isEncodingVideo(ctx, s.redisStore, videoName)
+				if err != nil {
+					report.Errors = append(report.Errors, err.Error())
+					continue
+				}
+				if isEncoding {
+					report.SkippedFiles++
+					continue
+				}
+			}
+
+			path := filepath.Join(target.folder, entry.Name())
+			report.Orphans = append(report.Orphans, dto.OrphanFileDTO{Path: path, Size: info.Size(), ModifiedAt: info.ModTime()})
+			if dryRun {
+				continue
+			}
+
+			if err := os.Remove(path); err != nil {
+				report.Errors = append(report.Errors, err.Error())
+				continue
+			}
+			report.DeletedFiles++
+			report.FreedBytes += info.Size()
+		}
+	}
+
+	return report, nil
+}
+
+// Start runs the collector every interval until ctx is done, only on the leader instance
+func (s *FileGCService) Start(ctx context.Context, interval time.Duration, leader *LeaderElector) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !leader.IsLeader() {
+				continue
+			}
+			report, err := s.Run(ctx, false)
+			if err != nil {
+				log.Printf("File gc failed: %v\n", err)
+				continue
+			}
+			log.Printf("File gc scanned %d files, deleted %d orphans, freed %d bytes, %d errors\n", report.ScannedFiles, report.DeletedFiles, report.FreedBytes, len(report.Errors))
+		}
+	}
+}
@@ -0,0 +1,465 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const DEFAULT_GDPR_EXPORT_EXPIRATION = 7 * 24 * time.Hour
+
+var (
+	ErrGDPRRequestPending = errors.New("a request of this type is already pending for the user")
+	ErrGDPRProtectedUser  = errors.New("admin accounts can't be erased")
+)
+
+// GDPROptions are set from config, exports are removed after ExportExpiration
+type GDPROptions struct {
+	ExportsFolder    string
+	Folders          StorageFolders
+	ExportExpiration time.Duration
+}
+
+// GDPRService runs subject-access exports and erasures of users in jobs, each request gets a signed completion report
+type GDPRService struct {
+	repo       *repository.Repository
+	trash      *TrashService
+	recurrence *RecurrenceService
+	job        *JobService
+}
+
+func newGDPRService(repo *repository.Repository, trash *TrashService, recurrence *RecurrenceService, job *JobService) *GDPRService {
+	return &GDPRService{
+		repo:       repo,
+		trash:      trash,
+		recurrence: recurrence,
+		job:        job,
+	}
+}
+
+func exportExpirationOrDefault(expiration time.Duration) time.Duration {
+	if expiration <= 0 {
+		return DEFAULT_GDPR_EXPORT_EXPIRATION
+	}
+	return expiration
+}
+
+func (s *GDPRService) toGDPRRequestDto(request *model.GDPRRequest, apiURL string, exportExpiration time.Duration) dto.GDPRRequestDTO {
+	result := dto.GDPRRequestDTO{
+		ID:        request.ID,
+		UserID:    request.UserID,
+		Username:  request.Username,
+		Type:      request.Type,
+		Status:    request.Status,
+		Reason:    request.Reason,
+		JobID:     request.JobID,
+		Signature: request.Signature,
+		Error:     request.Error,
+		CreatedAt: request.CreatedAt,
+	}
+	if request.Report.Valid {
+		result.Report = json.RawMessage(request.Report.String)
+		result.SignatureValid = utils.VerifyReport([]byte(request.Report.String), request.Signature)
+	}
+	if request.CompletedAt.Valid {
+		result.CompletedAt = &request.CompletedAt.Time
+		if request.FileName != "" && time.Since(request.CompletedAt.Time) < exportExpirationOrDefault(exportExpiration) {
+			result.DownloadURL = utils.MakeExportURL(apiURL, request.FileName)
+		}
+	}
+	if request.RequestedBy != nil {
+		result.RequestedBy = &dto.UserResponseDTO{ID: request.RequestedBy.ID, Username: request.RequestedBy.Username, DisplayName: request.RequestedBy.DisplayName}
+	}
+	return result
+}
+
+func (s *GDPRService) GetRequests(req *dto.GDPRRequestQuery, apiURL string, exportExpiration time.Duration) (*utils.PaginationModel[dto.GDPRRequestDTO], error) {
+	pagination, err := s.repo.GDPR.Page(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.GDPRRequestDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.GDPRRequest) dto.GDPRRequestDTO {
+		return s.toGDPRRequestDto(&e, apiURL, exportExpiration)
+	})
+	return result, nil
+}
+
+// GetRequest returns nil when the request doesn't exist
+func (s *GDPRService) GetRequest(id uint, apiURL string, exportExpiration time.Duration) (*dto.GDPRRequestDTO, error) {
+	request, err := s.repo.GDPR.FindByID(id)
+	if err != nil || request == nil {
+		return nil, err
+	}
+	result := s.toGDPRRequestDto(request, apiURL, exportExpiration)
+	return &result, nil
+}
+
+func (s *GDPRService) createRequest(user *model.User, requestType model.GDPRRequestType, jobType model.JobType, reason string, requestedByID uint) (*dto.GDPRRequestCreatedDTO, error) {
+	pending, err := s.repo.GDPR.HasPending(user.ID, requestType)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, ErrGDPRRequestPending
+	}
+
+	request := &model.GDPRRequest{
+		UserID:        user.ID,
+		Username:      user.Username,
+		Type:          requestType,
+		Status:        model.GDPRRequestStatusPending,
+		Reason:        reason,
+		RequestedByID: &requestedByID,
+	}
+	if err := s.repo.GDPR.Create(request); err != nil {
+		return nil, err
+	}
+	job, err := s.job.Enqueue(jobType, dto.GDPRPayload{RequestID: request.ID}, 0, requestedByID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.GDPR.SetJobID(request.ID, job.ID); err != nil {
+		return nil, err
+	}
+	return &dto.GDPRRequestCreatedDTO{RequestID: request.ID, JobID: job.ID}, nil
+}
+
+// RequestExport queues a model.JobTypeGDPRExport job which zips the records and uploaded files of user
+func (s *GDPRService) RequestExport(user *model.User, requestedByID uint) (*dto.GDPRRequestCreatedDTO, error) {
+	return s.createRequest(user, model.GDPRRequestTypeExport, model.JobTypeGDPRExport, "", requestedByID)
+}
+
+// RequestErasure queues a model.JobTypeGDPRErasure job which applies dto.GDPRErasurePolicy to user
+func (s *GDPRService) RequestErasure(user *model.User, reason string, requestedByID uint) (*dto.GDPRRequestCreatedDTO, error) {
+	if user.Role.Type == model.ADMINROLE || user.Role.Type == model.SUPPERADMINROLE {
+		return nil, ErrGDPRProtectedUser
+	}
+	return s.createRequest(user, model.GDPRRequestTypeErasure, model.JobTypeGDPRErasure, reason, requestedByID)
+}
+
+// run loads the request of a job and saves the signed report returned by fn, the request fails with the last attempt of the job
+func (s *GDPRService) run(ctx context.Context, job *model.Job, fn func(request *model.GDPRRequest, report *dto.GDPRReport) error) error {
+	payload, err := decodeJobPayload[dto.GDPRPayload](job)
+	if err != nil {
+		return err
+	}
+	request, err := s.repo.GDPR.FindByID(payload.RequestID)
+	if err != nil {
+		return err
+	}
+	if request == nil {
+		return fmt.Errorf("gdpr request %d not found", payload.RequestID)
+	}
+	if request.Status != model.GDPRRequestStatusPending {
+		return nil
+	}
+
+	report := &dto.GDPRReport{
+		RequestID:     request.ID,
+		Type:          request.Type,
+		UserID:        request.UserID,
+		Username:      request.Username,
+		Reason:        request.Reason,
+		PolicyVersion: dto.GDPR_POLICY_VERSION,
+		StartedAt:     time.Now(),
+	}
+	if request.RequestedByID != nil {
+		report.RequestedByID = *request.RequestedByID
+	}
+
+	if err := fn(request, report); err != nil {
+		if job.Attempts >= job.MaxAttempts || ctx.Err() != nil {
+			if err := s.repo.GDPR.Fail(request.ID, err.Error()); err != nil {
+				log.Printf("Failed to save gdpr request %d: %v\n", request.ID, err)
+			}
+		}
+		return err
+	}
+
+	report.CompletedAt = time.Now()
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return s.repo.GDPR.Complete(request.ID, report.FileName, string(data), utils.SignReport(data))
+}
+
+type gdprZipWriter struct {
+	zip          *zip.Writer
+	report       *dto.GDPRReport
+	missingFiles []string
+}
+
+func (w *gdprZipWriter) writeJSON(record string, value any, count int) error {
+	f, err := w.zip.Create(record + ".json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	w.report.Add(record, dto.GDPR_ACTION_EXPORTED, int64(count))
+	return nil
+}
+
+// writeFile adds a file of the storage as files/<folder>/<name>, missing files are reported instead
+func (w *gdprZipWriter) writeFile(folderName, folder, fileName string) (bool, error) {
+	src, err := os.Open(filepath.Join(folder, fileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			w.missingFiles = append(w.missingFiles, fmt.Sprintf("%s/%s", folderName, fileName))
+			return false, nil
+		}
+		return false, err
+	}
+	defer src.Close()
+
+	dst, err := w.zip.Create(fmt.Sprintf("%s/%s/%s", dto.GDPR_RECORD_FILES, folderName, fileName))
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func writeGDPRRecords[T any](w *gdprZipWriter, record string, find func(userID uint) ([]T, error), userID uint) error {
+	records, err := find(userID)
+	if err != nil {
+		return err
+	}
+	if records == nil {
+		records = []T{}
+	}
+	return w.writeJSON(record, records, len(records))
+}
+
+func (s *GDPRService) writeExport(ctx context.Context, w *gdprZipWriter, userID uint, options GDPROptions, report JobProgressFunc) error {
+	profile, err := s.repo.GDPR.FindProfile(userID)
+	if err != nil {
+		return err
+	}
+	if err := w.writeJSON(dto.GDPR_RECORD_PROFILE, profile, 1); err != nil {
+		return err
+	}
+
+	if err := writeGDPRRecords(w, dto.GDPR_RECORD_STREAMS, s.repo.GDPR.FindStreams, userID); err != nil {
+		return err
+	}
+	if err := writeGDPRRecords(w, dto.GDPR_RECORD_COMMENTS, s.repo.GDPR.FindComments, userID); err != nil {
+		return err
+	}
+	if err := writeGDPRRecords(w, dto.GDPR_RECORD_LIKES, s.repo.GDPR.FindLikes, userID); err != nil {
+		return err
+	}
+	if err := writeGDPRRecords(w, dto.GDPR_RECORD_VIEWS, s.repo.GDPR.FindViews, userID); err != nil {
+		return err
+	}
+	if err := writeGDPRRecords(w, dto.GDPR_RECORD_SHARES, s.repo.GDPR.FindShares, userID); err != nil {
+		return err
+	}
+	if err := writeGDPRRecords(w, dto.GDPR_RECORD_BOOKMARKS, s.repo.GDPR.FindBookmarks, userID); err != nil {
+		return err
+	}
+	if err := writeGDPRRecords(w, dto.GDPR_RECORD_SUBSCRIPTIONS, s.repo.GDPR.FindSubscriptions, userID); err != nil {
+		return err
+	}
+	if err := writeGDPRRecords(w, dto.GDPR_RECORD_NOTIFICATIONS, s.repo.GDPR.FindNotifications, userID); err != nil {
+		return err
+	}
+	report(30, "records exported")
+
+	thumbnails, videos, err := s.repo.GDPR.FindUploadedFiles(userID)
+	if err != nil {
+		return err
+	}
+	type uploadedFile struct{ folderName, folder, fileName string }
+	files := make([]uploadedFile, 0, len(thumbnails)+len(videos)+1)
+	if profile.AvatarFileName != "" {
+		files = append(files, uploadedFile{"avatar", options.Folders.Avatar, profile.AvatarFileName})
+	}
+	for _, fileName := range thumbnails {
+		files = append(files, uploadedFile{"thumbnail", options.Folders.Thumbnail, fileName})
+	}
+	for _, fileName := range videos {
+		files = append(files, uploadedFile{"scheduled_videos", options.Folders.ScheduledVideos, fileName})
+	}
+
+	var written int64
+	for i, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ok, err := w.writeFile(file.folderName, file.folder, file.fileName)
+		if err != nil {
+			return err
+		}
+		if ok {
+			written++
+		}
+		report(uint(30+(i+1)*60/len(files)), fmt.Sprintf("%d of %d files", i+1, len(files)))
+	}
+	w.report.Add(dto.GDPR_RECORD_FILES, dto.GDPR_ACTION_EXPORTED, written)
+	w.report.MissingFiles = w.missingFiles
+	return nil
+}
+
+func fileSHA256(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// ExportJob runs a model.JobTypeGDPRExport job, the zip is served by signed urls until a model.JobTypeRemoveFiles job removes it
+func (s *GDPRService) ExportJob(options GDPROptions) JobHandler {
+	return func(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+		return s.run(ctx, job, func(request *model.GDPRRequest, gdprReport *dto.GDPRReport) error {
+			if err := os.MkdirAll(options.ExportsFolder, 0755); err != nil {
+				return err
+			}
+
+			// the random name keeps exports of the same user apart
+			fileName := fmt.Sprintf("gdpr_%d_%s.zip", request.UserID, utils.MakeUniqueID())
+			path := filepath.Join(options.ExportsFolder, fileName)
+			tmpPath := path + ".part"
+			f, err := os.Create(tmpPath)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(tmpPath)
+
+			w := &gdprZipWriter{zip: zip.NewWriter(f), report: gdprReport}
+			if err := s.writeExport(ctx, w, request.UserID, options, report); err != nil {
+				f.Close()
+				return err
+			}
+			if err := w.zip.Close(); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			if err := os.Rename(tmpPath, path); err != nil {
+				return err
+			}
+
+			if gdprReport.FileSHA256, gdprReport.FileSize, err = fileSHA256(path); err != nil {
+				return err
+			}
+			gdprReport.FileName = fileName
+
+			expiresAt := time.Now().Add(exportExpirationOrDefault(options.ExportExpiration))
+			if _, err := s.job.EnqueueAt(model.JobTypeRemoveFiles, dto.RemoveFilesPayload{Files: []dto.RemoveFileEntry{{Path: path}}}, 0, 0, expiresAt); err != nil {
+				log.Printf("Failed to queue removal of export %s: %v\n", path, err)
+			}
+			return nil
+		})
+	}
+}
+
+// ErasureJob runs a model.JobTypeGDPRErasure job, every step can run again so failed attempts are retried.
+// Live or encoding streams fail the attempt, streams under legal hold are kept.
+func (s *GDPRService) ErasureJob(options GDPROptions) JobHandler {
+	return func(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+		return s.run(ctx, job, func(request *model.GDPRRequest, gdprReport *dto.GDPRReport) error {
+			gdprReport.Policy = dto.GDPRErasurePolicy
+			profile, err := s.repo.GDPR.FindProfile(request.UserID)
+			if err != nil {
+				return err
+			}
+
+			recurrenceIDs, err := s.repo.GDPR.FindRecurrenceIDs(request.UserID)
+			if err != nil {
+				return err
+			}
+			for _, id := range recurrenceIDs {
+				if err := s.recurrence.Delete(id); err != nil {
+					return err
+				}
+			}
+			gdprReport.Add(dto.GDPR_RECORD_RECURRENCES, dto.GDPR_ACTION_DELETED, int64(len(recurrenceIDs)))
+
+			streams, err := s.repo.GDPR.FindAllStreams(request.UserID)
+			if err != nil {
+				return err
+			}
+			var deleted, kept int64
+			for i := range streams {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if streams[i].LegalHold {
+					kept++
+					continue
+				}
+				if err := s.trash.purgeStream(ctx, &streams[i], options.Folders); err != nil {
+					return fmt.Errorf("stream %d: %w", streams[i].ID, err)
+				}
+				deleted++
+				report(uint((i+1)*80/len(streams)), fmt.Sprintf("%d of %d streams", i+1, len(streams)))
+			}
+			gdprReport.Add(dto.GDPR_RECORD_STREAMS, dto.GDPR_ACTION_DELETED, deleted)
+			if kept > 0 {
+				gdprReport.Add(dto.GDPR_RECORD_STREAMS, dto.GDPR_ACTION_KEPT, kept)
+			}
+
+			counts, err := s.repo.GDPR.Erase(request.UserID, map[string]interface{}{
+				"username":         fmt.Sprintf("erased_%d", request.UserID),
+				"email":            fmt.Sprintf("erased_%d@erased.invalid", request.UserID),
+				"display_name":     "",
+				"password_hash":    "",
+				"otp":              "",
+				"otp_expires_at":   nil,
+				"avatar_file_name": sql.NullString{},
+				"blocked_reason":   "",
+				"status":           model.BLOCKED,
+				"suspended_until":  nil,
+			})
+			if err != nil {
+				return err
+			}
+			for _, entry := range dto.GDPRErasurePolicy {
+				if count, ok := counts[entry.Record]; ok {
+					gdprReport.Add(entry.Record, entry.Action, count)
+				}
+			}
+
+			if profile.AvatarFileName != "" {
+				if err := os.Remove(filepath.Join(options.Folders.Avatar, profile.AvatarFileName)); err != nil && !os.IsNotExist(err) {
+					log.Println(err)
+				}
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const DEFAULT_INVITE_EXPIRATION = 7 * 24 * time.Hour
+
+var (
+	ErrInviteNotPending = errors.New("invite was already accepted or revoked")
+	ErrInviteInvalid    = errors.New("invalid invite token")
+	ErrInviteExpired    = errors.New("invite link is expired, ask for a new one")
+)
+
+// InviteOptions are set from config, URL is the page which posts the token to the accept endpoint
+type InviteOptions struct {
+	Mailer     Mailer
+	URL        string
+	Expiration time.Duration
+}
+
+// InviteService lets invitees set their own password, the user is created when the invite is accepted
+type InviteService struct {
+	repo *repository.Repository
+	job  *JobService
+}
+
+func newInviteService(repo *repository.Repository, job *JobService) *InviteService {
+	return &InviteService{
+		repo: repo,
+		job:  job,
+	}
+}
+
+func toInviteDto(invite *model.Invite) dto.InviteDTO {
+	result := dto.InviteDTO{
+		ID:          invite.ID,
+		Email:       invite.Email,
+		Username:    invite.Username,
+		DisplayName: invite.DisplayName,
+		RoleType:    invite.Role.Type,
+		Status:      invite.Status,
+		Expired:     invite.Status == model.InviteStatusPending && invite.ExpiresAt != nil && invite.ExpiresAt.Before(time.Now()),
+		ExpiresAt:   invite.ExpiresAt,
+		SentAt:      invite.SentAt,
+		SentCount:   invite.SentCount,
+		LastError:   invite.LastError,
+		UserID:      invite.UserID,
+		AcceptedAt:  invite.AcceptedAt,
+		RevokedAt:   invite.RevokedAt,
+		CreatedAt:   invite.CreatedAt,
+	}
+	if invite.InvitedBy != nil {
+		result.InvitedBy = &dto.UserResponseDTO{ID: invite.InvitedBy.ID, Username: invite.InvitedBy.Username, DisplayName: invite.InvitedBy.DisplayName}
+	}
+	return result
+}
+
+func (s *InviteService) GetInvites(req *dto.InviteQuery) (*utils.PaginationModel[dto.InviteDTO], error) {
+	pagination, err := s.repo.Invite.Page(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.InviteDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.Invite) dto.InviteDTO {
+		return toInviteDto(&e)
+	})
+	return result, nil
+}
+
+// FindByID returns nil when the invite doesn't exist
+func (s *InviteService) FindByID(id uint) (*model.Invite, error) {
+	return s.repo.Invite.FindByID(id)
+}
+
+func (s *InviteService) GetInvite(id uint) (*dto.InviteDTO, error) {
+	invite, err := s.repo.Invite.FindByID(id)
+	if err != nil || invite == nil {
+		return nil, err
+	}
+	result := toInviteDto(invite)
+	return &result, nil
+}
+
+// Create saves a pending invite and queues the job which emails its link
+func (s *InviteService) Create(req *dto.CreateInviteRequest, invitedByID uint) (*model.Invite, *dto.InviteCreatedDTO, error) {
+	role, err := s.repo.Role.FindByType(req.RoleType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if role == nil {
+		return nil, nil, fmt.Errorf("role %s not found", req.RoleType)
+	}
+
+	invite := &model.Invite{
+		Email:       req.Email,
+		Username:    req.UserName,
+		DisplayName: req.DisplayName,
+		RoleID:      role.ID,
+		Role:        *role,
+		Status:      model.InviteStatusPending,
+		InvitedByID: &invitedByID,
+	}
+	err = s.repo.WithUserNamesLock(func(repo *repository.Repository) error {
+		if err := checkUserAvailable(repo, invite.Username, invite.Email, 0); err != nil {
+			return err
+		}
+		return repo.Invite.Create(invite)
+	})
+	if err != nil {
+		// the unique indexes still stop writes which don't take the lock
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, nil, ErrUserConflict
+		}
+		return nil, nil, err
+	}
+	job, err := s.enqueueSend(invite.ID, invitedByID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return invite, &dto.InviteCreatedDTO{InviteID: invite.ID, JobID: job.ID}, nil
+}
+
+func (s *InviteService) enqueueSend(inviteID, requestedByID uint) (*model.Job, error) {
+	return s.job.Enqueue(model.JobTypeSendInvite, dto.SendInvitePayload{InviteID: inviteID, RequestedByID: requestedByID}, 0, requestedByID)
+}
+
+// Resend mails a new link, the previous one stops working
+func (s *InviteService) Resend(invite *model.Invite, requestedByID uint) (*dto.InviteCreatedDTO, error) {
+	if invite.Status != model.InviteStatusPending {
+		return nil, ErrInviteNotPending
+	}
+	job, err := s.enqueueSend(invite.ID, requestedByID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.InviteCreatedDTO{InviteID: invite.ID, JobID: job.ID}, nil
+}
+
+func (s *InviteService) Revoke(invite *model.Invite) error {
+	revoked, err := s.repo.Invite.Revoke(invite.ID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInviteNotPending
+	}
+	return nil
+}
+
+// Accept creates the user of the invite with the password chosen by the invitee
+func (s *InviteService) Accept(token, password string) (*model.User, error) {
+	invite, err := s.repo.Invite.FindPendingByToken(utils.HashInviteToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if invite == nil {
+		return nil, ErrInviteInvalid
+	}
+	if invite.ExpiresAt == nil || invite.ExpiresAt.Before(time.Now()) {
+		return nil, ErrInviteExpired
+	}
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user := &model.User{
+		Username:       invite.Username,
+		Email:          invite.Email,
+		DisplayName:    invite.DisplayName,
+		PasswordHash:   hashedPassword,
+		RoleID:         invite.RoleID,
+		AvatarFileName: invite.AvatarFileName,
+		CreatedByID:    invite.InvitedByID,
+		UpdatedByID:    invite.InvitedByID,
+	}
+	err = s.repo.WithUserNamesLock(func(repo *repository.Repository) error {
+		// the invite reserves its own username and email
+		if err := checkUserAvailable(repo, invite.Username, invite.Email, invite.ID); err != nil {
+			return err
+		}
+		return repo.Invite.Accept(invite, user)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteInvalid
+		}
+		// the invite was accepted concurrently or a user took the name since the check
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrUserConflict
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// send returns false when the invite was accepted or revoked meanwhile
+func (s *InviteService) send(invite *model.Invite, options InviteOptions) (bool, error) {
+	expiration := options.Expiration
+	if expiration <= 0 {
+		expiration = DEFAULT_INVITE_EXPIRATION
+	}
+	token, tokenHash, err := utils.MakeInviteToken()
+	if err != nil {
+		return false, err
+	}
+	pending, err := s.repo.Invite.SetToken(invite.ID, tokenHash, time.Now().Add(expiration))
+	if err != nil || !pending {
+		return false, err
+	}
+
+	link := fmt.Sprintf("%s?token=%s", options.URL, url.QueryEscape(token))
+	body := fmt.Sprintf("Hi %s,\n\nYou're invited to join with username %s. Set your password here:\n\n%s\n\nThe link can be used once and expires on %s.\n",
+		invite.DisplayName, invite.Username, link, time.Now().Add(expiration).Format(time.RFC1123))
+	if err := options.Mailer.Send(invite.Email, "You're invited to Live Stream", body); err != nil {
+		if err := s.repo.Invite.SetError(invite.ID, err.Error()); err != nil {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// SendJob runs a model.JobTypeSendInvite job, failed emails are retried by the job queue
+func (s *InviteService) SendJob(options InviteOptions) JobHandler {
+	return func(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+		payload, err := decodeJobPayload[dto.SendInvitePayload](job)
+		if err != nil {
+			return err
+		}
+		invite, err := s.repo.Invite.FindByID(payload.InviteID)
+		if err != nil {
+			return err
+		}
+		if invite == nil || invite.Status != model.InviteStatusPending {
+			return nil
+		}
+
+		sent, err := s.send(invite, options)
+		if err != nil || !sent {
+			return err
+		}
+
+		adminLog := &model.AdminLog{
+			UserID:  payload.RequestedByID,
+			Action:  string(model.SendInvite),
+			Details: fmt.Sprintf("Sent invite %d email to %s.", invite.ID, invite.Email),
+		}
+		if err := s.repo.Admin.Create(adminLog); err != nil {
+			log.Printf("Job %d failed to create admin log: %v\n", job.ID, err)
+		}
+		return nil
+	}
+}
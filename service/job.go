@@ -0,0 +1,151 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"time"
+)
+
+const DEFAULT_JOB_MAX_ATTEMPTS = 3
+
+var ErrJobNotCancelable = errors.New("job already finished")
+
+// JobService queues background work, jobs are run by JobWorker
+type JobService struct {
+	repo *repository.Repository
+}
+
+func newJobService(repo *repository.Repository) *JobService {
+	return &JobService{
+		repo: repo,
+	}
+}
+
+// jobPayloadDto drops the password hashes which import jobs queued by older versions carry in their payload
+func jobPayloadDto(job *model.Job) json.RawMessage {
+	if job.Type != model.JobTypeImportUsers {
+		return json.RawMessage(job.Payload)
+	}
+	payload, err := decodeJobPayload[dto.ImportUsersPayload](job)
+	if err != nil {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func toJobDto(job *model.Job) dto.JobDTO {
+	result := dto.JobDTO{
+		ID:              job.ID,
+		Type:            job.Type,
+		Status:          job.Status,
+		Payload:         jobPayloadDto(job),
+		Progress:        job.Progress,
+		ProgressMessage: job.ProgressMessage,
+		Attempts:        job.Attempts,
+		MaxAttempts:     job.MaxAttempts,
+		LastError:       job.LastError,
+		CancelRequested: job.CancelRequested,
+		RunAt:           job.RunAt,
+		CreatedAt:       job.CreatedAt,
+		UpdatedAt:       job.UpdatedAt,
+	}
+	if job.Result.Valid {
+		result.Result = json.RawMessage(job.Result.String)
+	}
+	if job.StartedAt.Valid {
+		result.StartedAt = &job.StartedAt.Time
+	}
+	if job.FinishedAt.Valid {
+		result.FinishedAt = &job.FinishedAt.Time
+	}
+	if job.CreatedBy != nil {
+		result.CreatedBy = &dto.UserResponseDTO{ID: job.CreatedBy.ID, Username: job.CreatedBy.Username, DisplayName: job.CreatedBy.DisplayName}
+	}
+	return result
+}
+
+// Enqueue queues a job which runs as soon as a worker is free, maxAttempts 0 is DEFAULT_JOB_MAX_ATTEMPTS
+func (s *JobService) Enqueue(jobType model.JobType, payload any, maxAttempts uint, createdByID uint) (*model.Job, error) {
+	return s.EnqueueAt(jobType, payload, maxAttempts, createdByID, time.Now())
+}
+
+// EnqueueAt queues a job which runs from runAt
+func (s *JobService) EnqueueAt(jobType model.JobType, payload any, maxAttempts uint, createdByID uint, runAt time.Time) (*model.Job, error) {
+	return enqueueJob(s.repo, jobType, payload, maxAttempts, createdByID, runAt)
+}
+
+// EnqueueWith queues a job with repo of a transaction, the job only exists once the transaction commits
+func (s *JobService) EnqueueWith(repo *repository.Repository, jobType model.JobType, payload any, maxAttempts uint, createdByID uint) (*model.Job, error) {
+	return enqueueJob(repo, jobType, payload, maxAttempts, createdByID, time.Now())
+}
+
+func enqueueJob(repo *repository.Repository, jobType model.JobType, payload any, maxAttempts uint, createdByID uint, runAt time.Time) (*model.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if maxAttempts == 0 {
+		maxAttempts = DEFAULT_JOB_MAX_ATTEMPTS
+	}
+
+	job := &model.Job{
+		Type:        jobType,
+		Status:      model.JobStatusQueued,
+		Payload:     string(data),
+		MaxAttempts: maxAttempts,
+		RunAt:       runAt,
+	}
+	if createdByID != 0 {
+		job.CreatedByID = &createdByID
+	}
+	if err := repo.Job.Create(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *JobService) GetJobs(req *dto.JobQuery) (*utils.PaginationModel[dto.JobDTO], error) {
+	pagination, err := s.repo.Job.Page(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.JobDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.Job) dto.JobDTO {
+		return toJobDto(&e)
+	})
+	return result, nil
+}
+
+func (s *JobService) GetJob(id uint) (*dto.JobDTO, error) {
+	job, err := s.repo.Job.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	result := toJobDto(job)
+	return &result, nil
+}
+
+// Cancel cancels a queued job, running jobs stop at the next heartbeat of their worker
+func (s *JobService) Cancel(id uint) error {
+	if _, err := s.repo.Job.FindByID(id); err != nil {
+		return err
+	}
+	ok, err := s.repo.Job.RequestCancel(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrJobNotCancelable
+	}
+	return nil
+}
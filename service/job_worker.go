@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// JobProgressFunc reports progress of a running job in percent
+type JobProgressFunc func(progress uint, message string)
+
+// JobHandler runs a job, ctx is canceled when the job is canceled or the worker stops
+type JobHandler func(ctx context.Context, job *model.Job, report JobProgressFunc) error
+
+var errJobCanceled = errors.New("job canceled")
+
+const (
+	DEFAULT_JOB_INTERVAL     = 2 * time.Second
+	DEFAULT_JOB_LOCK_TIMEOUT = time.Minute
+)
+
+type JobWorkerOptions struct {
+	Concurrency int           // jobs run at the same time by this instance
+	LockTimeout time.Duration // running jobs of a worker which stopped refreshing the lock for this long are queued again
+	RetryDelay  time.Duration // multiplied by attempts
+}
+
+// JobWorker runs queued jobs. Every instance can run a worker, jobs are claimed with SKIP LOCKED so each runs once.
+// Job goes queued -> running -> succeeded, failed or canceled, failed attempts go back to queued until attempts run out.
+type JobWorker struct {
+	repo     *repository.Repository
+	options  JobWorkerOptions
+	workerID string
+	handlers map[model.JobType]JobHandler
+
+	wg      sync.WaitGroup
+	running atomic.Int32
+}
+
+func NewJobWorker(repo *repository.Repository, options JobWorkerOptions) *JobWorker {
+	if options.Concurrency <= 0 {
+		options.Concurrency = 1
+	}
+	if options.LockTimeout <= 0 {
+		options.LockTimeout = DEFAULT_JOB_LOCK_TIMEOUT
+	}
+	hostname, _ := os.Hostname()
+	return &JobWorker{
+		repo:     repo,
+		options:  options,
+		workerID: fmt.Sprintf("%s-%s", hostname, utils.MakeUniqueID()),
+		handlers: map[model.JobType]JobHandler{},
+	}
+}
+
+// Register must be called before Start
+func (w *JobWorker) Register(jobType model.JobType, handler JobHandler) {
+	w.handlers[jobType] = handler
+}
+
+func decodeJobPayload[T any](job *model.Job) (*T, error) {
+	var payload T
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("invalid payload of job %d: %w", job.ID, err)
+	}
+	return &payload, nil
+}
+
+// Tick requeues stale jobs and claims jobs until every slot is busy
+func (w *JobWorker) Tick(ctx context.Context) error {
+	if w.options.LockTimeout > 0 {
+		requeued, failed, err := w.repo.Job.RequeueStale(time.Now().Add(-w.options.LockTimeout))
+		if err != nil {
+			return err
+		}
+		if requeued > 0 || failed > 0 {
+			log.Printf("Job worker requeued %d and failed %d stale jobs\n", requeued, failed)
+		}
+	}
+
+	types := make([]model.JobType, 0, len(w.handlers))
+	for jobType := range w.handlers {
+		types = append(types, jobType)
+	}
+	if len(types) == 0 {
+		return nil
+	}
+
+	for int(w.running.Load()) < w.options.Concurrency && ctx.Err() == nil {
+		job, err := w.repo.Job.Claim(types, w.workerID)
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			return nil
+		}
+
+		w.running.Add(1)
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			defer w.running.Add(-1)
+			w.run(ctx, job)
+		}()
+	}
+	return nil
+}
+
+func (w *JobWorker) run(ctx context.Context, job *model.Job) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var canceled, lost atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		w.heartbeat(jobCtx, job.ID, done, func() {
+			canceled.Store(true)
+			cancel()
+		}, func() {
+			lost.Store(true)
+			cancel()
+		})
+	}()
+
+	report := func(progress uint, message string) {
+		if err := w.repo.Job.UpdateProgress(job.ID, w.workerID, progress, message); err != nil {
+			log.Printf("Job %d failed to report progress: %v\n", job.ID, err)
+		}
+	}
+
+	log.Printf("Job %d (%s) started, attempt %d\n", job.ID, job.Type, job.Attempts)
+	err := w.handlers[job.Type](jobCtx, job, report)
+	close(done)
+
+	// results are saved after the worker stops as well
+	var saved bool
+	var saveErr error
+	switch {
+	case lost.Load():
+		// the job was requeued as stale, whoever holds it now saves the result
+		log.Printf("Job %d lost its lock, result dropped\n", job.ID)
+		return
+	case canceled.Load() && err != nil:
+		log.Printf("Job %d canceled\n", job.ID)
+		saved, saveErr = w.repo.Job.Finish(job.ID, w.workerID, model.JobStatusCanceled, errJobCanceled.Error())
+	case ctx.Err() != nil:
+		// the next worker starts it again
+		saved, saveErr = w.repo.Job.Retry(job.ID, w.workerID, time.Now(), "worker stopped while running")
+	case err == nil:
+		log.Printf("Job %d succeeded\n", job.ID)
+		saved, saveErr = w.repo.Job.Finish(job.ID, w.workerID, model.JobStatusSucceeded, "")
+	case job.Attempts < job.MaxAttempts:
+		log.Printf("Job %d attempt %d failed: %v\n", job.ID, job.Attempts, err)
+		saved, saveErr = w.repo.Job.Retry(job.ID, w.workerID, time.Now().Add(w.options.RetryDelay*time.Duration(job.Attempts)), err.Error())
+	default:
+		log.Printf("Job %d failed: %v\n", job.ID, err)
+		saved, saveErr = w.repo.Job.Finish(job.ID, w.workerID, model.JobStatusFailed, err.Error())
+	}
+	if saveErr != nil {
+		log.Printf("Job %d failed to save result: %v\n", job.ID, saveErr)
+	} else if !saved {
+		log.Printf("Job %d lost its lock, result dropped\n", job.ID)
+	}
+}
+
+// heartbeat keeps the lock of a running job, it calls cancel when cancellation is requested and lose when
+// the job was requeued or claimed by another worker in the meantime
+func (w *JobWorker) heartbeat(ctx context.Context, id uint, done <-chan struct{}, cancel, lose func()) {
+	interval := w.options.LockTimeout / 3
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+			held, cancelRequested, err := w.repo.Job.Heartbeat(id, w.workerID)
+			if err != nil {
+				log.Printf("Job %d heartbeat failed: %v\n", id, err)
+				continue
+			}
+			if !held {
+				lose()
+				return
+			}
+			if cancelRequested {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// Start ticks every interval until ctx is done, then waits for running jobs to save their result
+func (w *JobWorker) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.wg.Wait()
+			return
+		case <-ticker.C:
+			if err := w.Tick(ctx); err != nil {
+				log.Printf("Job worker failed: %v\n", err)
+			}
+		}
+	}
+}
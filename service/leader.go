@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"gitlab/live/be-live-admin/cache"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+const DEFAULT_LEADER_LOCK_TTL = 30 * time.Second
+
+// LeaderElector holds the redis leader lock for this instance. The scheduler and the background loops which
+// must not run on several be-admin at once only do their work while IsLeader is true.
+type LeaderElector struct {
+	redisStore cache.RedisStore
+	ttl        time.Duration // lock expires when the instance dies
+	instanceID string
+	leader     atomic.Bool
+}
+
+func NewLeaderElector(redis cache.RedisStore, ttl time.Duration) *LeaderElector {
+	if ttl <= 0 {
+		ttl = DEFAULT_LEADER_LOCK_TTL
+	}
+	return &LeaderElector{
+		redisStore: redis,
+		ttl:        ttl,
+		instanceID: utils.MakeUniqueID(),
+	}
+}
+
+// Elect takes the lock or extends it when this instance holds it already
+func (l *LeaderElector) Elect(ctx context.Context) bool {
+	isLeader, err := l.redisStore.AcquireLock(ctx, cache.SCHEDULER_LEADER_KEY, l.instanceID, l.ttl)
+	if err != nil {
+		log.Printf("Failed to acquire leader lock: %v\n", err)
+		isLeader = false
+	}
+	l.leader.Store(isLeader)
+	return isLeader
+}
+
+// IsLeader is the result of the last election
+func (l *LeaderElector) IsLeader() bool {
+	return l.leader.Load()
+}
+
+// Start renews the lock well before it expires until ctx is done, then releases it
+func (l *LeaderElector) Start(ctx context.Context) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	l.Elect(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			l.leader.Store(false)
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := l.redisStore.ReleaseLock(releaseCtx, cache.SCHEDULER_LEADER_KEY, l.instanceID); err != nil {
+				log.Printf("Failed to release leader lock: %v\n", err)
+			}
+			return
+		case <-ticker.C:
+			l.Elect(ctx)
+		}
+	}
+}
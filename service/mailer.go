@@ -0,0 +1,42 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+)
+
+// Mailer sends plain text emails
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+}
+
+func NewSMTPMailer(host string, port int, username, password string) *SMTPMailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+	}
+}
+
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	auth := smtp.PlainAuth("", m.username, m.password, m.host)
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=\"utf-8\"\r\n\r\n%s", m.username, to, subject, body)
+	return smtp.SendMail(fmt.Sprintf("%s:%d", m.host, m.port), auth, m.username, []string{to}, []byte(msg))
+}
+
+// LogMailer logs emails instead of sending them, for running without smtp
+type LogMailer struct{}
+
+func (m *LogMailer) Send(to, subject, body string) error {
+	log.Printf("Mail to %s: %s\n%s\n", to, subject, body)
+	return nil
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"maps"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_NOTIFICATION_LOCALE = "en"
+	NOTIFICATION_TIME_LAYOUT    = "2006-01-02 15:04:05 -0700"
+)
+
+var (
+	ErrNotificationTemplateType     = errors.New("notification type has no templates")
+	ErrNotificationTemplateLocale   = errors.New("invalid locale, use a language tag like en or pt-BR")
+	ErrNotificationTemplateInvalid  = errors.New("invalid template")
+	ErrNotificationTemplateNotFound = errors.New("template not found")
+	ErrNotificationTemplateConflict = errors.New("template was saved by someone else at the same time, try again")
+)
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// notificationVariables are the variables templates of a type can use, with the sample data of previews
+var notificationVariables = map[model.NotificationType]map[string]string{
+	model.NotificationTypeSubscribeLive: {
+		"Username": "jane", "DisplayName": "Jane Doe", "StreamerName": "John Smith", "StreamTitle": "Friday night live",
+	},
+	model.NotificationTypeSubscribeVideo: {
+		"Username": "jane", "DisplayName": "Jane Doe", "StreamerName": "John Smith", "StreamTitle": "Friday night live",
+	},
+	model.NotificationTypeBlocked: {
+		"Username": "jane", "DisplayName": "Jane Doe", "Reason": "Spam", "SuspendedUntil": "2025-01-31 12:00:00 +0000",
+	},
+	model.NotificationTypeDeleted: {
+		"Username": "jane", "DisplayName": "Jane Doe",
+	},
+	model.NotificationTypeAnnouncement: {
+		"Username": "jane", "DisplayName": "Jane Doe", "Title": "Scheduled maintenance", "Body": "Streaming is down from 2 to 3 am.",
+	},
+}
+
+// defaultNotificationTemplates are used when no locale of the chain has a template
+var defaultNotificationTemplates = map[model.NotificationType][2]string{
+	model.NotificationTypeSubscribeLive:  {"", "{{.StreamerName}} is live: {{.StreamTitle}}"},
+	model.NotificationTypeSubscribeVideo: {"", "{{.StreamerName}} posted a new video: {{.StreamTitle}}"},
+	model.NotificationTypeBlocked:        {"Account blocked", "Your account was blocked{{if .SuspendedUntil}} until {{.SuspendedUntil}}{{end}}. Reason: {{.Reason}}"},
+	model.NotificationTypeDeleted:        {"Account deleted", "Your account was deleted."},
+	model.NotificationTypeAnnouncement:   {"{{.Title}}", "{{.Body}}"},
+}
+
+// normalizeLocale lower cases a language tag, pt_BR becomes pt-br
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// localeChain is the locale, its parents and the default locale, pt-br falls back to pt and en
+func localeChain(locale string) []string {
+	var result []string
+	for locale = normalizeLocale(locale); locale != ""; {
+		result = append(result, locale)
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	if len(result) == 0 || result[len(result)-1] != DEFAULT_NOTIFICATION_LOCALE {
+		result = append(result, DEFAULT_NOTIFICATION_LOCALE)
+	}
+	return result
+}
+
+// compiledNotificationTemplate is a template ready to render, Version 0 is built in
+type compiledNotificationTemplate struct {
+	Locale  string
+	Version uint
+	title   *template.Template
+	body    *template.Template
+}
+
+// compileNotificationTemplate parses title and body and renders them with the sample data, so templates using
+// variables the type doesn't have are rejected
+func compileNotificationTemplate(notificationType model.NotificationType, title, body string) (*compiledNotificationTemplate, error) {
+	result := &compiledNotificationTemplate{}
+	var err error
+	if result.title, err = template.New("title").Option("missingkey=error").Parse(title); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotificationTemplateInvalid, err)
+	}
+	if result.body, err = template.New("body").Option("missingkey=error").Parse(body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotificationTemplateInvalid, err)
+	}
+	if _, err := result.render(notificationType, notificationVariables[notificationType]); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// render executes the template with data, variables missing from data are empty
+func (t *compiledNotificationTemplate) render(notificationType model.NotificationType, data map[string]string) (*dto.RenderedNotificationDTO, error) {
+	values := maps.Clone(notificationVariables[notificationType])
+	for key := range values {
+		values[key] = ""
+	}
+	maps.Copy(values, data)
+
+	var title, body strings.Builder
+	if err := t.title.Execute(&title, values); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotificationTemplateInvalid, err)
+	}
+	if err := t.body.Execute(&body, values); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotificationTemplateInvalid, err)
+	}
+	return &dto.RenderedNotificationDTO{
+		Type:    notificationType,
+		Locale:  t.Locale,
+		Version: t.Version,
+		Title:   strings.TrimSpace(title.String()),
+		Content: strings.TrimSpace(body.String()),
+	}, nil
+}
+
+// NotificationTemplateService renders notification contents in the language of the user
+type NotificationTemplateService struct {
+	repo *repository.Repository
+}
+
+func newNotificationTemplateService(repo *repository.Repository) *NotificationTemplateService {
+	return &NotificationTemplateService{
+		repo: repo,
+	}
+}
+
+func toNotificationTemplateDto(template *model.NotificationTemplate) dto.NotificationTemplateDTO {
+	return dto.NotificationTemplateDTO{
+		ID:        template.ID,
+		Type:      template.Type,
+		Locale:    template.Locale,
+		Version:   template.Version,
+		Title:     template.Title,
+		Body:      template.Body,
+		CreatedBy: toUserSummaryDto(template.CreatedBy),
+		CreatedAt: template.CreatedAt,
+	}
+}
+
+// ParseKey checks the type and locale of a template, the locale is normalized
+func (s *NotificationTemplateService) ParseKey(notificationType, locale string) (model.NotificationType, string, error) {
+	if _, ok := notificationVariables[model.NotificationType(notificationType)]; !ok {
+		return "", "", ErrNotificationTemplateType
+	}
+	locale = normalizeLocale(locale)
+	if !localePattern.MatchString(locale) {
+		return "", "", ErrNotificationTemplateLocale
+	}
+	return model.NotificationType(notificationType), locale, nil
+}
+
+func (s *NotificationTemplateService) GetTemplates(req *dto.NotificationTemplateQuery) (*utils.PaginationModel[dto.NotificationTemplateDTO], error) {
+	req.Locale = normalizeLocale(req.Locale)
+	pagination, err := s.repo.NotificationTemplate.PageLatest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.NotificationTemplateDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.NotificationTemplate) dto.NotificationTemplateDTO {
+		return toNotificationTemplateDto(&e)
+	})
+	return result, nil
+}
+
+func (s *NotificationTemplateService) GetVariables() []dto.NotificationTemplateVariablesDTO {
+	result := make([]dto.NotificationTemplateVariablesDTO, 0, len(notificationVariables))
+	for _, notificationType := range []model.NotificationType{model.NotificationTypeSubscribeLive, model.NotificationTypeSubscribeVideo,
+		model.NotificationTypeBlocked, model.NotificationTypeDeleted, model.NotificationTypeAnnouncement} {
+		result = append(result, dto.NotificationTemplateVariablesDTO{
+			Type:         notificationType,
+			Variables:    notificationVariables[notificationType],
+			DefaultTitle: defaultNotificationTemplates[notificationType][0],
+			DefaultBody:  defaultNotificationTemplates[notificationType][1],
+		})
+	}
+	return result
+}
+
+func (s *NotificationTemplateService) GetVersions(notificationType model.NotificationType, locale string) ([]dto.NotificationTemplateDTO, error) {
+	versions, err := s.repo.NotificationTemplate.FindVersions(notificationType, locale)
+	if err != nil {
+		return nil, err
+	}
+	return utils.Map(versions, func(e model.NotificationTemplate) dto.NotificationTemplateDTO {
+		return toNotificationTemplateDto(&e)
+	}), nil
+}
+
+// Save checks the template renders with the sample data of its type and saves it as the next version
+func (s *NotificationTemplateService) Save(notificationType model.NotificationType, locale string, req *dto.NotificationTemplateRequest, createdByID uint) (*dto.NotificationTemplateDTO, error) {
+	if _, err := compileNotificationTemplate(notificationType, req.Title, req.Body); err != nil {
+		return nil, err
+	}
+	template := &model.NotificationTemplate{
+		Type:        notificationType,
+		Locale:      locale,
+		Title:       req.Title,
+		Body:        req.Body,
+		CreatedByID: &createdByID,
+	}
+	// concurrent saves read the same latest version, the unique index rejects all but one of them
+	err := s.repo.NotificationTemplate.CreateVersion(template)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		err = s.repo.NotificationTemplate.CreateVersion(template)
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrNotificationTemplateConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.repo.NotificationTemplate.FindVersion(notificationType, locale, template.Version)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		created = template
+	}
+	result := toNotificationTemplateDto(created)
+	return &result, nil
+}
+
+// Restore saves a copy of an earlier version as the next version
+func (s *NotificationTemplateService) Restore(notificationType model.NotificationType, locale string, version uint, createdByID uint) (*dto.NotificationTemplateDTO, error) {
+	restored, err := s.repo.NotificationTemplate.FindVersion(notificationType, locale, version)
+	if err != nil {
+		return nil, err
+	}
+	if restored == nil {
+		return nil, ErrNotificationTemplateNotFound
+	}
+	return s.Save(notificationType, locale, &dto.NotificationTemplateRequest{Title: restored.Title, Body: restored.Body}, createdByID)
+}
+
+// Delete removes every version of the template, the locale falls back to its parents afterwards
+func (s *NotificationTemplateService) Delete(notificationType model.NotificationType, locale string) error {
+	deleted, err := s.repo.NotificationTemplate.Delete(notificationType, locale)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotificationTemplateNotFound
+	}
+	return nil
+}
+
+// Preview renders the title and body of req, or the template its locale resolves to when body is empty
+func (s *NotificationTemplateService) Preview(req *dto.NotificationTemplatePreviewRequest) (*dto.RenderedNotificationDTO, error) {
+	data := maps.Clone(notificationVariables[req.Type])
+	maps.Copy(data, req.Data)
+
+	if req.Body != "" {
+		compiled, err := compileNotificationTemplate(req.Type, req.Title, req.Body)
+		if err != nil {
+			return nil, err
+		}
+		compiled.Locale = normalizeLocale(req.Locale)
+		return compiled.render(req.Type, data)
+	}
+	return s.Renderer(req.Type).Render(req.Locale, data)
+}
+
+// Render renders a single notification, see Renderer for many
+func (s *NotificationTemplateService) Render(notificationType model.NotificationType, locale string, data map[string]string) (*dto.RenderedNotificationDTO, error) {
+	return s.Renderer(notificationType).Render(locale, data)
+}
+
+// RenderBlocked renders the account_blocked notification of the user, suspendedUntil is nil when it's blocked permanently
+func (s *NotificationTemplateService) RenderBlocked(user *model.User, reason string, suspendedUntil *time.Time) (*dto.RenderedNotificationDTO, error) {
+	data := map[string]string{
+		"Username":    user.Username,
+		"DisplayName": user.DisplayName,
+		"Reason":      reason,
+	}
+	if suspendedUntil != nil {
+		data["SuspendedUntil"] = suspendedUntil.UTC().Format(NOTIFICATION_TIME_LAYOUT)
+	}
+	return s.Render(model.NotificationTypeBlocked, user.Locale, data)
+}
+
+// Renderer returns a renderer of the type which loads the template of each locale once
+func (s *NotificationTemplateService) Renderer(notificationType model.NotificationType) *NotificationRenderer {
+	return &NotificationRenderer{
+		repo:             s.repo,
+		notificationType: notificationType,
+		templates:        map[string]*compiledNotificationTemplate{},
+	}
+}
+
+// NotificationRenderer caches the templates locales resolve to, it's meant for one batch of notifications
+type NotificationRenderer struct {
+	repo             *repository.Repository
+	notificationType model.NotificationType
+	templates        map[string]*compiledNotificationTemplate
+}
+
+// resolve returns the latest template of the first locale of the chain which has a valid one, or the built in template
+func (r *NotificationRenderer) resolve(locale string) (*compiledNotificationTemplate, error) {
+	locale = normalizeLocale(locale)
+	if compiled, ok := r.templates[locale]; ok {
+		return compiled, nil
+	}
+
+	chain := localeChain(locale)
+	templates, err := r.repo.NotificationTemplate.FindLatest(r.notificationType, chain)
+	if err != nil {
+		return nil, err
+	}
+	byLocale := make(map[string]*model.NotificationTemplate, len(templates))
+	for i := range templates {
+		byLocale[templates[i].Locale] = &templates[i]
+	}
+
+	var result *compiledNotificationTemplate
+	for _, candidate := range chain {
+		found, ok := byLocale[candidate]
+		if !ok {
+			continue
+		}
+		compiled, err := compileNotificationTemplate(r.notificationType, found.Title, found.Body)
+		if err != nil {
+			log.Printf("Skipped %s notification template %s version %d: %v\n", r.notificationType, found.Locale, found.Version, err)
+			continue
+		}
+		compiled.Locale, compiled.Version = found.Locale, found.Version
+		result = compiled
+		break
+	}
+	if result == nil {
+		defaults, ok := defaultNotificationTemplates[r.notificationType]
+		if !ok {
+			return nil, ErrNotificationTemplateType
+		}
+		if result, err = compileNotificationTemplate(r.notificationType, defaults[0], defaults[1]); err != nil {
+			return nil, err
+		}
+		result.Locale = DEFAULT_NOTIFICATION_LOCALE
+	}
+	r.templates[locale] = result
+	return result, nil
+}
+
+// Render renders the notification in the locale, variables missing from data are empty
+func (r *NotificationRenderer) Render(locale string, data map[string]string) (*dto.RenderedNotificationDTO, error) {
+	compiled, err := r.resolve(locale)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.render(r.notificationType, data)
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+)
+
+var (
+	ErrPlaylistNotEditable  = errors.New("playlist can only be edited before the stream starts")
+	ErrInvalidPlaylistItem  = errors.New("invalid playlist item")
+	ErrPlaylistItemNotFound = errors.New("playlist item not found")
+)
+
+type PlaylistService struct {
+	repo *repository.Repository
+}
+
+func newPlaylistService(repo *repository.Repository) *PlaylistService {
+	return &PlaylistService{
+		repo: repo,
+	}
+}
+
+func toPlaylistItemDto(item *model.ScheduleStreamItem, apiURL string) dto.PlaylistItemDTO {
+	return dto.PlaylistItemDTO{
+		ID:           item.ID,
+		Position:     item.Position,
+		Type:         item.Type,
+		URL:          utils.MakeScheduleVideoURL(apiURL, item.FileName),
+		Duration:     item.Duration,
+		InPoint:      item.InPoint,
+		OutPoint:     item.OutPoint,
+		PlayDuration: item.PlayDuration(),
+	}
+}
+
+// total duration of items in seconds, rounded up
+func playlistDuration(items []model.ScheduleStreamItem) uint {
+	var total uint
+	for i := range items {
+		total += items[i].PlayDuration()
+	}
+	return (total + 999) / 1000
+}
+
+func validatePlaylistItem(item *model.ScheduleStreamItem) error {
+	if item.Type == model.PlaylistItemTypeSlate {
+		if item.Duration == 0 {
+			return fmt.Errorf("%w: duration of slate is required", ErrInvalidPlaylistItem)
+		}
+		return nil
+	}
+
+	if item.OutPoint > 0 && item.OutPoint <= item.InPoint {
+		return fmt.Errorf("%w: out_point must be after in_point", ErrInvalidPlaylistItem)
+	}
+	// duration is 0 when the video couldn't be probed
+	if item.Duration > 0 && (item.InPoint >= item.Duration || item.OutPoint > item.Duration) {
+		return fmt.Errorf("%w: in_point and out_point must be within the video of %dms", ErrInvalidPlaylistItem, item.Duration)
+	}
+	return nil
+}
+
+// upcoming scheduled stream of streamID, playlists of started streams can't change.
+// The stream row stays locked until the transaction of repo ends, so the scheduler can't claim it meanwhile.
+func getEditableScheduleStream(repo *repository.Repository, streamID uint) (*model.ScheduleStream, error) {
+	stream, err := repo.Stream.GetByIDForUpdate(streamID)
+	if err != nil {
+		return nil, err
+	}
+	if stream.Status != model.UPCOMING {
+		return nil, ErrPlaylistNotEditable
+	}
+	scheduleStream, err := repo.Stream.GetScheduleStreamByStreamID(int(streamID))
+	if err != nil {
+		return nil, err
+	}
+	scheduleStream.Stream = *stream
+	return scheduleStream, nil
+}
+
+// a longer playlist may overlap following streams
+func checkPlaylistConflicts(repo *repository.Repository, scheduleStream *model.ScheduleStream, totalDuration uint, limits ScheduleLimits) error {
+	if totalDuration <= scheduleStream.VideoDuration {
+		return nil
+	}
+	categories, err := repo.Stream.GetCategoriesByStreamID(scheduleStream.StreamID)
+	if err != nil {
+		return err
+	}
+	return checkScheduleConflicts(repo, []scheduleSlot{{
+		UserID:          scheduleStream.Stream.UserID,
+		CategoryIDs:     utils.Map(categories, func(e model.Category) uint { return e.ID }),
+		StartAt:         scheduleStream.ScheduledAt,
+		Duration:        limits.duration(totalDuration),
+		ExcludeStreamID: scheduleStream.StreamID,
+	}}, limits)
+}
+
+func (s *PlaylistService) GetPlaylist(streamID uint, apiURL string) (*dto.PlaylistRespDTO, error) {
+	scheduleStream, err := s.repo.Stream.GetScheduleStreamByStreamID(int(streamID))
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.Playlist.FindItems(scheduleStream.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PlaylistRespDTO{
+		TotalDuration: scheduleStream.VideoDuration,
+		Items: utils.Map(items, func(e model.ScheduleStreamItem) dto.PlaylistItemDTO {
+			return toPlaylistItemDto(&e, apiURL)
+		}),
+	}, nil
+}
+
+// GetPlaylistItems returns items of a scheduled stream in push order, nil when it plays its video only
+func (s *PlaylistService) GetPlaylistItems(scheduleStreamID uint) ([]model.ScheduleStreamItem, error) {
+	return s.repo.Playlist.FindItems(scheduleStreamID)
+}
+
+// AddItem inserts an uploaded video or slate at req.Position, the video of the stream becomes the first item of a new playlist
+func (s *PlaylistService) AddItem(streamID uint, req *dto.PlaylistItemRequest, limits ScheduleLimits) (*model.ScheduleStreamItem, error) {
+	var item *model.ScheduleStreamItem
+	if err := s.repo.WithScheduleLock(func(repo *repository.Repository) error {
+		scheduleStream, err := getEditableScheduleStream(repo, streamID)
+		if err != nil {
+			return err
+		}
+		items, err := repo.Playlist.FindItems(scheduleStream.ID)
+		if err != nil {
+			return err
+		}
+
+		var seed *model.ScheduleStreamItem
+		if len(items) == 0 {
+			seed = &model.ScheduleStreamItem{
+				ScheduleStreamID: scheduleStream.ID,
+				Position:         0,
+				Type:             model.PlaylistItemTypeVideo,
+				FileName:         scheduleStream.VideoName,
+				Duration:         scheduleStream.VideoDuration * 1000,
+			}
+			items = append(items, *seed)
+		}
+
+		item = &model.ScheduleStreamItem{
+			ScheduleStreamID: scheduleStream.ID,
+			Position:         uint(len(items)),
+			Type:             req.Type,
+			FileName:         req.FileName,
+			Duration:         req.FileDuration,
+			InPoint:          req.InPoint,
+			OutPoint:         req.OutPoint,
+		}
+		if req.Type == model.PlaylistItemTypeSlate {
+			item.Duration = req.Duration
+			item.InPoint, item.OutPoint = 0, 0
+		}
+		if req.Position != nil && *req.Position < item.Position {
+			item.Position = *req.Position
+		}
+		if err := validatePlaylistItem(item); err != nil {
+			return err
+		}
+
+		totalDuration := playlistDuration(append(items, *item))
+		if err := checkPlaylistConflicts(repo, scheduleStream, totalDuration, limits); err != nil {
+			return err
+		}
+		return repo.Playlist.AddItem(seed, item, totalDuration)
+	}); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// UpdateItem trims or moves an item, shown time of slates can be changed as well
+func (s *PlaylistService) UpdateItem(streamID, itemID uint, req *dto.UpdatePlaylistItemRequest, limits ScheduleLimits) (*model.ScheduleStreamItem, error) {
+	var item *model.ScheduleStreamItem
+	if err := s.repo.WithScheduleLock(func(repo *repository.Repository) error {
+		scheduleStream, err := getEditableScheduleStream(repo, streamID)
+		if err != nil {
+			return err
+		}
+		items, err := repo.Playlist.FindItems(scheduleStream.ID)
+		if err != nil {
+			return err
+		}
+
+		index := -1
+		for i := range items {
+			if items[i].ID == itemID {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return ErrPlaylistItemNotFound
+		}
+
+		item = &items[index]
+		oldPosition := item.Position
+		if req.Position != nil {
+			item.Position = min(*req.Position, uint(len(items)-1))
+		}
+		if item.Type == model.PlaylistItemTypeSlate {
+			if req.InPoint != nil || req.OutPoint != nil {
+				return fmt.Errorf("%w: slates can't be trimmed", ErrInvalidPlaylistItem)
+			}
+			if req.Duration != nil {
+				item.Duration = *req.Duration
+			}
+		} else {
+			// duration of videos is probed
+			if req.Duration != nil {
+				return fmt.Errorf("%w: duration is only set for slates", ErrInvalidPlaylistItem)
+			}
+			if req.InPoint != nil {
+				item.InPoint = *req.InPoint
+			}
+			if req.OutPoint != nil {
+				item.OutPoint = *req.OutPoint
+			}
+		}
+		if err := validatePlaylistItem(item); err != nil {
+			return err
+		}
+
+		totalDuration := playlistDuration(items)
+		if err := checkPlaylistConflicts(repo, scheduleStream, totalDuration, limits); err != nil {
+			return err
+		}
+		return repo.Playlist.UpdateItem(item, oldPosition, totalDuration)
+	}); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// DeleteItem removes an item and returns it so its file can be removed
+func (s *PlaylistService) DeleteItem(streamID, itemID uint) (*model.ScheduleStreamItem, error) {
+	var deleted *model.ScheduleStreamItem
+	if err := s.repo.WithScheduleLock(func(repo *repository.Repository) error {
+		scheduleStream, err := getEditableScheduleStream(repo, streamID)
+		if err != nil {
+			return err
+		}
+		items, err := repo.Playlist.FindItems(scheduleStream.ID)
+		if err != nil {
+			return err
+		}
+
+		remaining := make([]model.ScheduleStreamItem, 0, len(items))
+		for i := range items {
+			if items[i].ID == itemID {
+				deleted = &items[i]
+				continue
+			}
+			remaining = append(remaining, items[i])
+		}
+		if deleted == nil {
+			return ErrPlaylistItemNotFound
+		}
+
+		if len(remaining) == 0 {
+			return fmt.Errorf("%w: playlist needs at least one item", ErrInvalidPlaylistItem)
+		}
+		return repo.Playlist.DeleteItem(deleted, playlistDuration(remaining))
+	}); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// IsStreamVideo reports whether fileName is the video of the scheduled stream, which is kept when its item is removed
+func (s *PlaylistService) IsStreamVideo(streamID uint, fileName string) (bool, error) {
+	scheduleStream, err := s.repo.Stream.GetScheduleStreamByStreamID(int(streamID))
+	if err != nil {
+		return false, err
+	}
+	return scheduleStream.VideoName == fileName, nil
+}
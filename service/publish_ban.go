@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/cache"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"log"
+	"time"
+)
+
+var (
+	ErrPublishBanned    = errors.New("user is banned from going live")
+	ErrNotPublishBanned = errors.New("user is not banned from going live")
+)
+
+// PublishBanService stops users from going live without blocking their accounts
+type PublishBanService struct {
+	repo         *repository.Repository
+	redisStore   cache.RedisStore
+	stream       *StreamService
+	streamServer *streamServerService
+}
+
+func newPublishBanService(repo *repository.Repository, redis cache.RedisStore, stream *StreamService, streamServer *streamServerService) *PublishBanService {
+	return &PublishBanService{
+		repo:         repo,
+		redisStore:   redis,
+		stream:       stream,
+		streamServer: streamServer,
+	}
+}
+
+func toPublishBanDto(ban *model.PublishBan) *dto.PublishBanDTO {
+	return &dto.PublishBanDTO{
+		ID:        ban.ID,
+		Reason:    ban.Reason,
+		ExpiresAt: ban.ExpiresAt,
+		CreatedBy: toUserSummaryDto(ban.CreatedBy),
+		CreatedAt: ban.CreatedAt,
+	}
+}
+
+// writeCache sets the key checked by be-api, it expires with the ban
+func (s *PublishBanService) writeCache(ctx context.Context, ban *model.PublishBan, now time.Time) error {
+	var expiration time.Duration
+	if ban.ExpiresAt != nil {
+		expiration = ban.ExpiresAt.Sub(now)
+		if expiration <= 0 {
+			return nil
+		}
+	}
+	return s.redisStore.Set(ctx, fmt.Sprintf(cache.PUBLISH_BAN_PREFIX, ban.UserID), ban.Reason, expiration)
+}
+
+// Ban replaces the active ban of the user. Live streams of the user lose their key on the stream server and are
+// ended through be-api, failures are logged since the ban is in place already.
+func (s *PublishBanService) Ban(ctx context.Context, user *model.User, req *dto.PublishBanRequest, createdByID uint) (*dto.PublishBanResponse, error) {
+	now := time.Now()
+	ban := &model.PublishBan{
+		UserID:      user.ID,
+		Reason:      req.Reason,
+		CreatedByID: &createdByID,
+		CreatedAt:   now,
+	}
+	if req.Duration > 0 {
+		expiresAt := now.Add(time.Duration(req.Duration) * time.Second)
+		ban.ExpiresAt = &expiresAt
+	}
+	if err := s.repo.PublishBan.Replace(ban); err != nil {
+		return nil, err
+	}
+	if err := s.writeCache(ctx, ban, now); err != nil {
+		return nil, err
+	}
+
+	streams, err := s.repo.Stream.GetStartedStreamsByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.PublishBanResponse{EndedStreamIDs: []uint{}}
+	for _, stream := range streams {
+		if err := s.streamServer.DeleteChannelKey(stream.StreamKey); err != nil {
+			log.Printf("Failed to delete channel key of stream %d: %v\n", stream.ID, err)
+		}
+		if err := s.stream.EndLivByRedis(ctx, stream.ID); err != nil {
+			log.Printf("Failed to end stream %d of banned user %d: %v\n", stream.ID, user.ID, err)
+			continue
+		}
+		result.EndedStreamIDs = append(result.EndedStreamIDs, stream.ID)
+	}
+
+	created, err := s.repo.PublishBan.FindActive(user.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		created = ban
+	}
+	result.Ban = *toPublishBanDto(created)
+	return result, nil
+}
+
+func (s *PublishBanService) Lift(ctx context.Context, userID, liftedByID uint) error {
+	lifted, err := s.repo.PublishBan.Lift(userID, liftedByID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !lifted {
+		return ErrNotPublishBanned
+	}
+	return s.redisStore.Remove(ctx, fmt.Sprintf(cache.PUBLISH_BAN_PREFIX, userID))
+}
+
+// GetActive returns nil when the user may go live
+func (s *PublishBanService) GetActive(userID uint) (*dto.PublishBanDTO, error) {
+	ban, err := s.repo.PublishBan.FindActive(userID, time.Now())
+	if err != nil || ban == nil {
+		return nil, err
+	}
+	return toPublishBanDto(ban), nil
+}
+
+// Sync rewrites the keys of active bans, in case redis lost them
+func (s *PublishBanService) Sync(ctx context.Context) error {
+	now := time.Now()
+	bans, err := s.repo.PublishBan.FindAllActive(now)
+	if err != nil {
+		return err
+	}
+	for _, ban := range bans {
+		if err := s.writeCache(ctx, &ban, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,435 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"time"
+)
+
+const (
+	nextOccurrencesLimit = 5
+
+	DEFAULT_RECURRENCE_HORIZON = 7 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidRecurrence = errors.New("invalid recurrence")
+	ErrRecurrenceHorizon = errors.New("schedule.recurrence_horizon must be at least schedule.window")
+)
+
+// RecurrenceHorizon defaults horizon, it must cover the schedule window since conflicts are only checked
+// against created streams and occurrences beyond the horizon don't exist yet
+func RecurrenceHorizon(horizon, window time.Duration) (time.Duration, error) {
+	if horizon <= 0 {
+		horizon = DEFAULT_RECURRENCE_HORIZON
+	}
+	if horizon < window {
+		return 0, fmt.Errorf("%w, %s is less than %s", ErrRecurrenceHorizon, horizon, window)
+	}
+	return horizon, nil
+}
+
+type RecurrenceService struct {
+	repo *repository.Repository
+}
+
+func newRecurrenceService(repo *repository.Repository) *RecurrenceService {
+	return &RecurrenceService{
+		repo: repo,
+	}
+}
+
+type recurrenceRule struct {
+	rule     *utils.RRule
+	location *time.Location
+}
+
+func parseRecurrenceRule(rrule, timezone string) (*recurrenceRule, error) {
+	rule, err := utils.ParseRRule(rrule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRecurrence, err)
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %s", ErrInvalidRecurrence, timezone)
+	}
+	return &recurrenceRule{rule: rule, location: location}, nil
+}
+
+func parseExDates(exdates []string) ([]time.Time, error) {
+	result := make([]time.Time, 0, len(exdates))
+	for _, exdate := range exdates {
+		parsed, err := utils.ConvertDatetimeToTimestamp(exdate, utils.DATETIME_LAYOUT)
+		if err != nil {
+			return nil, fmt.Errorf("%w: exdate %s", ErrInvalidRecurrence, exdate)
+		}
+		result = append(result, *parsed)
+	}
+	return result, nil
+}
+
+// occurrences of the series in [from, to) without exceptions
+func (r *recurrenceRule) between(recurrence *model.StreamRecurrence, from, to time.Time) []time.Time {
+	exdates := utils.Map(recurrence.Exceptions, func(e model.StreamRecurrenceException) time.Time {
+		return e.OccurrenceAt
+	})
+	return r.rule.Between(recurrence.StartAt.In(r.location), from, to, exdates)
+}
+
+func (s *RecurrenceService) toStreamRecurrenceDto(recurrence *model.StreamRecurrence, apiURL string) dto.StreamRecurrenceRespDTO {
+	result := dto.StreamRecurrenceRespDTO{
+		ID: recurrence.ID,
+		User: dto.UserResponseDTO{
+			ID:          recurrence.User.ID,
+			Username:    recurrence.User.Username,
+			DisplayName: recurrence.User.DisplayName,
+			Email:       recurrence.User.Email,
+		},
+		Title:       recurrence.Title,
+		Description: recurrence.Description,
+		RRule:       recurrence.RRule,
+		Timezone:    recurrence.Timezone,
+		StartAt:     recurrence.StartAt,
+		ExDates: utils.Map(recurrence.Exceptions, func(e model.StreamRecurrenceException) time.Time {
+			return e.OccurrenceAt
+		}),
+		Categories: utils.Map(recurrence.Categories, func(e model.Category) dto.CategoryDTO {
+			return dto.CategoryDTO{ID: e.ID, Name: e.Name, CreatedAt: e.CreatedAt}
+		}),
+		ThumbnailURL:    utils.MakeThumbnailURL(apiURL, recurrence.ThumbnailFileName),
+		VideoURL:        utils.MakeScheduleVideoURL(apiURL, recurrence.VideoName),
+		VideoDuration:   recurrence.VideoDuration,
+		NextOccurrences: []time.Time{},
+		CreatedAt:       recurrence.CreatedAt,
+		UpdatedAt:       recurrence.UpdatedAt,
+	}
+	if recurrence.MaterializedUntil.Valid {
+		result.MaterializedUntil = &recurrence.MaterializedUntil.Time
+	}
+
+	if rule, err := parseRecurrenceRule(recurrence.RRule, recurrence.Timezone); err == nil {
+		// a year is enough to show next ones of any supported frequency
+		now := time.Now()
+		occurrences := rule.between(recurrence, now, now.AddDate(1, 0, 0))
+		result.NextOccurrences = occurrences[:min(len(occurrences), nextOccurrencesLimit)]
+	}
+	return result
+}
+
+func (s *RecurrenceService) GetAll(req *dto.StreamRecurrenceQuery, apiURL string) (*utils.PaginationModel[dto.StreamRecurrenceRespDTO], error) {
+	pagination, err := s.repo.Recurrence.Page(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.StreamRecurrenceRespDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.StreamRecurrence) dto.StreamRecurrenceRespDTO {
+		return s.toStreamRecurrenceDto(&e, apiURL)
+	})
+	return result, nil
+}
+
+func (s *RecurrenceService) GetByID(id uint) (*model.StreamRecurrence, error) {
+	return s.repo.Recurrence.FindByID(id)
+}
+
+func (s *RecurrenceService) GetDtoByID(id uint, apiURL string) (*dto.StreamRecurrenceRespDTO, error) {
+	recurrence, err := s.repo.Recurrence.FindByID(id)
+	if err != nil || recurrence == nil {
+		return nil, err
+	}
+	result := s.toStreamRecurrenceDto(recurrence, apiURL)
+	return &result, nil
+}
+
+// occurrences of the series up to horizon as slots for conflict detection
+func (s *RecurrenceService) scheduleSlots(recurrence *model.StreamRecurrence, rule *recurrenceRule, categoryIDs []uint, from time.Time, horizon time.Duration, limits ScheduleLimits) []scheduleSlot {
+	return utils.Map(rule.between(recurrence, from, time.Now().Add(horizon)), func(e time.Time) scheduleSlot {
+		return scheduleSlot{
+			UserID:              recurrence.UserID,
+			CategoryIDs:         categoryIDs,
+			StartAt:             e,
+			Duration:            limits.duration(recurrence.VideoDuration),
+			ExcludeRecurrenceID: recurrence.ID,
+		}
+	})
+}
+
+// Create saves the series and materializes occurrences up to horizon
+func (s *RecurrenceService) Create(req *dto.StreamRecurrenceRequest, horizon time.Duration, limits ScheduleLimits) (*model.StreamRecurrence, error) {
+	rule, err := parseRecurrenceRule(req.RRule, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	startAt, err := utils.ConvertDatetimeToTimestamp(req.StartAt, utils.DATETIME_LAYOUT)
+	if err != nil {
+		return nil, err
+	}
+	exdates, err := parseExDates(req.ExDates)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrence := &model.StreamRecurrence{
+		UserID:            req.UserID,
+		Title:             req.Title,
+		Description:       req.Description,
+		ThumbnailFileName: req.ThumbnailFileName,
+		VideoName:         req.VideoFileName,
+		VideoDuration:     req.VideoDuration,
+		RRule:             req.RRule,
+		Timezone:          req.Timezone,
+		StartAt:           *startAt,
+		CreatedByID:       req.CreatedByID,
+		UpdatedByID:       req.CreatedByID,
+	}
+	recurrence.Exceptions = utils.Map(exdates, func(e time.Time) model.StreamRecurrenceException {
+		return model.StreamRecurrenceException{OccurrenceAt: e}
+	})
+	if err := s.repo.WithScheduleLock(func(repo *repository.Repository) error {
+		if err := checkScheduleConflicts(repo, s.scheduleSlots(recurrence, rule, req.CategoryIDs, time.Now(), horizon, limits), limits); err != nil {
+			return err
+		}
+
+		if err := repo.Recurrence.Create(recurrence, req.CategoryIDs, exdates); err != nil {
+			return err
+		}
+		for i := range recurrence.Exceptions {
+			recurrence.Exceptions[i].RecurrenceID = recurrence.ID
+		}
+		return materialize(repo, recurrence, rule, time.Now(), horizon, limits)
+	}); err != nil {
+		return nil, err
+	}
+	return recurrence, nil
+}
+
+// Update changes the occurrence of req.FromStreamID and the following ones, or every upcoming one.
+// Occurrences still matching the rule keep their stream and get new title, description and categories,
+// others are removed and missing ones are created. Occurrences edited alone are never touched.
+func (s *RecurrenceService) Update(id uint, req *dto.UpdateStreamRecurrenceRequest, horizon time.Duration, limits ScheduleLimits) (*model.StreamRecurrence, error) {
+	recurrence, err := s.repo.Recurrence.FindByID(id)
+	if err != nil || recurrence == nil {
+		return nil, err
+	}
+
+	rule, err := parseRecurrenceRule(req.RRule, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	exdates, err := parseExDates(req.ExDates)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	from := now
+	if req.FromStreamID != 0 {
+		scheduleStream, err := s.repo.Stream.GetScheduleStreamByID(req.FromStreamID)
+		if err != nil {
+			return nil, err
+		}
+		if scheduleStream.RecurrenceID == nil || *scheduleStream.RecurrenceID != recurrence.ID {
+			return nil, fmt.Errorf("%w: stream %d is not an occurrence of this series", ErrInvalidRecurrence, req.FromStreamID)
+		}
+		from = scheduleStream.ScheduledAt
+	}
+
+	recurrence.Title = req.Title
+	recurrence.Description = req.Description
+	recurrence.RRule = req.RRule
+	recurrence.Timezone = req.Timezone
+	recurrence.UpdatedByID = req.UpdatedByID
+	if req.StartAt != "" {
+		startAt, err := utils.ConvertDatetimeToTimestamp(req.StartAt, utils.DATETIME_LAYOUT)
+		if err != nil {
+			return nil, err
+		}
+		recurrence.StartAt = *startAt
+	}
+	// occurrences from `from` are materialized again with the new rule
+	if recurrence.MaterializedUntil.Valid && recurrence.MaterializedUntil.Time.After(from) {
+		recurrence.MaterializedUntil = sql.NullTime{Time: from, Valid: true}
+	}
+
+	for _, exdate := range exdates {
+		recurrence.Exceptions = append(recurrence.Exceptions, model.StreamRecurrenceException{RecurrenceID: recurrence.ID, OccurrenceAt: exdate})
+	}
+	if err := s.repo.WithScheduleLock(func(repo *repository.Repository) error {
+		if err := checkScheduleConflicts(repo, s.scheduleSlots(recurrence, rule, req.CategoryIDs, from, horizon, limits), limits); err != nil {
+			return err
+		}
+
+		if err := repo.Recurrence.Update(recurrence, req.CategoryIDs, exdates); err != nil {
+			return err
+		}
+
+		upcoming, err := repo.Recurrence.GetUpcomingOccurrences(recurrence.ID, from)
+		if err != nil {
+			return err
+		}
+		occurrences := rule.between(recurrence, from, now.Add(horizon))
+
+		var keptStreamIDs, removedStreamIDs []uint
+		for _, scheduleStream := range upcoming {
+			matches := false
+			for _, occurrence := range occurrences {
+				if occurrence.Equal(scheduleStream.ScheduledAt) {
+					matches = true
+					break
+				}
+			}
+			if matches {
+				keptStreamIDs = append(keptStreamIDs, scheduleStream.StreamID)
+			} else {
+				removedStreamIDs = append(removedStreamIDs, scheduleStream.StreamID)
+			}
+		}
+
+		if err := repo.Recurrence.DeleteOccurrences(removedStreamIDs); err != nil {
+			return err
+		}
+		if err := repo.Recurrence.UpdateOccurrences(keptStreamIDs, recurrence); err != nil {
+			return err
+		}
+		return materialize(repo, recurrence, rule, now, horizon, limits)
+	}); err != nil {
+		return nil, err
+	}
+	return recurrence, nil
+}
+
+// Delete removes the series with its upcoming occurrences, past ones are kept.
+// It holds the schedule lock, so materialization can't add occurrences to a series being deleted.
+func (s *RecurrenceService) Delete(id uint) error {
+	return s.repo.WithScheduleLock(func(repo *repository.Repository) error {
+		upcoming, err := repo.Recurrence.GetUpcomingOccurrences(id, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := repo.Recurrence.DeleteOccurrences(utils.Map(upcoming, func(e model.ScheduleStream) uint {
+			return e.StreamID
+		})); err != nil {
+			return err
+		}
+		return repo.Recurrence.Delete(id)
+	})
+}
+
+// IsFileInUse reports whether a thumbnail or video of a series is still referenced
+func (s *RecurrenceService) IsFileInUse(recurrence *model.StreamRecurrence) (bool, bool, error) {
+	thumbnails, videos, err := s.repo.Recurrence.CountStreamsUsingFiles(recurrence.ThumbnailFileName, recurrence.VideoName)
+	if err != nil {
+		return false, false, err
+	}
+	return thumbnails > 0, videos > 0, nil
+}
+
+// IsFileShared reports whether a thumbnail or video of streamID is shared with a series or another occurrence,
+// such files must not be removed with the stream
+func (s *RecurrenceService) IsFileShared(fileName string, streamID uint) bool {
+	return isFileShared(s.repo, fileName, streamID)
+}
+
+func isFileShared(repo *repository.Repository, fileName string, streamID uint) bool {
+	shared, err := repo.Recurrence.IsFileShared(fileName, streamID)
+	if err != nil {
+		log.Println(err)
+		// keeping a file is safer, file gc removes it when it's not referenced
+		return true
+	}
+	return shared
+}
+
+// materialize creates occurrences up to now + horizon, run it under repo.WithScheduleLock.
+// Occurrences which conflict with streams booked since the series was checked are skipped, the booked streams win.
+func materialize(repo *repository.Repository, recurrence *model.StreamRecurrence, rule *recurrenceRule, now time.Time, horizon time.Duration, limits ScheduleLimits) error {
+	from := now
+	if recurrence.MaterializedUntil.Valid && recurrence.MaterializedUntil.Time.After(from) {
+		from = recurrence.MaterializedUntil.Time
+	}
+	to := now.Add(horizon)
+	if !from.Before(to) {
+		return nil
+	}
+
+	categoryIDs := utils.Map(recurrence.Categories, func(e model.Category) uint { return e.ID })
+	for _, occurrence := range rule.between(recurrence, from, to) {
+		slot := scheduleSlot{
+			UserID:              recurrence.UserID,
+			CategoryIDs:         categoryIDs,
+			StartAt:             occurrence,
+			Duration:            limits.duration(recurrence.VideoDuration),
+			ExcludeRecurrenceID: recurrence.ID,
+		}
+		if err := checkScheduleConflicts(repo, []scheduleSlot{slot}, limits); err != nil {
+			if !errors.Is(err, ErrScheduleConflict) {
+				return err
+			}
+			log.Printf("Recurrence %d skipped occurrence at %s: %v\n", recurrence.ID, occurrence.Format(time.RFC3339), err)
+			continue
+		}
+		if _, err := repo.Recurrence.CreateOccurrence(recurrence, occurrence, utils.MakeUniqueID()); err != nil {
+			return err
+		}
+	}
+
+	recurrence.MaterializedUntil = sql.NullTime{Time: to, Valid: true}
+	return repo.Recurrence.SetMaterializedUntil(recurrence.ID, to)
+}
+
+// Materialize creates upcoming streams of every series up to now + horizon, each series under the schedule lock
+func (s *RecurrenceService) Materialize(horizon time.Duration, limits ScheduleLimits) error {
+	now := time.Now()
+	recurrences, err := s.repo.Recurrence.FindDue(now.Add(horizon))
+	if err != nil {
+		return err
+	}
+
+	for _, due := range recurrences {
+		if err := s.repo.WithScheduleLock(func(repo *repository.Repository) error {
+			// the series may have been updated or deleted since it was found
+			recurrence, err := repo.Recurrence.FindByID(due.ID)
+			if err != nil || recurrence == nil {
+				return err
+			}
+			rule, err := parseRecurrenceRule(recurrence.RRule, recurrence.Timezone)
+			if err != nil {
+				log.Printf("Recurrence %d has invalid rule: %v\n", recurrence.ID, err)
+				return nil
+			}
+			return materialize(repo, recurrence, rule, now, horizon, limits)
+		}); err != nil {
+			log.Printf("Failed to materialize recurrence %d: %v\n", due.ID, err)
+		}
+	}
+	return nil
+}
+
+// Start materializes every interval until ctx is done, only on the leader instance
+func (s *RecurrenceService) Start(ctx context.Context, interval, horizon time.Duration, limits ScheduleLimits, leader *LeaderElector) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !leader.IsLeader() {
+				continue
+			}
+			if err := s.Materialize(horizon, limits); err != nil {
+				log.Printf("Recurrence materialization failed: %v\n", err)
+			}
+		}
+	}
+}
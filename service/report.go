@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReportTargetNotFound = errors.New("reported target not found")
+	ErrReportSelf           = errors.New("users can't report themselves")
+	ErrReportBlocked        = errors.New("blocked users can't report")
+	ErrReportDuplicate      = errors.New("target is already reported by the user")
+	ErrReportNotOpen        = errors.New("report is already claimed or closed")
+	ErrReportNotClaimed     = errors.New("report is not claimed")
+	ErrReportClosed         = errors.New("report is already resolved or dismissed")
+	ErrReportClaimedByOther = errors.New("report is claimed by another moderator")
+	ErrReportAssignee       = errors.New("reports can only be assigned to admins")
+	ErrReportAction         = errors.New("action doesn't apply to the reported target")
+	ErrReportBlockForbidden = errors.New("super admins can't be blocked, admins can only be blocked by super admins")
+)
+
+// ReportSLA is how long reports of each severity may wait, defaults are used for empty ones
+type ReportSLA struct {
+	High   time.Duration
+	Medium time.Duration
+	Low    time.Duration
+}
+
+var defaultReportSLA = ReportSLA{High: time.Hour, Medium: 4 * time.Hour, Low: 24 * time.Hour}
+
+func (sla ReportSLA) of(severity model.ReportSeverity) time.Duration {
+	switch severity {
+	case model.ReportSeverityHigh:
+		return durationOrDefault(sla.High, defaultReportSLA.High)
+	case model.ReportSeverityMedium:
+		return durationOrDefault(sla.Medium, defaultReportSLA.Medium)
+	}
+	return durationOrDefault(sla.Low, defaultReportSLA.Low)
+}
+
+func durationOrDefault(value, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// ReportActionOptions are used by actions of resolved reports, blocked users are notified through the client
+type ReportActionOptions struct {
+	ClientHost string
+	Token      string
+	PurgeDelay time.Duration
+}
+
+// ReportService keeps the moderation queue of reports filed by users of be-api
+type ReportService struct {
+	repo     *repository.Repository
+	stream   *StreamService
+	trash    *TrashService
+	comment  *CommentService
+	template *NotificationTemplateService
+}
+
+func newReportService(repo *repository.Repository, stream *StreamService, trash *TrashService, comment *CommentService, template *NotificationTemplateService) *ReportService {
+	return &ReportService{
+		repo:     repo,
+		stream:   stream,
+		trash:    trash,
+		comment:  comment,
+		template: template,
+	}
+}
+
+func toReportDto(report *model.Report, now time.Time) dto.ReportDTO {
+	result := dto.ReportDTO{
+		ID:         report.ID,
+		Reporter:   toUserSummaryDto(&report.Reporter),
+		TargetType: report.TargetType,
+		TargetID:   report.TargetID,
+		TargetUser: toUserSummaryDto(report.TargetUser),
+		Reason:     report.Reason,
+		Text:       report.Text,
+		Severity:   report.Severity,
+		Priority:   report.Priority,
+		Status:     report.Status,
+		DueAt:      report.DueAt,
+		Assignee:   toUserSummaryDto(report.Assignee),
+		AssignedAt: report.AssignedAt,
+		ResolvedBy: toUserSummaryDto(report.ResolvedBy),
+		ResolvedAt: report.ResolvedAt,
+		Action:     report.Action,
+		Note:       report.Note,
+		CreatedAt:  report.CreatedAt,
+		UpdatedAt:  report.UpdatedAt,
+	}
+	if report.ResolvedAt == nil {
+		result.RemainingSeconds = int64(report.DueAt.Sub(now).Seconds())
+		result.Overdue = result.RemainingSeconds < 0
+	} else {
+		result.Overdue = report.ResolvedAt.After(report.DueAt)
+	}
+	return result
+}
+
+// findTargetUser returns the owner of the reported target
+func (s *ReportService) findTargetUser(targetType model.ReportTargetType, targetID uint) (uint, error) {
+	switch targetType {
+	case model.ReportTargetStream:
+		stream, err := s.repo.Stream.GetByID(targetID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, ErrReportTargetNotFound
+			}
+			return 0, err
+		}
+		return stream.UserID, nil
+	case model.ReportTargetComment:
+		comment, err := s.repo.Comment.FindByID(targetID)
+		if err != nil {
+			return 0, err
+		}
+		if comment == nil {
+			return 0, ErrReportTargetNotFound
+		}
+		return comment.UserID, nil
+	case model.ReportTargetUser:
+		user, err := s.repo.User.FindByID(int(targetID))
+		if err != nil {
+			return 0, err
+		}
+		if user == nil {
+			return 0, ErrReportTargetNotFound
+		}
+		return user.ID, nil
+	}
+	return 0, fmt.Errorf("unknown target type %s", targetType)
+}
+
+// Create files a report, its SLA starts now and other reports of the target are raised in the queue
+func (s *ReportService) Create(req *dto.CreateReportRequest, reporterID uint, sla ReportSLA) (*model.Report, error) {
+	// blocked, suspended and trashed users could otherwise flood the queue, tokens of trashed users are still valid
+	reporter, err := s.repo.User.FindByID(int(reporterID))
+	if err != nil {
+		return nil, err
+	}
+	if reporter == nil || reporter.Status == model.BLOCKED {
+		return nil, ErrReportBlocked
+	}
+
+	targetUserID, err := s.findTargetUser(req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if targetUserID == reporterID {
+		return nil, ErrReportSelf
+	}
+	duplicate, err := s.repo.Report.HasUnresolved(reporterID, req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		return nil, ErrReportDuplicate
+	}
+
+	severity := model.ReportSeverities[req.Reason]
+	report := &model.Report{
+		ReporterID:   reporterID,
+		TargetType:   req.TargetType,
+		TargetID:     req.TargetID,
+		TargetUserID: &targetUserID,
+		Reason:       req.Reason,
+		Text:         req.Text,
+		Severity:     severity,
+		Status:       model.ReportStatusOpen,
+		DueAt:        time.Now().Add(sla.of(severity)),
+	}
+	if err := s.repo.Report.Create(report); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Report.Reprioritize(report.TargetType, report.TargetID); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *ReportService) GetReports(req *dto.ReportQuery) (*utils.PaginationModel[dto.ReportDTO], error) {
+	pagination, err := s.repo.Report.Page(req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := new(utils.PaginationModel[dto.ReportDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.Report) dto.ReportDTO {
+		return toReportDto(&e, now)
+	})
+	return result, nil
+}
+
+// FindByID returns nil when the report doesn't exist
+func (s *ReportService) FindByID(id uint) (*model.Report, error) {
+	return s.repo.Report.FindByID(id)
+}
+
+func (s *ReportService) ToDto(report *model.Report) dto.ReportDTO {
+	return toReportDto(report, time.Now())
+}
+
+func (s *ReportService) Claim(report *model.Report, moderatorID uint) error {
+	claimed, err := s.repo.Report.Claim(report.ID, moderatorID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrReportNotOpen
+	}
+	return nil
+}
+
+// Assign claims the report for an admin, reassigning it when it's claimed already
+func (s *ReportService) Assign(report *model.Report, assigneeID uint) (*model.User, error) {
+	assignee, err := s.repo.User.FindByID(int(assigneeID))
+	if err != nil {
+		return nil, err
+	}
+	if assignee == nil || !slices.Contains([]model.RoleType{model.ADMINROLE, model.SUPPERADMINROLE}, assignee.Role.Type) {
+		return nil, ErrReportAssignee
+	}
+	assigned, err := s.repo.Report.Assign(report.ID, assignee.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, ErrReportClosed
+	}
+	return assignee, nil
+}
+
+func (s *ReportService) Release(report *model.Report) error {
+	released, err := s.repo.Report.Release(report.ID)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return ErrReportNotClaimed
+	}
+	return nil
+}
+
+// checkClosable lets moderators close open reports and reports claimed by themselves
+func checkClosable(report *model.Report, moderatorID uint) error {
+	switch {
+	case report.Status == model.ReportStatusResolved || report.Status == model.ReportStatusDismissed:
+		return ErrReportClosed
+	case report.Status == model.ReportStatusClaimed && report.AssigneeID != nil && *report.AssigneeID != moderatorID:
+		return ErrReportClaimedByOther
+	}
+	return nil
+}
+
+// Resolve runs the action on the target, then resolves every unresolved report of the target
+func (s *ReportService) Resolve(ctx context.Context, report *model.Report, req *dto.ResolveReportRequest, moderatorID uint, moderatorRole model.RoleType, options ReportActionOptions) (*dto.ReportResolvedDTO, error) {
+	if err := checkClosable(report, moderatorID); err != nil {
+		return nil, err
+	}
+
+	result := &dto.ReportResolvedDTO{}
+	switch req.Action {
+	case model.ReportActionNone:
+	case model.ReportActionBlockUser:
+		if report.TargetUserID == nil {
+			return nil, ErrReportAction
+		}
+		if err := s.blockUser(*report.TargetUserID, moderatorID, moderatorRole, req.Note, options); err != nil {
+			return nil, err
+		}
+	case model.ReportActionDeleteStream:
+		if report.TargetType != model.ReportTargetStream {
+			return nil, ErrReportAction
+		}
+		stream, err := s.stream.GetStreamByID(report.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.stream.CheckDeletable(ctx, stream); err != nil {
+			return nil, err
+		}
+		job, err := s.trash.DeleteStream(stream.ID, moderatorID, options.PurgeDelay)
+		if err != nil {
+			return nil, err
+		}
+		result.JobID = &job.ID
+	case model.ReportActionEndLive:
+		if report.TargetType != model.ReportTargetStream {
+			return nil, ErrReportAction
+		}
+		if err := s.endLive(ctx, report.TargetID); err != nil {
+			return nil, err
+		}
+	case model.ReportActionHideComment:
+		if report.TargetType != model.ReportTargetComment {
+			return nil, ErrReportAction
+		}
+		comment, err := s.comment.FindByID(report.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		if comment == nil {
+			return nil, ErrReportTargetNotFound
+		}
+		if err := s.comment.Hide(ctx, comment, moderatorID, req.Note); err != nil && !errors.Is(err, ErrCommentHidden) {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown action %s", req.Action)
+	}
+
+	resolved, err := s.repo.Report.ResolveTarget(report, req.Action, req.Note, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	result.Resolved = resolved
+	return result, nil
+}
+
+// blockUser blocks like the deactivate endpoint, suspended users are blocked permanently
+func (s *ReportService) blockUser(id, moderatorID uint, moderatorRole model.RoleType, reason string, options ReportActionOptions) error {
+	user, err := s.repo.User.FindByID(int(id))
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrReportTargetNotFound
+	}
+	if user.Role.Type == model.SUPPERADMINROLE || (moderatorRole == model.ADMINROLE && user.Role.Type == model.ADMINROLE) {
+		return ErrReportBlockForbidden
+	}
+	if user.Status == model.BLOCKED && user.SuspendedUntil == nil {
+		return nil
+	}
+
+	notification, err := s.template.RenderBlocked(user, reason, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.User.ChangeStatus(user.ID, model.BLOCKED, reason, moderatorID); err != nil {
+		return err
+	}
+	_, err = utils.PostAsync[dto.CommonResponseDTO](fmt.Sprintf("%s/api/notification/blocked-deleted", options.ClientHost), options.Token, map[string]interface{}{"user_id": user.ID, "type": notification.Type, "title": notification.Title, "content": notification.Content})
+	if err != nil {
+		log.Printf("Failed to notify user %d: %v\n", user.ID, err)
+	}
+	return nil
+}
+
+// endLive ends a live stream, streams which are ending already are left as they are
+func (s *ReportService) endLive(ctx context.Context, id uint) error {
+	stream, err := s.stream.GetStreamByID(id)
+	if err != nil {
+		return err
+	}
+	if stream.Status != model.STARTED {
+		return ErrStreamNotLive
+	}
+	isEndingLive, err := s.stream.IsEndingLive(ctx, id)
+	if err != nil || isEndingLive {
+		return err
+	}
+	return s.stream.EndLivByRedis(ctx, id)
+}
+
+// Dismiss closes the report only, other reports of the target stay in the queue
+func (s *ReportService) Dismiss(report *model.Report, moderatorID uint, note string) error {
+	if err := checkClosable(report, moderatorID); err != nil {
+		return err
+	}
+	dismissed, err := s.repo.Report.Dismiss(report.ID, note, moderatorID)
+	if err != nil {
+		return err
+	}
+	if !dismissed {
+		return ErrReportClosed
+	}
+	return nil
+}
+
+// GetStats counts the queue now and reports closed per moderator in the range
+func (s *ReportService) GetStats(req *dto.ReportStatsQuery) (*dto.ReportStatsDTO, error) {
+	now := time.Now()
+	result := &dto.ReportStatsDTO{From: now.AddDate(0, 0, -dto.REPORT_STATS_DEFAULT_DAYS), To: now, Moderators: []dto.ReportModeratorStatsDTO{}}
+	if req.From != 0 {
+		result.From = time.Unix(req.From, 0)
+	}
+	if req.To != 0 {
+		result.To = time.Unix(req.To, 0)
+	}
+
+	counts, err := s.repo.Report.QueueCounts(now)
+	if err != nil {
+		return nil, err
+	}
+	result.Open, result.Claimed, result.Overdue = counts.Open, counts.Claimed, counts.Overdue
+
+	rows, err := s.repo.Report.ModeratorStats(result.From, result.To)
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := s.repo.Report.ClaimedCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[uint]*dto.ReportModeratorStatsDTO)
+	var ids []uint
+	get := func(id uint) *dto.ReportModeratorStatsDTO {
+		if stats[id] == nil {
+			stats[id] = &dto.ReportModeratorStatsDTO{}
+			ids = append(ids, id)
+		}
+		return stats[id]
+	}
+	for _, row := range rows {
+		entry := get(row.ModeratorID)
+		entry.Resolved, entry.Dismissed, entry.Breached, entry.AvgResolutionSeconds = row.Resolved, row.Dismissed, row.Breached, row.AvgResolutionSeconds
+	}
+	for id, count := range claimed {
+		get(id).Claimed = count
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	users, err := s.repo.Report.FindUsers(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		stats[users[i].ID].Moderator = toUserSummaryDto(&users[i])
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		result.Moderators = append(result.Moderators, *stats[id])
+	}
+	// busiest moderators first
+	slices.SortStableFunc(result.Moderators, func(a, b dto.ReportModeratorStatsDTO) int {
+		return int((b.Resolved + b.Dismissed) - (a.Resolved + a.Dismissed))
+	})
+	return result, nil
+}
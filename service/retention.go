@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/cache"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStreamLegalHold          = errors.New("stream is under legal hold")
+	ErrRetentionPolicyDuplicate = errors.New("retention policy already exists for this category")
+)
+
+type RetentionService struct {
+	repo       *repository.Repository
+	redisStore cache.RedisStore
+}
+
+func newRetentionService(repo *repository.Repository, redis cache.RedisStore) *RetentionService {
+	return &RetentionService{
+		repo:       repo,
+		redisStore: redis,
+	}
+}
+
+func (s *RetentionService) toRetentionPolicyDto(policy *model.RetentionPolicy) dto.RetentionPolicyRespDTO {
+	result := dto.RetentionPolicyRespDTO{
+		ID:                     policy.ID,
+		CategoryID:             policy.CategoryID,
+		DeleteRawAfterEncoding: policy.DeleteRawAfterEncoding,
+		DeleteVideoAfterDays:   policy.DeleteVideoAfterDays,
+		CreatedAt:              policy.CreatedAt,
+		UpdatedAt:              policy.UpdatedAt,
+		CreatedByID:            policy.CreatedByID,
+		UpdatedByID:            policy.UpdatedByID,
+	}
+	if policy.Category != nil {
+		result.CategoryName = policy.Category.Name
+	}
+	return result
+}
+
+func (s *RetentionService) GetAll() ([]dto.RetentionPolicyRespDTO, error) {
+	policies, err := s.repo.Retention.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return utils.Map(policies, func(e model.RetentionPolicy) dto.RetentionPolicyRespDTO {
+		return s.toRetentionPolicyDto(&e)
+	}), nil
+}
+
+func (s *RetentionService) GetByID(id uint) (*model.RetentionPolicy, error) {
+	return s.repo.Retention.FindByID(id)
+}
+
+// checkDuplicate allows one policy per category and one global policy, id is the policy being updated
+func (s *RetentionService) checkDuplicate(categoryID *uint, id uint) error {
+	if categoryID != nil {
+		if _, err := s.repo.Category.FindByID(*categoryID); err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.repo.Retention.FindByCategoryID(categoryID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return ErrRetentionPolicyDuplicate
+	}
+	return nil
+}
+
+func (s *RetentionService) Create(req *dto.RetentionPolicyRequestDTO) (*model.RetentionPolicy, error) {
+	if err := s.checkDuplicate(req.CategoryID, 0); err != nil {
+		return nil, err
+	}
+
+	policy := &model.RetentionPolicy{
+		CategoryID:             req.CategoryID,
+		DeleteRawAfterEncoding: req.DeleteRawAfterEncoding,
+		DeleteVideoAfterDays:   req.DeleteVideoAfterDays,
+		CreatedByID:            req.CreatedByID,
+		UpdatedByID:            req.CreatedByID,
+	}
+	if err := s.repo.Retention.Create(policy); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrRetentionPolicyDuplicate
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *RetentionService) Update(id uint, req *dto.RetentionPolicyRequestDTO) (*model.RetentionPolicy, error) {
+	policy, err := s.repo.Retention.FindByID(id)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+	if err := s.checkDuplicate(req.CategoryID, id); err != nil {
+		return nil, err
+	}
+
+	policy.CategoryID = req.CategoryID
+	policy.DeleteRawAfterEncoding = req.DeleteRawAfterEncoding
+	policy.DeleteVideoAfterDays = req.DeleteVideoAfterDays
+	policy.UpdatedByID = req.CreatedByID
+	if err := s.repo.Retention.Update(policy); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrRetentionPolicyDuplicate
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *RetentionService) Delete(id uint) error {
+	return s.repo.Retention.Delete(id)
+}
+
+type effectiveRetention struct {
+	policyIDs              []uint
+	deleteRawAfterEncoding bool
+	deleteVideoAfterDays   uint
+}
+
+// resolveRetention merges policies of stream's categories, the global policy applies only when none of them has one.
+// With several category policies the strictest wins: raw is deleted if any says so and the shortest video retention is used.
+func resolveRetention(categoryIDs []uint, byCategory map[uint]model.RetentionPolicy, global *model.RetentionPolicy) *effectiveRetention {
+	var result *effectiveRetention
+	for _, categoryID := range categoryIDs {
+		policy, ok := byCategory[categoryID]
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = &effectiveRetention{}
+		}
+		result.policyIDs = append(result.policyIDs, policy.ID)
+		result.deleteRawAfterEncoding = result.deleteRawAfterEncoding || policy.DeleteRawAfterEncoding
+		if policy.DeleteVideoAfterDays > 0 && (result.deleteVideoAfterDays == 0 || policy.DeleteVideoAfterDays < result.deleteVideoAfterDays) {
+			result.deleteVideoAfterDays = policy.DeleteVideoAfterDays
+		}
+	}
+
+	if result == nil && global != nil {
+		result = &effectiveRetention{
+			policyIDs:              []uint{global.ID},
+			deleteRawAfterEncoding: global.DeleteRawAfterEncoding,
+			deleteVideoAfterDays:   global.DeleteVideoAfterDays,
+		}
+	}
+	return result
+}
+
+// Apply deletes recordings of ended streams according to retention policies.
+// Streams under legal hold are never touched, pinned streams keep their mp4.
+// Deletions are written to admin logs on behalf of the super admin.
+func (s *RetentionService) Apply(ctx context.Context, folders StorageFolders) (int, error) {
+	policies, err := s.repo.Retention.FindAll()
+	if err != nil {
+		return 0, err
+	}
+	if len(policies) == 0 {
+		return 0, nil
+	}
+
+	var global *model.RetentionPolicy
+	byCategory := make(map[uint]model.RetentionPolicy)
+	for i, policy := range policies {
+		if policy.CategoryID == nil {
+			global = &policies[i]
+			continue
+		}
+		byCategory[*policy.CategoryID] = policy
+	}
+
+	streams, err := s.repo.Retention.GetEndedStreams()
+	if err != nil {
+		return 0, err
+	}
+	streamCategoryIDs, err := s.repo.Retention.GetStreamCategoryIDs()
+	if err != nil {
+		return 0, err
+	}
+	superAdmin, err := s.repo.User.FindByEmail(model.SUPER_ADMIN_EMAIL)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	now := time.Now()
+	for _, stream := range streams {
+		retention := resolveRetention(streamCategoryIDs[stream.ID], byCategory, global)
+		if retention == nil {
+			continue
+		}
+
+		videoName := stream.StreamKey + ".mp4"
+		isEncoding, err := isEncodingVideo(ctx, s.redisStore, videoName)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if isEncoding {
+			continue
+		}
+
+		videoPath := utils.MakeVideoPath(folders.Video, videoName)
+		liveVideoPaths, err := filepath.Glob(fmt.Sprintf("%s%s_*.flv", folders.Live, stream.StreamKey))
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		var filesToRemove []string
+		_, videoErr := os.Stat(videoPath)
+		videoExists := videoErr == nil
+		expired := retention.deleteVideoAfterDays > 0 && !stream.IsPinned &&
+			now.After(stream.EndedAt.AddDate(0, 0, int(retention.deleteVideoAfterDays)))
+
+		if expired {
+			if videoExists {
+				filesToRemove = append(filesToRemove, videoPath)
+			}
+			filesToRemove = append(filesToRemove, liveVideoPaths...)
+		} else if retention.deleteRawAfterEncoding && videoExists {
+			filesToRemove = append(filesToRemove, liveVideoPaths...)
+		}
+
+		// recordings are never added to usage, the next recalculation drops them
+		for _, path := range filesToRemove {
+			if err := os.Remove(path); err != nil {
+				log.Println(err)
+				continue
+			}
+			deleted++
+
+			if superAdmin == nil {
+				continue
+			}
+			adminLog := &model.AdminLog{
+				UserID:  superAdmin.ID,
+				Action:  string(model.RetentionDeleteRecording),
+				Details: fmt.Sprintf("Retention policy %v deleted %s of stream %d.", retention.policyIDs, filepath.Base(path), stream.ID),
+			}
+			if err := s.repo.Admin.Create(adminLog); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// Start applies retention policies every interval until ctx is done, only on the leader instance
+func (s *RetentionService) Start(ctx context.Context, interval time.Duration, folders StorageFolders, leader *LeaderElector) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !leader.IsLeader() {
+				continue
+			}
+			deleted, err := s.Apply(ctx, folders)
+			if err != nil {
+				log.Printf("Retention failed: %v\n", err)
+				continue
+			}
+			log.Printf("Retention deleted %d recordings\n", deleted)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/cache"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"sync"
+	"time"
+)
+
+const DEFAULT_SCHEDULER_CLAIM_TIMEOUT = 5 * time.Minute
+
+var (
+	errSchedulerStopped      = errors.New("scheduler stopped while pushing")
+	errSchedulerClaimExpired = errors.New("instance pushing the stream stopped renewing its claim")
+)
+
+type SchedulerOptions struct {
+	ScheduledVideosFolder string
+	RTMPURL               string
+	MaxRetries            uint // attempts after the first one
+	RetryDelay            time.Duration
+	MaxConcurrent         int           // 0 is unlimited
+	ClaimTimeout          time.Duration // claims which aren't renewed for it are reset, must be longer than the tick interval
+}
+
+// scheduleStore is what the scheduler reads and writes, backed by the repository
+type scheduleStore interface {
+	GetDueScheduleStreams(now time.Time, limit int) ([]model.ScheduleStream, error)
+	ClaimScheduleStream(streamID uint, now time.Time) (bool, error)
+	RenewScheduleStreamClaims(streamIDs []uint, now time.Time) error
+	GetStaleScheduleStreams(staleBefore time.Time) ([]model.ScheduleStream, error)
+	ResetStaleScheduleStream(streamID uint, staleBefore time.Time, retryAt *time.Time, pushError string, now time.Time) (bool, error)
+	MarkStreamStarted(streamID uint, streamToken string, startedAt time.Time) error
+	MarkStreamEnded(streamID uint, endedAt time.Time, pushError string) error
+	RetryScheduleStream(streamID uint, nextAttemptAt time.Time, pushError string) error
+	FindActivePublishBan(userID uint, now time.Time) (*model.PublishBan, error)
+	FindPlaylistItems(scheduleStreamID uint) ([]model.ScheduleStreamItem, error)
+}
+
+type repositoryScheduleStore struct {
+	*repository.StreamRepository
+	repo *repository.Repository
+}
+
+func (s *repositoryScheduleStore) FindActivePublishBan(userID uint, now time.Time) (*model.PublishBan, error) {
+	return s.repo.PublishBan.FindActive(userID, now)
+}
+
+func (s *repositoryScheduleStore) FindPlaylistItems(scheduleStreamID uint) ([]model.ScheduleStreamItem, error) {
+	return s.repo.Playlist.FindItems(scheduleStreamID)
+}
+
+// StreamScheduler starts pre-recorded streams at ScheduledAt by pushing their video to stream server.
+// Only the leader instance picks up streams, so running several be-admin is safe.
+// Stream goes upcoming -> pending (claimed) -> started (pushing) -> ended, failed pushes go back to upcoming until retries run out.
+// Claims are renewed while pushing, the leader resets claims of an instance which died mid-push.
+type StreamScheduler struct {
+	store        scheduleStore
+	leader       *LeaderElector
+	streamServer *streamServerService
+	pusher       StreamPusher
+	options      SchedulerOptions
+
+	wg      sync.WaitGroup
+	running *cache.FCache[uint, struct{}]
+}
+
+func NewStreamScheduler(repo *repository.Repository, leader *LeaderElector, streamServer *streamServerService, pusher StreamPusher, options SchedulerOptions) *StreamScheduler {
+	return newStreamScheduler(&repositoryScheduleStore{StreamRepository: repo.Stream, repo: repo}, leader, streamServer, pusher, options)
+}
+
+func newStreamScheduler(store scheduleStore, leader *LeaderElector, streamServer *streamServerService, pusher StreamPusher, options SchedulerOptions) *StreamScheduler {
+	if options.ClaimTimeout <= 0 {
+		options.ClaimTimeout = DEFAULT_SCHEDULER_CLAIM_TIMEOUT
+	}
+	return &StreamScheduler{
+		store:        store,
+		leader:       leader,
+		streamServer: streamServer,
+		pusher:       pusher,
+		options:      options,
+		running:      cache.NewFCache[uint, struct{}](),
+	}
+}
+
+// Tick starts every due stream once, pushes keep running in background
+func (s *StreamScheduler) Tick(ctx context.Context) error {
+	// pushes of this instance keep their claims even when it isn't the leader anymore
+	now := time.Now()
+	if running := s.running.Keys(); len(running) > 0 {
+		if err := s.store.RenewScheduleStreamClaims(running, now); err != nil {
+			log.Printf("Scheduler failed to renew claims: %v\n", err)
+		}
+	}
+
+	// claimed streams are not upcoming anymore, so losing the lock never starts a stream twice
+	if !s.leader.Elect(ctx) {
+		return nil
+	}
+
+	if err := s.resetStaleClaims(now); err != nil {
+		log.Printf("Scheduler failed to reset stale claims: %v\n", err)
+	}
+
+	limit := 100
+	if s.options.MaxConcurrent > 0 {
+		limit = s.options.MaxConcurrent - s.running.Size()
+		if limit <= 0 {
+			return nil
+		}
+	}
+
+	due, err := s.store.GetDueScheduleStreams(now, limit)
+	if err != nil {
+		return err
+	}
+
+	for _, scheduleStream := range due {
+		// a stopping scheduler would fail the push right away
+		if ctx.Err() != nil {
+			break
+		}
+		claimed, err := s.store.ClaimScheduleStream(scheduleStream.StreamID, now)
+		if err != nil {
+			log.Printf("Scheduler failed to claim stream %d: %v\n", scheduleStream.StreamID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		scheduleStream.PushAttempts++
+
+		s.running.Set(scheduleStream.StreamID, struct{}{})
+		s.wg.Add(1)
+		go func(scheduleStream model.ScheduleStream) {
+			defer s.wg.Done()
+			defer s.running.Delete(scheduleStream.StreamID)
+			s.run(ctx, &scheduleStream)
+		}(scheduleStream)
+	}
+	return nil
+}
+
+// resetStaleClaims retries or ends streams claimed by an instance which stopped renewing them
+func (s *StreamScheduler) resetStaleClaims(now time.Time) error {
+	staleBefore := now.Add(-s.options.ClaimTimeout)
+	stale, err := s.store.GetStaleScheduleStreams(staleBefore)
+	if err != nil {
+		return err
+	}
+
+	for _, scheduleStream := range stale {
+		if _, ok := s.running.Get(scheduleStream.StreamID); ok {
+			continue
+		}
+		var retryAt *time.Time
+		if scheduleStream.PushAttempts <= s.options.MaxRetries {
+			retryAt = &now
+		}
+		reset, err := s.store.ResetStaleScheduleStream(scheduleStream.StreamID, staleBefore, retryAt, errSchedulerClaimExpired.Error(), now)
+		if err != nil {
+			log.Printf("Scheduler failed to reset stream %d: %v\n", scheduleStream.StreamID, err)
+			continue
+		}
+		if reset {
+			log.Printf("Scheduled stream %d attempt %d failed: %v\n", scheduleStream.StreamID, scheduleStream.PushAttempts, errSchedulerClaimExpired)
+		}
+	}
+	return nil
+}
+
+func (s *StreamScheduler) run(ctx context.Context, scheduleStream *model.ScheduleStream) {
+	stream := &scheduleStream.Stream
+	err := s.push(ctx, scheduleStream)
+	if err != nil && ctx.Err() != nil {
+		err = errSchedulerStopped
+	}
+
+	// ctx may be cancelled already, results must still be saved
+	now := time.Now()
+	if err == nil {
+		log.Printf("Scheduled stream %d ended\n", stream.ID)
+		if err := s.store.MarkStreamEnded(stream.ID, now, ""); err != nil {
+			log.Printf("Scheduler failed to end stream %d: %v\n", stream.ID, err)
+		}
+		return
+	}
+
+	log.Printf("Scheduled stream %d attempt %d failed: %v\n", stream.ID, scheduleStream.PushAttempts, err)
+	if scheduleStream.PushAttempts <= s.options.MaxRetries && !errors.Is(err, ErrPublishBanned) {
+		if err := s.store.RetryScheduleStream(stream.ID, now.Add(s.options.RetryDelay), err.Error()); err != nil {
+			log.Printf("Scheduler failed to retry stream %d: %v\n", stream.ID, err)
+		}
+		return
+	}
+	if err := s.store.MarkStreamEnded(stream.ID, now, err.Error()); err != nil {
+		log.Printf("Scheduler failed to end stream %d: %v\n", stream.ID, err)
+	}
+}
+
+func (s *StreamScheduler) push(ctx context.Context, scheduleStream *model.ScheduleStream) error {
+	stream := &scheduleStream.Stream
+
+	ban, err := s.store.FindActivePublishBan(stream.UserID, time.Now())
+	if err != nil {
+		return err
+	}
+	if ban != nil {
+		return ErrPublishBanned
+	}
+
+	streamToken := stream.StreamToken.String
+	if !stream.StreamToken.Valid || streamToken == "" {
+		token, err := s.streamServer.GetChannelKey(stream.StreamKey)
+		if err != nil {
+			return fmt.Errorf("failed to get channel key: %w", err)
+		}
+		if token == "" {
+			return errors.New("stream server returned empty channel key")
+		}
+		streamToken = token
+	}
+
+	if err := s.store.MarkStreamStarted(stream.ID, streamToken, time.Now()); err != nil {
+		return err
+	}
+
+	log.Printf("Scheduled stream %d started, attempt %d\n", stream.ID, scheduleStream.PushAttempts)
+	pushURL := utils.MakePushURL(s.options.RTMPURL, streamToken)
+
+	items, err := s.store.FindPlaylistItems(scheduleStream.ID)
+	if err != nil {
+		return err
+	}
+	if len(items) > 0 {
+		return s.pushPlaylist(ctx, items, pushURL)
+	}
+
+	videoPath := utils.MakeVideoPath(s.options.ScheduledVideosFolder, scheduleStream.VideoName)
+	return s.pusher.Push(ctx, videoPath, pushURL)
+}
+
+func (s *StreamScheduler) pushPlaylist(ctx context.Context, items []model.ScheduleStreamItem, pushURL string) error {
+	inputs := utils.Map(items, func(e model.ScheduleStreamItem) utils.PlaylistInput {
+		input := utils.PlaylistInput{Path: utils.MakeVideoPath(s.options.ScheduledVideosFolder, e.FileName)}
+		if e.Type == model.PlaylistItemTypeSlate {
+			input.Slate = true
+			input.Duration = time.Duration(e.Duration) * time.Millisecond
+		} else {
+			input.InPoint = time.Duration(e.InPoint) * time.Millisecond
+			input.OutPoint = time.Duration(e.OutPoint) * time.Millisecond
+		}
+		return input
+	})
+	return s.pusher.PushPlaylist(ctx, inputs, pushURL)
+}
+
+// Start ticks every interval until ctx is done, then waits for running pushes to save their result
+func (s *StreamScheduler) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		case <-ticker.C:
+			if err := s.Tick(ctx); err != nil {
+				log.Printf("Scheduler failed: %v\n", err)
+			}
+		}
+	}
+}
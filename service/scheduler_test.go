@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gitlab/live/be-live-admin/cache"
+	"gitlab/live/be-live-admin/model"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLockStore grants the lock to the first owner, other methods of cache.RedisStore aren't used by the scheduler
+type fakeLockStore struct {
+	cache.RedisStore
+
+	mu     sync.Mutex
+	owner  string
+	refuse bool
+}
+
+func (f *fakeLockStore) AcquireLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.refuse || (f.owner != "" && f.owner != owner) {
+		return false, nil
+	}
+	f.owner = owner
+	return true, nil
+}
+
+func (f *fakeLockStore) ReleaseLock(ctx context.Context, key, owner string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.owner == owner {
+		f.owner = ""
+	}
+	return nil
+}
+
+// fakeScheduleStore keeps schedule streams in memory with the semantics of the repository queries
+type fakeScheduleStore struct {
+	mu      sync.Mutex
+	streams map[uint]*model.ScheduleStream
+	bans    map[uint]*model.PublishBan
+}
+
+func newFakeScheduleStore(streams ...model.ScheduleStream) *fakeScheduleStore {
+	store := &fakeScheduleStore{
+		streams: map[uint]*model.ScheduleStream{},
+		bans:    map[uint]*model.PublishBan{},
+	}
+	for i := range streams {
+		store.streams[streams[i].StreamID] = &streams[i]
+	}
+	return store
+}
+
+func (f *fakeScheduleStore) get(streamID uint) model.ScheduleStream {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return *f.streams[streamID]
+}
+
+func (f *fakeScheduleStore) GetDueScheduleStreams(now time.Time, limit int) ([]model.ScheduleStream, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []model.ScheduleStream
+	for _, scheduleStream := range f.streams {
+		if scheduleStream.Stream.Status != model.UPCOMING || scheduleStream.ScheduledAt.After(now) {
+			continue
+		}
+		if scheduleStream.NextAttemptAt.Valid && scheduleStream.NextAttemptAt.Time.After(now) {
+			continue
+		}
+		if len(result) < limit {
+			result = append(result, *scheduleStream)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeScheduleStore) ClaimScheduleStream(streamID uint, now time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	scheduleStream := f.streams[streamID]
+	if scheduleStream.Stream.Status != model.UPCOMING {
+		return false, nil
+	}
+	scheduleStream.Stream.Status = model.PENDING
+	scheduleStream.PushAttempts++
+	scheduleStream.ClaimedAt = sql.NullTime{Time: now, Valid: true}
+	return true, nil
+}
+
+func (f *fakeScheduleStore) RenewScheduleStreamClaims(streamIDs []uint, now time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, streamID := range streamIDs {
+		if scheduleStream := f.streams[streamID]; scheduleStream.ClaimedAt.Valid {
+			scheduleStream.ClaimedAt.Time = now
+		}
+	}
+	return nil
+}
+
+func (f *fakeScheduleStore) GetStaleScheduleStreams(staleBefore time.Time) ([]model.ScheduleStream, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []model.ScheduleStream
+	for _, scheduleStream := range f.streams {
+		status := scheduleStream.Stream.Status
+		if (status == model.PENDING || status == model.STARTED) && scheduleStream.ClaimedAt.Valid && scheduleStream.ClaimedAt.Time.Before(staleBefore) {
+			result = append(result, *scheduleStream)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeScheduleStore) ResetStaleScheduleStream(streamID uint, staleBefore time.Time, retryAt *time.Time, pushError string, now time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	scheduleStream := f.streams[streamID]
+	if !scheduleStream.ClaimedAt.Valid || !scheduleStream.ClaimedAt.Time.Before(staleBefore) {
+		return false, nil
+	}
+	scheduleStream.ClaimedAt = sql.NullTime{}
+	scheduleStream.LastPushError = pushError
+	if retryAt != nil {
+		scheduleStream.NextAttemptAt = sql.NullTime{Time: *retryAt, Valid: true}
+		scheduleStream.Stream.Status = model.UPCOMING
+	} else {
+		scheduleStream.NextAttemptAt = sql.NullTime{}
+		scheduleStream.Stream.Status = model.ENDED
+	}
+	return true, nil
+}
+
+func (f *fakeScheduleStore) MarkStreamStarted(streamID uint, streamToken string, startedAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.streams[streamID].Stream.Status = model.STARTED
+	return nil
+}
+
+func (f *fakeScheduleStore) MarkStreamEnded(streamID uint, endedAt time.Time, pushError string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	scheduleStream := f.streams[streamID]
+	scheduleStream.Stream.Status = model.ENDED
+	scheduleStream.LastPushError = pushError
+	scheduleStream.NextAttemptAt = sql.NullTime{}
+	scheduleStream.ClaimedAt = sql.NullTime{}
+	return nil
+}
+
+func (f *fakeScheduleStore) RetryScheduleStream(streamID uint, nextAttemptAt time.Time, pushError string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	scheduleStream := f.streams[streamID]
+	scheduleStream.Stream.Status = model.UPCOMING
+	scheduleStream.LastPushError = pushError
+	scheduleStream.NextAttemptAt = sql.NullTime{Time: nextAttemptAt, Valid: true}
+	scheduleStream.ClaimedAt = sql.NullTime{}
+	return nil
+}
+
+func (f *fakeScheduleStore) FindActivePublishBan(userID uint, now time.Time) (*model.PublishBan, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.bans[userID], nil
+}
+
+func (f *fakeScheduleStore) FindPlaylistItems(scheduleStreamID uint) ([]model.ScheduleStreamItem, error) {
+	return nil, nil
+}
+
+func makeDueScheduleStream(streamID uint) model.ScheduleStream {
+	return model.ScheduleStream{
+		ID:          streamID,
+		StreamID:    streamID,
+		ScheduledAt: time.Now().Add(-time.Minute),
+		VideoName:   "video.mp4",
+		Stream: model.Stream{
+			ID:          streamID,
+			UserID:      1,
+			Status:      model.UPCOMING,
+			StreamType:  model.PRERECORDSTREAM,
+			StreamKey:   "key",
+			StreamToken: sql.NullString{String: "token", Valid: true},
+		},
+	}
+}
+
+func newTestScheduler(store scheduleStore, locks *fakeLockStore, pusher StreamPusher, options SchedulerOptions) *StreamScheduler {
+	options.ScheduledVideosFolder = "videos/"
+	options.RTMPURL = "rtmp://localhost/live"
+	return newStreamScheduler(store, NewLeaderElector(locks, time.Minute), nil, pusher, options)
+}
+
+func TestSchedulerPushesDueStreamOnce(t *testing.T) {
+	store := newFakeScheduleStore(makeDueScheduleStream(1))
+	pusher := &FakePusher{}
+	scheduler := newTestScheduler(store, &fakeLockStore{}, pusher, SchedulerOptions{})
+
+	if err := scheduler.Tick(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	scheduler.wg.Wait()
+	if err := scheduler.Tick(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	scheduler.wg.Wait()
+
+	pushes := pusher.Pushes()
+	if len(pushes) != 1 || pushes[0] != "videos/video.mp4 -> rtmp://localhost/live/token" {
+		t.Fatalf("pushes = %v, want one push of the video", pushes)
+	}
+	scheduleStream := store.get(1)
+	if scheduleStream.Stream.Status != model.ENDED || scheduleStream.LastPushError != "" || scheduleStream.PushAttempts != 1 {
+		t.Fatalf("stream = %s with error %q after %d attempts, want ended after 1", scheduleStream.Stream.Status, scheduleStream.LastPushError, scheduleStream.PushAttempts)
+	}
+}
+
+func TestSchedulerClaimsOnlyAsLeader(t *testing.T) {
+	store := newFakeScheduleStore(makeDueScheduleStream(1))
+	pusher := &FakePusher{}
+	scheduler := newTestScheduler(store, &fakeLockStore{refuse: true}, pusher, SchedulerOptions{})
+
+	if err := scheduler.Tick(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	scheduler.wg.Wait()
+
+	if pushes := pusher.Pushes(); len(pushes) != 0 {
+		t.Fatalf("pushes = %v, want none without the lock", pushes)
+	}
+	if status := store.get(1).Stream.Status; status != model.UPCOMING {
+		t.Fatalf("status = %s, want upcoming", status)
+	}
+}
+
+func TestSchedulerRetriesFailedPushUntilRetriesRunOut(t *testing.T) {
+	store := newFakeScheduleStore(makeDueScheduleStream(1))
+	pusher := &FakePusher{Err: errors.New("connection refused")}
+	scheduler := newTestScheduler(store, &fakeLockStore{}, pusher, SchedulerOptions{MaxRetries: 1, RetryDelay: time.Hour})
+
+	if err := scheduler.Tick(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	scheduler.wg.Wait()
+
+	scheduleStream := store.get(1)
+	if scheduleStream.Stream.Status != model.UPCOMING || !scheduleStream.NextAttemptAt.Valid || scheduleStream.NextAttemptAt.Time.Before(time.Now().Add(time.Minute)) {
+		t.Fatalf("stream = %s next attempt %v, want upcoming after the retry delay", scheduleStream.Stream.Status, scheduleStream.NextAttemptAt)
+	}
+
+	// not due before the retry delay
+	if err := scheduler.Tick(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	scheduler.wg.Wait()
+	if pushes := pusher.Pushes(); len(pushes) != 1 {
+		t.Fatalf("pushes = %d, want 1 before the retry delay", len(pushes))
+	}
+
+	store.mu.Lock()
+	store.streams[1].NextAttemptAt.Time = time.Now().Add(-time.Second)
+	store.mu.Unlock()
+	if err := scheduler.Tick(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	scheduler.wg.Wait()
+
+	scheduleStream = store.get(1)
+	if scheduleStream.Stream.Status != model.ENDED || scheduleStream.PushAttempts != 2 || !strings.Contains(scheduleStream.LastPushError, "connection refused") {
+		t.Fatalf("stream = %s with error %q after %d attempts, want ended with the push error after 2", scheduleStream.Stream.Status, scheduleStream.LastPushError, scheduleStream.PushAttempts)
+	}
+}
+
+func TestSchedulerDoesNotRetryBannedStreamer(t *testing.T) {
+	store := newFakeScheduleStore(makeDueScheduleStream(1))
+	store.bans[1] = &model.PublishBan{UserID: 1}
+	pusher := &FakePusher{}
+	scheduler := newTestScheduler(store, &fakeLockStore{}, pusher, SchedulerOptions{MaxRetries: 3})
+
+	if err := scheduler.Tick(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	scheduler.wg.Wait()
+
+	if pushes := pusher.Pushes(); len(pushes) != 0 {
+		t.Fatalf("pushes = %v, want none for a banned streamer", pushes)
+	}
+	scheduleStream := store.get(1)
+	if scheduleStream.Stream.Status != model.ENDED || scheduleStream.LastPushError != ErrPublishBanned.Error() {
+		t.Fatalf("stream = %s with error %q, want ended as banned", scheduleStream.Stream.Status, scheduleStream.LastPushError)
+	}
+}
+
+func TestSchedulerStopRetriesRunningPush(t *testing.T) {
+	store := newFakeScheduleStore(makeDueScheduleStream(1))
+	locks := &fakeLockStore{}
+	pusher := &FakePusher{Duration: time.Hour}
+	scheduler := newTestScheduler(store, locks, pusher, SchedulerOptions{MaxRetries: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Start(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(pusher.Pushes()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("stream was not pushed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler didn't stop")
+	}
+
+	scheduleStream := store.get(1)
+	if scheduleStream.Stream.Status != model.UPCOMING || scheduleStream.LastPushError != errSchedulerStopped.Error() {
+		t.Fatalf("stream = %s with error %q, want upcoming to be retried", scheduleStream.Stream.Status, scheduleStream.LastPushError)
+	}
+}
+
+func TestSchedulerResetsStaleClaims(t *testing.T) {
+	stale := time.Now().Add(-time.Hour)
+	retried := makeDueScheduleStream(1)
+	retried.Stream.Status = model.STARTED
+	retried.PushAttempts = 1
+	retried.ClaimedAt = sql.NullTime{Time: stale, Valid: true}
+	exhausted := makeDueScheduleStream(2)
+	exhausted.Stream.Status = model.PENDING
+	exhausted.PushAttempts = 2
+	exhausted.ClaimedAt = sql.NullTime{Time: stale, Valid: true}
+	store := newFakeScheduleStore(retried, exhausted)
+
+	// pushes of the leader itself are renewed, not reset
+	pusher := &FakePusher{Duration: time.Hour}
+	scheduler := newTestScheduler(store, &fakeLockStore{}, pusher, SchedulerOptions{MaxRetries: 1, ClaimTimeout: time.Minute})
+	running := makeDueScheduleStream(3)
+	running.Stream.Status = model.STARTED
+	running.PushAttempts = 1
+	running.ClaimedAt = sql.NullTime{Time: stale, Valid: true}
+	store.streams[3] = &running
+	scheduler.running.Set(3, struct{}{})
+
+	// the retried stream is due again right away, so it's pushed by the same tick
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := scheduler.Tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	scheduler.wg.Wait()
+
+	if scheduleStream := store.get(1); scheduleStream.PushAttempts != 2 || scheduleStream.LastPushError != errSchedulerStopped.Error() {
+		t.Fatalf("stream 1 has %d attempts with error %q, want it retried", scheduleStream.PushAttempts, scheduleStream.LastPushError)
+	}
+	if scheduleStream := store.get(2); scheduleStream.Stream.Status != model.ENDED || scheduleStream.LastPushError != errSchedulerClaimExpired.Error() {
+		t.Fatalf("stream 2 = %s with error %q, want ended as expired", scheduleStream.Stream.Status, scheduleStream.LastPushError)
+	}
+	if scheduleStream := store.get(3); scheduleStream.Stream.Status != model.STARTED || time.Since(scheduleStream.ClaimedAt.Time) > time.Minute {
+		t.Fatalf("stream 3 = %s claimed at %v, want its claim renewed", scheduleStream.Stream.Status, scheduleStream.ClaimedAt.Time)
+	}
+}
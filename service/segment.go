@@ -0,0 +1,146 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+)
+
+var (
+	ErrUserSegmentNameTaken = errors.New("segment name already exists")
+	ErrUserSegmentInUse     = errors.New("segment is the target of announcements which are not sent yet")
+)
+
+// UserSegmentService saves user filters which announcements target
+type UserSegmentService struct {
+	repo *repository.Repository
+}
+
+func newUserSegmentService(repo *repository.Repository) *UserSegmentService {
+	return &UserSegmentService{
+		repo: repo,
+	}
+}
+
+// decodeSegmentFilter returns the filter of the segment without page, limit and sort
+func decodeSegmentFilter(segment *model.UserSegment) (*dto.UserQuery, error) {
+	var filter dto.UserQuery
+	if err := json.Unmarshal([]byte(segment.Filter), &filter); err != nil {
+		return nil, fmt.Errorf("invalid filter of segment %d: %w", segment.ID, err)
+	}
+	filter.Page, filter.Limit, filter.SortBy, filter.Sort = 0, 0, "", ""
+	return &filter, nil
+}
+
+func (s *UserSegmentService) toUserSegmentDto(segment *model.UserSegment) (*dto.UserSegmentDTO, error) {
+	filter, err := decodeSegmentFilter(segment)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.repo.User.CountByFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserSegmentDTO{
+		ID:        segment.ID,
+		Name:      segment.Name,
+		Filter:    *filter,
+		Users:     users,
+		CreatedBy: toUserSummaryDto(segment.CreatedBy),
+		CreatedAt: segment.CreatedAt,
+		UpdatedAt: segment.UpdatedAt,
+	}, nil
+}
+
+func (s *UserSegmentService) GetSegments(req *dto.UserSegmentQuery) (*utils.PaginationModel[dto.UserSegmentDTO], error) {
+	pagination, err := s.repo.Segment.Page(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.UserSegmentDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = make([]dto.UserSegmentDTO, 0, len(pagination.Page))
+	for _, e := range pagination.Page {
+		segment, err := s.toUserSegmentDto(&e)
+		if err != nil {
+			return nil, err
+		}
+		result.Page = append(result.Page, *segment)
+	}
+	return result, nil
+}
+
+func (s *UserSegmentService) FindByID(id uint) (*model.UserSegment, error) {
+	return s.repo.Segment.FindByID(id)
+}
+
+func (s *UserSegmentService) GetSegment(segment *model.UserSegment) (*dto.UserSegmentDTO, error) {
+	return s.toUserSegmentDto(segment)
+}
+
+// save checks the name is free for the segment and encodes the filter of req into it
+func (s *UserSegmentService) save(segment *model.UserSegment, req *dto.UserSegmentRequest) error {
+	existing, err := s.repo.Segment.FindByName(req.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != segment.ID {
+		return ErrUserSegmentNameTaken
+	}
+
+	filter := req.Filter
+	filter.Page, filter.Limit, filter.SortBy, filter.Sort = 0, 0, "", ""
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	segment.Name = req.Name
+	segment.Filter = string(data)
+	return nil
+}
+
+func (s *UserSegmentService) Create(req *dto.UserSegmentRequest, createdByID uint) (*dto.UserSegmentDTO, error) {
+	segment := &model.UserSegment{CreatedByID: &createdByID}
+	if err := s.save(segment, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Segment.Create(segment); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.Segment.FindByID(segment.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toUserSegmentDto(created)
+}
+
+// Update changes the filter of announcements to the segment which are not sent yet as well
+func (s *UserSegmentService) Update(segment *model.UserSegment, req *dto.UserSegmentRequest) (*dto.UserSegmentDTO, error) {
+	if err := s.save(segment, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Segment.Update(segment); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.Segment.FindByID(segment.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toUserSegmentDto(updated)
+}
+
+func (s *UserSegmentService) Delete(segment *model.UserSegment) error {
+	pending, err := s.repo.Announcement.CountPendingBySegment(segment.ID)
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		return ErrUserSegmentInUse
+	}
+	return s.repo.Segment.Delete(segment.ID)
+}
@@ -10,24 +10,77 @@ import (
 )
 
 type Service struct {
-	User         *UserService
-	Admin        *AdminService
-	Role         *RoleService
-	Stream       *StreamService
-	StreamServer *streamServerService
-	Category     *CategoryService
+	User          *UserService
+	Admin         *AdminService
+	Role          *RoleService
+	Stream        *StreamService
+	StreamServer  *streamServerService
+	Category      *CategoryService
+	Storage       *StorageService
+	Retention     *RetentionService
+	Recurrence    *RecurrenceService
+	Calendar      *CalendarService
+	Playlist      *PlaylistService
+	Clip          *ClipService
+	Job           *JobService
+	Bulk          *BulkService
+	Trash         *TrashService
+	GDPR          *GDPRService
+	UserImport    *UserImportService
+	Invite        *InviteService
+	Comment       *CommentService
+	CommentFilter *CommentFilterService
+	Report        *ReportService
+	Suspension    *SuspensionService
+	PublishBan    *PublishBanService
+	UserBlock     *UserBlockService
+	Subscription  *SubscriptionService
+	UserSegment   *UserSegmentService
+	Announcement  *AnnouncementService
+
+	NotificationTemplate *NotificationTemplateService
 
 	redisStore cache.RedisStore
 }
 
 func NewService(repo *repository.Repository, redis cache.RedisStore, streamServer *streamServerService) *Service {
+	stream := newStreamService(repo, redis, streamServer)
+	job := newJobService(repo)
+	trash := newTrashService(repo, stream, job)
+	notificationTemplate := newNotificationTemplateService(repo)
+	recurrence := newRecurrenceService(repo)
+	invite := newInviteService(repo, job)
+	comment := newCommentService(repo, redis)
 	return &Service{
-		User:       newUserService(repo, redis),
-		Admin:      newAdminService(repo),
-		Role:       NewRoleService(repo),
-		Category:   newCategoryService(repo),
-		Stream:     newStreamService(repo, redis, streamServer),
-		redisStore: redis,
+		User:          newUserService(repo, redis),
+		Admin:         newAdminService(repo),
+		Role:          NewRoleService(repo),
+		Category:      newCategoryService(repo),
+		Stream:        stream,
+		Storage:       newStorageService(repo),
+		Retention:     newRetentionService(repo, redis),
+		Recurrence:    recurrence,
+		Calendar:      newCalendarService(repo),
+		Playlist:      newPlaylistService(repo),
+		Clip:          newClipService(repo, job),
+		Job:           job,
+		Bulk:          newBulkService(repo, stream, job, trash, notificationTemplate),
+		Trash:         trash,
+		GDPR:          newGDPRService(repo, trash, recurrence, job),
+		UserImport:    newUserImportService(repo, job, invite),
+		Invite:        invite,
+		Comment:       comment,
+		CommentFilter: newCommentFilterService(repo, redis),
+		Report:        newReportService(repo, stream, trash, comment, notificationTemplate),
+		Suspension:    newSuspensionService(repo),
+		PublishBan:    newPublishBanService(repo, redis, stream, streamServer),
+		UserBlock:     newUserBlockService(repo),
+		Subscription:  newSubscriptionService(repo),
+		UserSegment:   newUserSegmentService(repo),
+		Announcement:  newAnnouncementService(repo, job, notificationTemplate),
+
+		NotificationTemplate: notificationTemplate,
+		redisStore:           redis,
 	}
 }
 
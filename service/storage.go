@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var ErrStorageQuotaExceeded = errors.New("storage quota exceeded")
+
+type StorageService struct {
+	repo *repository.Repository
+}
+
+func newStorageService(repo *repository.Repository) *StorageService {
+	return &StorageService{
+		repo: repo,
+	}
+}
+
+// CheckQuota returns ErrStorageQuotaExceeded when incoming bytes don't fit in the quota of user's role.
+// quotas are in bytes per role, missing or 0 is unlimited.
+func (s *StorageService) CheckQuota(userID uint, incoming int64, quotas map[model.RoleType]int64) error {
+	if incoming <= 0 {
+		return nil
+	}
+
+	user, err := s.repo.User.FindByID(int(userID))
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	quota := quotas[user.Role.Type]
+	if quota <= 0 {
+		return nil
+	}
+
+	usage, err := s.repo.Storage.GetByUserID(userID)
+	if err != nil {
+		return err
+	}
+	var used int64
+	if usage != nil {
+		used = usage.TotalBytes()
+	}
+
+	if used+incoming > quota {
+		return fmt.Errorf("%w: %s used of %s, upload needs %s", ErrStorageQuotaExceeded, utils.ConvertBytes(used), utils.ConvertBytes(quota), utils.ConvertBytes(incoming))
+	}
+	return nil
+}
+
+// TrackFile adds size of the file to user's usage. Accounting never fails the request, errors are logged.
+func (s *StorageService) TrackFile(userID uint, fileType model.StorageFileType, path string) {
+	s.addFileUsage(userID, fileType, path, 1)
+}
+
+// UntrackFile must be called before the file is removed
+func (s *StorageService) UntrackFile(userID uint, fileType model.StorageFileType, path string) {
+	s.addFileUsage(userID, fileType, path, -1)
+}
+
+func (s *StorageService) addFileUsage(userID uint, fileType model.StorageFileType, path string, sign int64) {
+	size, err := utils.GetfileSize(path)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	s.addUsage(userID, fileType, sign*size)
+}
+
+func (s *StorageService) addUsage(userID uint, fileType model.StorageFileType, bytes int64) {
+	if err := s.repo.Storage.AddUsage(userID, fileType, bytes); err != nil {
+		log.Println(err)
+	}
+}
+
+// RemoveFilesJob untracks and removes files of a model.JobTypeRemoveFiles job, missing files are skipped so retries are safe
+func (s *StorageService) RemoveFilesJob(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+	payload, err := decodeJobPayload[dto.RemoveFilesPayload](job)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for i, file := range payload.Files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// usage only shrinks once the file is gone, a failed remove is retried with its size still tracked
+		if info, err := os.Stat(file.Path); err == nil {
+			if err := os.Remove(file.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Println(err)
+				failed = append(failed, file.Path)
+			} else if file.UserID != 0 {
+				s.addUsage(file.UserID, file.FileType, -info.Size())
+			}
+		}
+		report(uint((i+1)*100/len(payload.Files)), fmt.Sprintf("%d of %d files", i+1, len(payload.Files)))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to remove %d files: %v", len(failed), failed)
+	}
+	return nil
+}
+
+func (s *StorageService) toStorageUsageDto(usage *model.StorageUsage, quotas map[model.RoleType]int64) dto.StorageUsageRespDTO {
+	return dto.StorageUsageRespDTO{
+		UserID:              usage.UserID,
+		Username:            usage.User.Username,
+		DisplayName:         usage.User.DisplayName,
+		RoleType:            usage.User.Role.Type,
+		ThumbnailBytes:      usage.ThumbnailBytes,
+		ScheduledVideoBytes: usage.ScheduledVideoBytes,
+		RecordingBytes:      usage.RecordingBytes,
+		TotalBytes:          usage.TotalBytes(),
+		QuotaBytes:          quotas[usage.User.Role.Type],
+		UpdatedAt:           usage.UpdatedAt,
+	}
+}
+
+func (s *StorageService) GetUsageReport(req *dto.StorageUsageQuery, quotas map[model.RoleType]int64) (*utils.PaginationModel[dto.StorageUsageRespDTO], error) {
+	pagination, err := s.repo.Storage.Page(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.StorageUsageRespDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.StorageUsage) dto.StorageUsageRespDTO {
+		return s.toStorageUsageDto(&e, quotas)
+	})
+	return result, nil
+}
+
+func (s *StorageService) GetUsageByUserID(userID uint, quotas map[model.RoleType]int64) (*dto.StorageUsageRespDTO, error) {
+	usage, err := s.repo.Storage.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if usage == nil {
+		user, err := s.repo.User.FindByID(int(userID))
+		if err != nil || user == nil {
+			return nil, err
+		}
+		usage = &model.StorageUsage{UserID: user.ID, User: *user}
+	}
+
+	result := s.toStorageUsageDto(usage, quotas)
+	return &result, nil
+}
+
+func fileSizeOrZero(path string) int64 {
+	size, err := utils.GetfileSize(path)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// RecalculateUsage rebuilds usage of every streamer from files on disk.
+// Recordings are written by be-api, so this is the only way they are counted, Start runs it periodically.
+func (s *StorageService) RecalculateUsage(folders StorageFolders) error {
+	files, err := s.repo.Storage.GetStreamFiles()
+	if err != nil {
+		return err
+	}
+
+	usageByUser := map[uint]*model.StorageUsage{}
+	// occurrences of a recurring schedule share thumbnail and video
+	counted := map[string]struct{}{}
+	for _, f := range files {
+		usage, ok := usageByUser[f.UserID]
+		if !ok {
+			usage = &model.StorageUsage{UserID: f.UserID}
+			usageByUser[f.UserID] = usage
+		}
+
+		if _, ok := counted[f.ThumbnailFileName]; !ok && f.ThumbnailFileName != "" {
+			counted[f.ThumbnailFileName] = struct{}{}
+			usage.ThumbnailBytes += fileSizeOrZero(fmt.Sprintf("%s%s", folders.Thumbnail, f.ThumbnailFileName))
+		}
+		if _, ok := counted[f.VideoName]; !ok && f.VideoName != "" {
+			counted[f.VideoName] = struct{}{}
+			usage.ScheduledVideoBytes += fileSizeOrZero(utils.MakeVideoPath(folders.ScheduledVideos, f.VideoName))
+		}
+		usage.RecordingBytes += fileSizeOrZero(utils.MakeVideoPath(folders.Video, f.StreamKey+".mp4"))
+
+		liveVideoPaths, err := filepath.Glob(fmt.Sprintf("%s%s_*.flv", folders.Live, f.StreamKey))
+		if err != nil {
+			return err
+		}
+		for _, path := range liveVideoPaths {
+			usage.RecordingBytes += fileSizeOrZero(path)
+		}
+	}
+
+	playlistFiles, err := s.repo.Storage.GetPlaylistFiles()
+	if err != nil {
+		return err
+	}
+	for _, f := range playlistFiles {
+		if _, ok := counted[f.FileName]; ok {
+			continue
+		}
+		counted[f.FileName] = struct{}{}
+		usage, ok := usageByUser[f.UserID]
+		if !ok {
+			usage = &model.StorageUsage{UserID: f.UserID}
+			usageByUser[f.UserID] = usage
+		}
+		usage.ScheduledVideoBytes += fileSizeOrZero(utils.MakeVideoPath(folders.ScheduledVideos, f.FileName))
+	}
+
+	clipFiles, err := s.repo.Storage.GetClipFiles()
+	if err != nil {
+		return err
+	}
+	for _, f := range clipFiles {
+		usage, ok := usageByUser[f.UserID]
+		if !ok {
+			usage = &model.StorageUsage{UserID: f.UserID}
+			usageByUser[f.UserID] = usage
+		}
+		usage.RecordingBytes += fileSizeOrZero(utils.MakeVideoPath(folders.Clips, f.FileName))
+	}
+
+	usages := make([]model.StorageUsage, 0, len(usageByUser))
+	for _, usage := range usageByUser {
+		usages = append(usages, *usage)
+	}
+	return s.repo.Storage.ReplaceAll(usages)
+}
+
+// Start recalculates usage every interval until ctx is done, only on the leader instance
+func (s *StorageService) Start(ctx context.Context, interval time.Duration, folders StorageFolders, leader *LeaderElector) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !leader.IsLeader() {
+				continue
+			}
+			if err := s.RecalculateUsage(folders); err != nil {
+				log.Printf("Storage usage recalculation failed: %v\n", err)
+			}
+		}
+	}
+}
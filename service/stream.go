@@ -18,6 +18,13 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrStreamNotEnded = errors.New("only ended streams can be rebroadcast")
+	ErrStreamIsLive   = errors.New("you can't delete stream while live")
+	ErrStreamEncoding = errors.New("you can't delete a stream while video is being encoded")
+	ErrStreamNotLive  = errors.New("you can't end a live stream which is not started")
+)
+
 type StreamService struct {
 	repo         *repository.Repository
 	redisStore   cache.RedisStore
@@ -216,7 +223,52 @@ func (s *StreamService) GetLiveStatDataInDay(cond *dto.StatisticsStreamInDayQuer
 	return result, nil
 }
 
-func (s *StreamService) toLiveStreamBroadCastDto(v *model.Stream, apiUrl, rtmpURL, hlsURL string) *dto.LiveStreamBroadCastDTO {
+// getRebroadcastDtos returns rebroadcasts of the ended streams with their analytics by the id of the replayed stream
+func (s *StreamService) getRebroadcastDtos(streams []model.Stream) (map[uint][]dto.RebroadcastDTO, error) {
+	var endedIDs []uint
+	for _, stream := range streams {
+		if stream.Status == model.ENDED {
+			endedIDs = append(endedIDs, stream.ID)
+		}
+	}
+	rebroadcasts, err := s.repo.Stream.GetRebroadcasts(endedIDs)
+	if err != nil {
+		return nil, err
+	}
+	analytics, err := s.repo.Stream.GetStreamAnalyticsByStreams(utils.Map(rebroadcasts, func(e model.ScheduleStream) uint { return e.StreamID }))
+	if err != nil {
+		return nil, err
+	}
+	analyticByStream := make(map[uint]*model.StreamAnalytics, len(analytics))
+	for i := range analytics {
+		analyticByStream[analytics[i].StreamID] = &analytics[i]
+	}
+
+	result := make(map[uint][]dto.RebroadcastDTO)
+	for _, rebroadcast := range rebroadcasts {
+		rebroadcastDto := dto.RebroadcastDTO{
+			StreamID:    rebroadcast.StreamID,
+			Title:       rebroadcast.Stream.Title,
+			Status:      rebroadcast.Stream.Status,
+			ScheduledAt: rebroadcast.ScheduledAt,
+		}
+		if analytic, ok := analyticByStream[rebroadcast.StreamID]; ok {
+			rebroadcastDto.LiveStreamAnalytic = &dto.LiveStreamRespDTO{
+				StreamID:  rebroadcast.StreamID,
+				Duration:  int64(analytic.Duration),
+				Likes:     analytic.Likes,
+				VideoSize: int64(analytic.VideoSize),
+				Viewers:   analytic.Views,
+				Comments:  analytic.Comments,
+				Shares:    analytic.Shares,
+			}
+		}
+		result[*rebroadcast.RebroadcastOfID] = append(result[*rebroadcast.RebroadcastOfID], rebroadcastDto)
+	}
+	return result, nil
+}
+
+func (s *StreamService) toLiveStreamBroadCastDto(v *model.Stream, rebroadcasts []dto.RebroadcastDTO, apiUrl, rtmpURL, hlsURL string) *dto.LiveStreamBroadCastDTO {
 
 	var liveStreamDto = new(dto.LiveStreamBroadCastDTO)
 	liveStreamDto.Title = v.Title
@@ -227,7 +279,12 @@ func (s *StreamService) toLiveStreamBroadCastDto(v *model.Stream, apiUrl, rtmpUR
 		liveStreamDto.PushURL = utils.MakePushURL(rtmpURL, v.StreamToken.String)
 	}
 	liveStreamDto.StreamType = v.StreamType
+	liveStreamDto.IsPinned = v.IsPinned
+	liveStreamDto.LegalHold = v.LegalHold
 	liveStreamDto.ThumbnailFileName = utils.MakeThumbnailURL(apiUrl, v.ThumbnailFileName)
+	if v.Status == model.ENDED {
+		liveStreamDto.VideoURL = utils.MakeRecordingVideoURL(apiUrl, v.StreamKey+".mp4")
+	}
 	if v.StartedAt.Valid {
 		liveStreamDto.StartedAt = &v.StartedAt.Time
 	}
@@ -273,8 +330,23 @@ func (s *StreamService) toLiveStreamBroadCastDto(v *model.Stream, apiUrl, rtmpUR
 		liveStreamDto.ScheduleStream.VideoURL = utils.MakeScheduleVideoURL(apiUrl, scheduleStream.VideoName)
 		liveStreamDto.ScheduleStream.VideoName = scheduleStream.VideoName
 		liveStreamDto.ScheduleStream.ScheduledAt = scheduleStream.ScheduledAt
+		liveStreamDto.ScheduleStream.VideoDuration = scheduleStream.VideoDuration
+		liveStreamDto.ScheduleStream.TotalDuration = scheduleStream.VideoDuration
+		items, err := s.repo.Playlist.FindItems(scheduleStream.ID)
+		if err != nil {
+			log.Println(err.Error())
+			return nil
+		}
+		liveStreamDto.ScheduleStream.Playlist = utils.Map(items, func(e model.ScheduleStreamItem) dto.PlaylistItemDTO {
+			return toPlaylistItemDto(&e, apiUrl)
+		})
+		liveStreamDto.ScheduleStream.PushAttempts = scheduleStream.PushAttempts
+		liveStreamDto.ScheduleStream.LastPushError = scheduleStream.LastPushError
+		liveStreamDto.ScheduleStream.RebroadcastOf = scheduleStream.RebroadcastOfID
 	}
 
+	liveStreamDto.Rebroadcasts = rebroadcasts
+
 	// categories if exist
 	categories, err := s.repo.Stream.GetCategoriesByStreamID(v.ID)
 	if err != nil {
@@ -297,8 +369,12 @@ func (s *StreamService) GetLiveStreamBroadCastWithPagination(page, limit uint, r
 	result := new(utils.PaginationModel[dto.LiveStreamBroadCastDTO])
 	result.BasePaginationModel = pagination.BasePaginationModel
 
+	rebroadcasts, err := s.getRebroadcastDtos(pagination.Page)
+	if err != nil {
+		return nil, err
+	}
 	for _, v := range pagination.Page {
-		liveStreamDto := s.toLiveStreamBroadCastDto(&v, apiUrl, rtmpURL, hlsURL)
+		liveStreamDto := s.toLiveStreamBroadCastDto(&v, rebroadcasts[v.ID], apiUrl, rtmpURL, hlsURL)
 		result.Page = append(result.Page, *liveStreamDto)
 	}
 
@@ -341,10 +417,14 @@ func (s *StreamService) GetLiveStreamBroadCastByID(id int, apiUrl, rtmpURL, hlsU
 	if err != nil {
 		return nil, err
 	}
-	return s.toLiveStreamBroadCastDto(v, apiUrl, rtmpURL, hlsURL), nil
+	rebroadcasts, err := s.getRebroadcastDtos([]model.Stream{*v})
+	if err != nil {
+		return nil, err
+	}
+	return s.toLiveStreamBroadCastDto(v, rebroadcasts[v.ID], apiUrl, rtmpURL, hlsURL), nil
 }
 
-func (s *StreamService) CreateStreamByAdmin(req *dto.StreamRequest) (*model.Stream, error) {
+func (s *StreamService) CreateStreamByAdmin(req *dto.StreamRequest, limits ScheduleLimits) (*model.Stream, error) {
 	channelKey := utils.MakeUniqueID()
 	schduledAt, err := utils.ConvertDatetimeToTimestamp(req.ScheduledAt, utils.DATETIME_LAYOUT)
 	if err != nil {
@@ -362,19 +442,140 @@ func (s *StreamService) CreateStreamByAdmin(req *dto.StreamRequest) (*model.Stre
 	}
 
 	schduleStream := &model.ScheduleStream{
-		ScheduledAt: *schduledAt,
-		VideoName:   req.VideoFileName,
+		ScheduledAt:   *schduledAt,
+		VideoName:     req.VideoFileName,
+		VideoDuration: req.VideoDuration,
+	}
+
+	if err := s.repo.WithScheduleLock(func(repo *repository.Repository) error {
+		if err := checkScheduleConflicts(repo, []scheduleSlot{{
+			UserID:      req.UserID,
+			CategoryIDs: req.CategoryIDs,
+			StartAt:     *schduledAt,
+			Duration:    limits.duration(req.VideoDuration),
+		}}, limits); err != nil {
+			return err
+		}
+		return repo.Stream.CreateScheduleStream(stream, schduleStream, req.CategoryIDs)
+	}); err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.Stream.CreateScheduleStream(stream, schduleStream, req.CategoryIDs); err != nil {
+	return stream, nil
+}
+
+// RebroadcastStream schedules the recording of an ended stream as a new pre-recorded stream with the same title, description and categories
+func (s *StreamService) RebroadcastStream(id uint, req *dto.RebroadcastStreamRequest, limits ScheduleLimits) (*model.Stream, error) {
+	source, err := s.repo.Stream.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if source.Status != model.ENDED {
+		return nil, ErrStreamNotEnded
+	}
+	categories, err := s.repo.Stream.GetCategoriesByStreamID(source.ID)
+	if err != nil {
+		return nil, err
+	}
+	scheduledAt, err := utils.ConvertDatetimeToTimestamp(req.ScheduledAt, utils.DATETIME_LAYOUT)
+	if err != nil {
 		return nil, err
 	}
 
+	categoryIDs := utils.Map(categories, func(e model.Category) uint { return e.ID })
+	stream := &model.Stream{
+		UserID:            source.UserID,
+		Title:             source.Title,
+		Description:       source.Description,
+		Status:            model.UPCOMING,
+		StreamKey:         utils.MakeUniqueID(),
+		StreamType:        model.PRERECORDSTREAM,
+		ThumbnailFileName: req.ThumbnailFileName,
+	}
+	scheduleStream := &model.ScheduleStream{
+		ScheduledAt:     *scheduledAt,
+		VideoName:       req.VideoFileName,
+		VideoDuration:   req.VideoDuration,
+		RebroadcastOfID: &source.ID,
+	}
+	if err := s.repo.WithScheduleLock(func(repo *repository.Repository) error {
+		if err := checkScheduleConflicts(repo, []scheduleSlot{{
+			UserID:      source.UserID,
+			CategoryIDs: categoryIDs,
+			StartAt:     *scheduledAt,
+			Duration:    limits.duration(req.VideoDuration),
+		}}, limits); err != nil {
+			return err
+		}
+		return repo.Stream.CreateScheduleStream(stream, scheduleStream, categoryIDs)
+	}); err != nil {
+		return nil, err
+	}
 	return stream, nil
 }
 
-func (s *StreamService) UpdateScheduledStreamByAdmin(id int, req *dto.UpdateScheduledStreamRequest) error {
-	return s.repo.Stream.UpdateScheduledStream(id, req)
+func (s *StreamService) UpdateScheduledStreamByAdmin(id int, req *dto.UpdateScheduledStreamRequest, limits ScheduleLimits) error {
+	scheduledAt, err := utils.ConvertDatetimeToTimestamp(req.ScheduledAt, utils.DATETIME_LAYOUT)
+	if err != nil {
+		return err
+	}
+	stream, err := s.repo.Stream.GetByID(uint(id))
+	if err != nil {
+		return err
+	}
+	scheduleStream, err := s.repo.Stream.GetScheduleStreamByStreamID(id)
+	if err != nil {
+		return err
+	}
+	categories, err := s.repo.Stream.GetCategoriesByStreamID(stream.ID)
+	if err != nil {
+		return err
+	}
+
+	items, err := s.repo.Playlist.FindItems(scheduleStream.ID)
+	if err != nil {
+		return err
+	}
+
+	// a new video replaces the video and its items of the playlist
+	videoDuration := scheduleStream.VideoDuration
+	if req.VideoFileName != "" {
+		videoDuration = req.VideoDuration
+		if len(items) > 0 {
+			for i := range items {
+				if items[i].FileName == scheduleStream.VideoName {
+					items[i].FileName = req.VideoFileName
+					items[i].Duration = req.VideoDuration * 1000
+					items[i].InPoint, items[i].OutPoint = 0, 0
+				}
+			}
+			videoDuration = playlistDuration(items)
+		}
+	}
+	return s.repo.WithScheduleLock(func(repo *repository.Repository) error {
+		if err := checkScheduleConflicts(repo, []scheduleSlot{{
+			UserID:          stream.UserID,
+			CategoryIDs:     utils.Map(categories, func(e model.Category) uint { return e.ID }),
+			StartAt:         *scheduledAt,
+			Duration:        limits.duration(videoDuration),
+			ExcludeStreamID: stream.ID,
+		}}, limits); err != nil {
+			return err
+		}
+
+		if err := repo.Stream.UpdateScheduledStream(id, req); err != nil {
+			return err
+		}
+		if req.VideoFileName != "" && len(items) > 0 {
+			if err := repo.Playlist.ReplaceFile(scheduleStream.ID, scheduleStream.VideoName, req.VideoFileName, req.VideoDuration*1000); err != nil {
+				return err
+			}
+			if err := repo.Playlist.SetTotalDuration(scheduleStream.ID, videoDuration); err != nil {
+				return err
+			}
+		}
+		return repo.Recurrence.DetachOccurrence(uint(id))
+	})
 }
 
 func (s *StreamService) UpdateStreamByAdmin(id int, req *dto.UpdateStreamRequest) (*model.Stream, error) {
@@ -388,6 +589,9 @@ func (s *StreamService) UpdateStreamByAdmin(id int, req *dto.UpdateStreamRequest
 	if err := s.repo.Stream.UpdateStream(liveStream, nil, req.CategoryIDs); err != nil {
 		return nil, err
 	}
+	if err := s.repo.Recurrence.DetachOccurrence(liveStream.ID); err != nil {
+		return nil, err
+	}
 
 	return liveStream, nil
 }
@@ -396,8 +600,125 @@ func (s *StreamService) UpdateThumbnailStreamByAdmin(id int, req *dto.UpdateStre
 	return s.repo.Stream.UpdateThumbnailStream(id, req.ThumbnailFileName)
 }
 
-func (s *StreamService) DeleteLiveStream(id int) error {
-	return s.repo.Stream.DeleteLiveStream(id)
+// DeleteLiveStream moves a stream to trash, its purge job removes it with related rows
+func (s *StreamService) DeleteLiveStream(id int, deletedByID uint) (time.Time, error) {
+	stream, err := s.repo.Stream.GetByID(uint(id))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if stream.LegalHold {
+		return time.Time{}, ErrStreamLegalHold
+	}
+
+	scheduleStream, err := s.repo.Stream.GetScheduleStreamByID(stream.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, err
+	}
+	deletedAt := time.Now()
+	if err := s.repo.Stream.SoftDelete(stream.ID, deletedByID, deletedAt); err != nil {
+		return time.Time{}, err
+	}
+
+	// deleted occurrence must not be materialized again
+	if scheduleStream != nil && scheduleStream.RecurrenceID != nil && scheduleStream.OccurrenceAt.Valid {
+		return deletedAt, s.repo.Recurrence.AddException(*scheduleStream.RecurrenceID, scheduleStream.OccurrenceAt.Time)
+	}
+	return deletedAt, nil
+}
+
+// CheckDeletable returns why a stream can't be deleted, nil when it can
+func (s *StreamService) CheckDeletable(ctx context.Context, stream *model.Stream) error {
+	if stream.Status == model.STARTED {
+		return ErrStreamIsLive
+	}
+	if stream.LegalHold {
+		return ErrStreamLegalHold
+	}
+	if stream.Status == model.ENDED {
+		isEncoding, err := s.IsEncodingVideo(ctx, stream.StreamKey)
+		if err != nil {
+			return err
+		}
+		if isEncoding {
+			return ErrStreamEncoding
+		}
+	}
+	return nil
+}
+
+// FilesToRemove checks the stream can be deleted and returns its files, files shared with a recurring schedule are removed with the series
+func (s *StreamService) FilesToRemove(ctx context.Context, deletedStream *dto.StreamAndStreamScheduleDto, folders StorageFolders) ([]dto.RemoveFileEntry, error) {
+	stream := deletedStream.Stream
+	if err := s.CheckDeletable(ctx, stream); err != nil {
+		return nil, err
+	}
+
+	var files []dto.RemoveFileEntry
+	add := func(path string, fileType model.StorageFileType) {
+		files = append(files, dto.RemoveFileEntry{Path: path, UserID: stream.UserID, FileType: fileType})
+	}
+	hasSchedule := deletedStream.ScheduleStream != nil && deletedStream.ScheduleStream.ID != 0
+
+	if stream.ThumbnailFileName != "" && !isFileShared(s.repo, stream.ThumbnailFileName, stream.ID) {
+		add(fmt.Sprintf("%s%s", folders.Thumbnail, stream.ThumbnailFileName), model.StorageFileTypeThumbnail)
+	}
+
+	if stream.Status == model.ENDED {
+		// recordings are written by be-api and only counted by StorageService.RecalculateUsage, so they aren't untracked
+		files = append(files, dto.RemoveFileEntry{Path: utils.MakeVideoPath(folders.Video, stream.StreamKey+".mp4")})
+		if liveVideoPath, err := utils.MakeLiveVideoPath(folders.Live, stream.StreamKey); err == nil {
+			files = append(files, dto.RemoveFileEntry{Path: liveVideoPath})
+		}
+
+		clips, err := s.repo.Clip.FindByStreamID(stream.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, clip := range clips {
+			if clip.Status == model.ClipStatusReady {
+				add(utils.MakeVideoPath(folders.Clips, clip.FileName), model.StorageFileTypeRecording)
+			}
+		}
+	}
+
+	if (stream.Status == model.ENDED || stream.Status == model.UPCOMING) && hasSchedule && !isFileShared(s.repo, deletedStream.ScheduleStream.VideoName, stream.ID) {
+		add(utils.MakeVideoPath(folders.ScheduledVideos, deletedStream.ScheduleStream.VideoName), model.StorageFileTypeScheduledVideo)
+	}
+
+	// playlist items are removed with the schedule stream, the first one is its video
+	if hasSchedule {
+		items, err := s.repo.Playlist.FindItems(deletedStream.ScheduleStream.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.FileName == deletedStream.ScheduleStream.VideoName || isFileShared(s.repo, item.FileName, stream.ID) {
+				continue
+			}
+			add(utils.MakeVideoPath(folders.ScheduledVideos, item.FileName), model.StorageFileTypeScheduledVideo)
+		}
+	}
+
+	return files, nil
+}
+
+func (s *StreamService) UpdateCategories(id uint, categoryIDs []uint) error {
+	stream, err := s.repo.Stream.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Stream.UpdateStream(stream, nil, categoryIDs); err != nil {
+		return err
+	}
+	return s.repo.Recurrence.DetachOccurrence(stream.ID)
+}
+
+func (s *StreamService) UpdateLegalHold(id uint, legalHold bool) error {
+	return s.repo.Stream.UpdateLegalHold(id, legalHold)
+}
+
+func (s *StreamService) UpdatePinned(id uint, isPinned bool) error {
+	return s.repo.Stream.UpdatePinned(id, isPinned)
 }
 
 func (s *StreamService) GetLiveStreamByID(id int) (*dto.StreamAndStreamScheduleDto, error) {
@@ -488,19 +809,25 @@ func (s *StreamService) GetLiveStatWithPagination(req *dto.LiveStatQuery) (*util
 }
 
 func (s *StreamService) IsEncodingVideo(ctx context.Context, streamKey string) (bool, error) {
-	cacheKey := fmt.Sprintf(cache.VIDEO_ENCODING_PREFIX, streamKey+".mp4")
-
-	isEncoding, err := cache.GetRedisValWithTyped[bool](s.redisStore, ctx, cacheKey)
+	isEncoding, err := isEncodingVideo(ctx, s.redisStore, streamKey+".mp4")
 	if err != nil {
-		if !errors.Is(err, redis.Nil) {
-			log.Println(err)
-			return false, err
-		}
+		log.Println(err)
+		return false, err
 	}
 
 	return isEncoding, nil
 }
 
+// isEncodingVideo checks the lock be-api holds while encoding videoName
+func isEncodingVideo(ctx context.Context, redisStore cache.RedisStore, videoName string) (bool, error) {
+	cacheKey := fmt.Sprintf(cache.VIDEO_ENCODING_PREFIX, videoName)
+	isEncoding, err := cache.GetRedisValWithTyped[bool](redisStore, ctx, cacheKey)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, err
+	}
+	return isEncoding, nil
+}
+
 func (s *StreamService) IsEndingLive(ctx context.Context, id uint) (bool, error) {
 	cacheKey := fmt.Sprintf(cache.IS_ENDING_LIVE_PREFIX, id)
 
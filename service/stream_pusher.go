@@ -0,0 +1,138 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"gitlab/live/be-live-admin/utils"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StreamPusher pushes a video file to the stream server, Push blocks until the video is over
+type StreamPusher interface {
+	Push(ctx context.Context, videoPath, pushURL string) error
+	// PushPlaylist pushes items one after another, they are re-encoded since videos and slates differ
+	PushPlaylist(ctx context.Context, items []utils.PlaylistInput, pushURL string) error
+}
+
+const (
+	PUSHER_INPUT_PLACEHOLDER  = "{input}"
+	PUSHER_OUTPUT_PLACEHOLDER = "{output}"
+)
+
+var DefaultFFmpegArgs = []string{"-re", "-i", PUSHER_INPUT_PLACEHOLDER, "-c", "copy", "-f", "flv", PUSHER_OUTPUT_PLACEHOLDER}
+
+// output of playlists, inputs and the concat filter are added before them
+var DefaultFFmpegPlaylistArgs = []string{
+	"-c:v", "libx264", "-preset", "veryfast", "-g", "60", "-c:a", "aac", "-ar", "44100",
+	"-f", "flv", PUSHER_OUTPUT_PLACEHOLDER,
+}
+
+const (
+	DEFAULT_PLAYLIST_WIDTH  = 1280
+	DEFAULT_PLAYLIST_HEIGHT = 720
+	PLAYLIST_FPS            = 30
+)
+
+type FFmpegPusher struct {
+	binary         string
+	args           []string
+	playlistArgs   []string
+	playlistWidth  int
+	playlistHeight int
+}
+
+// NewFFmpegPusher runs binary with args or playlistArgs, {input} and {output} are replaced by video path and push url.
+// Playlist items are scaled to playlistWidth x playlistHeight.
+func NewFFmpegPusher(binary string, args, playlistArgs []string, playlistWidth, playlistHeight int) *FFmpegPusher {
+	if binary == "" {
+		binary = "ffmpeg"
+	}
+	if len(args) == 0 {
+		args = DefaultFFmpegArgs
+	}
+	if len(playlistArgs) == 0 {
+		playlistArgs = DefaultFFmpegPlaylistArgs
+	}
+	if playlistWidth <= 0 || playlistHeight <= 0 {
+		playlistWidth, playlistHeight = DEFAULT_PLAYLIST_WIDTH, DEFAULT_PLAYLIST_HEIGHT
+	}
+	return &FFmpegPusher{
+		binary:         binary,
+		args:           args,
+		playlistArgs:   playlistArgs,
+		playlistWidth:  playlistWidth,
+		playlistHeight: playlistHeight,
+	}
+}
+
+func (p *FFmpegPusher) Push(ctx context.Context, videoPath, pushURL string) error {
+	return p.run(ctx, p.args, videoPath, pushURL)
+}
+
+func (p *FFmpegPusher) PushPlaylist(ctx context.Context, items []utils.PlaylistInput, pushURL string) error {
+	args := append(utils.BuildFFmpegConcatArgs(items, p.playlistWidth, p.playlistHeight, PLAYLIST_FPS), p.playlistArgs...)
+	return runFFmpeg(ctx, p.binary, args, strings.NewReplacer(PUSHER_OUTPUT_PLACEHOLDER, pushURL))
+}
+
+func (p *FFmpegPusher) run(ctx context.Context, argTemplates []string, input, pushURL string) error {
+	return runFFmpeg(ctx, p.binary, argTemplates, strings.NewReplacer(PUSHER_INPUT_PLACEHOLDER, input, PUSHER_OUTPUT_PLACEHOLDER, pushURL))
+}
+
+// runFFmpeg replaces placeholders of argTemplates and runs binary until it exits
+func runFFmpeg(ctx context.Context, binary string, argTemplates []string, replacer *strings.Replacer) error {
+	args := make([]string, len(argTemplates))
+	for i, arg := range argTemplates {
+		args[i] = replacer.Replace(arg)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// ffmpeg prints the reason at the end
+		output := stderr.String()
+		if len(output) > 500 {
+			output = output[len(output)-500:]
+		}
+		return fmt.Errorf("%s failed: %w: %s", binary, err, strings.TrimSpace(output))
+	}
+	return nil
+}
+
+// FakePusher doesn't push anything, it waits for Duration and returns Err.
+// Used for local runs and tests without ffmpeg and stream server.
+type FakePusher struct {
+	Duration time.Duration
+	Err      error
+
+	mu     sync.Mutex
+	pushes []string
+}
+
+func (p *FakePusher) Push(ctx context.Context, videoPath, pushURL string) error {
+	p.mu.Lock()
+	p.pushes = append(p.pushes, fmt.Sprintf("%s -> %s", videoPath, pushURL))
+	p.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(p.Duration):
+		return p.Err
+	}
+}
+
+func (p *FakePusher) PushPlaylist(ctx context.Context, items []utils.PlaylistInput, pushURL string) error {
+	return p.Push(ctx, strings.Join(utils.Map(items, func(e utils.PlaylistInput) string { return e.Path }), ","), pushURL)
+}
+
+// Pushes returns every push as "{video path} -> {push url}", paths of playlist items are joined by commas
+func (p *FakePusher) Pushes() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.pushes...)
+}
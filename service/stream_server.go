@@ -54,3 +54,28 @@ func (s *streamServerService) GetChannelKey(key string) (string, error) {
 	return response.Data, nil
 
 }
+
+// DeleteChannelKey revokes the key of a room, publishing with it fails until a new one is issued by GetChannelKey
+func (s *streamServerService) DeleteChannelKey(key string) error {
+	url := fmt.Sprintf("%s/control/delete?room=%s", s.streamServerHTTPURL, key)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	response := Response{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+	if response.Status != http.StatusOK {
+		return fmt.Errorf("stream server failed to delete channel key: %s", response.Data)
+	}
+	return nil
+}
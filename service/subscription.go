@@ -0,0 +1,176 @@
+package service
+
+import (
+	"cmp"
+	"errors"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"slices"
+	"time"
+)
+
+var (
+	ErrSubscriptionRange  = errors.New("from must be before to")
+	ErrSubscriptionGrowth = errors.New("growth is too long for the interval, use a larger one")
+)
+
+// SubscriptionService shows followers of streamers, subscriptions are made by users of be-api
+type SubscriptionService struct {
+	repo *repository.Repository
+}
+
+func newSubscriptionService(repo *repository.Repository) *SubscriptionService {
+	return &SubscriptionService{
+		repo: repo,
+	}
+}
+
+func muteRatio(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+// subscriptionRange defaults to the last days
+func subscriptionRange(from, to int64, days int) (time.Time, time.Time, error) {
+	end := time.Now()
+	if to != 0 {
+		end = time.Unix(to, 0)
+	}
+	start := end.AddDate(0, 0, -days)
+	if from != 0 {
+		start = time.Unix(from, 0)
+	}
+	if !start.Before(end) {
+		return start, end, ErrSubscriptionRange
+	}
+	return start, end, nil
+}
+
+func (s *SubscriptionService) GetPage(userID uint, direction string, req *dto.SubscriptionQuery) (*utils.PaginationModel[dto.SubscriptionDTO], error) {
+	pagination, err := s.repo.Subscription.Page(userID, direction, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(utils.PaginationModel[dto.SubscriptionDTO])
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.Subscription) dto.SubscriptionDTO {
+		other := &e.Subscriber
+		if direction == dto.SUBSCRIPTION_DIRECTION_SUBSCRIPTIONS {
+			other = &e.Streamer
+		}
+		return dto.SubscriptionDTO{
+			ID:               e.ID,
+			User:             toUserSummaryDto(other),
+			AccountCreatedAt: other.CreatedAt,
+			IsMute:           e.IsMute,
+			CreatedAt:        e.CreatedAt,
+		}
+	})
+	return result, nil
+}
+
+func (s *SubscriptionService) GetStats(userID uint) (*dto.SubscriptionStatsDTO, error) {
+	subscribers, err := s.repo.Subscription.Counts("streamer_id", userID)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions, err := s.repo.Subscription.Counts("subscriber_id", userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SubscriptionStatsDTO{
+		Subscribers:        subscribers.Total,
+		MutedSubscribers:   subscribers.Muted,
+		MuteRatio:          muteRatio(subscribers.Muted, subscribers.Total),
+		Subscriptions:      subscriptions.Total,
+		MutedSubscriptions: subscriptions.Muted,
+	}, nil
+}
+
+// GetGrowth counts subscribers per interval with running totals, empty intervals included
+func (s *SubscriptionService) GetGrowth(streamerID uint, req *dto.SubscriptionGrowthQuery) (*dto.SubscriptionGrowthDTO, error) {
+	from, to, err := subscriptionRange(req.From, req.To, dto.SUBSCRIPTION_GROWTH_DEFAULT_DAYS)
+	if err != nil {
+		return nil, err
+	}
+	interval := req.Interval
+	if interval == 0 {
+		interval = dto.SUBSCRIPTION_GROWTH_DEFAULT_INTERVAL
+	}
+	step := time.Duration(interval) * time.Second
+	count := int((to.Sub(from) + step - 1) / step)
+	if count > dto.SUBSCRIPTION_GROWTH_MAX_BUCKETS {
+		return nil, ErrSubscriptionGrowth
+	}
+
+	before, err := s.repo.Subscription.CountBefore(streamerID, from)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.Subscription.Growth(streamerID, from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.SubscriptionGrowthDTO{StreamerID: streamerID, From: from, To: to, Interval: interval, Buckets: make([]dto.SubscriptionGrowthBucket, count)}
+	for i := range result.Buckets {
+		result.Buckets[i].Start = from.Add(time.Duration(i) * step)
+	}
+	for _, row := range rows {
+		result.Buckets[row.Bucket].New = row.Total
+		result.Buckets[row.Bucket].NewMuted = row.Muted
+	}
+	total, muted := before.Total, before.Muted
+	for i := range result.Buckets {
+		total += result.Buckets[i].New
+		muted += result.Buckets[i].NewMuted
+		result.Buckets[i].Total = total
+		result.Buckets[i].MuteRatio = muteRatio(muted, total)
+	}
+	return result, nil
+}
+
+// GetBursts finds spans with many subscriptions from new accounts within a sliding window, a sign of follow spam
+func (s *SubscriptionService) GetBursts(streamerID uint, req *dto.SubscriptionBurstQuery) (*dto.SubscriptionBurstsDTO, error) {
+	from, to, err := subscriptionRange(req.From, req.To, dto.SUBSCRIPTION_BURST_DEFAULT_DAYS)
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.SubscriptionBurstsDTO{
+		StreamerID: streamerID,
+		From:       from,
+		To:         to,
+		Window:     cmp.Or(req.Window, dto.SUBSCRIPTION_BURST_DEFAULT_WINDOW),
+		MinCount:   cmp.Or(req.MinCount, dto.SUBSCRIPTION_BURST_DEFAULT_MIN_COUNT),
+		AccountAge: cmp.Or(req.AccountAge, dto.SUBSCRIPTION_BURST_DEFAULT_ACCOUNT_AGE),
+		Bursts:     []dto.SubscriptionBurstDTO{},
+	}
+
+	rows, err := s.repo.Subscription.Bursts(streamerID, from, to, result.Window, result.AccountAge, result.MinCount, dto.SUBSCRIPTION_BURST_MAX_BURSTS)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		end := row.End
+		if end.After(to) {
+			end = to
+		}
+		ids, err := s.repo.Subscription.FindNewAccountSubscriberIDs(streamerID, row.Start, end, result.AccountAge, dto.SUBSCRIPTION_REMOVE_MAX)
+		if err != nil {
+			return nil, err
+		}
+		result.Bursts = append(result.Bursts, dto.SubscriptionBurstDTO{Start: row.Start, End: end, Count: row.Count, SubscriberIDs: ids})
+	}
+	return result, nil
+}
+
+// Remove removes subscriptions of the subscribers to the streamer, it returns how many were removed
+func (s *SubscriptionService) Remove(streamerID uint, subscriberIDs []uint) (int64, error) {
+	slices.Sort(subscriberIDs)
+	return s.repo.Subscription.DeleteBySubscriberIDs(streamerID, slices.Compact(subscriberIDs))
+}
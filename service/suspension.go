@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"time"
+)
+
+const (
+	DEFAULT_MAX_STRIKES         = 3
+	DEFAULT_SUSPENSION_INTERVAL = time.Minute
+
+	reinstateBatchSize = 100
+)
+
+var (
+	ErrUserBlockedPermanently = errors.New("user is already blocked permanently")
+	ErrStrikeRevoked          = errors.New("strike is already revoked")
+	ErrStrikeNotOfUser        = errors.New("strike doesn't belong to the user")
+)
+
+// SuspensionService blocks users for a while on each strike, until they reach the max strikes
+type SuspensionService struct {
+	repo *repository.Repository
+}
+
+func newSuspensionService(repo *repository.Repository) *SuspensionService {
+	return &SuspensionService{
+		repo: repo,
+	}
+}
+
+func toUserStrikeDto(strike *model.UserStrike) dto.UserStrikeDTO {
+	return dto.UserStrikeDTO{
+		ID:             strike.ID,
+		UserID:         strike.UserID,
+		Reason:         strike.Reason,
+		Note:           strike.Note,
+		Duration:       strike.Duration,
+		SuspendedUntil: strike.SuspendedUntil,
+		Permanent:      strike.Permanent,
+		IssuedBy:       toUserSummaryDto(strike.IssuedBy),
+		Revoked:        strike.RevokedAt != nil,
+		RevokedAt:      strike.RevokedAt,
+		RevokedBy:      toUserSummaryDto(strike.RevokedBy),
+		CreatedAt:      strike.CreatedAt,
+	}
+}
+
+func strikeBlockedReason(strike *model.UserStrike) string {
+	if strike.Note == "" {
+		return string(strike.Reason)
+	}
+	return fmt.Sprintf("%s: %s", strike.Reason, strike.Note)
+}
+
+// Suspend adds a strike to the user, a running suspension is only ever extended
+func (s *SuspensionService) Suspend(user *model.User, req *dto.SuspendUserRequest, issuedByID uint, maxStrikes int) (*dto.SuspendUserResponse, error) {
+	if user.Status == model.BLOCKED && user.SuspendedUntil == nil {
+		return nil, ErrUserBlockedPermanently
+	}
+	if maxStrikes <= 0 {
+		maxStrikes = DEFAULT_MAX_STRIKES
+	}
+
+	strike := &model.UserStrike{
+		UserID:     user.ID,
+		Reason:     req.Reason,
+		Note:       req.Note,
+		Duration:   req.Duration,
+		IssuedByID: &issuedByID,
+	}
+	// the repository escalates with the strikes counted under the lock, user.Strikes may be stale
+	suspendedUntil := time.Now().Add(time.Duration(req.Duration) * time.Second)
+	strike.SuspendedUntil = &suspendedUntil
+	strikes, err := s.repo.Strike.Create(strike, uint(maxStrikes), strikeBlockedReason(strike))
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Strike.FindByID(strike.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SuspendUserResponse{
+		Strike:  toUserStrikeDto(created),
+		Strikes: strikes,
+	}, nil
+}
+
+func (s *SuspensionService) GetStrikes(user *model.User, maxStrikes int) (*dto.UserStrikesDTO, error) {
+	strikes, err := s.repo.Strike.FindByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if maxStrikes <= 0 {
+		maxStrikes = DEFAULT_MAX_STRIKES
+	}
+	return &dto.UserStrikesDTO{
+		Strikes:        user.Strikes,
+		MaxStrikes:     maxStrikes,
+		SuspendedUntil: user.SuspendedUntil,
+		Status:         user.Status,
+		History: utils.Map(strikes, func(e model.UserStrike) dto.UserStrikeDTO {
+			return toUserStrikeDto(&e)
+		}),
+	}, nil
+}
+
+func (s *SuspensionService) FindStrikeByID(id uint) (*model.UserStrike, error) {
+	return s.repo.Strike.FindByID(id)
+}
+
+// RevokeStrike reinstates the user when the strike is what blocks it now, it returns whether the user was reinstated
+func (s *SuspensionService) RevokeStrike(user *model.User, strike *model.UserStrike, revokedByID uint) (*dto.UserStrikeDTO, bool, error) {
+	if strike.UserID != user.ID {
+		return nil, false, ErrStrikeNotOfUser
+	}
+	if strike.RevokedAt != nil {
+		return nil, false, ErrStrikeRevoked
+	}
+
+	reinstate := false
+	if user.Status == model.BLOCKED {
+		if strike.Permanent {
+			reinstate = user.SuspendedUntil == nil
+		} else {
+			reinstate = user.SuspendedUntil != nil && strike.SuspendedUntil != nil && user.SuspendedUntil.Equal(*strike.SuspendedUntil)
+		}
+	}
+	revoked, err := s.repo.Strike.Revoke(strike, revokedByID, reinstate)
+	if err != nil {
+		return nil, false, err
+	}
+	if !revoked {
+		return nil, false, ErrStrikeRevoked
+	}
+
+	updated, err := s.repo.Strike.FindByID(strike.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	result := toUserStrikeDto(updated)
+	return &result, reinstate, nil
+}
+
+// ReinstateDue unblocks users whose suspension ended, it returns how many were reinstated
+func (s *SuspensionService) ReinstateDue(now time.Time) (int, error) {
+	superAdmin, err := s.repo.User.FindByEmail(model.SUPER_ADMIN_EMAIL)
+	if err != nil {
+		return 0, err
+	}
+
+	reinstated := 0
+	for {
+		users, err := s.repo.Strike.FindDueSuspensions(now, reinstateBatchSize)
+		if err != nil {
+			return reinstated, err
+		}
+		failed := 0
+		for _, user := range users {
+			ok, err := s.repo.Strike.Reinstate(user.ID, now)
+			if err != nil {
+				log.Printf("Failed to reinstate user %d: %v\n", user.ID, err)
+				failed++
+				continue
+			}
+			if !ok {
+				continue
+			}
+			reinstated++
+
+			if superAdmin == nil {
+				continue
+			}
+			adminLog := &model.AdminLog{
+				UserID:  superAdmin.ID,
+				Action:  string(model.ReinstateUser),
+				Details: fmt.Sprintf("Reinstated %s, suspended until %s.", user.Username, user.SuspendedUntil.Format(time.RFC3339)),
+			}
+			if err := s.repo.Admin.Create(adminLog); err != nil {
+				log.Println(err)
+			}
+		}
+		// failed ones are retried on the next run
+		if len(users) < reinstateBatchSize || failed == len(users) {
+			return reinstated, nil
+		}
+	}
+}
+
+func (s *SuspensionService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ReinstateDue(time.Now()); err != nil {
+				log.Printf("Suspension reinstatement failed: %v\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const DEFAULT_TRASH_PURGE_DELAY = 30 * 24 * time.Hour
+
+var ErrNotInTrash = errors.New("not found in trash")
+
+// TrashService soft deletes users and streams, a delayed job purges them with their files unless they're restored first
+type TrashService struct {
+	repo   *repository.Repository
+	stream *StreamService
+	job    *JobService
+}
+
+func newTrashService(repo *repository.Repository, stream *StreamService, job *JobService) *TrashService {
+	return &TrashService{
+		repo:   repo,
+		stream: stream,
+		job:    job,
+	}
+}
+
+func purgeDelayOrDefault(purgeDelay time.Duration) time.Duration {
+	if purgeDelay <= 0 {
+		return DEFAULT_TRASH_PURGE_DELAY
+	}
+	return purgeDelay
+}
+
+// DeleteUser moves a user to trash and queues its purge
+func (s *TrashService) DeleteUser(id, deletedByID uint, purgeDelay time.Duration) (*model.Job, error) {
+	deletedAt := time.Now()
+	if err := s.repo.User.Delete(id, deletedByID, deletedAt); err != nil {
+		return nil, err
+	}
+	return s.job.EnqueueAt(model.JobTypePurgeUser, dto.PurgePayload{ID: id, DeletedAt: deletedAt}, 0, deletedByID, deletedAt.Add(purgeDelayOrDefault(purgeDelay)))
+}
+
+// DeleteStream moves a stream to trash and queues its purge, callers check StreamService.CheckDeletable first
+func (s *TrashService) DeleteStream(id, deletedByID uint, purgeDelay time.Duration) (*model.Job, error) {
+	deletedAt, err := s.stream.DeleteLiveStream(int(id), deletedByID)
+	if err != nil {
+		return nil, err
+	}
+	return s.job.EnqueueAt(model.JobTypePurgeStream, dto.PurgePayload{ID: id, DeletedAt: deletedAt}, 0, deletedByID, deletedAt.Add(purgeDelayOrDefault(purgeDelay)))
+}
+
+func (s *TrashService) GetDeletedUser(id uint) (*model.User, error) {
+	user, err := s.repo.User.FindDeletedByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrNotInTrash
+	}
+	return user, nil
+}
+
+func (s *TrashService) GetDeletedStream(id uint) (*model.Stream, error) {
+	stream, err := s.repo.Stream.GetDeletedByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil {
+		return nil, ErrNotInTrash
+	}
+	return stream, nil
+}
+
+// RestoreUser takes a user out of trash, its purge job does nothing when it runs
+func (s *TrashService) RestoreUser(id uint) error {
+	if _, err := s.GetDeletedUser(id); err != nil {
+		return err
+	}
+	return s.repo.User.Restore(id)
+}
+
+// RestoreStream takes a stream out of trash, its purge job does nothing when it runs.
+// A restored occurrence belongs to its series again, so the exception added by the deletion is removed.
+func (s *TrashService) RestoreStream(id uint) error {
+	if _, err := s.GetDeletedStream(id); err != nil {
+		return err
+	}
+	scheduleStream, err := s.repo.Stream.GetScheduleStreamByID(id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return s.repo.Transaction(func(repo *repository.Repository) error {
+		if err := repo.Stream.Restore(id); err != nil {
+			return err
+		}
+		if scheduleStream != nil && scheduleStream.RecurrenceID != nil && scheduleStream.OccurrenceAt.Valid {
+			return repo.Recurrence.RemoveException(*scheduleStream.RecurrenceID, scheduleStream.OccurrenceAt.Time)
+		}
+		return nil
+	})
+}
+
+func (s *TrashService) GetTrash(req *dto.TrashQuery, purgeDelay time.Duration) (*utils.PaginationModel[dto.TrashItemDTO], error) {
+	purgeDelay = purgeDelayOrDefault(purgeDelay)
+	result := new(utils.PaginationModel[dto.TrashItemDTO])
+
+	if req.Type == dto.TRASH_TYPE_USER {
+		pagination, err := s.repo.Trash.PageUsers(req)
+		if err != nil {
+			return nil, err
+		}
+		result.BasePaginationModel = pagination.BasePaginationModel
+		result.Page = utils.Map(pagination.Page, func(e model.User) dto.TrashItemDTO {
+			return dto.TrashItemDTO{
+				ID:        e.ID,
+				Type:      dto.TRASH_TYPE_USER,
+				Name:      e.Username,
+				DeletedAt: e.DeletedAt.Time,
+				PurgeAt:   e.DeletedAt.Time.Add(purgeDelay),
+				DeletedBy: toUserSummaryDto(e.DeletedBy),
+			}
+		})
+		return result, nil
+	}
+
+	pagination, err := s.repo.Trash.PageStreams(req)
+	if err != nil {
+		return nil, err
+	}
+	result.BasePaginationModel = pagination.BasePaginationModel
+	result.Page = utils.Map(pagination.Page, func(e model.Stream) dto.TrashItemDTO {
+		return dto.TrashItemDTO{
+			ID:        e.ID,
+			Type:      dto.TRASH_TYPE_STREAM,
+			Name:      e.Title,
+			UserID:    e.UserID,
+			DeletedAt: e.DeletedAt.Time,
+			PurgeAt:   e.DeletedAt.Time.Add(purgeDelay),
+			DeletedBy: toUserSummaryDto(e.DeletedBy),
+		}
+	})
+	return result, nil
+}
+
+// isSameDeletion tells a purge job apart from one queued by an earlier deletion of a restored item
+func isSameDeletion(deletedAt gorm.DeletedAt, payload *dto.PurgePayload) bool {
+	return deletedAt.Valid && deletedAt.Time.Unix() == payload.DeletedAt.Unix()
+}
+
+// PurgeUserJob runs a model.JobTypePurgeUser job, streams of the user are removed by the database cascade and their files by file gc
+func (s *TrashService) PurgeUserJob(avatarFolder string) JobHandler {
+	return func(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+		payload, err := decodeJobPayload[dto.PurgePayload](job)
+		if err != nil {
+			return err
+		}
+		user, err := s.repo.User.FindDeletedByID(payload.ID)
+		if err != nil {
+			return err
+		}
+		if user == nil || !isSameDeletion(user.DeletedAt, payload) {
+			report(100, "restored")
+			return nil
+		}
+
+		if err := s.repo.User.Purge(user.ID); err != nil {
+			return err
+		}
+		if user.AvatarFileName.Valid {
+			if err := os.Remove(fmt.Sprintf("%s%s", avatarFolder, user.AvatarFileName.String)); err != nil && !os.IsNotExist(err) {
+				log.Println(err)
+			}
+		}
+		return nil
+	}
+}
+
+// PurgeStreamJob runs a model.JobTypePurgeStream job, files are removed by a model.JobTypeRemoveFiles job
+func (s *TrashService) PurgeStreamJob(folders StorageFolders) JobHandler {
+	return func(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+		payload, err := decodeJobPayload[dto.PurgePayload](job)
+		if err != nil {
+			return err
+		}
+		stream, err := s.repo.Stream.GetDeletedByID(payload.ID)
+		if err != nil {
+			return err
+		}
+		if stream == nil || !isSameDeletion(stream.DeletedAt, payload) {
+			report(100, "restored")
+			return nil
+		}
+		return s.purgeStream(ctx, stream, folders)
+	}
+}
+
+// purgeStream hard deletes a stream, trashed or not, and queues removal of its files
+func (s *TrashService) purgeStream(ctx context.Context, stream *model.Stream, folders StorageFolders) error {
+	scheduleStream, err := s.repo.Stream.GetScheduleStreamByID(stream.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	files, err := s.stream.FilesToRemove(ctx, &dto.StreamAndStreamScheduleDto{Stream: stream, ScheduleStream: scheduleStream}, folders)
+	if err != nil {
+		return err
+	}
+	// files are only removed once the purge commits and never leak when it does
+	return s.repo.Transaction(func(repo *repository.Repository) error {
+		if err := repo.Stream.DeleteLiveStream(int(stream.ID)); err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return nil
+		}
+		_, err := s.job.EnqueueWith(repo, model.JobTypeRemoveFiles, dto.RemoveFilesPayload{Files: files}, 0, 0)
+		return err
+	})
+}
@@ -2,14 +2,26 @@ package service
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"gitlab/live/be-live-admin/cache"
 	"gitlab/live/be-live-admin/dto"
 	"gitlab/live/be-live-admin/model"
 	"gitlab/live/be-live-admin/repository"
 	"gitlab/live/be-live-admin/utils"
 	"math/rand"
+	"slices"
+	"strings"
 	"sync"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUsernameTaken = errors.New("username already exists")
+	ErrEmailTaken    = errors.New("email already exists")
+	ErrUserConflict  = errors.New("username or email was just taken by another user")
 )
 
 type UserService struct {
@@ -34,6 +46,9 @@ func (s *UserService) toUserResponseDTO(user *model.User, apiURL string) dto.Use
 	userResp.Email = user.Email
 	userResp.Status = user.Status
 	userResp.BlockedReason = user.BlockedReason
+	userResp.SuspendedUntil = user.SuspendedUntil
+	userResp.Strikes = user.Strikes
+	userResp.Locale = user.Locale
 	if user.AvatarFileName.Valid {
 		userResp.AvatarFileName = utils.MakeAvatarURL(apiURL, user.AvatarFileName.String)
 	}
@@ -75,6 +90,14 @@ func (s *UserService) toUserResponseDTO(user *model.User, apiURL string) dto.Use
 	return *userResp
 }
 
+// toUserSummaryDto is the id, username and display name of a related user, nil when it wasn't loaded
+func toUserSummaryDto(user *model.User) *dto.UserResponseDTO {
+	if user == nil || user.ID == 0 {
+		return nil
+	}
+	return &dto.UserResponseDTO{ID: user.ID, Username: user.Username, DisplayName: user.DisplayName}
+}
+
 func (s *UserService) GetUserList(filter *dto.UserQuery, page, limit uint, apiURL string) (*utils.PaginationModel[dto.UserResponseDTO], error) {
 	pagination, err := s.repo.User.Page(filter, page, limit)
 	if err != nil {
@@ -94,14 +117,6 @@ func (s *UserService) GetUsernameList() ([]string, error) {
 	return s.repo.User.GetUsernameList()
 }
 
-func (s *UserService) DeleteByID(id uint, deletedByID uint) error {
-	if err := s.repo.User.Delete(id, deletedByID); err != nil {
-		return err
-	}
-	return nil
-
-}
-
 func (s *UserService) toUpdatedUserDTO(user *model.User, role model.RoleType, apiURL string) *dto.UpdateUserResponse {
 	return &dto.UpdateUserResponse{
 		ID:          user.ID,
@@ -125,6 +140,9 @@ func (s *UserService) makeUpdatedUserModel(user *model.User, updatedUser *dto.Up
 	if updatedUser.Email != "" {
 		user.Email = updatedUser.Email
 	}
+	if updatedUser.Locale != "" {
+		user.Locale = normalizeLocale(updatedUser.Locale)
+	}
 	if updatedUser.RoleType != "" {
 		role, err := s.repo.Role.FindByType(updatedUser.RoleType)
 		if err != nil {
@@ -186,6 +204,95 @@ func (s *UserService) ChangeAvatar(user *model.User, changeAvartar *dto.ChangeAv
 	return s.toUpdatedUserDTO(user, user.Role.Type, apiUrl), nil
 }
 
+// takenNames are the users, trashed ones included since they keep their unique values until they're purged,
+// and the pending invites which hold some of the looked up usernames and emails
+type takenNames struct {
+	users     []model.User
+	invites   []model.Invite
+	usernames map[string]struct{}
+	emails    map[string]struct{} // lower case
+}
+
+// findTakenNames looks up who holds usernames and emails, emails are compared lower case.
+// The invite exceptInviteID is left out, so accepting it doesn't collide with its own reservation.
+func findTakenNames(repo *repository.Repository, usernames, emails []string, exceptInviteID uint) (*takenNames, error) {
+	emails = utils.Map(emails, strings.ToLower)
+	users, err := repo.User.FindExisting(usernames, emails)
+	if err != nil {
+		return nil, err
+	}
+	invites, err := repo.Invite.FindPending(usernames, emails)
+	if err != nil {
+		return nil, err
+	}
+	invites = slices.DeleteFunc(invites, func(invite model.Invite) bool { return invite.ID == exceptInviteID })
+
+	result := &takenNames{
+		users:     users,
+		invites:   invites,
+		usernames: make(map[string]struct{}, len(users)+len(invites)),
+		emails:    make(map[string]struct{}, len(users)+len(invites)),
+	}
+	for _, user := range users {
+		result.usernames[user.Username] = struct{}{}
+		result.emails[strings.ToLower(user.Email)] = struct{}{}
+	}
+	for _, invite := range invites {
+		result.usernames[invite.Username] = struct{}{}
+		result.emails[strings.ToLower(invite.Email)] = struct{}{}
+	}
+	return result, nil
+}
+
+func (t *takenNames) hasUsername(username string) bool {
+	_, ok := t.usernames[username]
+	return ok
+}
+
+func (t *takenNames) hasEmail(email string) bool {
+	_, ok := t.emails[strings.ToLower(email)]
+	return ok
+}
+
+// check returns ErrUsernameTaken or ErrEmailTaken naming the trashed user or pending invite which holds them
+func (t *takenNames) check(username, email string) error {
+	email = strings.ToLower(email)
+	for _, user := range t.users {
+		if user.Username != username && strings.ToLower(user.Email) != email {
+			continue
+		}
+		err := ErrEmailTaken
+		if user.Username == username {
+			err = ErrUsernameTaken
+		}
+		if user.DeletedAt.Valid {
+			return fmt.Errorf("%w: user %d is in trash, restore or purge it first", err, user.ID)
+		}
+		return err
+	}
+	for _, invite := range t.invites {
+		if invite.Username != username && strings.ToLower(invite.Email) != email {
+			continue
+		}
+		err := ErrEmailTaken
+		if invite.Username == username {
+			err = ErrUsernameTaken
+		}
+		return fmt.Errorf("%w: it's reserved by pending invite %d", err, invite.ID)
+	}
+	return nil
+}
+
+// checkUserAvailable rejects usernames and emails of users and pending invites, run it under repo.WithUserNamesLock
+// with the write it allows
+func checkUserAvailable(repo *repository.Repository, username, email string, exceptInviteID uint) error {
+	taken, err := findTakenNames(repo, []string{username}, []string{email}, exceptInviteID)
+	if err != nil {
+		return err
+	}
+	return taken.check(username, email)
+}
+
 func (s *UserService) CreateUser(request *dto.CreateUserRequest) error {
 	var newUser = new(model.User)
 	newUser.Username = request.UserName
@@ -204,10 +311,17 @@ func (s *UserService) CreateUser(request *dto.CreateUserRequest) error {
 		newUser.AvatarFileName = sql.NullString{String: request.AvatarFileName, Valid: true}
 	}
 
-	if err := s.Create(newUser); err != nil {
-		return err
+	err = s.repo.WithUserNamesLock(func(repo *repository.Repository) error {
+		if err := checkUserAvailable(repo, newUser.Username, newUser.Email, 0); err != nil {
+			return err
+		}
+		return repo.User.Create(newUser)
+	})
+	// the unique indexes still stop writes which don't take the lock
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrUserConflict
 	}
-	return nil
+	return err
 }
 
 func (s *UserService) Create(user *model.User) error {
@@ -222,12 +336,14 @@ func (s *UserService) FindByID(id uint) (*model.User, error) {
 	return s.repo.User.FindByID(int(id))
 }
 
+// ChangeStatusUser ends suspensions, blocking is permanent
 func (s *UserService) ChangeStatusUser(user *model.User, updatedByID uint, status model.UserStatusType, reason, apiUrl string) (*dto.UpdateUserResponse, error) {
 	user.Status = status
 	user.BlockedReason = reason
 	user.UpdatedByID = &updatedByID
 	user.UpdatedAt = time.Now()
-	return s.toUpdatedUserDTO(user, user.Role.Type, apiUrl), s.repo.User.Update(user)
+	user.SuspendedUntil = nil
+	return s.toUpdatedUserDTO(user, user.Role.Type, apiUrl), s.repo.User.ChangeStatus(user.ID, status, reason, updatedByID)
 }
 
 func (s *UserService) ChangeStatusUserByID(id uint, updatedByID uint, status model.UserStatusType) error {
@@ -0,0 +1,524 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitlab/live/be-live-admin/dto"
+	"gitlab/live/be-live-admin/model"
+	"gitlab/live/be-live-admin/repository"
+	"gitlab/live/be-live-admin/utils"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+const (
+	USER_IMPORT_AVATAR_TIMEOUT       = 10 * time.Second
+	USER_IMPORT_AVATAR_MAX_REDIRECTS = 3
+)
+
+// avatar urls come from csv files, so internal hosts must not be reachable through them
+var avatarClient = utils.NewPublicHTTPClient(USER_IMPORT_AVATAR_TIMEOUT, USER_IMPORT_AVATAR_MAX_REDIRECTS)
+
+var (
+	ErrUserImportEmpty       = errors.New("csv has no rows")
+	ErrUserImportTooManyRows = fmt.Errorf("csv is limited to %d rows", dto.USER_IMPORT_MAX_ROWS)
+	ErrUserImportInvalidCSV  = errors.New("invalid csv")
+)
+
+// header names of csv columns, display name is accepted with a space too
+var userImportColumns = map[string]string{
+	"username":     "username",
+	"email":        "email",
+	"display_name": "display_name",
+	"display name": "display_name",
+	"role":         "role",
+	"avatar_url":   "avatar_url",
+	"password":     "password",
+}
+
+// invitees choose their password, this only passes validation of dto.CreateUserRequest
+const userImportInvitePlaceholder = "invited-user-password"
+
+// UserImportService creates users from csv files in a job, every row is validated before anything is created
+type UserImportService struct {
+	repo   *repository.Repository
+	job    *JobService
+	invite *InviteService
+}
+
+func newUserImportService(repo *repository.Repository, job *JobService, invite *InviteService) *UserImportService {
+	return &UserImportService{
+		repo:   repo,
+		job:    job,
+		invite: invite,
+	}
+}
+
+type userImportCandidate struct {
+	row          dto.UserImportRow
+	password     string
+	passwordHash string
+}
+
+func readUserImportCSV(r io.Reader) ([]userImportCandidate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUserImportInvalidCSV, err)
+	}
+	if len(records) < 2 {
+		return nil, ErrUserImportEmpty
+	}
+	if len(records)-1 > dto.USER_IMPORT_MAX_ROWS {
+		return nil, ErrUserImportTooManyRows
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		column, ok := userImportColumns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrUserImportInvalidCSV, name)
+		}
+		columns[column] = i
+	}
+	for _, column := range []string{"username", "email", "display_name", "role"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("%w: missing column %s", ErrUserImportInvalidCSV, column)
+		}
+	}
+
+	value := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	result := make([]userImportCandidate, 0, len(records)-1)
+	for i, record := range records[1:] {
+		result = append(result, userImportCandidate{
+			row: dto.UserImportRow{
+				Row:         i + 2,
+				Username:    value(record, "username"),
+				Email:       value(record, "email"),
+				DisplayName: value(record, "display_name"),
+				RoleType:    model.RoleType(strings.ToLower(value(record, "role"))),
+				AvatarURL:   value(record, "avatar_url"),
+			},
+			password: value(record, "password"),
+		})
+	}
+	return result, nil
+}
+
+func validationMessages(err error) []string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+	return utils.Map(validationErrors, func(e validator.FieldError) string {
+		if e.Param() != "" {
+			return fmt.Sprintf("%s failed on %s=%s", e.Field(), e.Tag(), e.Param())
+		}
+		return fmt.Sprintf("%s failed on %s", e.Field(), e.Tag())
+	})
+}
+
+// validate checks rows with the rules of dto.CreateUserRequest, duplicates in the file, existing users and pending invites
+func (s *UserImportService) validate(candidates []userImportCandidate, invite bool, validate func(i interface{}) error) (*dto.UserImportReport, error) {
+	taken, err := findTakenNames(s.repo,
+		utils.Map(candidates, func(c userImportCandidate) string { return c.row.Username }),
+		utils.Map(candidates, func(c userImportCandidate) string { return c.row.Email }), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.UserImportReport{Total: len(candidates), Errors: []dto.UserImportRowError{}}
+	seenUsernames := make(map[string]int)
+	seenEmails := make(map[string]int)
+	for _, c := range candidates {
+		var messages []string
+
+		password := c.password
+		if invite {
+			password = userImportInvitePlaceholder
+		} else if password == "" {
+			messages = append(messages, "Password is required unless users are invited")
+		}
+		req := dto.CreateUserRequest{
+			UserName:    c.row.Username,
+			Email:       c.row.Email,
+			DisplayName: c.row.DisplayName,
+			Password:    password,
+			RoleType:    c.row.RoleType,
+		}
+		if err := validate(&req); err != nil {
+			messages = append(messages, validationMessages(err)...)
+		}
+
+		if c.row.AvatarURL != "" {
+			if u, err := url.Parse(c.row.AvatarURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				messages = append(messages, "AvatarURL must be an http or https url")
+			}
+		}
+
+		if row, ok := seenUsernames[c.row.Username]; ok {
+			messages = append(messages, fmt.Sprintf("Username is duplicated in row %d", row))
+		} else if taken.hasUsername(c.row.Username) {
+			messages = append(messages, "Username already exists or is invited")
+		}
+		email := strings.ToLower(c.row.Email)
+		if row, ok := seenEmails[email]; ok {
+			messages = append(messages, fmt.Sprintf("Email is duplicated in row %d", row))
+		} else if taken.hasEmail(email) {
+			messages = append(messages, "Email already exists or is invited")
+		}
+		seenUsernames[c.row.Username] = c.row.Row
+		seenEmails[email] = c.row.Row
+
+		if len(messages) > 0 {
+			report.Invalid++
+			report.Errors = append(report.Errors, dto.UserImportRowError{Row: c.row.Row, Username: c.row.Username, Errors: messages})
+		} else {
+			report.Valid++
+		}
+	}
+	return report, nil
+}
+
+// hashPasswords runs bcrypt on every cpu, it's slow on purpose
+func hashPasswords(candidates []userImportCandidate) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	next := make(chan int)
+	for range runtime.NumCPU() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				hash, err := utils.HashPassword(candidates[i].password)
+				if err != nil {
+					mu.Lock()
+					firstErr = err
+					mu.Unlock()
+					continue
+				}
+				candidates[i].passwordHash = hash
+			}
+		}()
+	}
+	for i := range candidates {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return firstErr
+}
+
+// Import validates the csv and queues a model.JobTypeImportUsers job when every row is valid and it's not a dry run
+func (s *UserImportService) Import(r io.Reader, req *dto.UserImportRequest, createdByID uint, validate func(i interface{}) error) (*dto.UserImportReport, error) {
+	candidates, err := readUserImportCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.validate(candidates, req.Invite, validate)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = req.DryRun
+	if req.DryRun || report.Invalid > 0 {
+		return report, nil
+	}
+
+	if !req.Invite {
+		if err := hashPasswords(candidates); err != nil {
+			return nil, err
+		}
+	}
+	// hashes are staged next to the job, so they never show up in its payload
+	var job *model.Job
+	if err := s.repo.Transaction(func(repo *repository.Repository) error {
+		var err error
+		job, err = s.job.EnqueueWith(repo, model.JobTypeImportUsers, dto.ImportUsersPayload{
+			Rows: utils.Map(candidates, func(c userImportCandidate) dto.UserImportRow {
+				return c.row
+			}),
+			Invite:      req.Invite,
+			CreatedByID: createdByID,
+		}, 1, createdByID)
+		if err != nil || req.Invite {
+			return err
+		}
+		return repo.UserImport.CreateCredentials(utils.Map(candidates, func(c userImportCandidate) model.UserImportCredential {
+			return model.UserImportCredential{JobID: job.ID, Row: c.row.Row, PasswordHash: c.passwordHash}
+		}))
+	}); err != nil {
+		return nil, err
+	}
+	report.JobID = job.ID
+	return report, nil
+}
+
+// downloadAvatar saves an image of at most utils.MAX_IMAGE_SIZE bytes to the avatar folder and returns its file name
+func downloadAvatar(ctx context.Context, avatarURL, avatarFolder string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, USER_IMPORT_AVATAR_TIMEOUT)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, avatarURL, nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := avatarClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("avatar url returned %s", response.Status)
+	}
+	if response.ContentLength > utils.MAX_IMAGE_SIZE {
+		return "", errors.New("avatar exceeds the 1MB limit")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, utils.MAX_IMAGE_SIZE+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > utils.MAX_IMAGE_SIZE {
+		return "", errors.New("avatar exceeds the 1MB limit")
+	}
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", errors.New("avatar is not an image")
+	}
+
+	ext := strings.TrimPrefix(contentType, "image/")
+	fileName := fmt.Sprintf("%s.%s", utils.MakeUniqueIDWithTime(), ext)
+	if err := os.WriteFile(filepath.Join(avatarFolder, fileName), data, 0644); err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
+
+// createEach creates items in one batch, when it fails they are created one by one so only the bad ones fail
+func createEach[T any](items []T, create func([]T) error, reset func(T)) []error {
+	errs := make([]error, len(items))
+	if err := create(items); err == nil {
+		return errs
+	}
+	for i, item := range items {
+		reset(item)
+		errs[i] = create([]T{item})
+	}
+	return errs
+}
+
+// createBatch creates users of rows, or invites when payload.Invite, and queues the invite emails.
+// Rows whose username or email was taken since the csv was validated fail.
+func (s *UserImportService) createBatch(ctx context.Context, rows []dto.UserImportRow, payload *dto.ImportUsersPayload, passwordHashes map[int]string, roles map[model.RoleType]uint, avatarFolder string) []dto.UserImportRowResult {
+	if len(rows) == 0 {
+		return nil
+	}
+	results := make([]dto.UserImportRowResult, len(rows))
+	avatars := make([]sql.NullString, len(rows))
+	for i, row := range rows {
+		results[i] = dto.UserImportRowResult{Row: row.Row, Username: row.Username}
+		if row.AvatarURL != "" {
+			fileName, err := downloadAvatar(ctx, row.AvatarURL, avatarFolder)
+			if err != nil {
+				results[i].Warning = fmt.Sprintf("avatar: %v", err)
+			} else {
+				avatars[i] = sql.NullString{String: fileName, Valid: true}
+			}
+		}
+	}
+
+	errs := make([]error, len(rows))
+	ids := make([]uint, len(rows))
+	err := s.repo.WithUserNamesLock(func(repo *repository.Repository) error {
+		// rows taken by a user or an invite since the csv was validated fail
+		taken, err := findTakenNames(repo,
+			utils.Map(rows, func(row dto.UserImportRow) string { return row.Username }),
+			utils.Map(rows, func(row dto.UserImportRow) string { return row.Email }), 0)
+		if err != nil {
+			return err
+		}
+		var available []int
+		for i, row := range rows {
+			if taken.hasUsername(row.Username) {
+				errs[i] = errors.New("Username already exists or is invited")
+			} else if taken.hasEmail(row.Email) {
+				errs[i] = errors.New("Email already exists or is invited")
+			} else {
+				available = append(available, i)
+			}
+		}
+
+		var created []error
+		if payload.Invite {
+			invites := make([]*model.Invite, len(available))
+			for j, i := range available {
+				invites[j] = &model.Invite{
+					Email:          rows[i].Email,
+					Username:       rows[i].Username,
+					DisplayName:    rows[i].DisplayName,
+					RoleID:         roles[rows[i].RoleType],
+					AvatarFileName: avatars[i],
+					Status:         model.InviteStatusPending,
+					InvitedByID:    &payload.CreatedByID,
+				}
+			}
+			created = createEach(invites, repo.Invite.CreateBatch, func(e *model.Invite) { e.ID = 0 })
+			for j, i := range available {
+				ids[i] = invites[j].ID
+			}
+		} else {
+			users := make([]*model.User, len(available))
+			for j, i := range available {
+				users[j] = &model.User{
+					Username:       rows[i].Username,
+					Email:          rows[i].Email,
+					DisplayName:    rows[i].DisplayName,
+					PasswordHash:   passwordHashes[rows[i].Row],
+					RoleID:         roles[rows[i].RoleType],
+					AvatarFileName: avatars[i],
+					CreatedByID:    &payload.CreatedByID,
+					UpdatedByID:    &payload.CreatedByID,
+				}
+			}
+			created = createEach(users, repo.User.CreateBatch, func(e *model.User) { e.ID = 0 })
+			for j, i := range available {
+				ids[i] = users[j].ID
+			}
+		}
+		for j, i := range available {
+			errs[i] = created[j]
+			if errors.Is(errs[i], gorm.ErrDuplicatedKey) {
+				errs[i] = ErrUserConflict
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// nothing of the batch was saved
+		for i := range errs {
+			errs[i] = err
+		}
+	}
+
+	for i, err := range errs {
+		if payload.Invite {
+			results[i].InviteID = ids[i]
+		} else {
+			results[i].UserID = ids[i]
+		}
+		switch {
+		case err != nil:
+			results[i] = dto.UserImportRowResult{Row: results[i].Row, Username: results[i].Username, Status: dto.USER_IMPORT_ROW_FAILED, Error: err.Error()}
+			if avatars[i].Valid {
+				if err := os.Remove(filepath.Join(avatarFolder, avatars[i].String)); err != nil {
+					log.Println(err)
+				}
+			}
+		case payload.Invite:
+			results[i].Status = dto.USER_IMPORT_ROW_INVITED
+			if _, err := s.invite.enqueueSend(results[i].InviteID, payload.CreatedByID); err != nil {
+				if results[i].Warning != "" {
+					results[i].Warning += "; "
+				}
+				results[i].Warning += fmt.Sprintf("invite email: %v", err)
+			}
+		default:
+			results[i].Status = dto.USER_IMPORT_ROW_CREATED
+		}
+	}
+	return results
+}
+
+// ImportJob runs a model.JobTypeImportUsers job, rows are created in batches of dto.USER_IMPORT_BATCH_SIZE
+func (s *UserImportService) ImportJob(avatarFolder string) JobHandler {
+	return func(ctx context.Context, job *model.Job, report JobProgressFunc) error {
+		payload, err := decodeJobPayload[dto.ImportUsersPayload](job)
+		if err != nil {
+			return err
+		}
+		// import jobs run once, so the hashes are not needed after it
+		defer func() {
+			if err := s.repo.UserImport.DeleteCredentials(job.ID); err != nil {
+				log.Printf("Job %d failed to delete credentials: %v\n", job.ID, err)
+			}
+		}()
+		passwordHashes, err := s.repo.UserImport.FindCredentials(job.ID)
+		if err != nil {
+			return err
+		}
+		if !payload.Invite && len(passwordHashes) != len(payload.Rows) {
+			return fmt.Errorf("passwords of %d rows are missing", len(payload.Rows)-len(passwordHashes))
+		}
+
+		roles := make(map[model.RoleType]uint)
+		for _, roleType := range []model.RoleType{model.ADMINROLE, model.STREAMER, model.USERROLE} {
+			role, err := s.repo.Role.FindByType(roleType)
+			if err != nil {
+				return err
+			}
+			if role == nil {
+				return fmt.Errorf("role %s not found", roleType)
+			}
+			roles[roleType] = role.ID
+		}
+
+		result := &dto.UserImportResult{Total: len(payload.Rows)}
+		defer s.finish(job, payload, result)
+
+		for start := 0; start < len(payload.Rows); start += dto.USER_IMPORT_BATCH_SIZE {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			end := min(start+dto.USER_IMPORT_BATCH_SIZE, len(payload.Rows))
+			for _, row := range s.createBatch(ctx, payload.Rows[start:end], payload, passwordHashes, roles, avatarFolder) {
+				result.Add(row)
+			}
+			report(uint(end*100/len(payload.Rows)), fmt.Sprintf("%d of %d rows", end, len(payload.Rows)))
+		}
+		return nil
+	}
+}
+
+// finish saves the per-row results and the audit entry, partial results of canceled jobs are saved too
+func (s *UserImportService) finish(job *model.Job, payload *dto.ImportUsersPayload, result *dto.UserImportResult) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("Job %d failed to encode result: %v\n", job.ID, err)
+	} else if err := s.repo.Job.SaveResult(job.ID, string(data)); err != nil {
+		log.Printf("Job %d failed to save result: %v\n", job.ID, err)
+	}
+
+	adminLog := &model.AdminLog{
+		UserID:  payload.CreatedByID,
+		Action:  string(model.ImportUsers),
+		Details: fmt.Sprintf("Imported users in job %d: %d of %d created, %d failed, %d invited.", job.ID, result.Created, result.Total, result.Failed, result.Invited),
+	}
+	if err := s.repo.Admin.Create(adminLog); err != nil {
+		log.Printf("Job %d failed to create admin log: %v\n", job.ID, err)
+	}
+}
@@ -167,6 +167,56 @@ func SaveImage(c echo.Context, file *multipart.FileHeader, folderPath string) (i
 	return http.StatusOK, name, nil
 }
 
+// SaveUploadedFile copies an uploaded file to path, partial file is removed on failure
+func SaveUploadedFile(file *multipart.FileHeader, path string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, err = io.Copy(dst, src); err != nil {
+		if err := os.Remove(path); err != nil {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// LinkOrCopyFile hard links src to dst, the file is copied when they are on different file systems
+func LinkOrCopyFile(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err = io.Copy(out, in); err != nil {
+		if err := os.Remove(dst); err != nil {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
+
 func RemoveFiles(files []string) error {
 	for _, file := range files {
 		if err := os.Remove(file); err != nil {
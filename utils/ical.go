@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// minimal RFC 5545 writer for calendar feeds
+
+const icalTimeLayout = "20060102T150405Z"
+
+type ICalEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Categories  []string
+	Start       time.Time
+	End         time.Time
+	Status      string // CONFIRMED, TENTATIVE or CANCELLED
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// lines longer than 75 octets are folded with CRLF and a space
+func writeICalLine(b *strings.Builder, line string) {
+	for len(line) > 75 {
+		cut := 75
+		// don't split utf-8 sequences
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func BuildICalendar(name string, events []ICalEvent) string {
+	var b strings.Builder
+	stamp := time.Now().UTC().Format(icalTimeLayout)
+
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//be-live-admin//Scheduled Streams//EN")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:"+icalEscaper.Replace(name))
+	for _, event := range events {
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, "UID:"+event.UID)
+		writeICalLine(&b, "DTSTAMP:"+stamp)
+		writeICalLine(&b, "DTSTART:"+event.Start.UTC().Format(icalTimeLayout))
+		writeICalLine(&b, "DTEND:"+event.End.UTC().Format(icalTimeLayout))
+		writeICalLine(&b, "SUMMARY:"+icalEscaper.Replace(event.Summary))
+		if event.Description != "" {
+			writeICalLine(&b, "DESCRIPTION:"+icalEscaper.Replace(event.Description))
+		}
+		if len(event.Categories) > 0 {
+			writeICalLine(&b, "CATEGORIES:"+strings.Join(Map(event.Categories, icalEscaper.Replace), ","))
+		}
+		if event.Status != "" {
+			writeICalLine(&b, fmt.Sprintf("STATUS:%s", event.Status))
+		}
+		writeICalLine(&b, "END:VEVENT")
+	}
+	writeICalLine(&b, "END:VCALENDAR")
+	return b.String()
+}
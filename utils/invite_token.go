@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// MakeInviteToken returns a random token for invite links and its hash, only the hash is stored
+func MakeInviteToken() (string, string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(data)
+	return token, HashInviteToken(token), nil
+}
+
+func HashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
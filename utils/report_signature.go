@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+var ErrReportSigningKey = errors.New("gdpr.signing_key is required")
+
+// secret key of compliance reports, set once from config at startup
+var reportSecret []byte
+
+// SetReportSigner must be called at startup, there is no default secret key
+func SetReportSigner(secretKey string) error {
+	if secretKey == "" {
+		return ErrReportSigningKey
+	}
+	reportSecret = []byte(secretKey)
+	return nil
+}
+
+// SignReport returns the hex HMAC-SHA256 of a report, reports are signed as stored so they can be verified later
+func SignReport(report []byte) string {
+	mac := hmac.New(sha256.New, reportSecret)
+	mac.Write(report)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyReport(report []byte, signature string) bool {
+	return hmac.Equal([]byte(SignReport(report)), []byte(signature))
+}
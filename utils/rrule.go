@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// subset of RFC 5545 RRULE used by recurring scheduled streams:
+// FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, COUNT, UNTIL, BYDAY (without ordinals) and BYMONTHDAY
+
+const (
+	RRULE_DAILY   = "DAILY"
+	RRULE_WEEKLY  = "WEEKLY"
+	RRULE_MONTHLY = "MONTHLY"
+
+	// stops expanding rules which never match, e.g. BYMONTHDAY=31 with BYDAY on a weekly rule
+	maxRRulePeriods = 10000
+)
+
+var ErrInvalidRRule = errors.New("invalid rrule")
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+type RRule struct {
+	Freq        string
+	Interval    int
+	Count       int
+	Until       *time.Time
+	UntilIsDate bool // the series ends with the day of Until in the location of dtstart
+	ByDay       []time.Weekday
+	ByMonthDay  []int
+}
+
+func ParseRRule(rule string) (*RRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	result := &RRule{Interval: 1}
+
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRRule, part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			value = strings.ToUpper(value)
+			if !slices.Contains([]string{RRULE_DAILY, RRULE_WEEKLY, RRULE_MONTHLY}, value) {
+				return nil, fmt.Errorf("%w: unsupported FREQ %s", ErrInvalidRRule, value)
+			}
+			result.Freq = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL must be a positive number", ErrInvalidRRule)
+			}
+			result.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be a positive number", ErrInvalidRRule)
+			}
+			result.Count = count
+		case "UNTIL":
+			until, isDate, err := parseRRuleTime(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: UNTIL %s", ErrInvalidRRule, value)
+			}
+			result.Until = &until
+			result.UntilIsDate = isDate
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := rruleWeekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY %s", ErrInvalidRRule, day)
+				}
+				result.ByDay = append(result.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return nil, fmt.Errorf("%w: BYMONTHDAY %s", ErrInvalidRRule, day)
+				}
+				result.ByMonthDay = append(result.ByMonthDay, monthDay)
+			}
+		case "WKST":
+			if strings.ToUpper(value) != "MO" {
+				return nil, fmt.Errorf("%w: only WKST=MO is supported", ErrInvalidRRule)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRRule, key)
+		}
+	}
+
+	if result.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRRule)
+	}
+	if result.Count > 0 && result.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL can't be used together", ErrInvalidRRule)
+	}
+	return result, nil
+}
+
+// UNTIL is either a date or an utc date time, isDate is true for dates
+func parseRRuleTime(value string) (until time.Time, isDate bool, err error) {
+	if len(value) == len("20060102") {
+		until, err = time.Parse("20060102", value)
+		return until, true, err
+	}
+	until, err = time.Parse("20060102T150405Z", value)
+	return until, false, err
+}
+
+// until returns whether occurrence is within UNTIL, a date includes its whole day in the location of dtstart
+func (r *RRule) until(dtstart, occurrence time.Time) bool {
+	if r.Until == nil {
+		return true
+	}
+	if r.UntilIsDate {
+		end := time.Date(r.Until.Year(), r.Until.Month(), r.Until.Day()+1, 0, 0, 0, 0, dtstart.Location())
+		return occurrence.Before(end)
+	}
+	return !occurrence.After(*r.Until)
+}
+
+func (r *RRule) matchesDay(day time.Time) bool {
+	if len(r.ByDay) > 0 && !slices.Contains(r.ByDay, day.Weekday()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 {
+		daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		matched := false
+		for _, monthDay := range r.ByMonthDay {
+			if monthDay < 0 {
+				monthDay = daysInMonth + monthDay + 1
+			}
+			if monthDay == day.Day() {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// candidates of the n-th period, ordered, with wall clock time of dtstart
+func (r *RRule) periodCandidates(dtstart time.Time, n int) []time.Time {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+	}
+
+	switch r.Freq {
+	case RRULE_DAILY:
+		day := at(dtstart.Year(), dtstart.Month(), dtstart.Day()+n*r.Interval)
+		if r.matchesDay(day) {
+			return []time.Time{day}
+		}
+		return nil
+	case RRULE_WEEKLY:
+		// weeks start on monday
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		weekStart := at(dtstart.Year(), dtstart.Month(), dtstart.Day()-offset+n*r.Interval*7)
+		var result []time.Time
+		for i := 0; i < 7; i++ {
+			day := at(weekStart.Year(), weekStart.Month(), weekStart.Day()+i)
+			if len(r.ByDay) == 0 && day.Weekday() != dtstart.Weekday() {
+				continue
+			}
+			if r.matchesDay(day) {
+				result = append(result, day)
+			}
+		}
+		return result
+	default:
+		monthStart := time.Date(dtstart.Year(), dtstart.Month()+time.Month(n*r.Interval), 1, 0, 0, 0, 0, dtstart.Location())
+		daysInMonth := time.Date(monthStart.Year(), monthStart.Month()+1, 0, 0, 0, 0, 0, dtstart.Location()).Day()
+		var result []time.Time
+		for d := 1; d <= daysInMonth; d++ {
+			day := at(monthStart.Year(), monthStart.Month(), d)
+			if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 && d != dtstart.Day() {
+				continue
+			}
+			if r.matchesDay(day) {
+				result = append(result, day)
+			}
+		}
+		return result
+	}
+}
+
+// Between returns occurrences in [from, to) of the rule starting at dtstart, ordered.
+// dtstart is always the first occurrence, its location decides wall clock time across DST changes.
+// exdates are skipped but still counted by COUNT as RFC 5545 says.
+func (r *RRule) Between(dtstart, from, to time.Time, exdates []time.Time) []time.Time {
+	var result []time.Time
+	count := 0
+
+	emit := func(occurrence time.Time) bool {
+		if r.Count > 0 && count >= r.Count {
+			return false
+		}
+		if !r.until(dtstart, occurrence) {
+			return false
+		}
+		if !occurrence.Before(to) {
+			return false
+		}
+		count++
+		if occurrence.Before(from) {
+			return true
+		}
+		if slices.ContainsFunc(exdates, occurrence.Equal) {
+			return true
+		}
+		result = append(result, occurrence)
+		return true
+	}
+
+	if !emit(dtstart) {
+		return result
+	}
+	for n := 0; n < maxRRulePeriods; n++ {
+		for _, occurrence := range r.periodCandidates(dtstart, n) {
+			if !occurrence.After(dtstart) {
+				continue
+			}
+			if !emit(occurrence) {
+				return result
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRRuleBetween(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// wall clock times are in the location of the test
+	at := func(location *time.Location, value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name     string
+		rule     string
+		location *time.Location
+		dtstart  string
+		from     string // dtstart when empty
+		to       string
+		exdates  []string
+		want     []string
+	}{
+		{
+			name:     "count includes exdates",
+			rule:     "FREQ=DAILY;COUNT=5",
+			location: time.UTC,
+			dtstart:  "2025-01-01 10:00",
+			to:       "2025-02-01 00:00",
+			exdates:  []string{"2025-01-03 10:00"},
+			want:     []string{"2025-01-01 10:00", "2025-01-02 10:00", "2025-01-04 10:00", "2025-01-05 10:00"},
+		},
+		{
+			name:     "count includes occurrences before from",
+			rule:     "FREQ=DAILY;COUNT=3",
+			location: time.UTC,
+			dtstart:  "2025-01-01 10:00",
+			from:     "2025-01-02 00:00",
+			to:       "2025-02-01 00:00",
+			want:     []string{"2025-01-02 10:00", "2025-01-03 10:00"},
+		},
+		{
+			name:     "until date includes the whole day in the series location",
+			rule:     "FREQ=DAILY;UNTIL=20250103",
+			location: newYork,
+			dtstart:  "2025-01-01 20:00",
+			to:       "2025-02-01 00:00",
+			want:     []string{"2025-01-01 20:00", "2025-01-02 20:00", "2025-01-03 20:00"},
+		},
+		{
+			name:     "until date time is inclusive",
+			rule:     "FREQ=DAILY;UNTIL=20250103T100000Z",
+			location: time.UTC,
+			dtstart:  "2025-01-01 10:00",
+			to:       "2025-02-01 00:00",
+			want:     []string{"2025-01-01 10:00", "2025-01-02 10:00", "2025-01-03 10:00"},
+		},
+		{
+			name:     "last day of month",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=4",
+			location: time.UTC,
+			dtstart:  "2025-01-31 18:00",
+			to:       "2026-01-01 00:00",
+			want:     []string{"2025-01-31 18:00", "2025-02-28 18:00", "2025-03-31 18:00", "2025-04-30 18:00"},
+		},
+		{
+			name:     "monthly keeps the day of dtstart",
+			rule:     "FREQ=MONTHLY;COUNT=3",
+			location: time.UTC,
+			dtstart:  "2025-01-15 18:00",
+			to:       "2026-01-01 00:00",
+			want:     []string{"2025-01-15 18:00", "2025-02-15 18:00", "2025-03-15 18:00"},
+		},
+		{
+			name:     "weekly with interval",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=5",
+			location: time.UTC,
+			dtstart:  "2025-01-06 09:00",
+			to:       "2026-01-01 00:00",
+			want:     []string{"2025-01-06 09:00", "2025-01-08 09:00", "2025-01-20 09:00", "2025-01-22 09:00", "2025-02-03 09:00"},
+		},
+		{
+			name:     "weekly without byday repeats the weekday of dtstart",
+			rule:     "FREQ=WEEKLY;INTERVAL=3",
+			location: time.UTC,
+			dtstart:  "2025-01-08 09:00",
+			to:       "2025-02-20 00:00",
+			want:     []string{"2025-01-08 09:00", "2025-01-29 09:00", "2025-02-19 09:00"},
+		},
+		{
+			name:     "wall clock time is kept across dst",
+			rule:     "FREQ=WEEKLY;COUNT=3",
+			location: berlin,
+			dtstart:  "2025-03-23 10:00",
+			to:       "2026-01-01 00:00",
+			want:     []string{"2025-03-23 10:00", "2025-03-30 10:00", "2025-04-06 10:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dtstart := at(tt.location, tt.dtstart)
+			from := dtstart
+			if tt.from != "" {
+				from = at(tt.location, tt.from)
+			}
+			var exdates []time.Time
+			for _, exdate := range tt.exdates {
+				exdates = append(exdates, at(tt.location, exdate))
+			}
+
+			got := rule.Between(dtstart, from, at(tt.location, tt.to), exdates)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if !got[i].Equal(at(tt.location, want)) {
+					t.Errorf("occurrence %d is %v, want %s", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRRuleBetweenDSTOffset(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule, err := ParseRRule("FREQ=DAILY;COUNT=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10:00 is 09:00 UTC before the change on 2025-03-30 and 08:00 UTC after
+	got := rule.Between(time.Date(2025, 3, 29, 10, 0, 0, 0, berlin), time.Date(2025, 3, 29, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), nil)
+	want := []time.Time{time.Date(2025, 3, 29, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 30, 8, 0, 0, 0, time.UTC)}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("occurrence %d is %v, want %v", i, got[i].UTC(), want[i])
+		}
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		wantErr bool
+	}{
+		{rule: "RRULE:FREQ=WEEKLY;BYDAY=MO,FR"},
+		{rule: "FREQ=DAILY;UNTIL=20250103"},
+		{rule: "FREQ=YEARLY", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=2;UNTIL=20250103", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=0", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{rule: "COUNT=2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			_, err := ParseRRule(tt.rule)
+			if tt.wantErr && !errors.Is(err, ErrInvalidRRule) {
+				t.Fatalf("got %v, want %v", err, ErrInvalidRRule)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	SIGNED_URL_EXPIRES_PARAM   = "exp"
+	SIGNED_URL_SIGNATURE_PARAM = "sig"
+	SIGNED_URL_IP_PARAM        = "ip"
+
+	DEFAULT_SIGNED_URL_EXPIRATION = time.Hour
+)
+
+var (
+	ErrSignedURLMissing   = errors.New("missing signature")
+	ErrSignedURLExpired   = errors.New("signed url is expired")
+	ErrSignedURLInvalid   = errors.New("invalid signature")
+	ErrSignedURLIPInvalid = errors.New("signed url is not valid for this ip")
+	ErrSignedURLSecretKey = errors.New("api_file.secret_key is required")
+)
+
+// secret key and lifetime of file urls, set once from config at startup
+var (
+	fileURLSecret     []byte
+	fileURLExpiration = DEFAULT_SIGNED_URL_EXPIRATION
+)
+
+// SetFileURLSigner must be called at startup, there is no default secret key
+func SetFileURLSigner(secretKey string, expiration time.Duration) error {
+	if secretKey == "" {
+		return ErrSignedURLSecretKey
+	}
+	fileURLSecret = []byte(secretKey)
+	if expiration > 0 {
+		fileURLExpiration = expiration
+	}
+	return nil
+}
+
+// signFilePath signs the ip only when the url is bound to one, so unbound signatures don't depend on it
+func signFilePath(filePath, expires, clientIP string) string {
+	mac := hmac.New(sha256.New, fileURLSecret)
+	if clientIP == "" {
+		mac.Write([]byte(fmt.Sprintf("%s\n%s", filePath, expires)))
+	} else {
+		mac.Write([]byte(fmt.Sprintf("%s\n%s\n%s", filePath, expires, clientIP)))
+	}
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// filePath is the url path starting with /api/file, clientIP is optional and binds the url to that ip
+func MakeSignedFileURL(apiURL, filePath, clientIP string) string {
+	return makeSignedURL(apiURL, filePath, clientIP, fileURLExpiration)
+}
+
+// MakeSignedURL signs any url path with its own lifetime, e.g. calendar feeds which are added to external calendars
+func MakeSignedURL(apiURL, urlPath string, expiration time.Duration) string {
+	return makeSignedURL(apiURL, urlPath, "", expiration)
+}
+
+func makeSignedURL(apiURL, urlPath, clientIP string, expiration time.Duration) string {
+	expires := strconv.FormatInt(time.Now().Add(expiration).Unix(), 10)
+
+	query := url.Values{}
+	query.Set(SIGNED_URL_EXPIRES_PARAM, expires)
+	if clientIP != "" {
+		query.Set(SIGNED_URL_IP_PARAM, clientIP)
+	}
+	query.Set(SIGNED_URL_SIGNATURE_PARAM, signFilePath(urlPath, expires, clientIP))
+
+	return fmt.Sprintf("%s%s?%s", apiURL, urlPath, query.Encode())
+}
+
+// VerifySignedFilePath checks the signature and expiry, urls bound to an ip are only valid for clientIP
+func VerifySignedFilePath(filePath string, query url.Values, clientIP string) error {
+	expires := query.Get(SIGNED_URL_EXPIRES_PARAM)
+	signature := query.Get(SIGNED_URL_SIGNATURE_PARAM)
+	boundIP := query.Get(SIGNED_URL_IP_PARAM)
+	if expires == "" || signature == "" {
+		return ErrSignedURLMissing
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrSignedURLInvalid
+	}
+
+	expected := signFilePath(filePath, expires, boundIP)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrSignedURLInvalid
+	}
+	if boundIP != "" && boundIP != clientIP {
+		return ErrSignedURLIPInvalid
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return ErrSignedURLExpired
+	}
+
+	return nil
+}
@@ -14,15 +14,27 @@ func MakeBroadcastURL(hlsURL, streamKey string) string {
 
 // be aware, I haed coded thumbnail
 func MakeThumbnailURL(apiURL, fileName string) string {
-	return fmt.Sprintf("%s/api/file/thumbnail/%s", apiURL, fileName)
+	return MakeSignedFileURL(apiURL, fmt.Sprintf("/api/file/thumbnail/%s", fileName), "")
 }
 
 func MakeAvatarURL(apiURL, fileName string) string {
-	return fmt.Sprintf("%s/api/file/avatar/%s", apiURL, fileName)
+	return MakeSignedFileURL(apiURL, fmt.Sprintf("/api/file/avatar/%s", fileName), "")
 }
 
 func MakeScheduleVideoURL(apiURL, fileName string) string {
-	return fmt.Sprintf("%s/api/file/scheduled_videos/%s", apiURL, fileName)
+	return MakeSignedFileURL(apiURL, fmt.Sprintf("/api/file/scheduled_videos/%s", fileName), "")
+}
+
+func MakeRecordingVideoURL(apiURL, fileName string) string {
+	return MakeSignedFileURL(apiURL, fmt.Sprintf("/api/file/videos/%s", fileName), "")
+}
+
+func MakeClipURL(apiURL, fileName string) string {
+	return MakeSignedFileURL(apiURL, fmt.Sprintf("/api/file/clips/%s", fileName), "")
+}
+
+func MakeExportURL(apiURL, fileName string) string {
+	return MakeSignedFileURL(apiURL, fmt.Sprintf("/api/file/exports/%s", fileName), "")
 }
 
 // will be used by scheduled and ended videos
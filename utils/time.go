@@ -4,38 +4,31 @@ import (
 	"time"
 )
 
-func IsValidSchedule(scheduleAt string) bool {
+const DEFAULT_SCHEDULE_WINDOW = 72 * time.Hour
+
+// IsValidSchedule checks scheduleAt is in the future and within window, 0 window is DEFAULT_SCHEDULE_WINDOW
+func IsValidSchedule(scheduleAt string, window time.Duration) bool {
 	parsedTime, err := time.Parse(DATETIME_LAYOUT, scheduleAt)
 	if err != nil {
 		return false
 	}
 
-	// log.Println(parsedTime.UTC().String())
-
-	// log.Println(time.Now().UTC().String())
-
-	nowUTC := time.Now().UTC()
-	futureUTC := nowUTC.Add(72 * time.Hour)
-
-	// Check if the parsed time is within the valid range
-	if parsedTime.After(nowUTC) && parsedTime.Before(futureUTC) {
-		return true
-	}
+	return isWithinScheduleWindow(parsedTime, window)
+}
 
-	return false
+func IsValidScheduleTimestamp(scheduleAt uint, window time.Duration) bool {
+	return isWithinScheduleWindow(time.Unix(int64(scheduleAt), 0), window)
 }
 
-func IsValidScheduleTimestamp(scheduleAt uint) bool {
-	parsedTime := time.Unix(int64(scheduleAt), 0)
+func isWithinScheduleWindow(parsedTime time.Time, window time.Duration) bool {
+	if window <= 0 {
+		window = DEFAULT_SCHEDULE_WINDOW
+	}
 	nowUTC := time.Now().UTC()
-	futureUTC := nowUTC.Add(72 * time.Hour)
+	futureUTC := nowUTC.Add(window)
 
 	// Check if the parsed time is within the valid range
-	if parsedTime.After(nowUTC) && parsedTime.Before(futureUTC) {
-		return true
-	}
-
-	return false
+	return parsedTime.After(nowUTC) && parsedTime.Before(futureUTC)
 }
 
 func GetStartDateEndDateSameDay(dateString string) (*time.Time, *time.Time, error) {
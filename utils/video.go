@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProbeVideoDuration reads the duration of a video file with ffprobe
+func ProbeVideoDuration(ctx context.Context, ffprobePath, videoPath string) (time.Duration, error) {
+	if ffprobePath == "" {
+		ffprobePath = "ffprobe"
+	}
+
+	cmd := exec.CommandContext(ctx, ffprobePath,
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		videoPath,
+	)
+	// children holding the output pipe open don't block past the deadline
+	cmd.WaitDelay = time.Second
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe %s: %w", videoPath, err)
+	}
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe %s: unexpected duration %q", videoPath, strings.TrimSpace(string(output)))
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// PlaylistInput is an item of a pushed playlist, zero InPoint and OutPoint play the whole video
+type PlaylistInput struct {
+	Path     string
+	Slate    bool // still image shown for Duration
+	InPoint  time.Duration
+	OutPoint time.Duration
+	Duration time.Duration
+}
+
+// BuildFFmpegConcatArgs returns the inputs and the concat filter joining videos and slates,
+// items are scaled to width x height and resampled so they are encoded as one stream.
+// Output is mapped from [v] and [a] at native speed, videos need an audio track.
+func BuildFFmpegConcatArgs(inputs []PlaylistInput, width, height, fps int) []string {
+	var args []string
+	var filter, segments strings.Builder
+	index := 0
+	for i, input := range inputs {
+		videoIndex, audioIndex := index, index
+		if input.Slate {
+			args = append(args,
+				"-loop", "1", "-framerate", strconv.Itoa(fps), "-t", formatSeconds(input.Duration), "-i", input.Path,
+				"-f", "lavfi", "-t", formatSeconds(input.Duration), "-i", "anullsrc=r=44100:cl=stereo",
+			)
+			audioIndex = index + 1
+			index += 2
+		} else {
+			if input.InPoint > 0 {
+				args = append(args, "-ss", formatSeconds(input.InPoint))
+			}
+			if input.OutPoint > 0 {
+				args = append(args, "-to", formatSeconds(input.OutPoint))
+			}
+			args = append(args, "-i", input.Path)
+			index++
+		}
+
+		fmt.Fprintf(&filter, "[%d:v]scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=%d,format=yuv420p[v%d];",
+			videoIndex, width, height, width, height, fps, i)
+		fmt.Fprintf(&filter, "[%d:a]aresample=44100,aformat=sample_fmts=fltp:channel_layouts=stereo[a%d];", audioIndex, i)
+		fmt.Fprintf(&segments, "[v%d][a%d]", i, i)
+	}
+	fmt.Fprintf(&filter, "%sconcat=n=%d:v=1:a=1[cv][ca];[cv]realtime[v];[ca]arealtime[a]", segments.String(), len(inputs))
+
+	return append(args, "-filter_complex", filter.String(), "-map", "[v]", "-map", "[a]")
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}